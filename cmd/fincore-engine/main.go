@@ -1,14 +1,20 @@
 package main
 
 import (
+	"context"
+	"crypto/rand"
 	"net/http"
+	"time"
 
 	"github.com/Bendomey/fincore-engine/internal/config"
 	"github.com/Bendomey/fincore-engine/internal/db"
+	"github.com/Bendomey/fincore-engine/internal/events"
 	"github.com/Bendomey/fincore-engine/internal/handlers"
 	"github.com/Bendomey/fincore-engine/internal/repository"
 	"github.com/Bendomey/fincore-engine/internal/router"
 	"github.com/Bendomey/fincore-engine/internal/services"
+	"github.com/Bendomey/fincore-engine/internal/storage"
+	"github.com/Bendomey/fincore-engine/internal/webhooks"
 	"github.com/Bendomey/fincore-engine/pkg"
 	"github.com/getsentry/raven-go"
 	"github.com/go-playground/validator/v10"
@@ -30,11 +36,25 @@ func main() {
 		log.Fatal("failed to connect db:", err)
 	}
 
+	fileStorage, err := storage.New(cfg.Storage)
+	if err != nil {
+		raven.CaptureError(err, nil)
+		log.Fatal("failed to initialize storage:", err)
+	}
+
+	// both keys must be the same on every instance, random ones only do for a single instance in development.
+	tokenSigningKey := loadAuthKey(cfg, "AUTH_TOKEN_SIGNING_KEY", cfg.Auth.TokenSigningKey)
+	encryptionKey := loadAuthKey(cfg, "AUTH_ENCRYPTION_KEY", cfg.Auth.EncryptionKey)
+
 	// singleton is efficient.
 	validate := validator.New()
 
+	// wakes the event streams up, whichever instance the events were written on.
+	eventBroker := events.NewBroker(db.DSN(cfg))
+	go eventBroker.Run(context.Background())
+
 	repository := repository.NewRepository(database)
-	services := services.NewServices(repository)
+	services := services.NewServices(repository, fileStorage, eventBroker, tokenSigningKey, encryptionKey)
 	handlers := handlers.NewHandlers(services, validate)
 
 	appCtx := pkg.AppContext{
@@ -46,6 +66,24 @@ func main() {
 		Validator:  validate,
 	}
 
+	// deliver webhooks from the outbox in the background.
+	go webhooks.NewDispatcher(
+		repository.WebhookEventRepository,
+		repository.WebhookDeliveryRepository,
+	).Run(context.Background())
+
+	// settle the status of expired holds, they stop counting against balances as soon as they expire.
+	go func() {
+		ticker := time.NewTicker(time.Minute)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			if _, err := services.HoldService.ExpireHolds(context.Background()); err != nil {
+				raven.CaptureError(err, map[string]string{"function": "ExpireHolds"})
+			}
+		}
+	}()
+
 	r := router.New(appCtx)
 
 	log.Printf("Server running on :%s\n", cfg.Port)
@@ -58,3 +96,20 @@ func main() {
 		log.Fatalf("Error occurred while serving fincore engine, %v", errServer)
 	}
 }
+
+// loadAuthKey returns the key set in the named variable, which must be at least 32 bytes. Outside production a
+// random key stands in for a missing one.
+func loadAuthKey(cfg config.Config, name string, value string) []byte {
+	key := []byte(value)
+	if len(key) == 0 && cfg.Env != "production" {
+		log.Warnf("%s is not set, a random key is used until this instance stops", name)
+		key = make([]byte, 32)
+		rand.Read(key)
+	}
+
+	if len(key) < 32 {
+		log.Fatalf("%s must be at least 32 bytes", name)
+	}
+
+	return key
+}
@@ -0,0 +1,48 @@
+package jobs
+
+import (
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+// ApiKeyCredentials gives every key the credential of the first key it was rotated from, and moves the entries
+// made with a client's single secret over to the key that secret became. Until then the maker of those entries
+// was the client itself, which no other credential of the client could tell apart from its own.
+func ApiKeyCredentials() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "202610190006_api_key_credentials",
+		Migrate: func(db *gorm.DB) error {
+			err := db.Exec(`
+				WITH RECURSIVE lineage AS (
+					SELECT id, id AS credential_id FROM api_keys WHERE rotated_from_id IS NULL
+					UNION ALL
+					SELECT api_keys.id, lineage.credential_id
+					FROM api_keys
+					JOIN lineage ON api_keys.rotated_from_id = lineage.id::text
+				)
+				UPDATE api_keys SET credential_id = lineage.credential_id::text
+				FROM lineage
+				WHERE api_keys.id = lineage.id AND (api_keys.credential_id IS NULL OR api_keys.credential_id = '')
+			`).Error
+			if err != nil {
+				return err
+			}
+
+			for _, column := range []string{"created_by", "approved_by", "rejected_by"} {
+				err := db.Exec(
+					"UPDATE journal_entries SET " + column + " = api_keys.credential_id FROM clients, api_keys " +
+						"WHERE journal_entries." + column + " = clients.client_id " +
+						"AND api_keys.client_id = clients.id::text AND api_keys.prefix IS NULL",
+				).Error
+				if err != nil {
+					return err
+				}
+			}
+
+			return nil
+		},
+		Rollback: func(db *gorm.DB) error {
+			return nil
+		},
+	}
+}
@@ -0,0 +1,24 @@
+package jobs
+
+import (
+	"github.com/Bendomey/fincore-engine/internal/models"
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// ApiKeyScopes grants every scope to the keys made before keys had scopes, so they keep doing all they could.
+func ApiKeyScopes() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "202610190004_api_key_scopes",
+		Migrate: func(db *gorm.DB) error {
+			return db.Model(&models.ApiKey{}).
+				Where("scopes IS NULL").
+				UpdateColumn("scopes", datatypes.JSONSlice[string](models.ApiKeyScopes)).
+				Error
+		},
+		Rollback: func(db *gorm.DB) error {
+			return nil
+		},
+	}
+}
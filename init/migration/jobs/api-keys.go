@@ -0,0 +1,44 @@
+package jobs
+
+import (
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+// ApiKeysFromClientSecrets moves every client's single secret over to an api key labelled default, so it keeps
+// working and can be rotated or revoked like any other key.
+func ApiKeysFromClientSecrets() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "202610190003_api_keys_from_client_secrets",
+		Migrate: func(db *gorm.DB) error {
+			// databases created after the secret moved never had the column.
+			if !db.Migrator().HasColumn("clients", "client_secret_hash") {
+				return nil
+			}
+
+			err := db.Exec(`
+				INSERT INTO api_keys (client_id, label, secret_hash, created_at, updated_at)
+				SELECT clients.id, 'default', clients.client_secret_hash, clients.created_at, now()
+				FROM clients
+				WHERE clients.client_secret_hash IS NOT NULL AND clients.client_secret_hash <> ''
+			`).Error
+			if err != nil {
+				return err
+			}
+
+			return db.Migrator().DropColumn("clients", "client_secret_hash")
+		},
+		Rollback: func(db *gorm.DB) error {
+			err := db.Exec("ALTER TABLE clients ADD COLUMN IF NOT EXISTS client_secret_hash text").Error
+			if err != nil {
+				return err
+			}
+
+			return db.Exec(`
+				UPDATE clients SET client_secret_hash = api_keys.secret_hash
+				FROM api_keys
+				WHERE api_keys.client_id = clients.id::text AND api_keys.prefix IS NULL
+			`).Error
+		},
+	}
+}
@@ -0,0 +1,44 @@
+package jobs
+
+import (
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+// AuditEventsAppendOnly makes the database reject updates and deletes on audit events.
+func AuditEventsAppendOnly() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "202610190001_audit_events_append_only",
+		Migrate: func(db *gorm.DB) error {
+			err := db.Exec(`
+				CREATE OR REPLACE FUNCTION audit_events_append_only() RETURNS trigger AS $$
+				BEGIN
+					RAISE EXCEPTION 'audit_events is append-only';
+				END;
+				$$ LANGUAGE plpgsql
+			`).Error
+			if err != nil {
+				return err
+			}
+
+			err = db.Exec("DROP TRIGGER IF EXISTS audit_events_append_only ON audit_events").Error
+			if err != nil {
+				return err
+			}
+
+			return db.Exec(`
+				CREATE TRIGGER audit_events_append_only
+					BEFORE UPDATE OR DELETE ON audit_events
+					FOR EACH ROW EXECUTE FUNCTION audit_events_append_only()
+			`).Error
+		},
+		Rollback: func(db *gorm.DB) error {
+			err := db.Exec("DROP TRIGGER IF EXISTS audit_events_append_only ON audit_events").Error
+			if err != nil {
+				return err
+			}
+
+			return db.Exec("DROP FUNCTION IF EXISTS audit_events_append_only()").Error
+		},
+	}
+}
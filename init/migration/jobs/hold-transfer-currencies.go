@@ -0,0 +1,27 @@
+package jobs
+
+import (
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+// HoldTransferCurrencies sets the currency of the holds and transfers made before they kept one to their ledger's,
+// the currency their amounts were always in.
+func HoldTransferCurrencies() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "202610190009_hold_transfer_currencies",
+		Migrate: func(db *gorm.DB) error {
+			for _, table := range []string{"holds", "transfers"} {
+				err := db.Exec(
+					"UPDATE " + table + " SET currency = ledgers.currency FROM ledgers " +
+						"WHERE ledgers.id::text = " + table + ".ledger_id",
+				).Error
+				if err != nil {
+					return err
+				}
+			}
+
+			return nil
+		},
+	}
+}
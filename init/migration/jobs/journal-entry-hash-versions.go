@@ -0,0 +1,22 @@
+package jobs
+
+import (
+	"github.com/Bendomey/fincore-engine/internal/models"
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+// JournalEntryHashVersions records that the entries chained so far were hashed without their ledger or their
+// lines' currencies. They keep their hashes and are still verified against that content, rehashing them would
+// make a chain tampered with before now valid again.
+func JournalEntryHashVersions() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "202610190008_journal_entry_hash_versions",
+		Migrate: func(db *gorm.DB) error {
+			return db.Model(&models.JournalEntry{}).
+				Unscoped().
+				Where("hash IS NOT NULL AND hash_version IS NULL").
+				Update("hash_version", models.ChainHashVersionLegacy).Error
+		},
+	}
+}
@@ -0,0 +1,55 @@
+package jobs
+
+import (
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+// LedgerResources moves the transfers, holds, approval rules, dimensions and webhooks made before they were kept
+// per ledger into the ledger they belong to. Transfers and holds are in their account's ledger, dimension values
+// in their type's and outbox events in the ledger of the resource they carry. Everything else was made for the
+// client's books so far, its default ledger. Dimension codes become unique per ledger rather than per client.
+func LedgerResources() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "202610190007_ledger_resources",
+		Migrate: func(db *gorm.DB) error {
+			statements := []string{
+				`UPDATE transfers SET ledger_id = accounts.ledger_id FROM accounts
+				WHERE accounts.id::text = transfers.from_account_id AND transfers.ledger_id IS NULL`,
+				`UPDATE holds SET ledger_id = accounts.ledger_id FROM accounts
+				WHERE accounts.id::text = holds.account_id AND holds.ledger_id IS NULL`,
+				`UPDATE webhook_events SET ledger_id = webhook_events.payload->>'ledger_id'
+				WHERE webhook_events.ledger_id IS NULL AND webhook_events.payload->>'ledger_id' IS NOT NULL`,
+			}
+
+			for _, table := range []string{"approval_rules", "dimension_types", "webhook_endpoints", "webhook_events"} {
+				statements = append(statements,
+					"UPDATE "+table+" SET ledger_id = ledgers.id::text FROM ledgers "+
+						"WHERE ledgers.client_id = "+table+".client_id AND ledgers.is_default "+
+						"AND "+table+".ledger_id IS NULL",
+				)
+			}
+
+			statements = append(statements,
+				`UPDATE dimension_values SET ledger_id = dimension_types.ledger_id FROM dimension_types
+				WHERE dimension_types.id::text = dimension_values.dimension_type_id
+				AND dimension_values.ledger_id IS NULL`,
+				"DROP INDEX IF EXISTS idx_dimension_types_client_code",
+			)
+
+			for _, statement := range statements {
+				if err := db.Exec(statement).Error; err != nil {
+					return err
+				}
+			}
+
+			return nil
+		},
+		Rollback: func(db *gorm.DB) error {
+			return db.Exec(
+				"CREATE UNIQUE INDEX IF NOT EXISTS idx_dimension_types_client_code " +
+					"ON dimension_types (client_id, code)",
+			).Error
+		},
+	}
+}
@@ -0,0 +1,69 @@
+package jobs
+
+import (
+	"github.com/Bendomey/fincore-engine/internal/models"
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+// ledgerScopeGrants grant the ledger scopes to keys made before ledgers existed, alongside the account scope that
+// let them do as much.
+var ledgerScopeGrants = []struct {
+	held    string
+	granted string
+}{
+	{models.ApiKeyScopeAccountsRead, models.ApiKeyScopeLedgersRead},
+	{models.ApiKeyScopeAccountsWrite, models.ApiKeyScopeLedgersWrite},
+}
+
+// Ledgers gives every client a default ledger in its currency and moves its accounts and journal entries into it,
+// the books they were in so far. Account codes become unique per ledger rather than across every client.
+func Ledgers() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "202610190005_ledgers",
+		Migrate: func(db *gorm.DB) error {
+			err := db.Exec(`
+				INSERT INTO ledgers (
+					client_id, name, currency, fiscal_year_start_month, is_default, created_at, updated_at
+				)
+				SELECT clients.id::text, 'Default', clients.currency, 1, true, clients.created_at, now()
+				FROM clients
+				WHERE NOT EXISTS (
+					SELECT 1 FROM ledgers WHERE ledgers.client_id = clients.id::text AND ledgers.is_default
+				)
+			`).Error
+			if err != nil {
+				return err
+			}
+
+			for _, table := range []string{"accounts", "journal_entries"} {
+				err := db.Exec(
+					"UPDATE " + table + " SET ledger_id = ledgers.id::text FROM ledgers " +
+						"WHERE ledgers.client_id = " + table + ".client_id AND ledgers.is_default " +
+						"AND " + table + ".ledger_id IS NULL",
+				).Error
+				if err != nil {
+					return err
+				}
+			}
+
+			for _, grant := range ledgerScopeGrants {
+				err := db.Exec(
+					"UPDATE api_keys SET scopes = scopes || jsonb_build_array(?::text) "+
+						"WHERE scopes @> jsonb_build_array(?::text) AND NOT scopes @> jsonb_build_array(?::text)",
+					grant.granted,
+					grant.held,
+					grant.granted,
+				).Error
+				if err != nil {
+					return err
+				}
+			}
+
+			return db.Exec("DROP INDEX IF EXISTS idx_accounts_code").Error
+		},
+		Rollback: func(db *gorm.DB) error {
+			return db.Exec("CREATE UNIQUE INDEX IF NOT EXISTS idx_accounts_code ON accounts (code)").Error
+		},
+	}
+}
@@ -0,0 +1,56 @@
+package jobs
+
+import (
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+// searchVectors are the full-text search columns, generated by the database from the columns they index. The
+// 'simple' configuration is used as references and codes aren't words to stem.
+var searchVectors = []struct {
+	table      string
+	expression string
+}{
+	{"accounts", "setweight(to_tsvector('simple', coalesce(name, '')), 'A') || " +
+		"setweight(to_tsvector('simple', coalesce(description, '')), 'B')"},
+	{"journal_entries", "to_tsvector('simple', coalesce(reference, ''))"},
+	{"journal_entry_lines", "to_tsvector('simple', coalesce(notes, ''))"},
+}
+
+// FullTextSearch adds a GIN indexed search_vector column to the accounts, journal entries and lines.
+func FullTextSearch() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "202610190002_full_text_search",
+		Migrate: func(db *gorm.DB) error {
+			for _, vector := range searchVectors {
+				err := db.Exec(
+					"ALTER TABLE " + vector.table + " ADD COLUMN IF NOT EXISTS search_vector tsvector " +
+						"GENERATED ALWAYS AS (" + vector.expression + ") STORED",
+				).Error
+				if err != nil {
+					return err
+				}
+
+				err = db.Exec(
+					"CREATE INDEX IF NOT EXISTS idx_" + vector.table + "_search_vector ON " + vector.table +
+						" USING GIN (search_vector)",
+				).Error
+				if err != nil {
+					return err
+				}
+			}
+
+			return nil
+		},
+		Rollback: func(db *gorm.DB) error {
+			for _, vector := range searchVectors {
+				err := db.Exec("ALTER TABLE " + vector.table + " DROP COLUMN IF EXISTS search_vector").Error
+				if err != nil {
+					return err
+				}
+			}
+
+			return nil
+		},
+	}
+}
@@ -14,9 +14,26 @@ import (
 func updateMigration(db *gorm.DB) error {
 	err := db.AutoMigrate(
 		&models.Client{},
+		&models.Ledger{},
 		&models.Account{},
 		&models.JournalEntry{},
 		&models.JournalEntryLine{},
+		&models.DimensionType{},
+		&models.DimensionValue{},
+		&models.Budget{},
+		&models.BudgetLine{},
+		&models.ApprovalRule{},
+		&models.JournalEntryTransition{},
+		&models.Attachment{},
+		&models.AuditEvent{},
+		&models.IdempotencyKey{},
+		&models.WebhookEndpoint{},
+		&models.WebhookEvent{},
+		&models.WebhookDelivery{},
+		&models.Transfer{},
+		&models.Hold{},
+		&models.ApiKey{},
+		&models.RequestNonce{},
 	)
 	return err
 }
@@ -43,6 +60,15 @@ func ServiceAutoMigration(db *gorm.DB) error {
 
 	m = gormigrate.New(db, gormigrate.DefaultOptions, []*gormigrate.Migration{
 		jobs.SeedExample(),
+		jobs.AuditEventsAppendOnly(),
+		jobs.FullTextSearch(),
+		jobs.ApiKeysFromClientSecrets(),
+		jobs.ApiKeyScopes(),
+		jobs.Ledgers(),
+		jobs.ApiKeyCredentials(),
+		jobs.LedgerResources(),
+		jobs.JournalEntryHashVersions(),
+		jobs.HoldTransferCurrencies(),
 	})
 	m.Migrate()
 
@@ -18,11 +18,33 @@ type ISentry struct {
 	Environment string
 }
 
+type IStorage struct {
+	Driver    string // local, s3
+	LocalPath string
+
+	// S3 compatible storage, Endpoint can point at any compatible service (eg. MinIO).
+	S3Endpoint        string
+	S3Region          string
+	S3Bucket          string
+	S3AccessKeyID     string
+	S3SecretAccessKey string
+}
+
+type IAuth struct {
+	// TokenSigningKey signs the access tokens issued at /oauth/token, the same on every instance.
+	TokenSigningKey string
+
+	// EncryptionKey encrypts the api keys' request signing keys at rest.
+	EncryptionKey string
+}
+
 type Config struct {
 	Port     string
 	Database IDatabase
 	Env      string // development, staging, production
 	Sentry   ISentry
+	Storage  IStorage
+	Auth     IAuth
 }
 
 // Load loads config from environment variables
@@ -42,6 +64,19 @@ func Load() Config {
 			DSN:         getEnv("SENTRY_DSN", ""),
 			Environment: getEnv("SENTRY_ENVIRONMENT", "development"),
 		},
+		Storage: IStorage{
+			Driver:            getEnv("STORAGE_DRIVER", "local"),
+			LocalPath:         getEnv("STORAGE_LOCAL_PATH", "./storage"),
+			S3Endpoint:        getEnv("S3_ENDPOINT", ""),
+			S3Region:          getEnv("S3_REGION", "us-east-1"),
+			S3Bucket:          getEnv("S3_BUCKET", ""),
+			S3AccessKeyID:     getEnv("S3_ACCESS_KEY_ID", ""),
+			S3SecretAccessKey: getEnv("S3_SECRET_ACCESS_KEY", ""),
+		},
+		Auth: IAuth{
+			TokenSigningKey: getEnv("AUTH_TOKEN_SIGNING_KEY", ""),
+			EncryptionKey:   getEnv("AUTH_ENCRYPTION_KEY", ""),
+		},
 	}
 }
 
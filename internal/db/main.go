@@ -12,8 +12,9 @@ import (
 	"gorm.io/gorm/logger"
 )
 
-func Connect(cfg config.Config) (*gorm.DB, error) {
-	dsn := fmt.Sprintf(
+// DSN is the connection string of the configured database.
+func DSN(cfg config.Config) string {
+	return fmt.Sprintf(
 		"host=%s user=%s password=%s dbname=%s port=%s sslmode=%s TimeZone=UTC",
 		cfg.Database.Host,
 		cfg.Database.User,
@@ -22,8 +23,10 @@ func Connect(cfg config.Config) (*gorm.DB, error) {
 		cfg.Database.Port,
 		cfg.Database.SSLMode,
 	)
+}
 
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
+func Connect(cfg config.Config) (*gorm.DB, error) {
+	db, err := gorm.Open(postgres.Open(DSN(cfg)), &gorm.Config{
 		Logger: logger.Default.LogMode(logger.Info),
 	})
 	if err != nil {
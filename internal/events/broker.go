@@ -0,0 +1,141 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/Bendomey/fincore-engine/internal/repository"
+	"github.com/getsentry/raven-go"
+	"github.com/jackc/pgx/v5"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	minReconnectWait = time.Second
+	maxReconnectWait = 30 * time.Second
+)
+
+// Broker wakes up the event streams open on this instance when their client gets a new event. Events are
+// announced through postgres LISTEN/NOTIFY, so every instance hears about them whichever instance wrote them.
+// Subscribers are only told that something happened, they read the events themselves from their cursor, so a
+// missed wake-up costs latency rather than events.
+type Broker struct {
+	dsn string
+
+	mu          sync.Mutex
+	subscribers map[string]map[chan struct{}]struct{}
+}
+
+func NewBroker(dsn string) *Broker {
+	return &Broker{
+		dsn:         dsn,
+		subscribers: make(map[string]map[chan struct{}]struct{}),
+	}
+}
+
+// Subscribe returns a channel that receives whenever the client has new events. Call unsubscribe once done.
+func (b *Broker) Subscribe(clientID string) (<-chan struct{}, func()) {
+	// a buffer of one coalesces wake-ups that arrive while the subscriber is busy.
+	wake := make(chan struct{}, 1)
+
+	b.mu.Lock()
+	if b.subscribers[clientID] == nil {
+		b.subscribers[clientID] = make(map[chan struct{}]struct{})
+	}
+	b.subscribers[clientID][wake] = struct{}{}
+	b.mu.Unlock()
+
+	unsubscribe := func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+
+		delete(b.subscribers[clientID], wake)
+		if len(b.subscribers[clientID]) == 0 {
+			delete(b.subscribers, clientID)
+		}
+	}
+
+	return wake, unsubscribe
+}
+
+// Run listens for notifications until ctx is done, reconnecting when the connection drops. Subscribers are
+// woken after a reconnect since notifications sent in between are lost.
+func (b *Broker) Run(ctx context.Context) {
+	wait := minReconnectWait
+
+	for {
+		err := b.listen(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+
+		log.Errorf("event broker: %v, reconnecting in %s", err, wait)
+		raven.CaptureError(err, nil)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+
+		wait *= 2
+		if wait > maxReconnectWait {
+			wait = maxReconnectWait
+		}
+	}
+}
+
+func (b *Broker) listen(ctx context.Context) error {
+	conn, err := pgx.Connect(ctx, b.dsn)
+	if err != nil {
+		return err
+	}
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+repository.WebhookEventsChannel); err != nil {
+		return err
+	}
+
+	b.wakeAll()
+
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+
+		var payload repository.WebhookEventNotification
+		if err := json.Unmarshal([]byte(notification.Payload), &payload); err != nil {
+			continue
+		}
+
+		b.wake(payload.ClientID)
+	}
+}
+
+func (b *Broker) wake(clientID string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for wake := range b.subscribers[clientID] {
+		select {
+		case wake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (b *Broker) wakeAll() {
+	b.mu.Lock()
+	clientIDs := make([]string, 0, len(b.subscribers))
+	for clientID := range b.subscribers {
+		clientIDs = append(clientIDs, clientID)
+	}
+	b.mu.Unlock()
+
+	for _, clientID := range clientIDs {
+		b.wake(clientID)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"net/http"
 
 	"github.com/Bendomey/fincore-engine/internal/lib"
+	"github.com/Bendomey/fincore-engine/internal/models"
 	"github.com/Bendomey/fincore-engine/internal/repository"
 	"github.com/Bendomey/fincore-engine/internal/services"
 	"github.com/Bendomey/fincore-engine/internal/transformations"
@@ -28,6 +29,9 @@ type CreateAccountRequest struct {
 	IsGroup         bool    `json:"is_group"          validate:"boolean"`
 	ParentAccountID *string `json:"parent_account_id" validate:"omitempty,uuid4"`
 	Description     *string `json:"description"       validate:"omitempty,max=1024"`
+
+	DisallowNegativeBalance bool  `json:"disallow_negative_balance" validate:"boolean"`
+	OverdraftLimit          int64 `json:"overdraft_limit"           validate:"min=0"`
 }
 
 func (h *AccountHandler) CreateAccount(w http.ResponseWriter, r *http.Request) {
@@ -50,6 +54,13 @@ func (h *AccountHandler) CreateAccount(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	ledger, ledgerOk := lib.LedgerFromContext(r.Context())
+
+	if !ledgerOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
 	account, err := h.service.CreateAccount(r.Context(), services.CreateAccountInput{
 		Name:            body.Name,
 		AccountType:     body.Type,
@@ -58,6 +69,10 @@ func (h *AccountHandler) CreateAccount(w http.ResponseWriter, r *http.Request) {
 		ParentAccountID: body.ParentAccountID,
 		Description:     body.Description,
 		ClientID:        client.ID.String(),
+		LedgerID:        ledger.ID.String(),
+
+		DisallowNegativeBalance: body.DisallowNegativeBalance,
+		OverdraftLimit:          body.OverdraftLimit,
 	})
 	if err != nil {
 		w.WriteHeader(http.StatusBadRequest)
@@ -78,6 +93,9 @@ func (h *AccountHandler) CreateAccount(w http.ResponseWriter, r *http.Request) {
 type UpdateAccountRequest struct {
 	Name        *string `json:"name"        validate:"omitempty,min=3,max=255"`
 	Description *string `json:"description" validate:"omitempty,max=1024"`
+
+	DisallowNegativeBalance *bool  `json:"disallow_negative_balance" validate:"omitempty,boolean"`
+	OverdraftLimit          *int64 `json:"overdraft_limit"           validate:"omitempty,min=0"`
 }
 
 func (h *AccountHandler) UpdateAccount(w http.ResponseWriter, r *http.Request) {
@@ -93,20 +111,41 @@ func (h *AccountHandler) UpdateAccount(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	client, clientOk := lib.ClientFromContext(r.Context())
+
+	if !clientOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	ledger, ledgerOk := lib.LedgerFromContext(r.Context())
+
+	if !ledgerOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	version, versionOk := getIfMatchVersion(w, r)
+	if !versionOk {
+		return
+	}
+
 	account, err := h.service.UpdateAccount(r.Context(), chi.URLParam(r, "account_id"), services.UpdateAccountInput{
+		ClientID:    client.ID.String(),
+		LedgerID:    ledger.ID.String(),
+		Version:     version,
 		Name:        body.Name,
 		Description: body.Description,
+
+		DisallowNegativeBalance: body.DisallowNegativeBalance,
+		OverdraftLimit:          body.OverdraftLimit,
 	})
 	if err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]any{
-			"errors": map[string]string{
-				"message": err.Error(),
-			},
-		})
+		writeUpdateError(w, err)
 		return
 	}
 
+	w.Header().Set("ETag", lib.ETag(account.Version))
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(map[string]any{
 		"data": transformations.DBAccountToRestAccount(account, nil),
@@ -121,8 +160,16 @@ func (h *AccountHandler) DeleteAccount(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	ledger, ledgerOk := lib.LedgerFromContext(r.Context())
+
+	if !ledgerOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
 	err := h.service.DeleteAccount(r.Context(), services.DeleteAccountInput{
 		ClientID: client.ID.String(),
+		LedgerID: ledger.ID.String(),
 		ID:       chi.URLParam(r, "account_id"),
 	})
 	if err != nil {
@@ -153,6 +200,13 @@ func (h *AccountHandler) GetAccount(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	ledger, ledgerOk := lib.LedgerFromContext(r.Context())
+
+	if !ledgerOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
 	input := GetAccountRequest{
 		ClientID: client.ID.String(),
 		ID:       chi.URLParam(r, "account_id"),
@@ -167,6 +221,7 @@ func (h *AccountHandler) GetAccount(w http.ResponseWriter, r *http.Request) {
 
 	account, err := h.service.GetAccount(r.Context(), services.GetAccountInput{
 		ClientID: input.ClientID,
+		LedgerID: ledger.ID.String(),
 		ID:       input.ID,
 		Populate: input.Populate,
 	})
@@ -180,6 +235,7 @@ func (h *AccountHandler) GetAccount(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	w.Header().Set("ETag", lib.ETag(account.Version))
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(map[string]any{
 		"data": transformations.DBAccountToRestAccount(account, input.Populate),
@@ -202,6 +258,13 @@ func (h *AccountHandler) ListAccounts(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	ledger, ledgerOk := lib.LedgerFromContext(r.Context())
+
+	if !ledgerOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
 	filters := ListAccountsFilterRequest{
 		ClientID:        client.ID.String(),
 		ParentAccountID: lib.NullOrString(r.URL.Query().Get("parent_account_id")),
@@ -215,7 +278,7 @@ func (h *AccountHandler) ListAccounts(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	filterQuery, filterErr := lib.GenerateQuery(r.URL.Query())
+	filterQuery, filterErr := lib.GenerateQuery(r.URL.Query(), repository.AccountQueryFields)
 	if filterErr != nil {
 		w.WriteHeader(http.StatusBadRequest)
 		json.NewEncoder(w).Encode(map[string]any{
@@ -233,6 +296,7 @@ func (h *AccountHandler) ListAccounts(w http.ResponseWriter, r *http.Request) {
 
 	accounts, accountsErr := h.service.ListAccounts(r.Context(), *filterQuery, repository.ListAccountsFilter{
 		ClientId:        filters.ClientID,
+		LedgerId:        ledger.ID.String(),
 		ParentAccountId: filters.ParentAccountID,
 		AccountType:     filters.AccountType,
 		IsContra:        lib.ConvertStringPointerToBoolPointer(filters.IsContra),
@@ -249,22 +313,32 @@ func (h *AccountHandler) ListAccounts(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	count, countsErr := h.service.CountAccounts(r.Context(), *filterQuery, repository.ListAccountsFilter{
-		ClientId:        filters.ClientID,
-		ParentAccountId: filters.ParentAccountID,
-		AccountType:     filters.AccountType,
-		IsContra:        lib.ConvertStringPointerToBoolPointer(filters.IsContra),
-		IsGroup:         lib.ConvertStringPointerToBoolPointer(filters.IsGroup),
+	accounts, nextCursor := cursorPage(*filterQuery, accounts, func(account models.Account) lib.Cursor {
+		return lib.Cursor{CreatedAt: account.CreatedAt, ID: account.ID.String()}
 	})
 
-	if countsErr != nil {
-		w.WriteHeader(http.StatusNotFound)
-		json.NewEncoder(w).Encode(map[string]any{
-			"errors": map[string]string{
-				"message": countsErr.Error(),
-			},
+	var total *int64
+	if shouldCount(*filterQuery) {
+		count, countsErr := h.service.CountAccounts(r.Context(), *filterQuery, repository.ListAccountsFilter{
+			ClientId:        filters.ClientID,
+			LedgerId:        ledger.ID.String(),
+			ParentAccountId: filters.ParentAccountID,
+			AccountType:     filters.AccountType,
+			IsContra:        lib.ConvertStringPointerToBoolPointer(filters.IsContra),
+			IsGroup:         lib.ConvertStringPointerToBoolPointer(filters.IsGroup),
 		})
-		return
+
+		if countsErr != nil {
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(map[string]any{
+				"errors": map[string]string{
+					"message": countsErr.Error(),
+				},
+			})
+			return
+		}
+
+		total = &count
 	}
 
 	accountsTransformed := make([]interface{}, 0)
@@ -278,14 +352,6 @@ func (h *AccountHandler) ListAccounts(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(map[string]any{
 		"data": accountsTransformed,
-		"meta": map[string]any{
-			"page":              filterQuery.Page,
-			"page_size":         filterQuery.PageSize,
-			"order":             filterQuery.Order,
-			"order_by":          filterQuery.OrderBy,
-			"total":             count,
-			"has_next_page":     (filterQuery.Page * filterQuery.PageSize) < int(count),
-			"has_previous_page": filterQuery.Page > 1,
-		},
+		"meta": listMeta(*filterQuery, total, nextCursor),
 	})
 }
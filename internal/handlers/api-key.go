@@ -0,0 +1,287 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/Bendomey/fincore-engine/internal/lib"
+	"github.com/Bendomey/fincore-engine/internal/repository"
+	"github.com/Bendomey/fincore-engine/internal/services"
+	"github.com/Bendomey/fincore-engine/internal/transformations"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+)
+
+type ApiKeyHandler struct {
+	service  services.ApiKeyService
+	validate *validator.Validate
+}
+
+func NewApiKeyHandler(service services.ApiKeyService, validate *validator.Validate) ApiKeyHandler {
+	return ApiKeyHandler{service, validate}
+}
+
+type CreateApiKeyRequest struct {
+	Label     string     `json:"label"      validate:"required,min=1,max=255"`
+	Scopes    *[]string  `json:"scopes"     validate:"omitempty,min=1,dive,oneof=accounts:read accounts:write journal_entries:read journal_entries:write journal_entries:post reports:read dimensions:read dimensions:write budgets:read budgets:write approval_rules:read approval_rules:write transfers:read transfers:write holds:read holds:write webhooks:read webhooks:write events:read audit_events:read api_keys:read api_keys:write ledgers:read ledgers:write"`
+	ExpiresAt *time.Time `json:"expires_at" validate:"omitempty"`
+}
+
+func (h *ApiKeyHandler) CreateApiKey(w http.ResponseWriter, r *http.Request) {
+	var body CreateApiKeyRequest
+	if decodeErr := json.NewDecoder(r.Body).Decode(&body); decodeErr != nil {
+		http.Error(w, "Invalid JSON body", http.StatusBadRequest)
+		return
+	}
+
+	isPassedValidation := lib.ValidateRequest(h.validate, body, w)
+
+	if !isPassedValidation {
+		return
+	}
+
+	client, clientOk := lib.ClientFromContext(r.Context())
+
+	if !clientOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	// a key gets the scopes of the credential making it unless fewer are asked for, it is never granted more.
+	var scopes []string
+	if body.Scopes != nil {
+		scopes = *body.Scopes
+	}
+
+	response, err := h.service.CreateApiKey(r.Context(), services.CreateApiKeyInput{
+		ClientID:  client.ID.String(),
+		Label:     body.Label,
+		Scopes:    scopes,
+		ExpiresAt: body.ExpiresAt,
+	})
+	if err != nil {
+		writeApiKeyError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]any{
+		"data": transformations.DBApiKeyToRestApiKey(&response.ApiKey, &response.Secret),
+	})
+}
+
+type RotateApiKeyRequest struct {
+	// OverlapSeconds is how long the rotated key keeps working, up to 30 days.
+	OverlapSeconds *int64 `json:"overlap_seconds" validate:"omitempty,min=0,max=2592000"`
+}
+
+func (h *ApiKeyHandler) RotateApiKey(w http.ResponseWriter, r *http.Request) {
+	var body RotateApiKeyRequest
+	// the body is optional, the default overlap applies without one.
+	if r.ContentLength > 0 {
+		if decodeErr := json.NewDecoder(r.Body).Decode(&body); decodeErr != nil {
+			http.Error(w, "Invalid JSON body", http.StatusBadRequest)
+			return
+		}
+	}
+
+	isPassedValidation := lib.ValidateRequest(h.validate, body, w)
+
+	if !isPassedValidation {
+		return
+	}
+
+	client, clientOk := lib.ClientFromContext(r.Context())
+
+	if !clientOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	input := services.RotateApiKeyInput{
+		ClientID: client.ID.String(),
+		ID:       chi.URLParam(r, "api_key_id"),
+	}
+
+	if body.OverlapSeconds != nil {
+		overlap := time.Duration(*body.OverlapSeconds) * time.Second
+		input.Overlap = &overlap
+	}
+
+	response, err := h.service.RotateApiKey(r.Context(), input)
+	if err != nil {
+		writeApiKeyError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]any{
+		"data": transformations.DBApiKeyToRestApiKey(&response.ApiKey, &response.Secret),
+	})
+}
+
+func (h *ApiKeyHandler) RevokeApiKey(w http.ResponseWriter, r *http.Request) {
+	client, clientOk := lib.ClientFromContext(r.Context())
+
+	if !clientOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	apiKey, err := h.service.RevokeApiKey(r.Context(), services.GetApiKeyInput{
+		ClientID: client.ID.String(),
+		ID:       chi.URLParam(r, "api_key_id"),
+	})
+	if err != nil {
+		writeApiKeyError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]any{
+		"data": transformations.DBApiKeyToRestApiKey(apiKey, nil),
+	})
+}
+
+// writeApiKeyError answers a failed creation, rotation or revocation, 403 when the key is broader than the
+// credential used.
+func writeApiKeyError(w http.ResponseWriter, err error) {
+	if errors.Is(err, services.ErrApiKeyScopesExceeded) {
+		writeError(w, http.StatusForbidden, err.Error())
+		return
+	}
+
+	writeError(w, http.StatusBadRequest, err.Error())
+}
+
+type GetApiKeyRequest struct {
+	ClientID string `json:"client_id" validate:"required,uuid4"`
+	ID       string `json:"id"        validate:"required,uuid4"`
+}
+
+func (h *ApiKeyHandler) GetApiKey(w http.ResponseWriter, r *http.Request) {
+	client, clientOk := lib.ClientFromContext(r.Context())
+
+	if !clientOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	input := GetApiKeyRequest{
+		ClientID: client.ID.String(),
+		ID:       chi.URLParam(r, "api_key_id"),
+	}
+
+	isPassedValidation := lib.ValidateRequest(h.validate, input, w)
+
+	if !isPassedValidation {
+		return
+	}
+
+	apiKey, err := h.service.GetApiKey(r.Context(), services.GetApiKeyInput{
+		ClientID: input.ClientID,
+		ID:       input.ID,
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]any{
+			"errors": map[string]string{
+				"message": err.Error(),
+			},
+		})
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]any{
+		"data": transformations.DBApiKeyToRestApiKey(apiKey, nil),
+	})
+}
+
+type ListApiKeysFilterRequest struct {
+	ClientID string  `json:"client_id" validate:"required,uuid4"`
+	IsActive *string `json:"is_active" validate:"omitempty,boolean"`
+}
+
+func (h *ApiKeyHandler) ListApiKeys(w http.ResponseWriter, r *http.Request) {
+	client, clientOk := lib.ClientFromContext(r.Context())
+
+	if !clientOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	filters := ListApiKeysFilterRequest{
+		ClientID: client.ID.String(),
+		IsActive: lib.NullOrString(r.URL.Query().Get("is_active")),
+	}
+
+	isFiltersPassedValidation := lib.ValidateRequest(h.validate, filters, w)
+	if !isFiltersPassedValidation {
+		return
+	}
+
+	filterQuery, filterErr := lib.GenerateQuery(r.URL.Query(), repository.ApiKeyQueryFields)
+	if filterErr != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]any{
+			"errors": map[string]string{
+				"message": filterErr.Error(),
+			},
+		})
+		return
+	}
+
+	isFilterQueryPassedValidation := lib.ValidateRequest(h.validate, filterQuery, w)
+	if !isFilterQueryPassedValidation {
+		return
+	}
+
+	listFilters := repository.ListApiKeysFilter{
+		ClientId: filters.ClientID,
+		IsActive: lib.ConvertStringPointerToBoolPointer(filters.IsActive),
+	}
+
+	apiKeys, apiKeysErr := h.service.ListApiKeys(r.Context(), *filterQuery, listFilters)
+	if apiKeysErr != nil {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]any{
+			"errors": map[string]string{
+				"message": apiKeysErr.Error(),
+			},
+		})
+		return
+	}
+
+	count, countsErr := h.service.CountApiKeys(r.Context(), *filterQuery, listFilters)
+	if countsErr != nil {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]any{
+			"errors": map[string]string{
+				"message": countsErr.Error(),
+			},
+		})
+		return
+	}
+
+	apiKeysTransformed := make([]interface{}, 0)
+	for _, apiKey := range apiKeys {
+		apiKeysTransformed = append(apiKeysTransformed, transformations.DBApiKeyToRestApiKey(&apiKey, nil))
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]any{
+		"data": apiKeysTransformed,
+		"meta": map[string]any{
+			"page":              filterQuery.Page,
+			"page_size":         filterQuery.PageSize,
+			"order":             filterQuery.Order,
+			"order_by":          filterQuery.OrderBy,
+			"total":             count,
+			"has_next_page":     (filterQuery.Page * filterQuery.PageSize) < int(count),
+			"has_previous_page": filterQuery.Page > 1,
+		},
+	})
+}
@@ -0,0 +1,323 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/Bendomey/fincore-engine/internal/lib"
+	"github.com/Bendomey/fincore-engine/internal/repository"
+	"github.com/Bendomey/fincore-engine/internal/services"
+	"github.com/Bendomey/fincore-engine/internal/transformations"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+)
+
+type ApprovalRuleHandler struct {
+	service  services.ApprovalRuleService
+	validate *validator.Validate
+}
+
+func NewApprovalRuleHandler(service services.ApprovalRuleService, validate *validator.Validate) ApprovalRuleHandler {
+	return ApprovalRuleHandler{service, validate}
+}
+
+type CreateApprovalRuleRequest struct {
+	Name      string  `json:"name"       validate:"required,min=3,max=255"`
+	MinAmount *int64  `json:"min_amount" validate:"omitempty,number,min=0"`
+	AccountID *string `json:"account_id" validate:"omitempty,uuid4"`
+}
+
+func (h *ApprovalRuleHandler) CreateApprovalRule(w http.ResponseWriter, r *http.Request) {
+	var body CreateApprovalRuleRequest
+	if decodeErr := json.NewDecoder(r.Body).Decode(&body); decodeErr != nil {
+		http.Error(w, "Invalid JSON body", http.StatusBadRequest)
+		return
+	}
+
+	isPassedValidation := lib.ValidateRequest(h.validate, body, w)
+
+	if !isPassedValidation {
+		return
+	}
+
+	client, clientOk := lib.ClientFromContext(r.Context())
+
+	if !clientOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	ledger, ledgerOk := lib.LedgerFromContext(r.Context())
+
+	if !ledgerOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	approvalRule, err := h.service.CreateApprovalRule(r.Context(), services.CreateApprovalRuleInput{
+		ClientID:  client.ID.String(),
+		LedgerID:  ledger.ID.String(),
+		Name:      body.Name,
+		MinAmount: body.MinAmount,
+		AccountID: body.AccountID,
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]any{
+			"errors": map[string]string{
+				"message": err.Error(),
+			},
+		})
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]any{
+		"data": transformations.DBApprovalRuleToRestApprovalRule(approvalRule, nil),
+	})
+}
+
+type UpdateApprovalRuleRequest struct {
+	Name      *string `json:"name"       validate:"omitempty,min=3,max=255"`
+	MinAmount *int64  `json:"min_amount" validate:"omitempty,number,min=0"`
+	AccountID *string `json:"account_id" validate:"omitempty,uuid4"`
+	IsActive  *bool   `json:"is_active"  validate:"omitempty,boolean"`
+}
+
+func (h *ApprovalRuleHandler) UpdateApprovalRule(w http.ResponseWriter, r *http.Request) {
+	var body UpdateApprovalRuleRequest
+	if decodeErr := json.NewDecoder(r.Body).Decode(&body); decodeErr != nil {
+		http.Error(w, "Invalid JSON body", http.StatusBadRequest)
+		return
+	}
+
+	isPassedValidation := lib.ValidateRequest(h.validate, body, w)
+
+	if !isPassedValidation {
+		return
+	}
+
+	client, clientOk := lib.ClientFromContext(r.Context())
+
+	if !clientOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	ledger, ledgerOk := lib.LedgerFromContext(r.Context())
+
+	if !ledgerOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	approvalRule, err := h.service.UpdateApprovalRule(r.Context(), services.UpdateApprovalRuleInput{
+		ClientID:  client.ID.String(),
+		LedgerID:  ledger.ID.String(),
+		ID:        chi.URLParam(r, "approval_rule_id"),
+		Name:      body.Name,
+		MinAmount: body.MinAmount,
+		AccountID: body.AccountID,
+		IsActive:  body.IsActive,
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]any{
+			"errors": map[string]string{
+				"message": err.Error(),
+			},
+		})
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]any{
+		"data": transformations.DBApprovalRuleToRestApprovalRule(approvalRule, nil),
+	})
+}
+
+func (h *ApprovalRuleHandler) DeleteApprovalRule(w http.ResponseWriter, r *http.Request) {
+	client, clientOk := lib.ClientFromContext(r.Context())
+
+	if !clientOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	ledger, ledgerOk := lib.LedgerFromContext(r.Context())
+
+	if !ledgerOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	err := h.service.DeleteApprovalRule(r.Context(), services.GetApprovalRuleInput{
+		ClientID: client.ID.String(),
+		LedgerID: ledger.ID.String(),
+		ID:       chi.URLParam(r, "approval_rule_id"),
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]any{
+			"errors": map[string]string{
+				"message": err.Error(),
+			},
+		})
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	json.NewEncoder(w).Encode(map[string]any{})
+}
+
+type GetApprovalRuleRequest struct {
+	ClientID string    `json:"client_id" validate:"required,uuid4"`
+	ID       string    `json:"id"        validate:"required,uuid4"`
+	Populate *[]string `json:"populate"  validate:"omitempty,dive,oneof=Account"`
+}
+
+func (h *ApprovalRuleHandler) GetApprovalRule(w http.ResponseWriter, r *http.Request) {
+	client, clientOk := lib.ClientFromContext(r.Context())
+
+	if !clientOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	ledger, ledgerOk := lib.LedgerFromContext(r.Context())
+
+	if !ledgerOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	input := GetApprovalRuleRequest{
+		ClientID: client.ID.String(),
+		ID:       chi.URLParam(r, "approval_rule_id"),
+		Populate: getPopulateFields(r),
+	}
+
+	isPassedValidation := lib.ValidateRequest(h.validate, input, w)
+
+	if !isPassedValidation {
+		return
+	}
+
+	approvalRule, err := h.service.GetApprovalRule(r.Context(), services.GetApprovalRuleInput{
+		ClientID: input.ClientID,
+		LedgerID: ledger.ID.String(),
+		ID:       input.ID,
+		Populate: input.Populate,
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]any{
+			"errors": map[string]string{
+				"message": err.Error(),
+			},
+		})
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]any{
+		"data": transformations.DBApprovalRuleToRestApprovalRule(approvalRule, input.Populate),
+	})
+}
+
+type ListApprovalRulesFilterRequest struct {
+	ClientID string  `json:"client_id" validate:"required,uuid4"`
+	IsActive *string `json:"is_active" validate:"omitempty,boolean"`
+}
+
+func (h *ApprovalRuleHandler) ListApprovalRules(w http.ResponseWriter, r *http.Request) {
+	client, clientOk := lib.ClientFromContext(r.Context())
+
+	if !clientOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	ledger, ledgerOk := lib.LedgerFromContext(r.Context())
+
+	if !ledgerOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	filters := ListApprovalRulesFilterRequest{
+		ClientID: client.ID.String(),
+		IsActive: lib.NullOrString(r.URL.Query().Get("is_active")),
+	}
+
+	isFiltersPassedValidation := lib.ValidateRequest(h.validate, filters, w)
+	if !isFiltersPassedValidation {
+		return
+	}
+
+	filterQuery, filterErr := lib.GenerateQuery(r.URL.Query(), repository.ApprovalRuleQueryFields)
+	if filterErr != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]any{
+			"errors": map[string]string{
+				"message": filterErr.Error(),
+			},
+		})
+		return
+	}
+
+	isFilterQueryPassedValidation := lib.ValidateRequest(h.validate, filterQuery, w)
+	if !isFilterQueryPassedValidation {
+		return
+	}
+
+	listFilters := repository.ListApprovalRulesFilter{
+		ClientId: filters.ClientID,
+		LedgerId: ledger.ID.String(),
+		IsActive: lib.ConvertStringPointerToBoolPointer(filters.IsActive),
+	}
+
+	approvalRules, approvalRulesErr := h.service.ListApprovalRules(r.Context(), *filterQuery, listFilters)
+	if approvalRulesErr != nil {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]any{
+			"errors": map[string]string{
+				"message": approvalRulesErr.Error(),
+			},
+		})
+		return
+	}
+
+	count, countsErr := h.service.CountApprovalRules(r.Context(), *filterQuery, listFilters)
+	if countsErr != nil {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]any{
+			"errors": map[string]string{
+				"message": countsErr.Error(),
+			},
+		})
+		return
+	}
+
+	approvalRulesTransformed := make([]interface{}, 0)
+	for _, approvalRule := range approvalRules {
+		approvalRulesTransformed = append(
+			approvalRulesTransformed,
+			transformations.DBApprovalRuleToRestApprovalRule(&approvalRule, filterQuery.Populate),
+		)
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]any{
+		"data": approvalRulesTransformed,
+		"meta": map[string]any{
+			"page":              filterQuery.Page,
+			"page_size":         filterQuery.PageSize,
+			"order":             filterQuery.Order,
+			"order_by":          filterQuery.OrderBy,
+			"total":             count,
+			"has_next_page":     (filterQuery.Page * filterQuery.PageSize) < int(count),
+			"has_previous_page": filterQuery.Page > 1,
+		},
+	})
+}
@@ -0,0 +1,321 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+
+	"github.com/Bendomey/fincore-engine/internal/lib"
+	"github.com/Bendomey/fincore-engine/internal/repository"
+	"github.com/Bendomey/fincore-engine/internal/services"
+	"github.com/Bendomey/fincore-engine/internal/transformations"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+)
+
+// maxAttachmentSize caps uploaded attachments at 10MB.
+const maxAttachmentSize = 10 << 20
+
+type AttachmentHandler struct {
+	service  services.AttachmentService
+	validate *validator.Validate
+}
+
+func NewAttachmentHandler(service services.AttachmentService, validate *validator.Validate) AttachmentHandler {
+	return AttachmentHandler{service, validate}
+}
+
+// UploadAttachment accepts a multipart/form-data body with the document in the "file" field.
+func (h *AttachmentHandler) UploadAttachment(w http.ResponseWriter, r *http.Request) {
+	client, clientOk := lib.ClientFromContext(r.Context())
+
+	if !clientOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	ledger, ledgerOk := lib.LedgerFromContext(r.Context())
+
+	if !ledgerOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxAttachmentSize+(1<<20))
+	file, header, fileErr := r.FormFile("file")
+	if fileErr != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]any{
+			"errors": map[string]string{
+				"message": "a file (max 10MB) is required in the file field",
+			},
+		})
+		return
+	}
+	defer file.Close()
+
+	if header.Size > maxAttachmentSize {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]any{
+			"errors": map[string]string{
+				"message": "file is larger than 10MB",
+			},
+		})
+		return
+	}
+
+	contentType := header.Header.Get("Content-Type")
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	attachment, err := h.service.UploadAttachment(r.Context(), services.UploadAttachmentInput{
+		ClientID:       client.ID.String(),
+		LedgerID:       ledger.ID.String(),
+		JournalEntryID: chi.URLParam(r, "journal_entry_id"),
+		FileName:       filepath.Base(header.Filename),
+		ContentType:    contentType,
+		Content:        file,
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]any{
+			"errors": map[string]string{
+				"message": err.Error(),
+			},
+		})
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]any{
+		"data": transformations.DBAttachmentToRestAttachment(attachment),
+	})
+}
+
+func (h *AttachmentHandler) DeleteAttachment(w http.ResponseWriter, r *http.Request) {
+	client, clientOk := lib.ClientFromContext(r.Context())
+
+	if !clientOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	ledger, ledgerOk := lib.LedgerFromContext(r.Context())
+
+	if !ledgerOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	err := h.service.DeleteAttachment(r.Context(), services.GetAttachmentInput{
+		ClientID:       client.ID.String(),
+		LedgerID:       ledger.ID.String(),
+		JournalEntryID: chi.URLParam(r, "journal_entry_id"),
+		ID:             chi.URLParam(r, "attachment_id"),
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]any{
+			"errors": map[string]string{
+				"message": err.Error(),
+			},
+		})
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	json.NewEncoder(w).Encode(map[string]any{})
+}
+
+type GetAttachmentRequest struct {
+	ClientID       string `json:"client_id"        validate:"required,uuid4"`
+	JournalEntryID string `json:"journal_entry_id" validate:"required,uuid4"`
+	ID             string `json:"id"               validate:"required,uuid4"`
+}
+
+func (h *AttachmentHandler) GetAttachment(w http.ResponseWriter, r *http.Request) {
+	client, clientOk := lib.ClientFromContext(r.Context())
+
+	if !clientOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	ledger, ledgerOk := lib.LedgerFromContext(r.Context())
+
+	if !ledgerOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	input := GetAttachmentRequest{
+		ClientID:       client.ID.String(),
+		JournalEntryID: chi.URLParam(r, "journal_entry_id"),
+		ID:             chi.URLParam(r, "attachment_id"),
+	}
+
+	isPassedValidation := lib.ValidateRequest(h.validate, input, w)
+
+	if !isPassedValidation {
+		return
+	}
+
+	attachment, err := h.service.GetAttachment(r.Context(), services.GetAttachmentInput{
+		ClientID:       input.ClientID,
+		LedgerID:       ledger.ID.String(),
+		JournalEntryID: input.JournalEntryID,
+		ID:             input.ID,
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]any{
+			"errors": map[string]string{
+				"message": err.Error(),
+			},
+		})
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]any{
+		"data": transformations.DBAttachmentToRestAttachment(attachment),
+	})
+}
+
+// DownloadAttachment streams the stored document back with its original content type.
+func (h *AttachmentHandler) DownloadAttachment(w http.ResponseWriter, r *http.Request) {
+	client, clientOk := lib.ClientFromContext(r.Context())
+
+	if !clientOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	ledger, ledgerOk := lib.LedgerFromContext(r.Context())
+
+	if !ledgerOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	input := GetAttachmentRequest{
+		ClientID:       client.ID.String(),
+		JournalEntryID: chi.URLParam(r, "journal_entry_id"),
+		ID:             chi.URLParam(r, "attachment_id"),
+	}
+
+	isPassedValidation := lib.ValidateRequest(h.validate, input, w)
+
+	if !isPassedValidation {
+		return
+	}
+
+	attachment, content, err := h.service.DownloadAttachment(r.Context(), services.GetAttachmentInput{
+		ClientID:       input.ClientID,
+		LedgerID:       ledger.ID.String(),
+		JournalEntryID: input.JournalEntryID,
+		ID:             input.ID,
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]any{
+			"errors": map[string]string{
+				"message": err.Error(),
+			},
+		})
+		return
+	}
+	defer content.Close()
+
+	w.Header().Set("Content-Type", attachment.ContentType)
+	w.Header().Set("Content-Length", fmt.Sprint(attachment.Size))
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", attachment.FileName))
+	w.Header().Set("X-FinCore-Content-Sha256", attachment.ContentHash)
+	w.WriteHeader(http.StatusOK)
+	io.Copy(w, content)
+}
+
+func (h *AttachmentHandler) ListAttachments(w http.ResponseWriter, r *http.Request) {
+	client, clientOk := lib.ClientFromContext(r.Context())
+
+	if !clientOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	ledger, ledgerOk := lib.LedgerFromContext(r.Context())
+
+	if !ledgerOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	filterQuery, filterErr := lib.GenerateQuery(r.URL.Query(), repository.AttachmentQueryFields)
+	if filterErr != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]any{
+			"errors": map[string]string{
+				"message": filterErr.Error(),
+			},
+		})
+		return
+	}
+
+	isFilterQueryPassedValidation := lib.ValidateRequest(h.validate, filterQuery, w)
+	if !isFilterQueryPassedValidation {
+		return
+	}
+
+	filters := repository.ListAttachmentsFilter{
+		ClientId:       client.ID.String(),
+		LedgerId:       ledger.ID.String(),
+		JournalEntryId: chi.URLParam(r, "journal_entry_id"),
+	}
+
+	attachments, attachmentsErr := h.service.ListAttachments(r.Context(), *filterQuery, filters)
+	if attachmentsErr != nil {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]any{
+			"errors": map[string]string{
+				"message": attachmentsErr.Error(),
+			},
+		})
+		return
+	}
+
+	count, countsErr := h.service.CountAttachments(r.Context(), *filterQuery, filters)
+	if countsErr != nil {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]any{
+			"errors": map[string]string{
+				"message": countsErr.Error(),
+			},
+		})
+		return
+	}
+
+	attachmentsTransformed := make([]interface{}, 0)
+	for _, attachment := range attachments {
+		attachmentsTransformed = append(
+			attachmentsTransformed,
+			transformations.DBAttachmentToRestAttachment(&attachment),
+		)
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]any{
+		"data": attachmentsTransformed,
+		"meta": map[string]any{
+			"page":              filterQuery.Page,
+			"page_size":         filterQuery.PageSize,
+			"order":             filterQuery.Order,
+			"order_by":          filterQuery.OrderBy,
+			"total":             count,
+			"has_next_page":     (filterQuery.Page * filterQuery.PageSize) < int(count),
+			"has_previous_page": filterQuery.Page > 1,
+		},
+	})
+}
@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/Bendomey/fincore-engine/internal/lib"
+	"github.com/Bendomey/fincore-engine/internal/repository"
+	"github.com/Bendomey/fincore-engine/internal/services"
+	"github.com/Bendomey/fincore-engine/internal/transformations"
+	"github.com/go-playground/validator/v10"
+)
+
+type AuditEventHandler struct {
+	service  services.AuditEventService
+	validate *validator.Validate
+}
+
+func NewAuditEventHandler(service services.AuditEventService, validate *validator.Validate) AuditEventHandler {
+	return AuditEventHandler{service, validate}
+}
+
+type ListAuditEventsFilterRequest struct {
+	ClientID     string  `json:"client_id"     validate:"required,uuid4"`
+	LedgerID     *string `json:"ledger_id"     validate:"omitempty,uuid4"`
+	ActorID      *string `json:"actor_id"      validate:"omitempty,max=255"`
+	RequestID    *string `json:"request_id"    validate:"omitempty,max=255"`
+	Action       *string `json:"action"        validate:"omitempty,oneof=create update delete submit post approve reject void reverse capture rotate revoke"`
+	ResourceType *string `json:"resource_type" validate:"omitempty,oneof=client account journal_entry transfer hold api_key ledger"`
+	ResourceID   *string `json:"resource_id"   validate:"omitempty,uuid4"`
+}
+
+func (h *AuditEventHandler) ListAuditEvents(w http.ResponseWriter, r *http.Request) {
+	client, clientOk := lib.ClientFromContext(r.Context())
+
+	if !clientOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	filters := ListAuditEventsFilterRequest{
+		ClientID:     client.ID.String(),
+		LedgerID:     lib.NullOrString(r.URL.Query().Get("ledger_id")),
+		ActorID:      lib.NullOrString(r.URL.Query().Get("actor_id")),
+		RequestID:    lib.NullOrString(r.URL.Query().Get("request_id")),
+		Action:       lib.NullOrString(r.URL.Query().Get("action")),
+		ResourceType: lib.NullOrString(r.URL.Query().Get("resource_type")),
+		ResourceID:   lib.NullOrString(r.URL.Query().Get("resource_id")),
+	}
+
+	isFiltersPassedValidation := lib.ValidateRequest(h.validate, filters, w)
+	if !isFiltersPassedValidation {
+		return
+	}
+
+	filterQuery, filterErr := lib.GenerateQuery(r.URL.Query(), repository.AuditEventQueryFields)
+	if filterErr != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]any{
+			"errors": map[string]string{
+				"message": filterErr.Error(),
+			},
+		})
+		return
+	}
+
+	isFilterQueryPassedValidation := lib.ValidateRequest(h.validate, filterQuery, w)
+	if !isFilterQueryPassedValidation {
+		return
+	}
+
+	listFilters := repository.ListAuditEventsFilter{
+		ClientId:     filters.ClientID,
+		LedgerId:     filters.LedgerID,
+		ActorId:      filters.ActorID,
+		RequestId:    filters.RequestID,
+		Action:       filters.Action,
+		ResourceType: filters.ResourceType,
+		ResourceId:   filters.ResourceID,
+	}
+
+	auditEvents, auditEventsErr := h.service.ListAuditEvents(r.Context(), *filterQuery, listFilters)
+	if auditEventsErr != nil {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]any{
+			"errors": map[string]string{
+				"message": auditEventsErr.Error(),
+			},
+		})
+		return
+	}
+
+	count, countsErr := h.service.CountAuditEvents(r.Context(), *filterQuery, listFilters)
+	if countsErr != nil {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]any{
+			"errors": map[string]string{
+				"message": countsErr.Error(),
+			},
+		})
+		return
+	}
+
+	auditEventsTransformed := make([]interface{}, 0)
+	for _, auditEvent := range auditEvents {
+		auditEventsTransformed = append(
+			auditEventsTransformed,
+			transformations.DBAuditEventToRestAuditEvent(&auditEvent),
+		)
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]any{
+		"data": auditEventsTransformed,
+		"meta": map[string]any{
+			"page":              filterQuery.Page,
+			"page_size":         filterQuery.PageSize,
+			"order":             filterQuery.Order,
+			"order_by":          filterQuery.OrderBy,
+			"total":             count,
+			"has_next_page":     (filterQuery.Page * filterQuery.PageSize) < int(count),
+			"has_previous_page": filterQuery.Page > 1,
+		},
+	})
+}
@@ -0,0 +1,564 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/Bendomey/fincore-engine/internal/lib"
+	"github.com/Bendomey/fincore-engine/internal/repository"
+	"github.com/Bendomey/fincore-engine/internal/services"
+	"github.com/Bendomey/fincore-engine/internal/transformations"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+)
+
+type BudgetHandler struct {
+	service  services.BudgetService
+	validate *validator.Validate
+}
+
+func NewBudgetHandler(service services.BudgetService, validate *validator.Validate) BudgetHandler {
+	return BudgetHandler{service, validate}
+}
+
+type CreateBudgetLineRequest struct {
+	AccountID        string  `json:"account_id"         validate:"required,uuid4"`
+	DimensionValueID *string `json:"dimension_value_id" validate:"omitempty,uuid4"`
+	PeriodStart      string  `json:"period_start"       validate:"required,datetime=2006-01-02"`
+	PeriodEnd        string  `json:"period_end"         validate:"required,datetime=2006-01-02"`
+	Amount           int64   `json:"amount"             validate:"number"`
+}
+
+type CreateBudgetRequest struct {
+	Name        string                    `json:"name"        validate:"required,min=3,max=255"`
+	Description *string                   `json:"description" validate:"omitempty,max=1024"`
+	Lines       []CreateBudgetLineRequest `json:"lines"       validate:"omitempty,dive"`
+}
+
+func (h *BudgetHandler) CreateBudget(w http.ResponseWriter, r *http.Request) {
+	var body CreateBudgetRequest
+	if decodeErr := json.NewDecoder(r.Body).Decode(&body); decodeErr != nil {
+		http.Error(w, "Invalid JSON body", http.StatusBadRequest)
+		return
+	}
+
+	isPassedValidation := lib.ValidateRequest(h.validate, body, w)
+
+	if !isPassedValidation {
+		return
+	}
+
+	client, clientOk := lib.ClientFromContext(r.Context())
+
+	if !clientOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	ledger, ledgerOk := lib.LedgerFromContext(r.Context())
+
+	if !ledgerOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	lines := make([]services.CreateBudgetLineInput, 0)
+	for _, line := range body.Lines {
+		lines = append(lines, services.CreateBudgetLineInput{
+			ClientID:         client.ID.String(),
+			LedgerID:         ledger.ID.String(),
+			AccountID:        line.AccountID,
+			DimensionValueID: line.DimensionValueID,
+			PeriodStart:      line.PeriodStart,
+			PeriodEnd:        line.PeriodEnd,
+			Amount:           line.Amount,
+		})
+	}
+
+	budget, err := h.service.CreateBudget(r.Context(), services.CreateBudgetInput{
+		ClientID:    client.ID.String(),
+		Name:        body.Name,
+		Description: body.Description,
+		Lines:       lines,
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]any{
+			"errors": map[string]string{
+				"message": err.Error(),
+			},
+		})
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]any{
+		"data": transformations.DBBudgetToRestBudget(budget, nil),
+	})
+}
+
+type UpdateBudgetRequest struct {
+	Name        *string `json:"name"        validate:"omitempty,min=3,max=255"`
+	Description *string `json:"description" validate:"omitempty,max=1024"`
+}
+
+func (h *BudgetHandler) UpdateBudget(w http.ResponseWriter, r *http.Request) {
+	var body UpdateBudgetRequest
+	if decodeErr := json.NewDecoder(r.Body).Decode(&body); decodeErr != nil {
+		http.Error(w, "Invalid JSON body", http.StatusBadRequest)
+		return
+	}
+
+	isPassedValidation := lib.ValidateRequest(h.validate, body, w)
+
+	if !isPassedValidation {
+		return
+	}
+
+	client, clientOk := lib.ClientFromContext(r.Context())
+
+	if !clientOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	budget, err := h.service.UpdateBudget(r.Context(), services.UpdateBudgetInput{
+		ClientID:    client.ID.String(),
+		ID:          chi.URLParam(r, "budget_id"),
+		Name:        body.Name,
+		Description: body.Description,
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]any{
+			"errors": map[string]string{
+				"message": err.Error(),
+			},
+		})
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]any{
+		"data": transformations.DBBudgetToRestBudget(budget, nil),
+	})
+}
+
+func (h *BudgetHandler) DeleteBudget(w http.ResponseWriter, r *http.Request) {
+	client, clientOk := lib.ClientFromContext(r.Context())
+
+	if !clientOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	err := h.service.DeleteBudget(r.Context(), services.GetBudgetInput{
+		ClientID: client.ID.String(),
+		ID:       chi.URLParam(r, "budget_id"),
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]any{
+			"errors": map[string]string{
+				"message": err.Error(),
+			},
+		})
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	json.NewEncoder(w).Encode(map[string]any{})
+}
+
+type GetBudgetRequest struct {
+	ClientID string    `json:"client_id" validate:"required,uuid4"`
+	ID       string    `json:"id"        validate:"required,uuid4"`
+	Populate *[]string `json:"populate"  validate:"omitempty,dive,oneof=BudgetLines BudgetLines.Account"`
+}
+
+func (h *BudgetHandler) GetBudget(w http.ResponseWriter, r *http.Request) {
+	client, clientOk := lib.ClientFromContext(r.Context())
+
+	if !clientOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	input := GetBudgetRequest{
+		ClientID: client.ID.String(),
+		ID:       chi.URLParam(r, "budget_id"),
+		Populate: getPopulateFields(r),
+	}
+
+	isPassedValidation := lib.ValidateRequest(h.validate, input, w)
+
+	if !isPassedValidation {
+		return
+	}
+
+	budget, err := h.service.GetBudget(r.Context(), services.GetBudgetInput{
+		ClientID: input.ClientID,
+		ID:       input.ID,
+		Populate: input.Populate,
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]any{
+			"errors": map[string]string{
+				"message": err.Error(),
+			},
+		})
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]any{
+		"data": transformations.DBBudgetToRestBudget(budget, input.Populate),
+	})
+}
+
+func (h *BudgetHandler) ListBudgets(w http.ResponseWriter, r *http.Request) {
+	client, clientOk := lib.ClientFromContext(r.Context())
+
+	if !clientOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	filterQuery, filterErr := lib.GenerateQuery(r.URL.Query(), repository.BudgetQueryFields)
+	if filterErr != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]any{
+			"errors": map[string]string{
+				"message": filterErr.Error(),
+			},
+		})
+		return
+	}
+
+	isFilterQueryPassedValidation := lib.ValidateRequest(h.validate, filterQuery, w)
+	if !isFilterQueryPassedValidation {
+		return
+	}
+
+	filters := repository.ListBudgetsFilter{
+		ClientId: client.ID.String(),
+	}
+
+	budgets, budgetsErr := h.service.ListBudgets(r.Context(), *filterQuery, filters)
+	if budgetsErr != nil {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]any{
+			"errors": map[string]string{
+				"message": budgetsErr.Error(),
+			},
+		})
+		return
+	}
+
+	count, countsErr := h.service.CountBudgets(r.Context(), *filterQuery, filters)
+	if countsErr != nil {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]any{
+			"errors": map[string]string{
+				"message": countsErr.Error(),
+			},
+		})
+		return
+	}
+
+	budgetsTransformed := make([]interface{}, 0)
+	for _, budget := range budgets {
+		budgetsTransformed = append(
+			budgetsTransformed,
+			transformations.DBBudgetToRestBudget(&budget, filterQuery.Populate),
+		)
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]any{
+		"data": budgetsTransformed,
+		"meta": map[string]any{
+			"page":              filterQuery.Page,
+			"page_size":         filterQuery.PageSize,
+			"order":             filterQuery.Order,
+			"order_by":          filterQuery.OrderBy,
+			"total":             count,
+			"has_next_page":     (filterQuery.Page * filterQuery.PageSize) < int(count),
+			"has_previous_page": filterQuery.Page > 1,
+		},
+	})
+}
+
+func (h *BudgetHandler) CreateBudgetLine(w http.ResponseWriter, r *http.Request) {
+	var body CreateBudgetLineRequest
+	if decodeErr := json.NewDecoder(r.Body).Decode(&body); decodeErr != nil {
+		http.Error(w, "Invalid JSON body", http.StatusBadRequest)
+		return
+	}
+
+	isPassedValidation := lib.ValidateRequest(h.validate, body, w)
+
+	if !isPassedValidation {
+		return
+	}
+
+	client, clientOk := lib.ClientFromContext(r.Context())
+
+	if !clientOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	ledger, ledgerOk := lib.LedgerFromContext(r.Context())
+
+	if !ledgerOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	budgetLine, err := h.service.CreateBudgetLine(r.Context(), services.CreateBudgetLineInput{
+		ClientID:         client.ID.String(),
+		LedgerID:         ledger.ID.String(),
+		BudgetID:         chi.URLParam(r, "budget_id"),
+		AccountID:        body.AccountID,
+		DimensionValueID: body.DimensionValueID,
+		PeriodStart:      body.PeriodStart,
+		PeriodEnd:        body.PeriodEnd,
+		Amount:           body.Amount,
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]any{
+			"errors": map[string]string{
+				"message": err.Error(),
+			},
+		})
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]any{
+		"data": transformations.DBBudgetLineToRestBudgetLine(budgetLine, nil),
+	})
+}
+
+// UploadBudgetLines accepts a text/csv body with the columns
+// account_code, period_start, period_end, amount and optionally dimension, dimension_value. Account codes are the
+// selected ledger's.
+func (h *BudgetHandler) UploadBudgetLines(w http.ResponseWriter, r *http.Request) {
+	client, clientOk := lib.ClientFromContext(r.Context())
+
+	if !clientOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	ledger, ledgerOk := lib.LedgerFromContext(r.Context())
+
+	if !ledgerOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	budgetLines, err := h.service.UploadBudgetLines(r.Context(), services.UploadBudgetLinesInput{
+		ClientID: client.ID.String(),
+		BudgetID: chi.URLParam(r, "budget_id"),
+		LedgerID: ledger.ID.String(),
+		File:     r.Body,
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]any{
+			"errors": map[string]string{
+				"message": err.Error(),
+			},
+		})
+		return
+	}
+
+	budgetLinesTransformed := make([]interface{}, 0)
+	for _, budgetLine := range budgetLines {
+		budgetLinesTransformed = append(
+			budgetLinesTransformed,
+			transformations.DBBudgetLineToRestBudgetLine(&budgetLine, nil),
+		)
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]any{
+		"data": budgetLinesTransformed,
+	})
+}
+
+type UpdateBudgetLineRequest struct {
+	Amount *int64 `json:"amount" validate:"omitempty,number"`
+}
+
+func (h *BudgetHandler) UpdateBudgetLine(w http.ResponseWriter, r *http.Request) {
+	var body UpdateBudgetLineRequest
+	if decodeErr := json.NewDecoder(r.Body).Decode(&body); decodeErr != nil {
+		http.Error(w, "Invalid JSON body", http.StatusBadRequest)
+		return
+	}
+
+	isPassedValidation := lib.ValidateRequest(h.validate, body, w)
+
+	if !isPassedValidation {
+		return
+	}
+
+	client, clientOk := lib.ClientFromContext(r.Context())
+
+	if !clientOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	budgetLine, err := h.service.UpdateBudgetLine(r.Context(), services.UpdateBudgetLineInput{
+		ClientID: client.ID.String(),
+		BudgetID: chi.URLParam(r, "budget_id"),
+		ID:       chi.URLParam(r, "budget_line_id"),
+		Amount:   body.Amount,
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]any{
+			"errors": map[string]string{
+				"message": err.Error(),
+			},
+		})
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]any{
+		"data": transformations.DBBudgetLineToRestBudgetLine(budgetLine, nil),
+	})
+}
+
+func (h *BudgetHandler) DeleteBudgetLine(w http.ResponseWriter, r *http.Request) {
+	client, clientOk := lib.ClientFromContext(r.Context())
+
+	if !clientOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	err := h.service.DeleteBudgetLine(r.Context(), services.GetBudgetLineInput{
+		ClientID: client.ID.String(),
+		BudgetID: chi.URLParam(r, "budget_id"),
+		ID:       chi.URLParam(r, "budget_line_id"),
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]any{
+			"errors": map[string]string{
+				"message": err.Error(),
+			},
+		})
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	json.NewEncoder(w).Encode(map[string]any{})
+}
+
+type ListBudgetLinesFilterRequest struct {
+	ClientID         string  `json:"client_id"          validate:"required,uuid4"`
+	BudgetID         string  `json:"budget_id"          validate:"required,uuid4"`
+	AccountID        *string `json:"account_id"         validate:"omitempty,uuid4"`
+	DimensionValueID *string `json:"dimension_value_id" validate:"omitempty,uuid4"`
+}
+
+func (h *BudgetHandler) ListBudgetLines(w http.ResponseWriter, r *http.Request) {
+	client, clientOk := lib.ClientFromContext(r.Context())
+
+	if !clientOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	filters := ListBudgetLinesFilterRequest{
+		ClientID:         client.ID.String(),
+		BudgetID:         chi.URLParam(r, "budget_id"),
+		AccountID:        lib.NullOrString(r.URL.Query().Get("account_id")),
+		DimensionValueID: lib.NullOrString(r.URL.Query().Get("dimension_value_id")),
+	}
+
+	isFiltersPassedValidation := lib.ValidateRequest(h.validate, filters, w)
+	if !isFiltersPassedValidation {
+		return
+	}
+
+	filterQuery, filterErr := lib.GenerateQuery(r.URL.Query(), repository.BudgetLineQueryFields)
+	if filterErr != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]any{
+			"errors": map[string]string{
+				"message": filterErr.Error(),
+			},
+		})
+		return
+	}
+
+	isFilterQueryPassedValidation := lib.ValidateRequest(h.validate, filterQuery, w)
+	if !isFilterQueryPassedValidation {
+		return
+	}
+
+	listFilters := repository.ListBudgetLinesFilter{
+		BudgetId:         filters.BudgetID,
+		AccountId:        filters.AccountID,
+		DimensionValueId: filters.DimensionValueID,
+	}
+
+	budgetLines, budgetLinesErr := h.service.ListBudgetLines(
+		r.Context(),
+		services.GetBudgetInput{ClientID: filters.ClientID, ID: filters.BudgetID},
+		*filterQuery,
+		listFilters,
+	)
+	if budgetLinesErr != nil {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]any{
+			"errors": map[string]string{
+				"message": budgetLinesErr.Error(),
+			},
+		})
+		return
+	}
+
+	count, countsErr := h.service.CountBudgetLines(r.Context(), *filterQuery, listFilters)
+	if countsErr != nil {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]any{
+			"errors": map[string]string{
+				"message": countsErr.Error(),
+			},
+		})
+		return
+	}
+
+	budgetLinesTransformed := make([]interface{}, 0)
+	for _, budgetLine := range budgetLines {
+		budgetLinesTransformed = append(
+			budgetLinesTransformed,
+			transformations.DBBudgetLineToRestBudgetLine(&budgetLine, filterQuery.Populate),
+		)
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]any{
+		"data": budgetLinesTransformed,
+		"meta": map[string]any{
+			"page":              filterQuery.Page,
+			"page_size":         filterQuery.PageSize,
+			"order":             filterQuery.Order,
+			"order_by":          filterQuery.OrderBy,
+			"total":             count,
+			"has_next_page":     (filterQuery.Page * filterQuery.PageSize) < int(count),
+			"has_previous_page": filterQuery.Page > 1,
+		},
+	})
+}
@@ -20,8 +20,9 @@ func NewClientHandler(service services.ClientService, validate *validator.Valida
 }
 
 type CreateClientRequest struct {
-	Name  string `json:"name"  validate:"required,min=3,max=255"`
-	Email string `json:"email" validate:"required,email"`
+	Name     string  `json:"name"     validate:"required,min=3,max=255"`
+	Email    string  `json:"email"    validate:"required,email"`
+	Currency *string `json:"currency" validate:"omitempty,len=3,uppercase"`
 }
 
 func (h *ClientHandler) CreateClient(w http.ResponseWriter, r *http.Request) {
@@ -38,8 +39,9 @@ func (h *ClientHandler) CreateClient(w http.ResponseWriter, r *http.Request) {
 	}
 
 	client, err := h.service.CreateClient(r.Context(), services.CreateUserInput{
-		Name:  body.Name,
-		Email: body.Email,
+		Name:     body.Name,
+		Email:    body.Email,
+		Currency: body.Currency,
 	})
 	if err != nil {
 		w.WriteHeader(http.StatusBadRequest)
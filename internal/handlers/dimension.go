@@ -0,0 +1,611 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/Bendomey/fincore-engine/internal/lib"
+	"github.com/Bendomey/fincore-engine/internal/repository"
+	"github.com/Bendomey/fincore-engine/internal/services"
+	"github.com/Bendomey/fincore-engine/internal/transformations"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+)
+
+type DimensionHandler struct {
+	service  services.DimensionService
+	validate *validator.Validate
+}
+
+func NewDimensionHandler(service services.DimensionService, validate *validator.Validate) DimensionHandler {
+	return DimensionHandler{service, validate}
+}
+
+type CreateDimensionTypeRequest struct {
+	Code        string  `json:"code"        validate:"required,min=2,max=64"`
+	Name        string  `json:"name"        validate:"required,min=3,max=255"`
+	Description *string `json:"description" validate:"omitempty,max=1024"`
+}
+
+func (h *DimensionHandler) CreateDimensionType(w http.ResponseWriter, r *http.Request) {
+	var body CreateDimensionTypeRequest
+	if decodeErr := json.NewDecoder(r.Body).Decode(&body); decodeErr != nil {
+		http.Error(w, "Invalid JSON body", http.StatusBadRequest)
+		return
+	}
+
+	isPassedValidation := lib.ValidateRequest(h.validate, body, w)
+
+	if !isPassedValidation {
+		return
+	}
+
+	client, clientOk := lib.ClientFromContext(r.Context())
+
+	if !clientOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	ledger, ledgerOk := lib.LedgerFromContext(r.Context())
+
+	if !ledgerOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	dimensionType, err := h.service.CreateDimensionType(r.Context(), services.CreateDimensionTypeInput{
+		ClientID:    client.ID.String(),
+		LedgerID:    ledger.ID.String(),
+		Code:        body.Code,
+		Name:        body.Name,
+		Description: body.Description,
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]any{
+			"errors": map[string]string{
+				"message": err.Error(),
+			},
+		})
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]any{
+		"data": transformations.DBDimensionTypeToRestDimensionType(dimensionType, nil),
+	})
+}
+
+type UpdateDimensionTypeRequest struct {
+	Name        *string `json:"name"        validate:"omitempty,min=3,max=255"`
+	Description *string `json:"description" validate:"omitempty,max=1024"`
+}
+
+func (h *DimensionHandler) UpdateDimensionType(w http.ResponseWriter, r *http.Request) {
+	var body UpdateDimensionTypeRequest
+	if decodeErr := json.NewDecoder(r.Body).Decode(&body); decodeErr != nil {
+		http.Error(w, "Invalid JSON body", http.StatusBadRequest)
+		return
+	}
+
+	isPassedValidation := lib.ValidateRequest(h.validate, body, w)
+
+	if !isPassedValidation {
+		return
+	}
+
+	client, clientOk := lib.ClientFromContext(r.Context())
+
+	if !clientOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	ledger, ledgerOk := lib.LedgerFromContext(r.Context())
+
+	if !ledgerOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	dimensionType, err := h.service.UpdateDimensionType(r.Context(), services.UpdateDimensionTypeInput{
+		ClientID:    client.ID.String(),
+		LedgerID:    ledger.ID.String(),
+		ID:          chi.URLParam(r, "dimension_type_id"),
+		Name:        body.Name,
+		Description: body.Description,
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]any{
+			"errors": map[string]string{
+				"message": err.Error(),
+			},
+		})
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]any{
+		"data": transformations.DBDimensionTypeToRestDimensionType(dimensionType, nil),
+	})
+}
+
+func (h *DimensionHandler) DeleteDimensionType(w http.ResponseWriter, r *http.Request) {
+	client, clientOk := lib.ClientFromContext(r.Context())
+
+	if !clientOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	ledger, ledgerOk := lib.LedgerFromContext(r.Context())
+
+	if !ledgerOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	err := h.service.DeleteDimensionType(r.Context(), services.GetDimensionTypeInput{
+		ClientID: client.ID.String(),
+		LedgerID: ledger.ID.String(),
+		ID:       chi.URLParam(r, "dimension_type_id"),
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]any{
+			"errors": map[string]string{
+				"message": err.Error(),
+			},
+		})
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	json.NewEncoder(w).Encode(map[string]any{})
+}
+
+type GetDimensionTypeRequest struct {
+	ClientID string    `json:"client_id" validate:"required,uuid4"`
+	ID       string    `json:"id"        validate:"required,uuid4"`
+	Populate *[]string `json:"populate"  validate:"omitempty,dive,oneof=DimensionValues"`
+}
+
+func (h *DimensionHandler) GetDimensionType(w http.ResponseWriter, r *http.Request) {
+	client, clientOk := lib.ClientFromContext(r.Context())
+
+	if !clientOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	ledger, ledgerOk := lib.LedgerFromContext(r.Context())
+
+	if !ledgerOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	input := GetDimensionTypeRequest{
+		ClientID: client.ID.String(),
+		ID:       chi.URLParam(r, "dimension_type_id"),
+		Populate: getPopulateFields(r),
+	}
+
+	isPassedValidation := lib.ValidateRequest(h.validate, input, w)
+
+	if !isPassedValidation {
+		return
+	}
+
+	dimensionType, err := h.service.GetDimensionType(r.Context(), services.GetDimensionTypeInput{
+		ClientID: input.ClientID,
+		LedgerID: ledger.ID.String(),
+		ID:       input.ID,
+		Populate: input.Populate,
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]any{
+			"errors": map[string]string{
+				"message": err.Error(),
+			},
+		})
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]any{
+		"data": transformations.DBDimensionTypeToRestDimensionType(dimensionType, input.Populate),
+	})
+}
+
+func (h *DimensionHandler) ListDimensionTypes(w http.ResponseWriter, r *http.Request) {
+	client, clientOk := lib.ClientFromContext(r.Context())
+
+	if !clientOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	ledger, ledgerOk := lib.LedgerFromContext(r.Context())
+
+	if !ledgerOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	filterQuery, filterErr := lib.GenerateQuery(r.URL.Query(), repository.DimensionTypeQueryFields)
+	if filterErr != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]any{
+			"errors": map[string]string{
+				"message": filterErr.Error(),
+			},
+		})
+		return
+	}
+
+	isFilterQueryPassedValidation := lib.ValidateRequest(h.validate, filterQuery, w)
+	if !isFilterQueryPassedValidation {
+		return
+	}
+
+	filters := repository.ListDimensionTypesFilter{
+		ClientId: client.ID.String(),
+		LedgerId: ledger.ID.String(),
+	}
+
+	dimensionTypes, dimensionTypesErr := h.service.ListDimensionTypes(r.Context(), *filterQuery, filters)
+	if dimensionTypesErr != nil {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]any{
+			"errors": map[string]string{
+				"message": dimensionTypesErr.Error(),
+			},
+		})
+		return
+	}
+
+	count, countsErr := h.service.CountDimensionTypes(r.Context(), *filterQuery, filters)
+	if countsErr != nil {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]any{
+			"errors": map[string]string{
+				"message": countsErr.Error(),
+			},
+		})
+		return
+	}
+
+	dimensionTypesTransformed := make([]interface{}, 0)
+	for _, dimensionType := range dimensionTypes {
+		dimensionTypesTransformed = append(
+			dimensionTypesTransformed,
+			transformations.DBDimensionTypeToRestDimensionType(&dimensionType, filterQuery.Populate),
+		)
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]any{
+		"data": dimensionTypesTransformed,
+		"meta": map[string]any{
+			"page":              filterQuery.Page,
+			"page_size":         filterQuery.PageSize,
+			"order":             filterQuery.Order,
+			"order_by":          filterQuery.OrderBy,
+			"total":             count,
+			"has_next_page":     (filterQuery.Page * filterQuery.PageSize) < int(count),
+			"has_previous_page": filterQuery.Page > 1,
+		},
+	})
+}
+
+type CreateDimensionValueRequest struct {
+	Code        string  `json:"code"        validate:"required,min=1,max=64"`
+	Name        string  `json:"name"        validate:"required,min=1,max=255"`
+	Description *string `json:"description" validate:"omitempty,max=1024"`
+}
+
+func (h *DimensionHandler) CreateDimensionValue(w http.ResponseWriter, r *http.Request) {
+	var body CreateDimensionValueRequest
+	if decodeErr := json.NewDecoder(r.Body).Decode(&body); decodeErr != nil {
+		http.Error(w, "Invalid JSON body", http.StatusBadRequest)
+		return
+	}
+
+	isPassedValidation := lib.ValidateRequest(h.validate, body, w)
+
+	if !isPassedValidation {
+		return
+	}
+
+	client, clientOk := lib.ClientFromContext(r.Context())
+
+	if !clientOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	ledger, ledgerOk := lib.LedgerFromContext(r.Context())
+
+	if !ledgerOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	dimensionValue, err := h.service.CreateDimensionValue(r.Context(), services.CreateDimensionValueInput{
+		ClientID:        client.ID.String(),
+		LedgerID:        ledger.ID.String(),
+		DimensionTypeID: chi.URLParam(r, "dimension_type_id"),
+		Code:            body.Code,
+		Name:            body.Name,
+		Description:     body.Description,
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]any{
+			"errors": map[string]string{
+				"message": err.Error(),
+			},
+		})
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]any{
+		"data": transformations.DBDimensionValueToRestDimensionValue(dimensionValue, nil),
+	})
+}
+
+type UpdateDimensionValueRequest struct {
+	Name        *string `json:"name"        validate:"omitempty,min=1,max=255"`
+	Description *string `json:"description" validate:"omitempty,max=1024"`
+	IsActive    *bool   `json:"is_active"   validate:"omitempty,boolean"`
+}
+
+func (h *DimensionHandler) UpdateDimensionValue(w http.ResponseWriter, r *http.Request) {
+	var body UpdateDimensionValueRequest
+	if decodeErr := json.NewDecoder(r.Body).Decode(&body); decodeErr != nil {
+		http.Error(w, "Invalid JSON body", http.StatusBadRequest)
+		return
+	}
+
+	isPassedValidation := lib.ValidateRequest(h.validate, body, w)
+
+	if !isPassedValidation {
+		return
+	}
+
+	client, clientOk := lib.ClientFromContext(r.Context())
+
+	if !clientOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	ledger, ledgerOk := lib.LedgerFromContext(r.Context())
+
+	if !ledgerOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	dimensionValue, err := h.service.UpdateDimensionValue(r.Context(), services.UpdateDimensionValueInput{
+		ClientID:        client.ID.String(),
+		LedgerID:        ledger.ID.String(),
+		DimensionTypeID: chi.URLParam(r, "dimension_type_id"),
+		ID:              chi.URLParam(r, "dimension_value_id"),
+		Name:            body.Name,
+		Description:     body.Description,
+		IsActive:        body.IsActive,
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]any{
+			"errors": map[string]string{
+				"message": err.Error(),
+			},
+		})
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]any{
+		"data": transformations.DBDimensionValueToRestDimensionValue(dimensionValue, nil),
+	})
+}
+
+func (h *DimensionHandler) DeleteDimensionValue(w http.ResponseWriter, r *http.Request) {
+	client, clientOk := lib.ClientFromContext(r.Context())
+
+	if !clientOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	ledger, ledgerOk := lib.LedgerFromContext(r.Context())
+
+	if !ledgerOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	err := h.service.DeleteDimensionValue(r.Context(), services.GetDimensionValueInput{
+		ClientID:        client.ID.String(),
+		LedgerID:        ledger.ID.String(),
+		DimensionTypeID: chi.URLParam(r, "dimension_type_id"),
+		ID:              chi.URLParam(r, "dimension_value_id"),
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]any{
+			"errors": map[string]string{
+				"message": err.Error(),
+			},
+		})
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	json.NewEncoder(w).Encode(map[string]any{})
+}
+
+type GetDimensionValueRequest struct {
+	ClientID        string    `json:"client_id"         validate:"required,uuid4"`
+	DimensionTypeID string    `json:"dimension_type_id" validate:"required,uuid4"`
+	ID              string    `json:"id"                validate:"required,uuid4"`
+	Populate        *[]string `json:"populate"          validate:"omitempty,dive,oneof=DimensionType"`
+}
+
+func (h *DimensionHandler) GetDimensionValue(w http.ResponseWriter, r *http.Request) {
+	client, clientOk := lib.ClientFromContext(r.Context())
+
+	if !clientOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	ledger, ledgerOk := lib.LedgerFromContext(r.Context())
+
+	if !ledgerOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	input := GetDimensionValueRequest{
+		ClientID:        client.ID.String(),
+		DimensionTypeID: chi.URLParam(r, "dimension_type_id"),
+		ID:              chi.URLParam(r, "dimension_value_id"),
+		Populate:        getPopulateFields(r),
+	}
+
+	isPassedValidation := lib.ValidateRequest(h.validate, input, w)
+
+	if !isPassedValidation {
+		return
+	}
+
+	dimensionValue, err := h.service.GetDimensionValue(r.Context(), services.GetDimensionValueInput{
+		ClientID:        input.ClientID,
+		LedgerID:        ledger.ID.String(),
+		DimensionTypeID: input.DimensionTypeID,
+		ID:              input.ID,
+		Populate:        input.Populate,
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]any{
+			"errors": map[string]string{
+				"message": err.Error(),
+			},
+		})
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]any{
+		"data": transformations.DBDimensionValueToRestDimensionValue(dimensionValue, input.Populate),
+	})
+}
+
+type ListDimensionValuesFilterRequest struct {
+	ClientID        string  `json:"client_id"         validate:"required,uuid4"`
+	DimensionTypeID string  `json:"dimension_type_id" validate:"required,uuid4"`
+	IsActive        *string `json:"is_active"         validate:"omitempty,boolean"`
+}
+
+func (h *DimensionHandler) ListDimensionValues(w http.ResponseWriter, r *http.Request) {
+	client, clientOk := lib.ClientFromContext(r.Context())
+
+	if !clientOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	ledger, ledgerOk := lib.LedgerFromContext(r.Context())
+
+	if !ledgerOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	filters := ListDimensionValuesFilterRequest{
+		ClientID:        client.ID.String(),
+		DimensionTypeID: chi.URLParam(r, "dimension_type_id"),
+		IsActive:        lib.NullOrString(r.URL.Query().Get("is_active")),
+	}
+
+	isFiltersPassedValidation := lib.ValidateRequest(h.validate, filters, w)
+	if !isFiltersPassedValidation {
+		return
+	}
+
+	filterQuery, filterErr := lib.GenerateQuery(r.URL.Query(), repository.DimensionValueQueryFields)
+	if filterErr != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]any{
+			"errors": map[string]string{
+				"message": filterErr.Error(),
+			},
+		})
+		return
+	}
+
+	isFilterQueryPassedValidation := lib.ValidateRequest(h.validate, filterQuery, w)
+	if !isFilterQueryPassedValidation {
+		return
+	}
+
+	listFilters := repository.ListDimensionValuesFilter{
+		ClientId:        filters.ClientID,
+		LedgerId:        ledger.ID.String(),
+		DimensionTypeId: filters.DimensionTypeID,
+		IsActive:        lib.ConvertStringPointerToBoolPointer(filters.IsActive),
+	}
+
+	dimensionValues, dimensionValuesErr := h.service.ListDimensionValues(r.Context(), *filterQuery, listFilters)
+	if dimensionValuesErr != nil {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]any{
+			"errors": map[string]string{
+				"message": dimensionValuesErr.Error(),
+			},
+		})
+		return
+	}
+
+	count, countsErr := h.service.CountDimensionValues(r.Context(), *filterQuery, listFilters)
+	if countsErr != nil {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]any{
+			"errors": map[string]string{
+				"message": countsErr.Error(),
+			},
+		})
+		return
+	}
+
+	dimensionValuesTransformed := make([]interface{}, 0)
+	for _, dimensionValue := range dimensionValues {
+		dimensionValuesTransformed = append(
+			dimensionValuesTransformed,
+			transformations.DBDimensionValueToRestDimensionValue(&dimensionValue, filterQuery.Populate),
+		)
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]any{
+		"data": dimensionValuesTransformed,
+		"meta": map[string]any{
+			"page":              filterQuery.Page,
+			"page_size":         filterQuery.PageSize,
+			"order":             filterQuery.Order,
+			"order_by":          filterQuery.OrderBy,
+			"total":             count,
+			"has_next_page":     (filterQuery.Page * filterQuery.PageSize) < int(count),
+			"has_previous_page": filterQuery.Page > 1,
+		},
+	})
+}
@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Bendomey/fincore-engine/internal/lib"
+	"github.com/Bendomey/fincore-engine/internal/services"
+	"github.com/Bendomey/fincore-engine/internal/transformations"
+	"github.com/go-playground/validator/v10"
+)
+
+const (
+	// sent as a comment so proxies don't close an idle stream.
+	eventStreamHeartbeat = 15 * time.Second
+	eventStreamBatchSize = 100
+)
+
+type EventHandler struct {
+	service  services.EventService
+	validate *validator.Validate
+}
+
+func NewEventHandler(service services.EventService, validate *validator.Validate) EventHandler {
+	return EventHandler{service, validate}
+}
+
+// StreamEvents streams the client's ledger events as server-sent events. Each event's id is its sequence, a
+// reconnecting client sends the last one back as Last-Event-ID (or ?last_event_id=) and resumes right after it.
+// Without a cursor the stream starts with the next event.
+func (h *EventHandler) StreamEvents(w http.ResponseWriter, r *http.Request) {
+	client, clientOk := lib.ClientFromContext(r.Context())
+
+	if !clientOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, "streaming is not supported")
+		return
+	}
+
+	clientID := client.ID.String()
+
+	// subscribe before reading the backlog so nothing written in between is missed.
+	wake, unsubscribe := h.service.Subscribe(clientID)
+	defer unsubscribe()
+
+	cursor, err := h.getEventStreamCursor(r, clientID)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(eventStreamHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		for {
+			webhookEvents, err := h.service.ListEventsAfter(r.Context(), clientID, cursor, eventStreamBatchSize)
+			if err != nil {
+				return
+			}
+
+			for _, webhookEvent := range webhookEvents {
+				data, err := json.Marshal(transformations.DBWebhookEventToRestWebhookEvent(&webhookEvent))
+				if err != nil {
+					return
+				}
+
+				fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", webhookEvent.Sequence, webhookEvent.Type, data)
+				cursor = webhookEvent.Sequence
+			}
+			flusher.Flush()
+
+			if len(webhookEvents) < eventStreamBatchSize {
+				break
+			}
+		}
+
+		select {
+		case <-r.Context().Done():
+			return
+		case <-wake:
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": heartbeat\n\n")
+			flusher.Flush()
+		}
+	}
+}
+
+func (h *EventHandler) getEventStreamCursor(r *http.Request, clientID string) (int64, error) {
+	lastEventID := r.Header.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = r.URL.Query().Get("last_event_id")
+	}
+
+	if lastEventID == "" {
+		return h.service.LatestEventSequence(r.Context(), clientID)
+	}
+
+	cursor, err := strconv.ParseInt(lastEventID, 10, 64)
+	if err != nil || cursor < 0 {
+		return 0, errors.New("Last-Event-ID must be the id of a streamed event")
+	}
+
+	return cursor, nil
+}
@@ -0,0 +1,312 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/Bendomey/fincore-engine/internal/lib"
+	"github.com/Bendomey/fincore-engine/internal/repository"
+	"github.com/Bendomey/fincore-engine/internal/services"
+	"github.com/Bendomey/fincore-engine/internal/transformations"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+)
+
+type HoldHandler struct {
+	service  services.HoldService
+	validate *validator.Validate
+}
+
+func NewHoldHandler(service services.HoldService, validate *validator.Validate) HoldHandler {
+	return HoldHandler{service, validate}
+}
+
+type CreateHoldRequest struct {
+	AccountID            string                  `json:"account_id"             validate:"required,uuid4"`
+	DestinationAccountID string                  `json:"destination_account_id" validate:"required,uuid4,nefield=AccountID"`
+	Amount               int64                   `json:"amount"                 validate:"required,min=1"`
+	Reference            string                  `json:"reference"              validate:"required,min=3,max=255"`
+	ExpiresAt            *string                 `json:"expires_at"             validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	Metadata             *map[string]interface{} `json:"metadata"               validate:"omitempty"`
+}
+
+func (h *HoldHandler) CreateHold(w http.ResponseWriter, r *http.Request) {
+	var body CreateHoldRequest
+	if decodeErr := json.NewDecoder(r.Body).Decode(&body); decodeErr != nil {
+		http.Error(w, "Invalid JSON body", http.StatusBadRequest)
+		return
+	}
+
+	isPassedValidation := lib.ValidateRequest(h.validate, body, w)
+
+	if !isPassedValidation {
+		return
+	}
+
+	client, clientOk := lib.ClientFromContext(r.Context())
+
+	if !clientOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	ledger, ledgerOk := lib.LedgerFromContext(r.Context())
+
+	if !ledgerOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	hold, err := h.service.CreateHold(r.Context(), services.CreateHoldInput{
+		ClientID:             client.ID.String(),
+		LedgerID:             ledger.ID.String(),
+		AccountID:            body.AccountID,
+		DestinationAccountID: body.DestinationAccountID,
+		Amount:               body.Amount,
+		Currency:             ledger.Currency,
+		Reference:            body.Reference,
+		ExpiresAt:            body.ExpiresAt,
+		Metadata:             body.Metadata,
+	})
+	if err != nil {
+		writePostingError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]any{
+		"data": transformations.DBHoldToRestHold(hold, nil),
+	})
+}
+
+type CaptureHoldRequest struct {
+	Amount *int64 `json:"amount" validate:"omitempty,min=1"`
+}
+
+// CaptureHold posts the held amount, or part of it, to the destination account. The body may be omitted to
+// capture the whole hold.
+func (h *HoldHandler) CaptureHold(w http.ResponseWriter, r *http.Request) {
+	var body CaptureHoldRequest
+	if r.ContentLength > 0 {
+		if decodeErr := json.NewDecoder(r.Body).Decode(&body); decodeErr != nil {
+			http.Error(w, "Invalid JSON body", http.StatusBadRequest)
+			return
+		}
+	}
+
+	isPassedValidation := lib.ValidateRequest(h.validate, body, w)
+
+	if !isPassedValidation {
+		return
+	}
+
+	client, clientOk := lib.ClientFromContext(r.Context())
+
+	if !clientOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	ledger, ledgerOk := lib.LedgerFromContext(r.Context())
+
+	if !ledgerOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	credentialID, _ := lib.CredentialFromContext(r.Context())
+
+	hold, err := h.service.CaptureHold(r.Context(), services.CaptureHoldInput{
+		ClientID: client.ID.String(),
+		LedgerID: ledger.ID.String(),
+		ActorID:  credentialID,
+		ID:       chi.URLParam(r, "hold_id"),
+		Amount:   body.Amount,
+	})
+	if err != nil {
+		writeHoldError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]any{
+		"data": transformations.DBHoldToRestHold(hold, &[]string{"JournalEntry"}),
+	})
+}
+
+func (h *HoldHandler) VoidHold(w http.ResponseWriter, r *http.Request) {
+	client, clientOk := lib.ClientFromContext(r.Context())
+
+	if !clientOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	ledger, ledgerOk := lib.LedgerFromContext(r.Context())
+
+	if !ledgerOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	hold, err := h.service.VoidHold(r.Context(), services.GetHoldInput{
+		ClientID: client.ID.String(),
+		LedgerID: ledger.ID.String(),
+		ID:       chi.URLParam(r, "hold_id"),
+	})
+	if err != nil {
+		writeHoldError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]any{
+		"data": transformations.DBHoldToRestHold(hold, nil),
+	})
+}
+
+// writeHoldError answers a failed capture or void, 409 when the hold was already settled or its capture awaits
+// approval.
+func writeHoldError(w http.ResponseWriter, err error) {
+	if errors.Is(err, repository.ErrHoldNotPending) || errors.Is(err, repository.ErrHoldCaptureAwaitingApproval) {
+		writeError(w, http.StatusConflict, err.Error())
+		return
+	}
+
+	writePostingError(w, err)
+}
+
+type GetHoldRequest struct {
+	ClientID string    `json:"client_id" validate:"required,uuid4"`
+	ID       string    `json:"id"        validate:"required,uuid4"`
+	Populate *[]string `json:"populate"  validate:"omitempty,dive,oneof=Account DestinationAccount JournalEntry"`
+}
+
+func (h *HoldHandler) GetHold(w http.ResponseWriter, r *http.Request) {
+	client, clientOk := lib.ClientFromContext(r.Context())
+
+	if !clientOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	ledger, ledgerOk := lib.LedgerFromContext(r.Context())
+
+	if !ledgerOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	input := GetHoldRequest{
+		ClientID: client.ID.String(),
+		ID:       chi.URLParam(r, "hold_id"),
+		Populate: getPopulateFields(r),
+	}
+
+	isPassedValidation := lib.ValidateRequest(h.validate, input, w)
+
+	if !isPassedValidation {
+		return
+	}
+
+	hold, err := h.service.GetHold(r.Context(), services.GetHoldInput{
+		ClientID: input.ClientID,
+		LedgerID: ledger.ID.String(),
+		ID:       input.ID,
+		Populate: input.Populate,
+	})
+	if err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]any{
+		"data": transformations.DBHoldToRestHold(hold, input.Populate),
+	})
+}
+
+type ListHoldsFilterRequest struct {
+	ClientID  string    `json:"client_id"  validate:"required,uuid4"`
+	AccountID *string   `json:"account_id" validate:"omitempty,uuid4"`
+	Status    *string   `json:"status"     validate:"omitempty,oneof=PENDING CAPTURED VOIDED EXPIRED"`
+	Populate  *[]string `json:"populate"   validate:"omitempty,dive,oneof=Account DestinationAccount JournalEntry"`
+}
+
+func (h *HoldHandler) ListHolds(w http.ResponseWriter, r *http.Request) {
+	client, clientOk := lib.ClientFromContext(r.Context())
+
+	if !clientOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	ledger, ledgerOk := lib.LedgerFromContext(r.Context())
+
+	if !ledgerOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	filters := ListHoldsFilterRequest{
+		ClientID:  client.ID.String(),
+		AccountID: lib.NullOrString(r.URL.Query().Get("account_id")),
+		Status:    lib.NullOrString(r.URL.Query().Get("status")),
+		Populate:  getPopulateFields(r),
+	}
+
+	isFiltersPassedValidation := lib.ValidateRequest(h.validate, filters, w)
+	if !isFiltersPassedValidation {
+		return
+	}
+
+	filterQuery, filterErr := lib.GenerateQuery(r.URL.Query(), repository.HoldQueryFields)
+	if filterErr != nil {
+		writeError(w, http.StatusBadRequest, filterErr.Error())
+		return
+	}
+
+	isFilterQueryPassedValidation := lib.ValidateRequest(h.validate, filterQuery, w)
+	if !isFilterQueryPassedValidation {
+		return
+	}
+
+	listFilters := repository.ListHoldsFilter{
+		ClientId:  filters.ClientID,
+		LedgerId:  ledger.ID.String(),
+		AccountId: filters.AccountID,
+		Status:    filters.Status,
+	}
+
+	holds, holdsErr := h.service.ListHolds(r.Context(), *filterQuery, listFilters)
+	if holdsErr != nil {
+		writeError(w, http.StatusNotFound, holdsErr.Error())
+		return
+	}
+
+	count, countsErr := h.service.CountHolds(r.Context(), *filterQuery, listFilters)
+	if countsErr != nil {
+		writeError(w, http.StatusNotFound, countsErr.Error())
+		return
+	}
+
+	holdsTransformed := make([]interface{}, 0)
+	for _, hold := range holds {
+		holdsTransformed = append(holdsTransformed, transformations.DBHoldToRestHold(&hold, filterQuery.Populate))
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]any{
+		"data": holdsTransformed,
+		"meta": map[string]any{
+			"page":              filterQuery.Page,
+			"page_size":         filterQuery.PageSize,
+			"order":             filterQuery.Order,
+			"order_by":          filterQuery.OrderBy,
+			"total":             count,
+			"has_next_page":     (filterQuery.Page * filterQuery.PageSize) < int(count),
+			"has_previous_page": filterQuery.Page > 1,
+		},
+	})
+}
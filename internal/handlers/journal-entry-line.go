@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/Bendomey/fincore-engine/internal/lib"
+	"github.com/Bendomey/fincore-engine/internal/models"
+	"github.com/Bendomey/fincore-engine/internal/repository"
+	"github.com/Bendomey/fincore-engine/internal/services"
+	"github.com/Bendomey/fincore-engine/internal/transformations"
+	"github.com/go-playground/validator/v10"
+)
+
+type JournalEntryLineHandler struct {
+	service  services.JournalEntryLineService
+	validate *validator.Validate
+}
+
+func NewJournalEntryLineHandler(
+	service services.JournalEntryLineService,
+	validate *validator.Validate,
+) JournalEntryLineHandler {
+	return JournalEntryLineHandler{service, validate}
+}
+
+type ListJournalEntryLinesFilterRequest struct {
+	ClientID  string    `json:"client_id"  validate:"required,uuid4"`
+	AccountID *string   `json:"account_id" validate:"omitempty,uuid4"`
+	Side      *string   `json:"side"       validate:"omitempty,oneof=DEBIT CREDIT"`
+	MinAmount *string   `json:"min_amount" validate:"omitempty,number"`
+	MaxAmount *string   `json:"max_amount" validate:"omitempty,number"`
+	Status    *string   `json:"status"     validate:"omitempty,oneof=DRAFT PENDING_APPROVAL POSTED REVERSED VOIDED"`
+	Notes     *string   `json:"notes"      validate:"omitempty,max=255"`
+	Populate  *[]string `json:"populate"   validate:"omitempty,dive,oneof=Account JournalEntry DimensionValues"`
+}
+
+// ListJournalEntryLines lists lines across the entries of the selected ledger. Filtering on a group account matches
+// the lines of every account below it, and start_date/end_date are on the entries' transaction date.
+func (h *JournalEntryLineHandler) ListJournalEntryLines(w http.ResponseWriter, r *http.Request) {
+	client, clientOk := lib.ClientFromContext(r.Context())
+
+	if !clientOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	ledger, ledgerOk := lib.LedgerFromContext(r.Context())
+
+	if !ledgerOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	filters := ListJournalEntryLinesFilterRequest{
+		ClientID:  client.ID.String(),
+		AccountID: lib.NullOrString(r.URL.Query().Get("account_id")),
+		Side:      lib.NullOrString(r.URL.Query().Get("side")),
+		MinAmount: lib.NullOrString(r.URL.Query().Get("min_amount")),
+		MaxAmount: lib.NullOrString(r.URL.Query().Get("max_amount")),
+		Status:    lib.NullOrString(r.URL.Query().Get("status")),
+		Notes:     lib.NullOrString(r.URL.Query().Get("notes")),
+		Populate:  getPopulateFields(r),
+	}
+
+	isFiltersPassedValidation := lib.ValidateRequest(h.validate, filters, w)
+	if !isFiltersPassedValidation {
+		return
+	}
+
+	filterQuery, filterErr := lib.GenerateQuery(r.URL.Query(), repository.JournalEntryLineQueryFields)
+	if filterErr != nil {
+		writeError(w, http.StatusBadRequest, filterErr.Error())
+		return
+	}
+
+	isFilterQueryPassedValidation := lib.ValidateRequest(h.validate, filterQuery, w)
+	if !isFilterQueryPassedValidation {
+		return
+	}
+
+	minAmount, minErr := parseAmountFilter(filters.MinAmount)
+	maxAmount, maxErr := parseAmountFilter(filters.MaxAmount)
+	if minErr != nil || maxErr != nil {
+		writeError(w, http.StatusBadRequest, "min_amount and max_amount must be whole amounts in minor units")
+		return
+	}
+
+	listFilters := repository.ListJournalEntryLinesFilter{
+		ClientId:  filters.ClientID,
+		LedgerId:  ledger.ID.String(),
+		AccountId: filters.AccountID,
+		Side:      filters.Side,
+		MinAmount: minAmount,
+		MaxAmount: maxAmount,
+		Status:    filters.Status,
+		Notes:     filters.Notes,
+		DateRange: filterQuery.DateRange,
+	}
+
+	journalEntryLines, linesErr := h.service.ListJournalEntryLines(r.Context(), *filterQuery, listFilters)
+	if linesErr != nil {
+		writeError(w, http.StatusNotFound, linesErr.Error())
+		return
+	}
+
+	journalEntryLines, nextCursor := cursorPage(
+		*filterQuery,
+		journalEntryLines,
+		func(journalEntryLine models.JournalEntryLine) lib.Cursor {
+			return lib.Cursor{CreatedAt: journalEntryLine.CreatedAt, ID: journalEntryLine.ID.String()}
+		},
+	)
+
+	var total *int64
+	if shouldCount(*filterQuery) {
+		count, countsErr := h.service.CountJournalEntryLines(r.Context(), *filterQuery, listFilters)
+		if countsErr != nil {
+			writeError(w, http.StatusNotFound, countsErr.Error())
+			return
+		}
+
+		total = &count
+	}
+
+	journalEntryLinesTransformed := make([]interface{}, 0)
+	for _, journalEntryLine := range journalEntryLines {
+		journalEntryLinesTransformed = append(
+			journalEntryLinesTransformed,
+			transformations.DBJournalEntryLineToRestJournalEntryLineRow(&journalEntryLine, filterQuery.Populate),
+		)
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]any{
+		"data": journalEntryLinesTransformed,
+		"meta": listMeta(*filterQuery, total, nextCursor),
+	})
+}
+
+func parseAmountFilter(amount *string) (*int64, error) {
+	if amount == nil {
+		return nil, nil
+	}
+
+	parsed, err := strconv.ParseInt(*amount, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	return &parsed, nil
+}
@@ -1,10 +1,12 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 
 	"github.com/Bendomey/fincore-engine/internal/lib"
+	"github.com/Bendomey/fincore-engine/internal/models"
 	"github.com/Bendomey/fincore-engine/internal/repository"
 	"github.com/Bendomey/fincore-engine/internal/services"
 	"github.com/Bendomey/fincore-engine/internal/transformations"
@@ -22,16 +24,18 @@ func NewJournalEntryHandler(service services.JournalEntryService, validate *vali
 }
 
 type CreateJournalEntryLineInput struct {
-	AccountID string  `json:"account_id" validate:"required,uuid4"`
-	Notes     *string `json:"notes"      validate:"omitempty,max=1024"`
-	Debit     int64   `json:"debit"      validate:"number,min=0"`
-	Credit    int64   `json:"credit"     validate:"number,min=0"`
+	AccountID  string            `json:"account_id" validate:"required,uuid4"`
+	Notes      *string           `json:"notes"      validate:"omitempty,max=1024"`
+	Debit      int64             `json:"debit"      validate:"number,min=0"`
+	Credit     int64             `json:"credit"     validate:"number,min=0"`
+	Dimensions map[string]string `json:"dimensions" validate:"omitempty,dive,keys,required,max=64,endkeys,required,max=64"`
 }
 
 type CreateJournalEntryRequest struct {
-	Status          string                        `json:"status"           validate:"required,oneof=DRAFT POSTED"`
+	Status          string                        `json:"status"           validate:"required,oneof=DRAFT PENDING_APPROVAL POSTED"`
 	Reference       string                        `json:"reference"        validate:"required,min=3,max=255"`
 	TransactionDate *string                       `json:"transaction_date" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	Currency        *string                       `json:"currency"         validate:"omitempty,len=3,uppercase"`
 	Metadata        *map[string]interface{}       `json:"metadata"         validate:"omitempty"`
 	Lines           []CreateJournalEntryLineInput `json:"lines"            validate:"required,min=2,dive"`
 }
@@ -56,13 +60,46 @@ func (h *JournalEntryHandler) CreateJournalEntry(w http.ResponseWriter, r *http.
 		return
 	}
 
+	ledger, ledgerOk := lib.LedgerFromContext(r.Context())
+
+	if !ledgerOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	// the route only asks for journal_entries:write, posting straight away takes journal_entries:post as well.
+	canPost := lib.HasScope(r.Context(), models.ApiKeyScopeJournalEntriesPost)
+	if body.Status == models.JournalEntryStatusPosted && !canPost {
+		w.WriteHeader(http.StatusForbidden)
+		json.NewEncoder(w).Encode(map[string]any{
+			"errors": map[string]string{
+				"message": "the credential used is missing the " + models.ApiKeyScopeJournalEntriesPost + " scope",
+			},
+		})
+		return
+	}
+
+	// amounts are in the ledger's currency, the currency sent only guards against amounts of another scale.
+	if body.Currency != nil && *body.Currency != ledger.Currency {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]any{
+			"errors": map[string]string{
+				"message": "amounts of this ledger are in " + ledger.Currency,
+			},
+		})
+		return
+	}
+
+	credentialID, _ := lib.CredentialFromContext(r.Context())
+
 	lines := make([]services.CreateJournalEntryLineInput, 0)
 	for _, line := range body.Lines {
 		lines = append(lines, services.CreateJournalEntryLineInput{
-			AccountID: line.AccountID,
-			Notes:     line.Notes,
-			Debit:     line.Debit,
-			Credit:    line.Credit,
+			AccountID:  line.AccountID,
+			Notes:      line.Notes,
+			Debit:      line.Debit,
+			Credit:     line.Credit,
+			Dimensions: line.Dimensions,
 		})
 	}
 
@@ -74,14 +111,12 @@ func (h *JournalEntryHandler) CreateJournalEntry(w http.ResponseWriter, r *http.
 		Lines:           lines,
 
 		ClientID: client.ID.String(),
+		LedgerID: ledger.ID.String(),
+		ActorID:  credentialID,
+		Currency: ledger.Currency,
 	})
 	if err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]any{
-			"errors": map[string]string{
-				"message": err.Error(),
-			},
-		})
+		writePostingError(w, err)
 		return
 	}
 
@@ -92,11 +127,12 @@ func (h *JournalEntryHandler) CreateJournalEntry(w http.ResponseWriter, r *http.
 }
 
 type UpdateJournalEntryLineInput struct {
-	ID        *string `json:"id"         validate:"omitempty,uuid4"`
-	AccountID *string `json:"account_id" validate:"omitempty,uuid4"`
-	Notes     *string `json:"notes"      validate:"omitempty,max=1024"`
-	Debit     *int64  `json:"debit"      validate:"omitempty,number,min=0"`
-	Credit    *int64  `json:"credit"     validate:"omitempty,number,min=0"`
+	ID         *string            `json:"id"         validate:"omitempty,uuid4"`
+	AccountID  *string            `json:"account_id" validate:"omitempty,uuid4"`
+	Notes      *string            `json:"notes"      validate:"omitempty,max=1024"`
+	Debit      *int64             `json:"debit"      validate:"omitempty,number,min=0"`
+	Credit     *int64             `json:"credit"     validate:"omitempty,number,min=0"`
+	Dimensions *map[string]string `json:"dimensions" validate:"omitempty,dive,keys,required,max=64,endkeys,required,max=64"`
 }
 
 type UpdateJournalEntryRequest struct {
@@ -126,15 +162,28 @@ func (h *JournalEntryHandler) UpdateJournalEntry(w http.ResponseWriter, r *http.
 		return
 	}
 
+	ledger, ledgerOk := lib.LedgerFromContext(r.Context())
+
+	if !ledgerOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	version, versionOk := getIfMatchVersion(w, r)
+	if !versionOk {
+		return
+	}
+
 	lines := make([]services.UpdateJournalEntryLineInput, 0)
 	if body.Lines != nil {
 		for _, line := range *body.Lines {
 			lines = append(lines, services.UpdateJournalEntryLineInput{
-				ID:        line.ID,
-				AccountID: line.AccountID,
-				Notes:     line.Notes,
-				Debit:     line.Debit,
-				Credit:    line.Credit,
+				ID:         line.ID,
+				AccountID:  line.AccountID,
+				Notes:      line.Notes,
+				Debit:      line.Debit,
+				Credit:     line.Credit,
+				Dimensions: line.Dimensions,
 			})
 		}
 	}
@@ -150,25 +199,72 @@ func (h *JournalEntryHandler) UpdateJournalEntry(w http.ResponseWriter, r *http.
 			Lines:           &lines,
 
 			ClientID: client.ID.String(),
+			LedgerID: ledger.ID.String(),
+			Version:  version,
 		},
 	)
 	if err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]any{
-			"errors": map[string]string{
-				"message": err.Error(),
-			},
-		})
+		writeUpdateError(w, err)
 		return
 	}
 
+	w.Header().Set("ETag", lib.ETag(journalEntry.Version))
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(map[string]any{
 		"data": transformations.DBJournalEntryToRestJournalEntry(journalEntry, nil),
 	})
 }
 
+func (h *JournalEntryHandler) SubmitJournalEntry(w http.ResponseWriter, r *http.Request) {
+	h.transitionJournalEntry(w, r, h.service.SubmitJournalEntry)
+}
+
 func (h *JournalEntryHandler) PostJournalEntry(w http.ResponseWriter, r *http.Request) {
+	h.transitionJournalEntry(w, r, h.service.PostJournalEntry)
+}
+
+func (h *JournalEntryHandler) ApproveJournalEntry(w http.ResponseWriter, r *http.Request) {
+	h.transitionJournalEntry(w, r, h.service.ApproveJournalEntry)
+}
+
+func (h *JournalEntryHandler) RejectJournalEntry(w http.ResponseWriter, r *http.Request) {
+	h.transitionJournalEntry(w, r, h.service.RejectJournalEntry)
+}
+
+func (h *JournalEntryHandler) VoidJournalEntry(w http.ResponseWriter, r *http.Request) {
+	h.transitionJournalEntry(w, r, h.service.VoidJournalEntry)
+}
+
+// ReverseJournalEntry responds with the entry posted to reverse the given one.
+func (h *JournalEntryHandler) ReverseJournalEntry(w http.ResponseWriter, r *http.Request) {
+	h.transitionJournalEntry(w, r, h.service.ReverseJournalEntry)
+}
+
+type TransitionJournalEntryRequest struct {
+	Reason *string `json:"reason" validate:"omitempty,max=1024"`
+}
+
+// transitionJournalEntry runs a status transition on behalf of the credential that authenticated the request.
+// The body, with an optional reason, may be omitted.
+func (h *JournalEntryHandler) transitionJournalEntry(
+	w http.ResponseWriter,
+	r *http.Request,
+	transition func(ctx context.Context, input services.TransitionJournalEntryInput) (*models.JournalEntry, error),
+) {
+	var body TransitionJournalEntryRequest
+	if r.ContentLength > 0 {
+		if decodeErr := json.NewDecoder(r.Body).Decode(&body); decodeErr != nil {
+			http.Error(w, "Invalid JSON body", http.StatusBadRequest)
+			return
+		}
+	}
+
+	isPassedValidation := lib.ValidateRequest(h.validate, body, w)
+
+	if !isPassedValidation {
+		return
+	}
+
 	client, clientOk := lib.ClientFromContext(r.Context())
 
 	if !clientOk {
@@ -176,26 +272,35 @@ func (h *JournalEntryHandler) PostJournalEntry(w http.ResponseWriter, r *http.Re
 		return
 	}
 
-	journalEntry, err := h.service.PostJournalEntry(r.Context(), services.GetJournalEntryInput{
+	ledger, ledgerOk := lib.LedgerFromContext(r.Context())
+
+	if !ledgerOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	credentialID, _ := lib.CredentialFromContext(r.Context())
+
+	journalEntry, err := transition(r.Context(), services.TransitionJournalEntryInput{
 		ClientID: client.ID.String(),
+		LedgerID: ledger.ID.String(),
 		ID:       chi.URLParam(r, "journal_entry_id"),
+		ActorID:  credentialID,
+		Reason:   body.Reason,
 	})
 	if err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]any{
-			"errors": map[string]string{
-				"message": err.Error(),
-			},
-		})
+		writeUpdateError(w, err)
 		return
 	}
 
+	w.Header().Set("ETag", lib.ETag(journalEntry.Version))
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(map[string]any{
 		"data": transformations.DBJournalEntryToRestJournalEntry(journalEntry, nil),
 	})
 }
 
+// DeleteJournalEntry voids the entry, it is kept for audit rather than deleted.
 func (h *JournalEntryHandler) DeleteJournalEntry(w http.ResponseWriter, r *http.Request) {
 	client, clientOk := lib.ClientFromContext(r.Context())
 
@@ -204,9 +309,20 @@ func (h *JournalEntryHandler) DeleteJournalEntry(w http.ResponseWriter, r *http.
 		return
 	}
 
-	err := h.service.DeleteJournalEntry(r.Context(), services.GetJournalEntryInput{
+	ledger, ledgerOk := lib.LedgerFromContext(r.Context())
+
+	if !ledgerOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	credentialID, _ := lib.CredentialFromContext(r.Context())
+
+	_, err := h.service.VoidJournalEntry(r.Context(), services.TransitionJournalEntryInput{
 		ClientID: client.ID.String(),
+		LedgerID: ledger.ID.String(),
 		ID:       chi.URLParam(r, "journal_entry_id"),
+		ActorID:  credentialID,
 	})
 	if err != nil {
 		w.WriteHeader(http.StatusBadRequest)
@@ -225,7 +341,7 @@ func (h *JournalEntryHandler) DeleteJournalEntry(w http.ResponseWriter, r *http.
 type GetJournalEntryRequest struct {
 	ClientID string    `json:"client_id" validate:"required,uuid4"`
 	ID       string    `json:"id"        validate:"required,uuid4"`
-	Populate *[]string `json:"populate"  validate:"omitempty,dive,oneof=JournalEntryLines, Account"`
+	Populate *[]string `json:"populate"  validate:"omitempty,dive,oneof=JournalEntryLines Account JournalEntryLines.DimensionValues JournalEntryTransitions"`
 }
 
 func (h *JournalEntryHandler) GetJournalEntry(w http.ResponseWriter, r *http.Request) {
@@ -236,6 +352,13 @@ func (h *JournalEntryHandler) GetJournalEntry(w http.ResponseWriter, r *http.Req
 		return
 	}
 
+	ledger, ledgerOk := lib.LedgerFromContext(r.Context())
+
+	if !ledgerOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
 	input := GetJournalEntryRequest{
 		ClientID: client.ID.String(),
 		ID:       chi.URLParam(r, "journal_entry_id"),
@@ -250,6 +373,7 @@ func (h *JournalEntryHandler) GetJournalEntry(w http.ResponseWriter, r *http.Req
 
 	journalEntry, err := h.service.GetJournalEntry(r.Context(), services.GetJournalEntryInput{
 		ClientID: input.ClientID,
+		LedgerID: ledger.ID.String(),
 		ID:       input.ID,
 		Populate: input.Populate,
 	})
@@ -263,6 +387,7 @@ func (h *JournalEntryHandler) GetJournalEntry(w http.ResponseWriter, r *http.Req
 		return
 	}
 
+	w.Header().Set("ETag", lib.ETag(journalEntry.Version))
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(map[string]any{
 		"data": transformations.DBJournalEntryToRestJournalEntry(journalEntry, input.Populate),
@@ -271,7 +396,7 @@ func (h *JournalEntryHandler) GetJournalEntry(w http.ResponseWriter, r *http.Req
 
 type ListJournalEntriesFilterRequest struct {
 	ClientID string  `json:"client_id" validate:"required,uuid4"`
-	Status   *string `json:"status"    validate:"omitempty,oneof=DRAFT POSTED"`
+	Status   *string `json:"status"    validate:"omitempty,oneof=DRAFT PENDING_APPROVAL POSTED REVERSED VOIDED"`
 }
 
 func (h *JournalEntryHandler) ListJournalEntries(w http.ResponseWriter, r *http.Request) {
@@ -282,6 +407,13 @@ func (h *JournalEntryHandler) ListJournalEntries(w http.ResponseWriter, r *http.
 		return
 	}
 
+	ledger, ledgerOk := lib.LedgerFromContext(r.Context())
+
+	if !ledgerOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
 	filters := ListJournalEntriesFilterRequest{
 		ClientID: client.ID.String(),
 		Status:   lib.NullOrString(r.URL.Query().Get("status")),
@@ -292,7 +424,7 @@ func (h *JournalEntryHandler) ListJournalEntries(w http.ResponseWriter, r *http.
 		return
 	}
 
-	filterQuery, filterErr := lib.GenerateQuery(r.URL.Query())
+	filterQuery, filterErr := lib.GenerateQuery(r.URL.Query(), repository.JournalEntryQueryFields)
 	if filterErr != nil {
 		w.WriteHeader(http.StatusBadRequest)
 		json.NewEncoder(w).Encode(map[string]any{
@@ -303,6 +435,12 @@ func (h *JournalEntryHandler) ListJournalEntries(w http.ResponseWriter, r *http.
 		return
 	}
 
+	metadataFilters, metadataErr := getMetadataFilters(r)
+	if metadataErr != nil {
+		writeError(w, http.StatusBadRequest, metadataErr.Error())
+		return
+	}
+
 	isFilterQueryPassedValidation := lib.ValidateRequest(h.validate, filterQuery, w)
 	if !isFilterQueryPassedValidation {
 		return
@@ -313,7 +451,9 @@ func (h *JournalEntryHandler) ListJournalEntries(w http.ResponseWriter, r *http.
 		*filterQuery,
 		repository.ListJournalEntriesFilter{
 			ClientId: filters.ClientID,
+			LedgerId: ledger.ID.String(),
 			Status:   filters.Status,
+			Metadata: metadataFilters,
 		},
 	)
 
@@ -327,19 +467,38 @@ func (h *JournalEntryHandler) ListJournalEntries(w http.ResponseWriter, r *http.
 		return
 	}
 
-	count, countsErr := h.service.CountJournalEntries(r.Context(), *filterQuery, repository.ListJournalEntriesFilter{
-		ClientId: filters.ClientID,
-		Status:   filters.Status,
-	})
+	journalEntries, nextCursor := cursorPage(
+		*filterQuery,
+		journalEntries,
+		func(journalEntry models.JournalEntry) lib.Cursor {
+			return lib.Cursor{CreatedAt: journalEntry.CreatedAt, ID: journalEntry.ID.String()}
+		},
+	)
 
-	if countsErr != nil {
-		w.WriteHeader(http.StatusNotFound)
-		json.NewEncoder(w).Encode(map[string]any{
-			"errors": map[string]string{
-				"message": countsErr.Error(),
+	var total *int64
+	if shouldCount(*filterQuery) {
+		count, countsErr := h.service.CountJournalEntries(
+			r.Context(),
+			*filterQuery,
+			repository.ListJournalEntriesFilter{
+				ClientId: filters.ClientID,
+				LedgerId: ledger.ID.String(),
+				Status:   filters.Status,
+				Metadata: metadataFilters,
 			},
-		})
-		return
+		)
+
+		if countsErr != nil {
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(map[string]any{
+				"errors": map[string]string{
+					"message": countsErr.Error(),
+				},
+			})
+			return
+		}
+
+		total = &count
 	}
 
 	journalEntriesTransformed := make([]interface{}, 0)
@@ -353,14 +512,6 @@ func (h *JournalEntryHandler) ListJournalEntries(w http.ResponseWriter, r *http.
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(map[string]any{
 		"data": journalEntriesTransformed,
-		"meta": map[string]any{
-			"page":              filterQuery.Page,
-			"page_size":         filterQuery.PageSize,
-			"order":             filterQuery.Order,
-			"order_by":          filterQuery.OrderBy,
-			"total":             count,
-			"has_next_page":     (filterQuery.Page * filterQuery.PageSize) < int(count),
-			"has_previous_page": filterQuery.Page > 1,
-		},
+		"meta": listMeta(*filterQuery, total, nextCursor),
 	})
 }
@@ -0,0 +1,293 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/Bendomey/fincore-engine/internal/lib"
+	"github.com/Bendomey/fincore-engine/internal/repository"
+	"github.com/Bendomey/fincore-engine/internal/services"
+	"github.com/Bendomey/fincore-engine/internal/transformations"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+)
+
+type LedgerHandler struct {
+	service  services.LedgerService
+	validate *validator.Validate
+}
+
+func NewLedgerHandler(service services.LedgerService, validate *validator.Validate) LedgerHandler {
+	return LedgerHandler{service, validate}
+}
+
+type CreateLedgerRequest struct {
+	Name                 string  `json:"name"                    validate:"required,min=3,max=255"`
+	Description          *string `json:"description"             validate:"omitempty,max=1024"`
+	Currency             string  `json:"currency"                validate:"required,len=3,uppercase"`
+	FiscalYearStartMonth *int    `json:"fiscal_year_start_month" validate:"omitempty,min=1,max=12"`
+	IsDefault            bool    `json:"is_default"              validate:"boolean"`
+}
+
+func (h *LedgerHandler) CreateLedger(w http.ResponseWriter, r *http.Request) {
+	var body CreateLedgerRequest
+	if decodeErr := json.NewDecoder(r.Body).Decode(&body); decodeErr != nil {
+		http.Error(w, "Invalid JSON body", http.StatusBadRequest)
+		return
+	}
+
+	isPassedValidation := lib.ValidateRequest(h.validate, body, w)
+
+	if !isPassedValidation {
+		return
+	}
+
+	client, clientOk := lib.ClientFromContext(r.Context())
+
+	if !clientOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	ledger, err := h.service.CreateLedger(r.Context(), services.CreateLedgerInput{
+		ClientID:             client.ID.String(),
+		Name:                 body.Name,
+		Description:          body.Description,
+		Currency:             body.Currency,
+		FiscalYearStartMonth: body.FiscalYearStartMonth,
+		IsDefault:            body.IsDefault,
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]any{
+			"errors": map[string]string{
+				"message": err.Error(),
+			},
+		})
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]any{
+		"data": transformations.DBLedgerToRestLedger(ledger),
+	})
+}
+
+type UpdateLedgerRequest struct {
+	Name                 *string `json:"name"                    validate:"omitempty,min=3,max=255"`
+	Description          *string `json:"description"             validate:"omitempty,max=1024"`
+	Currency             *string `json:"currency"                validate:"omitempty,len=3,uppercase"`
+	FiscalYearStartMonth *int    `json:"fiscal_year_start_month" validate:"omitempty,min=1,max=12"`
+	IsDefault            *bool   `json:"is_default"              validate:"omitempty,boolean"`
+}
+
+func (h *LedgerHandler) UpdateLedger(w http.ResponseWriter, r *http.Request) {
+	var body UpdateLedgerRequest
+	if decodeErr := json.NewDecoder(r.Body).Decode(&body); decodeErr != nil {
+		http.Error(w, "Invalid JSON body", http.StatusBadRequest)
+		return
+	}
+
+	isPassedValidation := lib.ValidateRequest(h.validate, body, w)
+
+	if !isPassedValidation {
+		return
+	}
+
+	client, clientOk := lib.ClientFromContext(r.Context())
+
+	if !clientOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	ledger, err := h.service.UpdateLedger(r.Context(), services.UpdateLedgerInput{
+		ClientID:             client.ID.String(),
+		ID:                   chi.URLParam(r, "ledger_id"),
+		Name:                 body.Name,
+		Description:          body.Description,
+		Currency:             body.Currency,
+		FiscalYearStartMonth: body.FiscalYearStartMonth,
+		IsDefault:            body.IsDefault,
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]any{
+			"errors": map[string]string{
+				"message": err.Error(),
+			},
+		})
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]any{
+		"data": transformations.DBLedgerToRestLedger(ledger),
+	})
+}
+
+func (h *LedgerHandler) DeleteLedger(w http.ResponseWriter, r *http.Request) {
+	client, clientOk := lib.ClientFromContext(r.Context())
+
+	if !clientOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	err := h.service.DeleteLedger(r.Context(), services.GetLedgerInput{
+		ClientID: client.ID.String(),
+		ID:       chi.URLParam(r, "ledger_id"),
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]any{
+			"errors": map[string]string{
+				"message": err.Error(),
+			},
+		})
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	json.NewEncoder(w).Encode(map[string]any{})
+}
+
+type GetLedgerRequest struct {
+	ClientID string `json:"client_id" validate:"required,uuid4"`
+	ID       string `json:"id"        validate:"required,uuid4"`
+}
+
+func (h *LedgerHandler) GetLedger(w http.ResponseWriter, r *http.Request) {
+	client, clientOk := lib.ClientFromContext(r.Context())
+
+	if !clientOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	input := GetLedgerRequest{
+		ClientID: client.ID.String(),
+		ID:       chi.URLParam(r, "ledger_id"),
+	}
+
+	isPassedValidation := lib.ValidateRequest(h.validate, input, w)
+
+	if !isPassedValidation {
+		return
+	}
+
+	ledger, err := h.service.GetLedger(r.Context(), services.GetLedgerInput{
+		ClientID: input.ClientID,
+		ID:       input.ID,
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]any{
+			"errors": map[string]string{
+				"message": err.Error(),
+			},
+		})
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]any{
+		"data": transformations.DBLedgerToRestLedger(ledger),
+	})
+}
+
+func (h *LedgerHandler) ListLedgers(w http.ResponseWriter, r *http.Request) {
+	client, clientOk := lib.ClientFromContext(r.Context())
+
+	if !clientOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	filterQuery, filterErr := lib.GenerateQuery(r.URL.Query(), repository.LedgerQueryFields)
+	if filterErr != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]any{
+			"errors": map[string]string{
+				"message": filterErr.Error(),
+			},
+		})
+		return
+	}
+
+	isFilterQueryPassedValidation := lib.ValidateRequest(h.validate, filterQuery, w)
+	if !isFilterQueryPassedValidation {
+		return
+	}
+
+	listFilters := repository.ListLedgersFilter{
+		ClientId: client.ID.String(),
+	}
+
+	ledgers, ledgersErr := h.service.ListLedgers(r.Context(), *filterQuery, listFilters)
+	if ledgersErr != nil {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]any{
+			"errors": map[string]string{
+				"message": ledgersErr.Error(),
+			},
+		})
+		return
+	}
+
+	count, countsErr := h.service.CountLedgers(r.Context(), *filterQuery, listFilters)
+	if countsErr != nil {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]any{
+			"errors": map[string]string{
+				"message": countsErr.Error(),
+			},
+		})
+		return
+	}
+
+	ledgersTransformed := make([]interface{}, 0)
+	for _, ledger := range ledgers {
+		ledgersTransformed = append(ledgersTransformed, transformations.DBLedgerToRestLedger(&ledger))
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]any{
+		"data": ledgersTransformed,
+		"meta": map[string]any{
+			"page":              filterQuery.Page,
+			"page_size":         filterQuery.PageSize,
+			"order":             filterQuery.Order,
+			"order_by":          filterQuery.OrderBy,
+			"total":             count,
+			"has_next_page":     (filterQuery.Page * filterQuery.PageSize) < int(count),
+			"has_previous_page": filterQuery.Page > 1,
+		},
+	})
+}
+
+// VerifyLedger verifies the hash chain of the ledger the request selected.
+func (h *LedgerHandler) VerifyLedger(w http.ResponseWriter, r *http.Request) {
+	ledger, ledgerOk := lib.LedgerFromContext(r.Context())
+
+	if !ledgerOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	verification, err := h.service.VerifyLedger(r.Context(), ledger.ID.String())
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]any{
+			"errors": map[string]string{
+				"message": err.Error(),
+			},
+		})
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]any{
+		"data": transformations.LedgerVerificationToRestLedgerVerification(verification),
+	})
+}
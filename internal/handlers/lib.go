@@ -1,8 +1,17 @@
 package handlers
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
+
+	"github.com/Bendomey/fincore-engine/internal/lib"
+	"github.com/Bendomey/fincore-engine/internal/repository"
 )
 
 func getPopulateFields(r *http.Request) *[]string {
@@ -16,3 +25,174 @@ func getPopulateFields(r *http.Request) *[]string {
 
 	return populateFields
 }
+
+// getDimensionFilters reads `dimension[<code>]=<value>,<value>` query params into a dimension code => value codes map.
+func getDimensionFilters(r *http.Request) map[string][]string {
+	dimensions := make(map[string][]string)
+
+	for key, values := range r.URL.Query() {
+		if !strings.HasPrefix(key, "dimension[") || !strings.HasSuffix(key, "]") {
+			continue
+		}
+
+		code := strings.TrimSuffix(strings.TrimPrefix(key, "dimension["), "]")
+		for _, value := range values {
+			for _, valueCode := range strings.Split(value, ",") {
+				if valueCode != "" {
+					dimensions[code] = append(dimensions[code], valueCode)
+				}
+			}
+		}
+	}
+
+	return dimensions
+}
+
+// metadataParamPattern matches `metadata[<key>]` and `metadata_has[<key>]` params, with a [<key>] per nesting level.
+var metadataParamPattern = regexp.MustCompile(`^(metadata|metadata_has)((?:\[[^\[\]]+\])+)$`)
+
+// maxMetadataDepth bounds how deep in the metadata a filter may reach.
+const maxMetadataDepth = 8
+
+// getMetadataFilters reads `metadata[<key>][<key>]=<value>` exact match and `metadata_has[<key>]=true|false` key
+// existence query params.
+func getMetadataFilters(r *http.Request) ([]repository.MetadataFilter, error) {
+	query := r.URL.Query()
+
+	keys := make([]string, 0)
+	for key := range query {
+		if strings.HasPrefix(key, "metadata[") || strings.HasPrefix(key, "metadata_has[") {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Strings(keys)
+
+	filters := make([]repository.MetadataFilter, 0)
+	for _, key := range keys {
+		match := metadataParamPattern.FindStringSubmatch(key)
+		if match == nil {
+			return nil, fmt.Errorf("invalid metadata filter %s", key)
+		}
+
+		path := strings.Split(strings.TrimSuffix(strings.TrimPrefix(match[2], "["), "]"), "][")
+		if len(path) > maxMetadataDepth {
+			return nil, fmt.Errorf("metadata filters reach %d levels deep at most", maxMetadataDepth)
+		}
+
+		for _, value := range query[key] {
+			filter := repository.MetadataFilter{Path: path, Value: value}
+
+			if match[1] == "metadata_has" {
+				exists, err := strconv.ParseBool(value)
+				if err != nil {
+					return nil, fmt.Errorf("%s must be true or false", key)
+				}
+
+				filter.Exists = &exists
+			}
+
+			filters = append(filters, filter)
+		}
+	}
+
+	return filters, nil
+}
+
+// getIfMatchVersion reads the version the caller expects to be changing from the If-Match header.
+// Updates without it are refused with 428 so nobody overwrites a change they never saw.
+func getIfMatchVersion(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	ifMatch := r.Header.Get("If-Match")
+	if ifMatch == "" {
+		writeError(w, http.StatusPreconditionRequired, "If-Match header is required, send the ETag of the resource")
+		return 0, false
+	}
+
+	version, err := lib.ParseETag(ifMatch)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return 0, false
+	}
+
+	return version, true
+}
+
+// writeUpdateError answers a failed update, 412 when the resource changed since the caller read it.
+func writeUpdateError(w http.ResponseWriter, err error) {
+	if errors.Is(err, repository.ErrVersionConflict) {
+		writeError(w, http.StatusPreconditionFailed, err.Error())
+		return
+	}
+
+	writePostingError(w, err)
+}
+
+// writePostingError answers a failed write that may have posted to the ledger, 422 when an account's balance
+// policy refused it.
+func writePostingError(w http.ResponseWriter, err error) {
+	if errors.Is(err, repository.ErrInsufficientBalance) {
+		writeError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	writeError(w, http.StatusBadRequest, err.Error())
+}
+
+// cursorPage trims the extra row fetched by a cursor paginated list, returning the cursor of the next page, nil on
+// the last one. The cursor carries the list's order so it can't be used to read on in the other.
+func cursorPage[T any](filterQuery lib.FilterQuery, rows []T, cursorOf func(T) lib.Cursor) ([]T, *string) {
+	if filterQuery.Cursor == nil || len(rows) <= filterQuery.PageSize {
+		return rows, nil
+	}
+
+	rows = rows[:filterQuery.PageSize]
+	cursor := cursorOf(rows[len(rows)-1])
+	cursor.Order = filterQuery.Order
+	nextCursor := cursor.Encode()
+
+	return rows, &nextCursor
+}
+
+// shouldCount tells whether a list should count its rows. Counting is skipped for cursor pagination unless asked
+// for, being what makes it cheap on large tables.
+func shouldCount(filterQuery lib.FilterQuery) bool {
+	return filterQuery.Cursor == nil || filterQuery.Cursor.IncludeTotal
+}
+
+// listMeta builds the meta of a list response. total is nil when the rows weren't counted.
+func listMeta(filterQuery lib.FilterQuery, total *int64, nextCursor *string) map[string]any {
+	if filterQuery.Cursor == nil {
+		return map[string]any{
+			"page":              filterQuery.Page,
+			"page_size":         filterQuery.PageSize,
+			"order":             filterQuery.Order,
+			"order_by":          filterQuery.OrderBy,
+			"total":             *total,
+			"has_next_page":     (filterQuery.Page * filterQuery.PageSize) < int(*total),
+			"has_previous_page": filterQuery.Page > 1,
+		}
+	}
+
+	meta := map[string]any{
+		"page_size":     filterQuery.PageSize,
+		"order":         filterQuery.Order,
+		"order_by":      "created_at",
+		"next_cursor":   nextCursor,
+		"has_next_page": nextCursor != nil,
+	}
+
+	if total != nil {
+		meta["total"] = *total
+	}
+
+	return meta
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]any{
+		"errors": map[string]string{
+			"message": message,
+		},
+	})
+}
@@ -6,19 +6,64 @@ import (
 )
 
 type Handlers struct {
-	ClientHandler       ClientHandler
-	AccountHandler      AccountHandler
-	JournalEntryHandler JournalEntryHandler
+	ClientHandler           ClientHandler
+	AccountHandler          AccountHandler
+	JournalEntryHandler     JournalEntryHandler
+	DimensionHandler        DimensionHandler
+	ReportHandler           ReportHandler
+	BudgetHandler           BudgetHandler
+	ApprovalRuleHandler     ApprovalRuleHandler
+	AttachmentHandler       AttachmentHandler
+	AuditEventHandler       AuditEventHandler
+	LedgerHandler           LedgerHandler
+	WebhookHandler          WebhookHandler
+	EventHandler            EventHandler
+	TransferHandler         TransferHandler
+	HoldHandler             HoldHandler
+	JournalEntryLineHandler JournalEntryLineHandler
+	SearchHandler           SearchHandler
+	ApiKeyHandler           ApiKeyHandler
+	OAuthHandler            OAuthHandler
 }
 
 func NewHandlers(services services.Services, validate *validator.Validate) Handlers {
 	clientHandler := NewClientHandler(services.ClientService, validate)
 	accountHandler := NewAccountHandler(services.AccountService, validate)
 	journalEntryHandler := NewJournalEntryHandler(services.JournalEntryService, validate)
+	dimensionHandler := NewDimensionHandler(services.DimensionService, validate)
+	reportHandler := NewReportHandler(services.ReportService, validate)
+	budgetHandler := NewBudgetHandler(services.BudgetService, validate)
+	approvalRuleHandler := NewApprovalRuleHandler(services.ApprovalRuleService, validate)
+	attachmentHandler := NewAttachmentHandler(services.AttachmentService, validate)
+	auditEventHandler := NewAuditEventHandler(services.AuditEventService, validate)
+	ledgerHandler := NewLedgerHandler(services.LedgerService, validate)
+	webhookHandler := NewWebhookHandler(services.WebhookService, validate)
+	eventHandler := NewEventHandler(services.EventService, validate)
+	transferHandler := NewTransferHandler(services.TransferService, validate)
+	holdHandler := NewHoldHandler(services.HoldService, validate)
+	journalEntryLineHandler := NewJournalEntryLineHandler(services.JournalEntryLineService, validate)
+	searchHandler := NewSearchHandler(services.SearchService, validate)
+	apiKeyHandler := NewApiKeyHandler(services.ApiKeyService, validate)
+	oauthHandler := NewOAuthHandler(services.AccessTokenService, validate)
 
 	return Handlers{
-		ClientHandler:       clientHandler,
-		AccountHandler:      accountHandler,
-		JournalEntryHandler: journalEntryHandler,
+		ClientHandler:           clientHandler,
+		AccountHandler:          accountHandler,
+		JournalEntryHandler:     journalEntryHandler,
+		DimensionHandler:        dimensionHandler,
+		ReportHandler:           reportHandler,
+		BudgetHandler:           budgetHandler,
+		ApprovalRuleHandler:     approvalRuleHandler,
+		AttachmentHandler:       attachmentHandler,
+		AuditEventHandler:       auditEventHandler,
+		LedgerHandler:           ledgerHandler,
+		WebhookHandler:          webhookHandler,
+		EventHandler:            eventHandler,
+		TransferHandler:         transferHandler,
+		HoldHandler:             holdHandler,
+		JournalEntryLineHandler: journalEntryLineHandler,
+		SearchHandler:           searchHandler,
+		ApiKeyHandler:           apiKeyHandler,
+		OAuthHandler:            oauthHandler,
 	}
 }
@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/Bendomey/fincore-engine/internal/services"
+	"github.com/go-playground/validator/v10"
+)
+
+type OAuthHandler struct {
+	service  services.AccessTokenService
+	validate *validator.Validate
+}
+
+func NewOAuthHandler(service services.AccessTokenService, validate *validator.Validate) OAuthHandler {
+	return OAuthHandler{service, validate}
+}
+
+type IssueTokenRequest struct {
+	GrantType    string `json:"grant_type"    validate:"required"`
+	ClientID     string `json:"client_id"     validate:"required"`
+	ClientSecret string `json:"client_secret" validate:"required"`
+	Scope        string `json:"scope"         validate:"omitempty,max=2048"`
+}
+
+// IssueToken is the OAuth2 token endpoint for the client credentials grant (RFC 6749 section 4.4). The client
+// authenticates with HTTP Basic or client_id and client_secret in the form, and responses and errors are shaped
+// the way the RFC says rather than like the rest of the API, so OAuth2 clients and gateways work with it as is.
+func (h *OAuthHandler) IssueToken(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeOAuthError(w, http.StatusBadRequest, "invalid_request", "the body must be form encoded")
+		return
+	}
+
+	body := IssueTokenRequest{
+		GrantType:    r.PostForm.Get("grant_type"),
+		ClientID:     r.PostForm.Get("client_id"),
+		ClientSecret: r.PostForm.Get("client_secret"),
+		Scope:        r.PostForm.Get("scope"),
+	}
+
+	// credentials sent with HTTP Basic are form encoded first.
+	basicClientID, basicClientSecret, isBasicAuth := r.BasicAuth()
+	if isBasicAuth {
+		clientID, idErr := url.QueryUnescape(basicClientID)
+		clientSecret, secretErr := url.QueryUnescape(basicClientSecret)
+		if idErr != nil || secretErr != nil {
+			writeOAuthError(w, http.StatusBadRequest, "invalid_request", "malformed basic authorization")
+			return
+		}
+
+		body.ClientID = clientID
+		body.ClientSecret = clientSecret
+	}
+
+	if err := h.validate.Struct(body); err != nil {
+		writeOAuthError(
+			w,
+			http.StatusBadRequest,
+			"invalid_request",
+			"grant_type, client_id and client_secret are required",
+		)
+		return
+	}
+
+	if body.GrantType != "client_credentials" {
+		writeOAuthError(w, http.StatusBadRequest, "unsupported_grant_type", "only client_credentials is supported")
+		return
+	}
+
+	input := services.IssueAccessTokenInput{
+		ClientID:     body.ClientID,
+		ClientSecret: body.ClientSecret,
+	}
+
+	if scopes := strings.Fields(body.Scope); len(scopes) > 0 {
+		input.Scopes = &scopes
+	}
+
+	token, err := h.service.IssueAccessToken(r.Context(), input)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidClientCredentials):
+			if isBasicAuth {
+				w.Header().Set("WWW-Authenticate", `Basic realm="fincore"`)
+			}
+			writeOAuthError(w, http.StatusUnauthorized, "invalid_client", err.Error())
+		case errors.Is(err, services.ErrInvalidScope):
+			writeOAuthError(w, http.StatusBadRequest, "invalid_scope", err.Error())
+		default:
+			writeOAuthError(w, http.StatusInternalServerError, "server_error", "the token could not be issued")
+		}
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Pragma", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]any{
+		"access_token": token.Token,
+		"token_type":   "Bearer",
+		"expires_in":   int64(services.AccessTokenLifetime.Seconds()),
+		"scope":        strings.Join(token.Scopes, " "),
+	})
+}
+
+func writeOAuthError(w http.ResponseWriter, status int, code string, description string) {
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Pragma", "no-cache")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{
+		"error":             code,
+		"error_description": description,
+	})
+}
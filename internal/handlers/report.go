@@ -0,0 +1,198 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/Bendomey/fincore-engine/internal/lib"
+	"github.com/Bendomey/fincore-engine/internal/services"
+	"github.com/Bendomey/fincore-engine/internal/transformations"
+	"github.com/go-playground/validator/v10"
+)
+
+type ReportHandler struct {
+	service  services.ReportService
+	validate *validator.Validate
+}
+
+func NewReportHandler(service services.ReportService, validate *validator.Validate) ReportHandler {
+	return ReportHandler{service, validate}
+}
+
+type ReportFilterRequest struct {
+	ClientID   string              `json:"client_id"  validate:"required,uuid4"`
+	GroupBy    *string             `json:"group_by"   validate:"omitempty,min=2,max=64"`
+	Dimensions map[string][]string `json:"dimensions" validate:"omitempty,dive,keys,required,max=64,endkeys,min=1"`
+	DateRange  *lib.DateRangeType  `json:"date_range" validate:"omitempty"`
+
+	// FiscalYear reports on one fiscal year of the ledger in place of a date range.
+	FiscalYear *string `json:"fiscal_year" validate:"omitempty,number,len=4,excluded_with=DateRange"`
+}
+
+// getReportInput parses and validates the query params shared by every report.
+func (h *ReportHandler) getReportInput(w http.ResponseWriter, r *http.Request) (*services.ReportInput, bool) {
+	client, clientOk := lib.ClientFromContext(r.Context())
+
+	if !clientOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return nil, false
+	}
+
+	ledger, ledgerOk := lib.LedgerFromContext(r.Context())
+
+	if !ledgerOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return nil, false
+	}
+
+	filterQuery, filterErr := lib.GenerateQuery(r.URL.Query(), nil)
+	if filterErr != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]any{
+			"errors": map[string]string{
+				"message": filterErr.Error(),
+			},
+		})
+		return nil, false
+	}
+
+	filters := ReportFilterRequest{
+		ClientID:   client.ID.String(),
+		GroupBy:    lib.NullOrString(r.URL.Query().Get("group_by")),
+		Dimensions: getDimensionFilters(r),
+		DateRange:  filterQuery.DateRange,
+		FiscalYear: lib.NullOrString(r.URL.Query().Get("fiscal_year")),
+	}
+
+	isFiltersPassedValidation := lib.ValidateRequest(h.validate, filters, w)
+	if !isFiltersPassedValidation {
+		return nil, false
+	}
+
+	dateRange := filters.DateRange
+	if filters.FiscalYear != nil {
+		year, err := lib.ConvertStringToInt(*filters.FiscalYear)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]any{
+				"errors": map[string]string{
+					"message": err.Error(),
+				},
+			})
+			return nil, false
+		}
+
+		start, end := ledger.FiscalYear(year)
+		dateRange = &lib.DateRangeType{StartTime: start, EndTime: end}
+	}
+
+	return &services.ReportInput{
+		ClientID:   filters.ClientID,
+		LedgerID:   ledger.ID.String(),
+		DateRange:  dateRange,
+		Dimensions: filters.Dimensions,
+		GroupBy:    filters.GroupBy,
+		Currency:   ledger.Currency,
+	}, true
+}
+
+func (h *ReportHandler) GetAccountBalances(w http.ResponseWriter, r *http.Request) {
+	input, ok := h.getReportInput(w, r)
+	if !ok {
+		return
+	}
+
+	report, err := h.service.GetAccountBalances(r.Context(), *input)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]any{
+			"errors": map[string]string{
+				"message": err.Error(),
+			},
+		})
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]any{
+		"data": transformations.ReportAccountBalancesToRestAccountBalances(report),
+	})
+}
+
+func (h *ReportHandler) GetIncomeStatement(w http.ResponseWriter, r *http.Request) {
+	input, ok := h.getReportInput(w, r)
+	if !ok {
+		return
+	}
+
+	report, err := h.service.GetIncomeStatement(r.Context(), *input)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]any{
+			"errors": map[string]string{
+				"message": err.Error(),
+			},
+		})
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]any{
+		"data": transformations.ReportIncomeStatementToRestIncomeStatement(report),
+	})
+}
+
+type BudgetVsActualRequest struct {
+	ClientID string  `json:"client_id" validate:"required,uuid4"`
+	BudgetID string  `json:"budget_id" validate:"required,uuid4"`
+	Interval *string `json:"interval"  validate:"omitempty,oneof=month"`
+}
+
+func (h *ReportHandler) GetBudgetVsActual(w http.ResponseWriter, r *http.Request) {
+	client, clientOk := lib.ClientFromContext(r.Context())
+
+	if !clientOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	ledger, ledgerOk := lib.LedgerFromContext(r.Context())
+
+	if !ledgerOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	input := BudgetVsActualRequest{
+		ClientID: client.ID.String(),
+		BudgetID: r.URL.Query().Get("budget_id"),
+		Interval: lib.NullOrString(r.URL.Query().Get("interval")),
+	}
+
+	isPassedValidation := lib.ValidateRequest(h.validate, input, w)
+	if !isPassedValidation {
+		return
+	}
+
+	report, err := h.service.GetBudgetVsActual(r.Context(), services.BudgetVsActualInput{
+		ClientID: input.ClientID,
+		BudgetID: input.BudgetID,
+		LedgerID: ledger.ID.String(),
+		Currency: ledger.Currency,
+		Interval: input.Interval,
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]any{
+			"errors": map[string]string{
+				"message": err.Error(),
+			},
+		})
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]any{
+		"data": transformations.ReportBudgetVsActualToRestBudgetVsActual(report),
+	})
+}
@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Bendomey/fincore-engine/internal/lib"
+	"github.com/Bendomey/fincore-engine/internal/services"
+	"github.com/Bendomey/fincore-engine/internal/transformations"
+	"github.com/go-playground/validator/v10"
+)
+
+type SearchHandler struct {
+	service  services.SearchService
+	validate *validator.Validate
+}
+
+func NewSearchHandler(service services.SearchService, validate *validator.Validate) SearchHandler {
+	return SearchHandler{service, validate}
+}
+
+type SearchRequest struct {
+	ClientID string   `json:"client_id" validate:"required,uuid4"`
+	Query    string   `json:"q"         validate:"required,max=255"`
+	Types    []string `json:"types"     validate:"omitempty,dive,oneof=account journal_entry journal_entry_line"`
+	Limit    int      `json:"limit"     validate:"min=1,max=100"`
+}
+
+// Search answers the best ranked accounts, journal entries and lines of the selected ledger matching q.
+func (h *SearchHandler) Search(w http.ResponseWriter, r *http.Request) {
+	client, clientOk := lib.ClientFromContext(r.Context())
+
+	if !clientOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	ledger, ledgerOk := lib.LedgerFromContext(r.Context())
+
+	if !ledgerOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	input := SearchRequest{
+		ClientID: client.ID.String(),
+		Query:    strings.TrimSpace(r.URL.Query().Get("q")),
+		Limit:    20,
+	}
+
+	if types := r.URL.Query().Get("types"); types != "" {
+		input.Types = strings.Split(types, ",")
+	}
+
+	if limit := r.URL.Query().Get("limit"); limit != "" {
+		parsed, err := strconv.Atoi(limit)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "limit must be a number")
+			return
+		}
+
+		input.Limit = parsed
+	}
+
+	isPassedValidation := lib.ValidateRequest(h.validate, input, w)
+
+	if !isPassedValidation {
+		return
+	}
+
+	hits, err := h.service.Search(r.Context(), services.SearchInput{
+		ClientID: input.ClientID,
+		LedgerID: ledger.ID.String(),
+		Query:    input.Query,
+		Types:    input.Types,
+		Limit:    input.Limit,
+	})
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	hitsTransformed := make([]interface{}, 0)
+	for _, hit := range hits {
+		hitsTransformed = append(hitsTransformed, transformations.SearchHitToRestSearchHit(&hit))
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]any{
+		"data": hitsTransformed,
+	})
+}
@@ -0,0 +1,238 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/Bendomey/fincore-engine/internal/lib"
+	"github.com/Bendomey/fincore-engine/internal/repository"
+	"github.com/Bendomey/fincore-engine/internal/services"
+	"github.com/Bendomey/fincore-engine/internal/transformations"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+)
+
+type TransferHandler struct {
+	service  services.TransferService
+	validate *validator.Validate
+}
+
+func NewTransferHandler(service services.TransferService, validate *validator.Validate) TransferHandler {
+	return TransferHandler{service, validate}
+}
+
+type CreateTransferRequest struct {
+	ID              *string                 `json:"id"               validate:"omitempty,uuid4"`
+	FromAccountID   string                  `json:"from_account_id"  validate:"required,uuid4"`
+	ToAccountID     string                  `json:"to_account_id"    validate:"required,uuid4,nefield=FromAccountID"`
+	Amount          int64                   `json:"amount"           validate:"required,min=1"`
+	Reference       string                  `json:"reference"        validate:"required,min=3,max=255"`
+	TransactionDate *string                 `json:"transaction_date" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	Metadata        *map[string]interface{} `json:"metadata"         validate:"omitempty"`
+}
+
+// CreateTransfer answers 201 with the new transfer, or 200 with the existing one when the id was already used
+// for the same transfer.
+func (h *TransferHandler) CreateTransfer(w http.ResponseWriter, r *http.Request) {
+	var body CreateTransferRequest
+	if decodeErr := json.NewDecoder(r.Body).Decode(&body); decodeErr != nil {
+		http.Error(w, "Invalid JSON body", http.StatusBadRequest)
+		return
+	}
+
+	isPassedValidation := lib.ValidateRequest(h.validate, body, w)
+
+	if !isPassedValidation {
+		return
+	}
+
+	client, clientOk := lib.ClientFromContext(r.Context())
+
+	if !clientOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	ledger, ledgerOk := lib.LedgerFromContext(r.Context())
+
+	if !ledgerOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	credentialID, _ := lib.CredentialFromContext(r.Context())
+
+	transfer, created, err := h.service.CreateTransfer(r.Context(), services.CreateTransferInput{
+		ClientID:        client.ID.String(),
+		LedgerID:        ledger.ID.String(),
+		ActorID:         credentialID,
+		ID:              body.ID,
+		FromAccountID:   body.FromAccountID,
+		ToAccountID:     body.ToAccountID,
+		Amount:          body.Amount,
+		Currency:        ledger.Currency,
+		Reference:       body.Reference,
+		TransactionDate: body.TransactionDate,
+		Metadata:        body.Metadata,
+	})
+	if err != nil {
+		if errors.Is(err, services.ErrTransferIDReused) {
+			writeError(w, http.StatusConflict, err.Error())
+			return
+		}
+
+		writePostingError(w, err)
+		return
+	}
+
+	status := http.StatusCreated
+	if !created {
+		status = http.StatusOK
+	}
+
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]any{
+		"data": transformations.DBTransferToRestTransfer(transfer, &[]string{"JournalEntry"}),
+	})
+}
+
+type GetTransferRequest struct {
+	ClientID string    `json:"client_id" validate:"required,uuid4"`
+	ID       string    `json:"id"        validate:"required,uuid4"`
+	Populate *[]string `json:"populate"  validate:"omitempty,dive,oneof=FromAccount ToAccount JournalEntry JournalEntry.JournalEntryLines"`
+}
+
+func (h *TransferHandler) GetTransfer(w http.ResponseWriter, r *http.Request) {
+	client, clientOk := lib.ClientFromContext(r.Context())
+
+	if !clientOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	ledger, ledgerOk := lib.LedgerFromContext(r.Context())
+
+	if !ledgerOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	input := GetTransferRequest{
+		ClientID: client.ID.String(),
+		ID:       chi.URLParam(r, "transfer_id"),
+		Populate: getPopulateFields(r),
+	}
+
+	isPassedValidation := lib.ValidateRequest(h.validate, input, w)
+
+	if !isPassedValidation {
+		return
+	}
+
+	transfer, err := h.service.GetTransfer(r.Context(), services.GetTransferInput{
+		ClientID: input.ClientID,
+		LedgerID: ledger.ID.String(),
+		ID:       input.ID,
+		Populate: input.Populate,
+	})
+	if err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]any{
+		"data": transformations.DBTransferToRestTransfer(transfer, input.Populate),
+	})
+}
+
+type ListTransfersFilterRequest struct {
+	ClientID      string    `json:"client_id"       validate:"required,uuid4"`
+	AccountID     *string   `json:"account_id"      validate:"omitempty,uuid4"`
+	FromAccountID *string   `json:"from_account_id" validate:"omitempty,uuid4"`
+	ToAccountID   *string   `json:"to_account_id"   validate:"omitempty,uuid4"`
+	Populate      *[]string `json:"populate"        validate:"omitempty,dive,oneof=FromAccount ToAccount JournalEntry JournalEntry.JournalEntryLines"`
+}
+
+func (h *TransferHandler) ListTransfers(w http.ResponseWriter, r *http.Request) {
+	client, clientOk := lib.ClientFromContext(r.Context())
+
+	if !clientOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	ledger, ledgerOk := lib.LedgerFromContext(r.Context())
+
+	if !ledgerOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	filters := ListTransfersFilterRequest{
+		ClientID:      client.ID.String(),
+		AccountID:     lib.NullOrString(r.URL.Query().Get("account_id")),
+		FromAccountID: lib.NullOrString(r.URL.Query().Get("from_account_id")),
+		ToAccountID:   lib.NullOrString(r.URL.Query().Get("to_account_id")),
+		Populate:      getPopulateFields(r),
+	}
+
+	isFiltersPassedValidation := lib.ValidateRequest(h.validate, filters, w)
+	if !isFiltersPassedValidation {
+		return
+	}
+
+	filterQuery, filterErr := lib.GenerateQuery(r.URL.Query(), repository.TransferQueryFields)
+	if filterErr != nil {
+		writeError(w, http.StatusBadRequest, filterErr.Error())
+		return
+	}
+
+	isFilterQueryPassedValidation := lib.ValidateRequest(h.validate, filterQuery, w)
+	if !isFilterQueryPassedValidation {
+		return
+	}
+
+	listFilters := repository.ListTransfersFilter{
+		ClientId:      filters.ClientID,
+		LedgerId:      ledger.ID.String(),
+		AccountId:     filters.AccountID,
+		FromAccountId: filters.FromAccountID,
+		ToAccountId:   filters.ToAccountID,
+	}
+
+	transfers, transfersErr := h.service.ListTransfers(r.Context(), *filterQuery, listFilters)
+	if transfersErr != nil {
+		writeError(w, http.StatusNotFound, transfersErr.Error())
+		return
+	}
+
+	count, countsErr := h.service.CountTransfers(r.Context(), *filterQuery, listFilters)
+	if countsErr != nil {
+		writeError(w, http.StatusNotFound, countsErr.Error())
+		return
+	}
+
+	transfersTransformed := make([]interface{}, 0)
+	for _, transfer := range transfers {
+		transfersTransformed = append(
+			transfersTransformed,
+			transformations.DBTransferToRestTransfer(&transfer, filterQuery.Populate),
+		)
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]any{
+		"data": transfersTransformed,
+		"meta": map[string]any{
+			"page":              filterQuery.Page,
+			"page_size":         filterQuery.PageSize,
+			"order":             filterQuery.Order,
+			"order_by":          filterQuery.OrderBy,
+			"total":             count,
+			"has_next_page":     (filterQuery.Page * filterQuery.PageSize) < int(count),
+			"has_previous_page": filterQuery.Page > 1,
+		},
+	})
+}
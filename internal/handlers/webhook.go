@@ -0,0 +1,518 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/Bendomey/fincore-engine/internal/lib"
+	"github.com/Bendomey/fincore-engine/internal/repository"
+	"github.com/Bendomey/fincore-engine/internal/services"
+	"github.com/Bendomey/fincore-engine/internal/transformations"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+)
+
+type WebhookHandler struct {
+	service  services.WebhookService
+	validate *validator.Validate
+}
+
+func NewWebhookHandler(service services.WebhookService, validate *validator.Validate) WebhookHandler {
+	return WebhookHandler{service, validate}
+}
+
+type CreateWebhookEndpointRequest struct {
+	URL         string   `json:"url"         validate:"required,url,startswith=http,max=2048"`
+	Description *string  `json:"description" validate:"omitempty,max=255"`
+	Events      []string `json:"events"      validate:"required,min=1,dive,oneof=account.created journal_entry.created journal_entry.posted journal_entry.reversed"`
+}
+
+func (h *WebhookHandler) CreateWebhookEndpoint(w http.ResponseWriter, r *http.Request) {
+	var body CreateWebhookEndpointRequest
+	if decodeErr := json.NewDecoder(r.Body).Decode(&body); decodeErr != nil {
+		http.Error(w, "Invalid JSON body", http.StatusBadRequest)
+		return
+	}
+
+	isPassedValidation := lib.ValidateRequest(h.validate, body, w)
+
+	if !isPassedValidation {
+		return
+	}
+
+	client, clientOk := lib.ClientFromContext(r.Context())
+
+	if !clientOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	ledger, ledgerOk := lib.LedgerFromContext(r.Context())
+
+	if !ledgerOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	response, err := h.service.CreateWebhookEndpoint(r.Context(), services.CreateWebhookEndpointInput{
+		ClientID:    client.ID.String(),
+		LedgerID:    ledger.ID.String(),
+		URL:         body.URL,
+		Description: body.Description,
+		Events:      body.Events,
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]any{
+			"errors": map[string]string{
+				"message": err.Error(),
+			},
+		})
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]any{
+		"data": transformations.DBWebhookEndpointToRestWebhookEndpoint(&response.WebhookEndpoint, &response.Secret),
+	})
+}
+
+type UpdateWebhookEndpointRequest struct {
+	URL         *string   `json:"url"         validate:"omitempty,url,startswith=http,max=2048"`
+	Description *string   `json:"description" validate:"omitempty,max=255"`
+	Events      *[]string `json:"events"      validate:"omitempty,min=1,dive,oneof=account.created journal_entry.created journal_entry.posted journal_entry.reversed"`
+	IsActive    *bool     `json:"is_active"   validate:"omitempty,boolean"`
+}
+
+func (h *WebhookHandler) UpdateWebhookEndpoint(w http.ResponseWriter, r *http.Request) {
+	var body UpdateWebhookEndpointRequest
+	if decodeErr := json.NewDecoder(r.Body).Decode(&body); decodeErr != nil {
+		http.Error(w, "Invalid JSON body", http.StatusBadRequest)
+		return
+	}
+
+	isPassedValidation := lib.ValidateRequest(h.validate, body, w)
+
+	if !isPassedValidation {
+		return
+	}
+
+	client, clientOk := lib.ClientFromContext(r.Context())
+
+	if !clientOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	ledger, ledgerOk := lib.LedgerFromContext(r.Context())
+
+	if !ledgerOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	webhookEndpoint, err := h.service.UpdateWebhookEndpoint(r.Context(), services.UpdateWebhookEndpointInput{
+		ClientID:    client.ID.String(),
+		LedgerID:    ledger.ID.String(),
+		ID:          chi.URLParam(r, "webhook_endpoint_id"),
+		URL:         body.URL,
+		Description: body.Description,
+		Events:      body.Events,
+		IsActive:    body.IsActive,
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]any{
+			"errors": map[string]string{
+				"message": err.Error(),
+			},
+		})
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]any{
+		"data": transformations.DBWebhookEndpointToRestWebhookEndpoint(webhookEndpoint, nil),
+	})
+}
+
+func (h *WebhookHandler) DeleteWebhookEndpoint(w http.ResponseWriter, r *http.Request) {
+	client, clientOk := lib.ClientFromContext(r.Context())
+
+	if !clientOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	ledger, ledgerOk := lib.LedgerFromContext(r.Context())
+
+	if !ledgerOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	err := h.service.DeleteWebhookEndpoint(r.Context(), services.GetWebhookEndpointInput{
+		ClientID: client.ID.String(),
+		LedgerID: ledger.ID.String(),
+		ID:       chi.URLParam(r, "webhook_endpoint_id"),
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]any{
+			"errors": map[string]string{
+				"message": err.Error(),
+			},
+		})
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	json.NewEncoder(w).Encode(map[string]any{})
+}
+
+type GetWebhookEndpointRequest struct {
+	ClientID string `json:"client_id" validate:"required,uuid4"`
+	ID       string `json:"id"        validate:"required,uuid4"`
+}
+
+func (h *WebhookHandler) GetWebhookEndpoint(w http.ResponseWriter, r *http.Request) {
+	client, clientOk := lib.ClientFromContext(r.Context())
+
+	if !clientOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	ledger, ledgerOk := lib.LedgerFromContext(r.Context())
+
+	if !ledgerOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	input := GetWebhookEndpointRequest{
+		ClientID: client.ID.String(),
+		ID:       chi.URLParam(r, "webhook_endpoint_id"),
+	}
+
+	isPassedValidation := lib.ValidateRequest(h.validate, input, w)
+
+	if !isPassedValidation {
+		return
+	}
+
+	webhookEndpoint, err := h.service.GetWebhookEndpoint(r.Context(), services.GetWebhookEndpointInput{
+		ClientID: input.ClientID,
+		LedgerID: ledger.ID.String(),
+		ID:       input.ID,
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]any{
+			"errors": map[string]string{
+				"message": err.Error(),
+			},
+		})
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]any{
+		"data": transformations.DBWebhookEndpointToRestWebhookEndpoint(webhookEndpoint, nil),
+	})
+}
+
+type ListWebhookEndpointsFilterRequest struct {
+	ClientID string  `json:"client_id" validate:"required,uuid4"`
+	IsActive *string `json:"is_active" validate:"omitempty,boolean"`
+}
+
+func (h *WebhookHandler) ListWebhookEndpoints(w http.ResponseWriter, r *http.Request) {
+	client, clientOk := lib.ClientFromContext(r.Context())
+
+	if !clientOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	ledger, ledgerOk := lib.LedgerFromContext(r.Context())
+
+	if !ledgerOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	filters := ListWebhookEndpointsFilterRequest{
+		ClientID: client.ID.String(),
+		IsActive: lib.NullOrString(r.URL.Query().Get("is_active")),
+	}
+
+	isFiltersPassedValidation := lib.ValidateRequest(h.validate, filters, w)
+	if !isFiltersPassedValidation {
+		return
+	}
+
+	filterQuery, filterErr := lib.GenerateQuery(r.URL.Query(), repository.WebhookEndpointQueryFields)
+	if filterErr != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]any{
+			"errors": map[string]string{
+				"message": filterErr.Error(),
+			},
+		})
+		return
+	}
+
+	isFilterQueryPassedValidation := lib.ValidateRequest(h.validate, filterQuery, w)
+	if !isFilterQueryPassedValidation {
+		return
+	}
+
+	listFilters := repository.ListWebhookEndpointsFilter{
+		ClientId: filters.ClientID,
+		LedgerId: ledger.ID.String(),
+		IsActive: lib.ConvertStringPointerToBoolPointer(filters.IsActive),
+	}
+
+	webhookEndpoints, webhookEndpointsErr := h.service.ListWebhookEndpoints(r.Context(), *filterQuery, listFilters)
+	if webhookEndpointsErr != nil {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]any{
+			"errors": map[string]string{
+				"message": webhookEndpointsErr.Error(),
+			},
+		})
+		return
+	}
+
+	count, countsErr := h.service.CountWebhookEndpoints(r.Context(), *filterQuery, listFilters)
+	if countsErr != nil {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]any{
+			"errors": map[string]string{
+				"message": countsErr.Error(),
+			},
+		})
+		return
+	}
+
+	webhookEndpointsTransformed := make([]interface{}, 0)
+	for _, webhookEndpoint := range webhookEndpoints {
+		webhookEndpointsTransformed = append(
+			webhookEndpointsTransformed,
+			transformations.DBWebhookEndpointToRestWebhookEndpoint(&webhookEndpoint, nil),
+		)
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]any{
+		"data": webhookEndpointsTransformed,
+		"meta": map[string]any{
+			"page":              filterQuery.Page,
+			"page_size":         filterQuery.PageSize,
+			"order":             filterQuery.Order,
+			"order_by":          filterQuery.OrderBy,
+			"total":             count,
+			"has_next_page":     (filterQuery.Page * filterQuery.PageSize) < int(count),
+			"has_previous_page": filterQuery.Page > 1,
+		},
+	})
+}
+
+type GetWebhookDeliveryRequest struct {
+	ClientID          string    `json:"client_id"           validate:"required,uuid4"`
+	WebhookEndpointID string    `json:"webhook_endpoint_id" validate:"required,uuid4"`
+	ID                string    `json:"id"                  validate:"required,uuid4"`
+	Populate          *[]string `json:"populate"            validate:"omitempty,dive,oneof=WebhookEvent"`
+}
+
+func (h *WebhookHandler) GetWebhookDelivery(w http.ResponseWriter, r *http.Request) {
+	client, clientOk := lib.ClientFromContext(r.Context())
+
+	if !clientOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	ledger, ledgerOk := lib.LedgerFromContext(r.Context())
+
+	if !ledgerOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	input := GetWebhookDeliveryRequest{
+		ClientID:          client.ID.String(),
+		WebhookEndpointID: chi.URLParam(r, "webhook_endpoint_id"),
+		ID:                chi.URLParam(r, "webhook_delivery_id"),
+		Populate:          getPopulateFields(r),
+	}
+
+	isPassedValidation := lib.ValidateRequest(h.validate, input, w)
+
+	if !isPassedValidation {
+		return
+	}
+
+	webhookDelivery, err := h.service.GetWebhookDelivery(r.Context(), services.GetWebhookDeliveryInput{
+		ClientID:          input.ClientID,
+		LedgerID:          ledger.ID.String(),
+		WebhookEndpointID: input.WebhookEndpointID,
+		ID:                input.ID,
+		Populate:          input.Populate,
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]any{
+			"errors": map[string]string{
+				"message": err.Error(),
+			},
+		})
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]any{
+		"data": transformations.DBWebhookDeliveryToRestWebhookDelivery(webhookDelivery, input.Populate),
+	})
+}
+
+func (h *WebhookHandler) RedeliverWebhookDelivery(w http.ResponseWriter, r *http.Request) {
+	client, clientOk := lib.ClientFromContext(r.Context())
+
+	if !clientOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	ledger, ledgerOk := lib.LedgerFromContext(r.Context())
+
+	if !ledgerOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	webhookDelivery, err := h.service.RedeliverWebhookDelivery(r.Context(), services.GetWebhookDeliveryInput{
+		ClientID:          client.ID.String(),
+		LedgerID:          ledger.ID.String(),
+		WebhookEndpointID: chi.URLParam(r, "webhook_endpoint_id"),
+		ID:                chi.URLParam(r, "webhook_delivery_id"),
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]any{
+			"errors": map[string]string{
+				"message": err.Error(),
+			},
+		})
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(map[string]any{
+		"data": transformations.DBWebhookDeliveryToRestWebhookDelivery(webhookDelivery, nil),
+	})
+}
+
+type ListWebhookDeliveriesFilterRequest struct {
+	ClientID          string  `json:"client_id"           validate:"required,uuid4"`
+	WebhookEndpointID string  `json:"webhook_endpoint_id" validate:"required,uuid4"`
+	Status            *string `json:"status"              validate:"omitempty,oneof=PENDING SUCCEEDED FAILED"`
+	EventType         *string `json:"event_type"          validate:"omitempty,oneof=account.created journal_entry.created journal_entry.posted journal_entry.reversed"`
+}
+
+func (h *WebhookHandler) ListWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	client, clientOk := lib.ClientFromContext(r.Context())
+
+	if !clientOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	ledger, ledgerOk := lib.LedgerFromContext(r.Context())
+
+	if !ledgerOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	filters := ListWebhookDeliveriesFilterRequest{
+		ClientID:          client.ID.String(),
+		WebhookEndpointID: chi.URLParam(r, "webhook_endpoint_id"),
+		Status:            lib.NullOrString(r.URL.Query().Get("status")),
+		EventType:         lib.NullOrString(r.URL.Query().Get("event_type")),
+	}
+
+	isFiltersPassedValidation := lib.ValidateRequest(h.validate, filters, w)
+	if !isFiltersPassedValidation {
+		return
+	}
+
+	filterQuery, filterErr := lib.GenerateQuery(r.URL.Query(), repository.WebhookDeliveryQueryFields)
+	if filterErr != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]any{
+			"errors": map[string]string{
+				"message": filterErr.Error(),
+			},
+		})
+		return
+	}
+
+	isFilterQueryPassedValidation := lib.ValidateRequest(h.validate, filterQuery, w)
+	if !isFilterQueryPassedValidation {
+		return
+	}
+
+	listFilters := repository.ListWebhookDeliveriesFilter{
+		ClientId:          filters.ClientID,
+		LedgerId:          ledger.ID.String(),
+		WebhookEndpointId: filters.WebhookEndpointID,
+		Status:            filters.Status,
+		EventType:         filters.EventType,
+	}
+
+	webhookDeliveries, webhookDeliveriesErr := h.service.ListWebhookDeliveries(r.Context(), *filterQuery, listFilters)
+	if webhookDeliveriesErr != nil {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]any{
+			"errors": map[string]string{
+				"message": webhookDeliveriesErr.Error(),
+			},
+		})
+		return
+	}
+
+	count, countsErr := h.service.CountWebhookDeliveries(r.Context(), *filterQuery, listFilters)
+	if countsErr != nil {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]any{
+			"errors": map[string]string{
+				"message": countsErr.Error(),
+			},
+		})
+		return
+	}
+
+	webhookDeliveriesTransformed := make([]interface{}, 0)
+	for _, webhookDelivery := range webhookDeliveries {
+		webhookDeliveriesTransformed = append(
+			webhookDeliveriesTransformed,
+			transformations.DBWebhookDeliveryToRestWebhookDelivery(&webhookDelivery, filterQuery.Populate),
+		)
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]any{
+		"data": webhookDeliveriesTransformed,
+		"meta": map[string]any{
+			"page":              filterQuery.Page,
+			"page_size":         filterQuery.PageSize,
+			"order":             filterQuery.Order,
+			"order_by":          filterQuery.OrderBy,
+			"total":             count,
+			"has_next_page":     (filterQuery.Page * filterQuery.PageSize) < int(count),
+			"has_previous_page": filterQuery.Page > 1,
+		},
+	})
+}
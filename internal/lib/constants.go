@@ -2,13 +2,22 @@ package lib
 
 import (
 	"context"
+	"slices"
+	"time"
 
 	"github.com/Bendomey/fincore-engine/internal/models"
+	"github.com/go-chi/chi/v5/middleware"
 )
 
 type contextKey string
 
-const clientContextKey contextKey = "fin-core-client"
+const (
+	clientContextKey     contextKey = "fin-core-client"
+	credentialContextKey contextKey = "fin-core-credential"
+	credentialExpiryKey  contextKey = "fin-core-credential-expiry"
+	scopesContextKey     contextKey = "fin-core-scopes"
+	ledgerContextKey     contextKey = "fin-core-ledger"
+)
 
 func WithClient(ctx context.Context, client *models.Client) context.Context {
 	return context.WithValue(ctx, clientContextKey, client)
@@ -18,3 +27,56 @@ func ClientFromContext(ctx context.Context) (*models.Client, bool) {
 	client, ok := ctx.Value(clientContextKey).(*models.Client)
 	return client, ok
 }
+
+// WithCredential attaches the identifier of the credential that authenticated the request.
+func WithCredential(ctx context.Context, credentialID string) context.Context {
+	return context.WithValue(ctx, credentialContextKey, credentialID)
+}
+
+func CredentialFromContext(ctx context.Context) (string, bool) {
+	credentialID, ok := ctx.Value(credentialContextKey).(string)
+	return credentialID, ok
+}
+
+// WithCredentialExpiry attaches when the credential that authenticated the request stops working, for credentials
+// that expire.
+func WithCredentialExpiry(ctx context.Context, expiresAt time.Time) context.Context {
+	return context.WithValue(ctx, credentialExpiryKey, expiresAt)
+}
+
+func CredentialExpiryFromContext(ctx context.Context) (time.Time, bool) {
+	expiresAt, ok := ctx.Value(credentialExpiryKey).(time.Time)
+	return expiresAt, ok
+}
+
+// WithScopes attaches the scopes the credential that authenticated the request was granted.
+func WithScopes(ctx context.Context, scopes []string) context.Context {
+	return context.WithValue(ctx, scopesContextKey, scopes)
+}
+
+func ScopesFromContext(ctx context.Context) ([]string, bool) {
+	scopes, ok := ctx.Value(scopesContextKey).([]string)
+	return scopes, ok
+}
+
+// HasScope tells whether the credential that authenticated the request was granted the scope.
+func HasScope(ctx context.Context, scope string) bool {
+	scopes, _ := ScopesFromContext(ctx)
+	return slices.Contains(scopes, scope)
+}
+
+// WithLedger attaches the ledger the request works in.
+func WithLedger(ctx context.Context, ledger *models.Ledger) context.Context {
+	return context.WithValue(ctx, ledgerContextKey, ledger)
+}
+
+func LedgerFromContext(ctx context.Context) (*models.Ledger, bool) {
+	ledger, ok := ctx.Value(ledgerContextKey).(*models.Ledger)
+	return ledger, ok
+}
+
+// RequestIDFromContext returns the id chi's RequestID middleware assigned to the request.
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	requestID := middleware.GetReqID(ctx)
+	return requestID, requestID != ""
+}
@@ -0,0 +1,50 @@
+package lib
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// CursorQuery asks for a keyset paginated page, ordered by created_at then id. After is where the previous page
+// ended, nil for the first page. Rows are only counted when IncludeTotal is set.
+type CursorQuery struct {
+	After        *Cursor
+	IncludeTotal bool
+}
+
+// Cursor is the position of a row in a keyset paginated list, along with the order the list was read in, a
+// position meaning nothing in the other order. Clients only ever see it encoded.
+type Cursor struct {
+	CreatedAt time.Time `json:"c"`
+	ID        string    `json:"i"`
+	Order     string    `json:"o"`
+}
+
+// Encode turns the cursor into the opaque string returned as next_cursor.
+func (c Cursor) Encode() string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// DecodeCursor reads back a cursor returned as next_cursor.
+func DecodeCursor(value string) (*Cursor, error) {
+	invalid := errors.New("cursor is invalid")
+
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, invalid
+	}
+
+	var cursor Cursor
+	if err := json.Unmarshal(raw, &cursor); err != nil || cursor.ID == "" || cursor.CreatedAt.IsZero() {
+		return nil, invalid
+	}
+
+	if cursor.Order != "asc" && cursor.Order != "desc" {
+		return nil, invalid
+	}
+
+	return &cursor, nil
+}
@@ -0,0 +1,106 @@
+package lib
+
+import (
+	"encoding/base64"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	cursor := Cursor{
+		CreatedAt: time.Date(2026, 10, 19, 12, 30, 0, 123456000, time.UTC),
+		ID:        "7e2e0544-931c-4c07-a761-5ae95202d4e1",
+		Order:     "asc",
+	}
+
+	decoded, err := DecodeCursor(cursor.Encode())
+	if err != nil {
+		t.Fatalf("DecodeCursor() error = %v", err)
+	}
+
+	if !decoded.CreatedAt.Equal(cursor.CreatedAt) || decoded.ID != cursor.ID || decoded.Order != cursor.Order {
+		t.Errorf("DecodeCursor() = %+v, want %+v", *decoded, cursor)
+	}
+}
+
+func TestDecodeCursor(t *testing.T) {
+	encode := func(raw string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(raw))
+	}
+
+	tests := []struct {
+		name    string
+		value   string
+		wantErr bool
+	}{
+		{"valid", encode(`{"c":"2026-01-01T00:00:00Z","i":"7e2e0544","o":"desc"}`), false},
+		{"not base64", "not a cursor!", true},
+		{"padded base64", base64.URLEncoding.EncodeToString([]byte(`{"c":"2026-01-01T00:00:00Z"}`)), true},
+		{"not json", encode(`c=2026-01-01`), true},
+		{"no id", encode(`{"c":"2026-01-01T00:00:00Z","o":"desc"}`), true},
+		{"no created at", encode(`{"i":"7e2e0544","o":"desc"}`), true},
+		{"bad created at", encode(`{"c":"yesterday","i":"7e2e0544","o":"desc"}`), true},
+		{"no order", encode(`{"c":"2026-01-01T00:00:00Z","i":"7e2e0544"}`), true},
+		{"unknown order", encode(`{"c":"2026-01-01T00:00:00Z","i":"7e2e0544","o":"sideways"}`), true},
+		{"injected order", encode(`{"c":"2026-01-01T00:00:00Z","i":"7e2e0544","o":"desc; DROP TABLE accounts"}`), true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := DecodeCursor(test.value)
+			if (err != nil) != test.wantErr {
+				t.Errorf("DecodeCursor() error = %v, want error %v", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestGenerateQueryCursor(t *testing.T) {
+	fields := QueryFields{
+		"created_at": {Column: "accounts.created_at", Type: FieldTime},
+		"name":       {Column: "accounts.name", Type: FieldString},
+	}
+
+	cursorFor := func(order string) string {
+		return Cursor{CreatedAt: time.Unix(1760000000, 0), ID: "7e2e0544", Order: order}.Encode()
+	}
+
+	tests := []struct {
+		name      string
+		query     url.Values
+		wantAfter bool
+		wantErr   bool
+	}{
+		{"first page", url.Values{"cursor": {""}}, false, false},
+		{"next page", url.Values{"cursor": {cursorFor("desc")}}, true, false},
+		{"next page ascending", url.Values{"cursor": {cursorFor("asc")}, "order": {"asc"}}, true, false},
+		{"next page sorted", url.Values{"cursor": {cursorFor("asc")}, "sort": {"created_at"}}, true, false},
+		{"order switched", url.Values{"cursor": {cursorFor("desc")}, "order": {"asc"}}, false, true},
+		{"order switched back", url.Values{"cursor": {cursorFor("asc")}}, false, true},
+		{"sort switched", url.Values{"cursor": {cursorFor("asc")}, "sort": {"-created_at"}}, false, true},
+		{"tampered", url.Values{"cursor": {cursorFor("desc")[2:]}}, false, true},
+		{"other order by", url.Values{"cursor": {""}, "order_by": {"name"}}, false, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			filterQuery, err := GenerateQuery(test.query, fields)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("GenerateQuery() error = %v, want error %v", err, test.wantErr)
+			}
+
+			if test.wantErr {
+				return
+			}
+
+			if filterQuery.Cursor == nil {
+				t.Fatal("GenerateQuery() made no cursor query")
+			}
+
+			if (filterQuery.Cursor.After != nil) != test.wantAfter {
+				t.Errorf("GenerateQuery() after = %v, want after %v", filterQuery.Cursor.After, test.wantAfter)
+			}
+		})
+	}
+}
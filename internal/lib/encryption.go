@@ -0,0 +1,60 @@
+package lib
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+)
+
+var ErrDecryption = errors.New("could not decrypt value")
+
+// Encrypt seals plaintext with AES-256-GCM under a key derived from key, base64 encoding the nonce and
+// ciphertext together so it can be kept in a text column.
+func Encrypt(plaintext []byte, key []byte) (string, error) {
+	aead, err := newEncryptionAEAD(key)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(aead.Seal(nonce, nonce, plaintext, nil)), nil
+}
+
+// Decrypt opens a value sealed by Encrypt with the same key.
+func Decrypt(ciphertext string, key []byte) ([]byte, error) {
+	aead, err := newEncryptionAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return nil, ErrDecryption
+	}
+
+	plaintext, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], nil)
+	if err != nil {
+		return nil, ErrDecryption
+	}
+
+	return plaintext, nil
+}
+
+func newEncryptionAEAD(key []byte) (cipher.AEAD, error) {
+	// any length of key gives the 32 bytes AES-256 takes.
+	derivedKey := sha256.Sum256(key)
+
+	block, err := aes.NewCipher(derivedKey[:])
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
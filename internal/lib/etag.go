@@ -0,0 +1,24 @@
+package lib
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// ETag formats a resource version as a strong entity tag.
+func ETag(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+// ParseETag reads the version back out of an entity tag as sent in an If-Match header. Weak tags are accepted.
+func ParseETag(tag string) (int64, error) {
+	tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+
+	version, err := strconv.ParseInt(strings.Trim(tag, `"`), 10, 64)
+	if err != nil {
+		return 0, errors.New("If-Match must be the ETag of the resource")
+	}
+
+	return version, nil
+}
@@ -0,0 +1,37 @@
+package lib
+
+import "testing"
+
+func TestParseETag(t *testing.T) {
+	tests := []struct {
+		name    string
+		tag     string
+		want    int64
+		wantErr bool
+	}{
+		{"strong", `"3"`, 3, false},
+		{"weak", `W/"3"`, 3, false},
+		{"surrounding spaces", ` "42" `, 42, false},
+		{"as formatted", ETag(7), 7, false},
+		{"empty", "", 0, true},
+		{"empty quotes", `""`, 0, true},
+		{"not a number", `"abc"`, 0, true},
+		{"decimal", `"1.5"`, 0, true},
+		{"wildcard", "*", 0, true},
+		{"lowercase weak prefix", `w/"3"`, 0, true},
+		{"out of range", `"9223372036854775808"`, 0, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			version, err := ParseETag(test.tag)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("ParseETag(%q) error = %v, want error %v", test.tag, err, test.wantErr)
+			}
+
+			if version != test.want {
+				t.Errorf("ParseETag(%q) = %d, want %d", test.tag, version, test.want)
+			}
+		})
+	}
+}
@@ -1,7 +1,9 @@
 package lib
 
 import (
+	"errors"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -15,6 +17,13 @@ type FilterQuery struct {
 	Search    *Search        `json:"search"     validate:"omitempty"`
 	DateRange *DateRangeType `json:"date_range" validate:"omitempty"`
 	Populate  *[]string      `json:"populate"   validate:"omitempty"`
+
+	// Filters and Sort are resolved against the list's QueryFields, empty Sort being the list's default order.
+	Filters []FieldFilter `json:"filters"`
+	Sort    []SortField   `json:"sort"`
+
+	// Cursor switches the list from page to keyset pagination, see CursorQuery.
+	Cursor *CursorQuery `json:"cursor" validate:"omitempty"`
 }
 
 // DateRangeType
@@ -23,14 +32,16 @@ type DateRangeType struct {
 	EndTime   time.Time `json:"end_time"   validate:"required,gtfield=StartTime"`
 }
 
-// Search
+// Search matches Query against any of Columns.
 type Search struct {
 	Query        string   `json:"query"         validate:"required"`
-	SearchFields []string `json:"search_fields" validate:"required,min=1"`
+	SearchFields []string `json:"search_fields"`
+	Columns      []string `json:"columns"       validate:"required,min=1"`
 }
 
-// GenerateQuery takes a loook at what is coming from client and then generates a sieve
-func GenerateQuery(argument url.Values) (*FilterQuery, error) {
+// GenerateQuery takes a loook at what is coming from client and then generates a sieve. Filters, sort and search
+// may only name the fields allowed by fields.
+func GenerateQuery(argument url.Values, fields QueryFields) (*FilterQuery, error) {
 	filterResult := GenerateEmptyQuery()
 
 	page := argument.Get("page")
@@ -58,6 +69,10 @@ func GenerateQuery(argument url.Values) (*FilterQuery, error) {
 	// order
 	order := argument.Get("order")
 	if order != "" {
+		if order != "asc" && order != "desc" {
+			return nil, errors.New("order must be asc or desc")
+		}
+
 		filterResult.Order = order
 	}
 
@@ -67,6 +82,30 @@ func GenerateQuery(argument url.Values) (*FilterQuery, error) {
 		filterResult.OrderBy = orderBy
 	}
 
+	sortFields, err := getSort(argument, fields, filterResult.Order)
+	if err != nil {
+		return nil, err
+	}
+
+	filterResult.Sort = sortFields
+
+	// the first sort field is reported as order_by.
+	if sortParam := argument.Get("sort"); sortParam != "" {
+		first := strings.Split(sortParam, ",")[0]
+		filterResult.OrderBy = strings.TrimPrefix(first, "-")
+		filterResult.Order = "asc"
+		if strings.HasPrefix(first, "-") {
+			filterResult.Order = "desc"
+		}
+	}
+
+	filters, err := getFieldFilters(argument, fields)
+	if err != nil {
+		return nil, err
+	}
+
+	filterResult.Filters = filters
+
 	// dateRange
 	startDate := argument.Get("start_date")
 	endDate := argument.Get("end_date")
@@ -89,14 +128,57 @@ func GenerateQuery(argument url.Values) (*FilterQuery, error) {
 	}
 
 	query := argument.Get("query")
-	searchFields := argument.Get("search_fields")
 
-	if query != "" && searchFields != "" {
-		fields := strings.Split(searchFields, ",")
+	if query != "" {
+		var searchFields []string
+		if argument.Get("search_fields") != "" {
+			searchFields = strings.Split(argument.Get("search_fields"), ",")
+		}
+
+		columns, err := getSearchColumns(searchFields, fields)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(columns) == 0 {
+			return nil, errors.New("this list cannot be searched")
+		}
 
 		filterResult.Search = &Search{
 			Query:        query,
-			SearchFields: fields,
+			SearchFields: searchFields,
+			Columns:      columns,
+		}
+	}
+
+	// cursor, an empty one asks for the first page.
+	if argument.Has("cursor") {
+		if filterResult.OrderBy != "created_at" || len(filterResult.Sort) > 1 {
+			return nil, errors.New("cursor pagination is ordered by created_at only")
+		}
+
+		filterResult.Cursor = &CursorQuery{}
+
+		if cursor := argument.Get("cursor"); cursor != "" {
+			after, err := DecodeCursor(cursor)
+			if err != nil {
+				return nil, err
+			}
+
+			if after.Order != filterResult.Order {
+				return nil, errors.New("cursor was returned for order " + after.Order + ", not " + filterResult.Order)
+			}
+
+			filterResult.Cursor.After = after
+		}
+
+		if includeTotal := argument.Get("include_total"); includeTotal != "" {
+			include, err := strconv.ParseBool(includeTotal)
+			if err != nil {
+				return nil, errors.New("include_total must be true or false")
+			}
+
+			filterResult.Cursor.IncludeTotal = include
 		}
 	}
 
@@ -0,0 +1,206 @@
+package lib
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrUnknownCurrency  = errors.New("unknown currency")
+	ErrCurrencyMismatch = errors.New("amounts are in different currencies")
+	ErrMoneyOverflow    = errors.New("amount is too large")
+)
+
+// DefaultCurrency is the currency of clients that didn't pick one.
+const DefaultCurrency = "USD"
+
+// currencyExponents are the number of decimal places of the minor unit of each ISO 4217 currency, e.g. 2 for USD
+// cents. Currencies missing here are refused.
+var currencyExponents = map[string]int{
+	// no minor unit
+	"BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "ISK": 0, "JPY": 0, "KMF": 0, "KRW": 0, "PYG": 0, "RWF": 0,
+	"UGX": 0, "VND": 0, "VUV": 0, "XAF": 0, "XOF": 0, "XPF": 0,
+
+	"AED": 2, "ARS": 2, "AUD": 2, "BDT": 2, "BRL": 2, "BWP": 2, "CAD": 2, "CHF": 2, "CNY": 2, "COP": 2,
+	"CZK": 2, "DKK": 2, "EGP": 2, "ETB": 2, "EUR": 2, "GBP": 2, "GHS": 2, "GMD": 2, "HKD": 2, "HUF": 2,
+	"IDR": 2, "ILS": 2, "INR": 2, "KES": 2, "LKR": 2, "MAD": 2, "MUR": 2, "MWK": 2, "MXN": 2, "MYR": 2,
+	"MZN": 2, "NAD": 2, "NGN": 2, "NOK": 2, "NZD": 2, "PEN": 2, "PHP": 2, "PKR": 2, "PLN": 2, "QAR": 2,
+	"RON": 2, "RUB": 2, "SAR": 2, "SEK": 2, "SGD": 2, "SLE": 2, "THB": 2, "TRY": 2, "TWD": 2, "TZS": 2,
+	"UAH": 2, "USD": 2, "XCD": 2, "ZAR": 2, "ZMW": 2,
+
+	"BHD": 3, "IQD": 3, "JOD": 3, "KWD": 3, "LYD": 3, "OMR": 3, "TND": 3,
+
+	"CLF": 4, "UYW": 4,
+}
+
+// CurrencyExponent returns the number of decimal places of the currency's minor unit.
+func CurrencyExponent(currency string) (int, error) {
+	exponent, ok := currencyExponents[currency]
+	if !ok {
+		return 0, fmt.Errorf("%w %s", ErrUnknownCurrency, currency)
+	}
+
+	return exponent, nil
+}
+
+// IsCurrency tells whether currency is an ISO 4217 code amounts can be kept in.
+func IsCurrency(currency string) bool {
+	_, ok := currencyExponents[currency]
+	return ok
+}
+
+// Money is an amount in the minor unit of its currency, e.g. 1050 USD is $10.50 and 1050 JPY is ¥1050. Operations
+// never round silently, they fail instead on a currency mismatch or when the result doesn't fit an int64.
+type Money struct {
+	Amount   int64
+	Currency string
+}
+
+func NewMoney(amount int64, currency string) (Money, error) {
+	if !IsCurrency(currency) {
+		return Money{}, fmt.Errorf("%w %s", ErrUnknownCurrency, currency)
+	}
+
+	return Money{Amount: amount, Currency: currency}, nil
+}
+
+func (m Money) Add(other Money) (Money, error) {
+	if m.Currency != other.Currency {
+		return Money{}, ErrCurrencyMismatch
+	}
+
+	if (other.Amount > 0 && m.Amount > math.MaxInt64-other.Amount) ||
+		(other.Amount < 0 && m.Amount < math.MinInt64-other.Amount) {
+		return Money{}, ErrMoneyOverflow
+	}
+
+	return Money{Amount: m.Amount + other.Amount, Currency: m.Currency}, nil
+}
+
+func (m Money) Sub(other Money) (Money, error) {
+	negated, err := other.Negate()
+	if err != nil {
+		return Money{}, err
+	}
+
+	return m.Add(negated)
+}
+
+func (m Money) Negate() (Money, error) {
+	if m.Amount == math.MinInt64 {
+		return Money{}, ErrMoneyOverflow
+	}
+
+	return Money{Amount: -m.Amount, Currency: m.Currency}, nil
+}
+
+func (m Money) Multiply(factor int64) (Money, error) {
+	product := new(big.Int).Mul(big.NewInt(m.Amount), big.NewInt(factor))
+	if !product.IsInt64() {
+		return Money{}, ErrMoneyOverflow
+	}
+
+	return Money{Amount: product.Int64(), Currency: m.Currency}, nil
+}
+
+// Allocate splits the amount in proportion to ratios without losing any minor unit. The units left over by
+// rounding each share toward zero go one each to the first shares, so the same split always gives the same
+// result and the shares always add up to the amount.
+func (m Money) Allocate(ratios ...int64) ([]Money, error) {
+	if len(ratios) == 0 {
+		return nil, errors.New("at least one ratio is needed to allocate an amount")
+	}
+
+	total := new(big.Int)
+	for _, ratio := range ratios {
+		if ratio < 0 {
+			return nil, errors.New("ratios must not be negative")
+		}
+
+		total.Add(total, big.NewInt(ratio))
+	}
+
+	if total.Sign() == 0 {
+		return nil, errors.New("ratios must not all be zero")
+	}
+
+	shares := make([]Money, len(ratios))
+	remainder := m.Amount
+	for i, ratio := range ratios {
+		// |amount * ratio / total| <= |amount|, so the share always fits.
+		share := new(big.Int).Mul(big.NewInt(m.Amount), big.NewInt(ratio))
+		share.Quo(share, total)
+
+		shares[i] = Money{Amount: share.Int64(), Currency: m.Currency}
+		remainder -= share.Int64()
+	}
+
+	unit := int64(1)
+	if remainder < 0 {
+		unit = -1
+	}
+
+	for i := 0; remainder != 0; i++ {
+		// only shares with a ratio take a leftover unit, a zero ratio always gets nothing.
+		if ratios[i%len(ratios)] == 0 {
+			continue
+		}
+
+		shares[i%len(ratios)].Amount += unit
+		remainder -= unit
+	}
+
+	return shares, nil
+}
+
+// Split divides the amount into parts as even as can be, the first parts taking the leftover units.
+func (m Money) Split(parts int) ([]Money, error) {
+	if parts < 1 {
+		return nil, errors.New("an amount is split in at least one part")
+	}
+
+	ratios := make([]int64, parts)
+	for i := range ratios {
+		ratios[i] = 1
+	}
+
+	return m.Allocate(ratios...)
+}
+
+// Decimal formats the amount in major units with the currency's decimals, e.g. "10.50" for 1050 USD.
+func (m Money) Decimal() string {
+	exponent := currencyExponents[m.Currency]
+
+	digits := strconv.FormatUint(absInt64(m.Amount), 10)
+	if len(digits) <= exponent {
+		digits = strings.Repeat("0", exponent-len(digits)+1) + digits
+	}
+
+	sign := ""
+	if m.Amount < 0 {
+		sign = "-"
+	}
+
+	if exponent == 0 {
+		return sign + digits
+	}
+
+	return sign + digits[:len(digits)-exponent] + "." + digits[len(digits)-exponent:]
+}
+
+func (m Money) String() string {
+	return m.Decimal() + " " + m.Currency
+}
+
+// absInt64 returns the magnitude of value, which for math.MinInt64 only fits in a uint64.
+func absInt64(value int64) uint64 {
+	if value < 0 {
+		return uint64(-(value + 1)) + 1
+	}
+
+	return uint64(value)
+}
@@ -0,0 +1,187 @@
+package lib
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestMoneyAdd(t *testing.T) {
+	tests := []struct {
+		name  string
+		a     Money
+		b     Money
+		want  int64
+		error error
+	}{
+		{"positive", Money{1050, "USD"}, Money{250, "USD"}, 1300, nil},
+		{"negative", Money{-1050, "USD"}, Money{-250, "USD"}, -1300, nil},
+		{"mixed signs", Money{1050, "USD"}, Money{-2000, "USD"}, -950, nil},
+		{"up to the max", Money{math.MaxInt64 - 1, "USD"}, Money{1, "USD"}, math.MaxInt64, nil},
+		{"down to the min", Money{math.MinInt64 + 1, "USD"}, Money{-1, "USD"}, math.MinInt64, nil},
+		{"over the max", Money{math.MaxInt64, "USD"}, Money{1, "USD"}, 0, ErrMoneyOverflow},
+		{"under the min", Money{math.MinInt64, "USD"}, Money{-1, "USD"}, 0, ErrMoneyOverflow},
+		{"min and max", Money{math.MinInt64, "USD"}, Money{math.MaxInt64, "USD"}, -1, nil},
+		{"different currencies", Money{100, "USD"}, Money{100, "EUR"}, 0, ErrCurrencyMismatch},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sum, err := test.a.Add(test.b)
+			if !errors.Is(err, test.error) {
+				t.Fatalf("Add() error = %v, want %v", err, test.error)
+			}
+
+			if err == nil && sum.Amount != test.want {
+				t.Errorf("Add() = %d, want %d", sum.Amount, test.want)
+			}
+		})
+	}
+}
+
+func TestMoneySubAndNegate(t *testing.T) {
+	tests := []struct {
+		name  string
+		a     Money
+		b     Money
+		want  int64
+		error error
+	}{
+		{"positive", Money{1050, "USD"}, Money{250, "USD"}, 800, nil},
+		{"below zero", Money{250, "USD"}, Money{1050, "USD"}, -800, nil},
+		{"the min", Money{0, "USD"}, Money{math.MinInt64, "USD"}, 0, ErrMoneyOverflow},
+		{"under the min", Money{math.MinInt64, "USD"}, Money{1, "USD"}, 0, ErrMoneyOverflow},
+		{"different currencies", Money{100, "USD"}, Money{100, "EUR"}, 0, ErrCurrencyMismatch},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			difference, err := test.a.Sub(test.b)
+			if !errors.Is(err, test.error) {
+				t.Fatalf("Sub() error = %v, want %v", err, test.error)
+			}
+
+			if err == nil && difference.Amount != test.want {
+				t.Errorf("Sub() = %d, want %d", difference.Amount, test.want)
+			}
+		})
+	}
+}
+
+func TestMoneyMultiply(t *testing.T) {
+	tests := []struct {
+		name   string
+		amount int64
+		factor int64
+		want   int64
+		error  error
+	}{
+		{"positive", 1050, 3, 3150, nil},
+		{"negative factor", 1050, -3, -3150, nil},
+		{"zero", 1050, 0, 0, nil},
+		{"min times one", math.MinInt64, 1, math.MinInt64, nil},
+		{"min times minus one", math.MinInt64, -1, 0, ErrMoneyOverflow},
+		{"over the max", math.MaxInt64/2 + 1, 2, 0, ErrMoneyOverflow},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			product, err := Money{test.amount, "USD"}.Multiply(test.factor)
+			if !errors.Is(err, test.error) {
+				t.Fatalf("Multiply() error = %v, want %v", err, test.error)
+			}
+
+			if err == nil && product.Amount != test.want {
+				t.Errorf("Multiply() = %d, want %d", product.Amount, test.want)
+			}
+		})
+	}
+}
+
+func TestMoneyAllocate(t *testing.T) {
+	tests := []struct {
+		name    string
+		amount  int64
+		ratios  []int64
+		want    []int64
+		wantErr bool
+	}{
+		{"even", 100, []int64{1, 1}, []int64{50, 50}, false},
+		{"leftover to the first shares", 100, []int64{1, 1, 1}, []int64{34, 33, 33}, false},
+		{"by weight", 1000, []int64{70, 20, 10}, []int64{700, 200, 100}, false},
+		{"negative amount", -100, []int64{1, 1, 1}, []int64{-34, -33, -33}, false},
+		{"zero amount", 0, []int64{1, 2}, []int64{0, 0}, false},
+		{"zero ratio gets nothing", 5, []int64{0, 1, 1}, []int64{0, 3, 2}, false},
+		{"leftover skips zero ratios", 7, []int64{1, 0, 1, 1}, []int64{3, 0, 2, 2}, false},
+		{"single ratio", 999, []int64{3}, []int64{999}, false},
+		{"max", math.MaxInt64, []int64{1, 1}, []int64{math.MaxInt64/2 + 1, math.MaxInt64 / 2}, false},
+		{"min", math.MinInt64, []int64{1, 1}, []int64{math.MinInt64 / 2, math.MinInt64 / 2}, false},
+		{
+			"min by uneven ratios",
+			math.MinInt64,
+			[]int64{1, 2},
+			[]int64{-3074457345618258603, -6148914691236517205},
+			false,
+		},
+		{"no ratios", 100, nil, nil, true},
+		{"all ratios zero", 100, []int64{0, 0}, nil, true},
+		{"negative ratio", 100, []int64{2, -1}, nil, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			shares, err := Money{test.amount, "USD"}.Allocate(test.ratios...)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("Allocate() error = %v, want error %v", err, test.wantErr)
+			}
+
+			if test.wantErr {
+				return
+			}
+
+			if len(shares) != len(test.want) {
+				t.Fatalf("Allocate() = %d shares, want %d", len(shares), len(test.want))
+			}
+
+			var total int64
+			for i, share := range shares {
+				if share.Amount != test.want[i] {
+					t.Errorf("share %d = %d, want %d", i, share.Amount, test.want[i])
+				}
+
+				if share.Currency != "USD" {
+					t.Errorf("share %d currency = %s, want USD", i, share.Currency)
+				}
+
+				total += share.Amount
+			}
+
+			if total != test.amount {
+				t.Errorf("shares add up to %d, want %d", total, test.amount)
+			}
+		})
+	}
+}
+
+func TestMoneyDecimal(t *testing.T) {
+	tests := []struct {
+		money Money
+		want  string
+	}{
+		{Money{1050, "USD"}, "10.50"},
+		{Money{5, "USD"}, "0.05"},
+		{Money{-5, "USD"}, "-0.05"},
+		{Money{0, "USD"}, "0.00"},
+		{Money{1050, "JPY"}, "1050"},
+		{Money{1234, "KWD"}, "1.234"},
+		{Money{math.MinInt64, "USD"}, "-92233720368547758.08"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.want, func(t *testing.T) {
+			if got := test.money.Decimal(); got != test.want {
+				t.Errorf("Decimal() = %s, want %s", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,219 @@
+package lib
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// FieldType decides how the values a field is filtered with are parsed, and which operators it takes.
+type FieldType int
+
+const (
+	FieldString FieldType = iota
+	FieldNumber
+	FieldBool
+	FieldTime
+	FieldUUID
+)
+
+// QueryField is a field a list can be filtered and sorted on. Only Column reaches the SQL, the names clients send
+// never do.
+type QueryField struct {
+	Column string
+	Type   FieldType
+	// Searchable fields are the ones `query` looks into, FieldString only.
+	Searchable bool
+}
+
+// QueryFields is the allowlist of the fields of one list, keyed by the name clients use.
+type QueryFields map[string]QueryField
+
+// FieldFilter is a `filter[<field>][<operator>]=<value>` condition, resolved against the list's QueryFields.
+type FieldFilter struct {
+	Column   string
+	Operator string
+	Values   []any
+}
+
+// SortField is one column of a `sort`, resolved against the list's QueryFields.
+type SortField struct {
+	Column string
+	Desc   bool
+}
+
+const (
+	OperatorEq       = "eq"
+	OperatorIn       = "in"
+	OperatorGte      = "gte"
+	OperatorLte      = "lte"
+	OperatorContains = "contains"
+)
+
+var filterParamPattern = regexp.MustCompile(`^filter\[([a-z_]+)\](?:\[([a-z]+)\])?$`)
+
+// getFieldFilters reads the `filter[<field>][<operator>]=<value>` params, `filter[<field>]=<value>` being eq. in
+// takes comma separated values.
+func getFieldFilters(argument url.Values, fields QueryFields) ([]FieldFilter, error) {
+	var filters []FieldFilter
+
+	keys := make([]string, 0, len(argument))
+	for key := range argument {
+		if strings.HasPrefix(key, "filter[") {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		values := argument[key]
+
+		match := filterParamPattern.FindStringSubmatch(key)
+		if match == nil {
+			return nil, fmt.Errorf("invalid filter %s", key)
+		}
+
+		field, ok := fields[match[1]]
+		if !ok {
+			return nil, fmt.Errorf("cannot filter on unknown field %s", match[1])
+		}
+
+		operator := match[2]
+		if operator == "" {
+			operator = OperatorEq
+		}
+
+		if !fieldTakesOperator(field.Type, operator) {
+			return nil, fmt.Errorf("cannot filter %s with %s", match[1], operator)
+		}
+
+		for _, value := range values {
+			raw := []string{value}
+			if operator == OperatorIn {
+				raw = strings.Split(value, ",")
+			}
+
+			filter := FieldFilter{Column: field.Column, Operator: operator}
+			for _, item := range raw {
+				parsed, err := parseFieldValue(field.Type, item)
+				if err != nil {
+					return nil, fmt.Errorf("invalid value for %s: %w", match[1], err)
+				}
+
+				filter.Values = append(filter.Values, parsed)
+			}
+
+			filters = append(filters, filter)
+		}
+	}
+
+	return filters, nil
+}
+
+func fieldTakesOperator(fieldType FieldType, operator string) bool {
+	switch operator {
+	case OperatorEq, OperatorIn:
+		return true
+	case OperatorGte, OperatorLte:
+		return fieldType == FieldNumber || fieldType == FieldTime
+	case OperatorContains:
+		return fieldType == FieldString
+	}
+
+	return false
+}
+
+func parseFieldValue(fieldType FieldType, value string) (any, error) {
+	switch fieldType {
+	case FieldNumber:
+		return strconv.ParseInt(value, 10, 64)
+	case FieldBool:
+		return strconv.ParseBool(value)
+	case FieldTime:
+		return time.Parse(time.RFC3339, value)
+	case FieldUUID:
+		id, err := uuid.FromString(value)
+		if err != nil {
+			return nil, err
+		}
+
+		return id.String(), nil
+	}
+
+	return value, nil
+}
+
+// getSort reads `sort=<field>,-<field>`, a leading - sorting that field descending. Lists that predate it are
+// still sorted by `order_by` and `order`. Nothing is returned when neither is sent, the default order being the
+// list's own.
+func getSort(argument url.Values, fields QueryFields, order string) ([]SortField, error) {
+	names := argument.Get("sort")
+	if names == "" {
+		orderBy := argument.Get("order_by")
+		if orderBy == "" {
+			return nil, nil
+		}
+
+		names = orderBy
+		if order == "desc" {
+			names = "-" + orderBy
+		}
+	}
+
+	var sortFields []SortField
+	for _, name := range strings.Split(names, ",") {
+		desc := strings.HasPrefix(name, "-")
+		name = strings.TrimPrefix(name, "-")
+
+		field, ok := fields[name]
+		if !ok {
+			return nil, fmt.Errorf("cannot sort on unknown field %s", name)
+		}
+
+		sortFields = append(sortFields, SortField{Column: field.Column, Desc: desc})
+	}
+
+	return sortFields, nil
+}
+
+// getSearchColumns returns the columns `query` looks into, the searchable fields among search_fields or all of
+// them when it isn't sent.
+func getSearchColumns(searchFields []string, fields QueryFields) ([]string, error) {
+	var columns []string
+
+	if len(searchFields) == 0 {
+		for _, field := range fields {
+			if field.Searchable {
+				columns = append(columns, field.Column)
+			}
+		}
+
+		// in a stable order, so the same search always makes the same statement.
+		sort.Strings(columns)
+
+		return columns, nil
+	}
+
+	for _, name := range searchFields {
+		field, ok := fields[name]
+		if !ok || !field.Searchable {
+			return nil, fmt.Errorf("cannot search unknown field %s", name)
+		}
+
+		columns = append(columns, field.Column)
+	}
+
+	return columns, nil
+}
+
+// EscapeLike escapes the wildcards of a value matched with LIKE, so it is matched literally.
+func EscapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+}
@@ -0,0 +1,242 @@
+package lib
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+)
+
+var testQueryFields = QueryFields{
+	"name":       {Column: "accounts.name", Type: FieldString, Searchable: true},
+	"code":       {Column: "accounts.code", Type: FieldString, Searchable: true},
+	"type":       {Column: "accounts.type", Type: FieldString},
+	"amount":     {Column: "accounts.overdraft_limit", Type: FieldNumber},
+	"is_group":   {Column: "accounts.is_group", Type: FieldBool},
+	"created_at": {Column: "accounts.created_at", Type: FieldTime},
+	"parent_id":  {Column: "accounts.parent_account_id", Type: FieldUUID},
+}
+
+func TestGetFieldFilters(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   url.Values
+		want    []FieldFilter
+		wantErr bool
+	}{
+		{"none", url.Values{"page": {"1"}}, nil, false},
+		{
+			"eq by default",
+			url.Values{"filter[name]": {"Cash"}},
+			[]FieldFilter{{Column: "accounts.name", Operator: OperatorEq, Values: []any{"Cash"}}},
+			false,
+		},
+		{
+			"in splits values",
+			url.Values{"filter[type]": {"ASSET,LIABILITY"}, "filter[type][in]": {"ASSET,LIABILITY"}},
+			[]FieldFilter{
+				{Column: "accounts.type", Operator: OperatorEq, Values: []any{"ASSET,LIABILITY"}},
+				{Column: "accounts.type", Operator: OperatorIn, Values: []any{"ASSET", "LIABILITY"}},
+			},
+			false,
+		},
+		{
+			"number range",
+			url.Values{"filter[amount][gte]": {"100"}, "filter[amount][lte]": {"-5"}},
+			[]FieldFilter{
+				{Column: "accounts.overdraft_limit", Operator: OperatorGte, Values: []any{int64(100)}},
+				{Column: "accounts.overdraft_limit", Operator: OperatorLte, Values: []any{int64(-5)}},
+			},
+			false,
+		},
+		{
+			"bool",
+			url.Values{"filter[is_group]": {"true"}},
+			[]FieldFilter{{Column: "accounts.is_group", Operator: OperatorEq, Values: []any{true}}},
+			false,
+		},
+		{
+			"time",
+			url.Values{"filter[created_at][gte]": {"2026-01-01T00:00:00Z"}},
+			[]FieldFilter{{
+				Column:   "accounts.created_at",
+				Operator: OperatorGte,
+				Values:   []any{time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)},
+			}},
+			false,
+		},
+		{
+			"uuid is normalized",
+			url.Values{"filter[parent_id]": {"7E2E0544-931C-4C07-A761-5AE95202D4E1"}},
+			[]FieldFilter{{
+				Column:   "accounts.parent_account_id",
+				Operator: OperatorEq,
+				Values:   []any{"7e2e0544-931c-4c07-a761-5ae95202d4e1"},
+			}},
+			false,
+		},
+		{
+			"contains on a string",
+			url.Values{"filter[name][contains]": {"100%_off"}},
+			[]FieldFilter{{Column: "accounts.name", Operator: OperatorContains, Values: []any{"100%_off"}}},
+			false,
+		},
+		{
+			"injection looking value is only a value",
+			url.Values{"filter[name]": {"x' OR '1'='1"}},
+			[]FieldFilter{{Column: "accounts.name", Operator: OperatorEq, Values: []any{"x' OR '1'='1"}}},
+			false,
+		},
+		{"unknown field", url.Values{"filter[password]": {"x"}}, nil, true},
+		{"column name instead of field", url.Values{"filter[overdraft_limit]": {"1"}}, nil, true},
+		{"unknown operator", url.Values{"filter[name][like]": {"x"}}, nil, true},
+		{"range on a string", url.Values{"filter[name][gte]": {"a"}}, nil, true},
+		{"range on a bool", url.Values{"filter[is_group][lte]": {"true"}}, nil, true},
+		{"contains on a number", url.Values{"filter[amount][contains]": {"1"}}, nil, true},
+		{"not a number", url.Values{"filter[amount]": {"1 OR 1=1"}}, nil, true},
+		{"decimal number", url.Values{"filter[amount]": {"1.5"}}, nil, true},
+		{"not a bool", url.Values{"filter[is_group]": {"yes"}}, nil, true},
+		{"not a time", url.Values{"filter[created_at][gte]": {"2026-01-01"}}, nil, true},
+		{"not a uuid", url.Values{"filter[parent_id]": {"'; DROP TABLE accounts; --"}}, nil, true},
+		{"one bad value in a list", url.Values{"filter[amount][in]": {"1,two,3"}}, nil, true},
+		{"sql in the field", url.Values{"filter[name;DROP TABLE accounts]": {"x"}}, nil, true},
+		{"uppercase field", url.Values{"filter[NAME]": {"x"}}, nil, true},
+		{"nested brackets", url.Values{"filter[name][eq][eq]": {"x"}}, nil, true},
+		{"unclosed bracket", url.Values{"filter[name": {"x"}}, nil, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			filters, err := getFieldFilters(test.query, testQueryFields)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("getFieldFilters() error = %v, want error %v", err, test.wantErr)
+			}
+
+			if !reflect.DeepEqual(filters, test.want) {
+				t.Errorf("getFieldFilters() = %+v, want %+v", filters, test.want)
+			}
+		})
+	}
+}
+
+func TestGetSort(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   url.Values
+		order   string
+		want    []SortField
+		wantErr bool
+	}{
+		{"list default", url.Values{}, "desc", nil, false},
+		{"ascending", url.Values{"sort": {"name"}}, "desc", []SortField{{Column: "accounts.name"}}, false},
+		{
+			"descending",
+			url.Values{"sort": {"-name"}},
+			"desc",
+			[]SortField{{Column: "accounts.name", Desc: true}},
+			false,
+		},
+		{
+			"several",
+			url.Values{"sort": {"type,-created_at"}},
+			"desc",
+			[]SortField{{Column: "accounts.type"}, {Column: "accounts.created_at", Desc: true}},
+			false,
+		},
+		{
+			"order_by ascending",
+			url.Values{"order_by": {"code"}},
+			"asc",
+			[]SortField{{Column: "accounts.code"}},
+			false,
+		},
+		{
+			"order_by descending",
+			url.Values{"order_by": {"code"}},
+			"desc",
+			[]SortField{{Column: "accounts.code", Desc: true}},
+			false,
+		},
+		{
+			"sort wins over order_by",
+			url.Values{"sort": {"name"}, "order_by": {"code"}},
+			"desc",
+			[]SortField{{Column: "accounts.name"}},
+			false,
+		},
+		{"unknown field", url.Values{"sort": {"password"}}, "desc", nil, true},
+		{"unknown order_by", url.Values{"order_by": {"accounts.name"}}, "asc", nil, true},
+		{"one unknown among several", url.Values{"sort": {"name,-secret"}}, "desc", nil, true},
+		{"empty field", url.Values{"sort": {"name,"}}, "desc", nil, true},
+		{"double minus", url.Values{"sort": {"--name"}}, "desc", nil, true},
+		{"plus prefix", url.Values{"sort": {"+name"}}, "desc", nil, true},
+		{"sql direction", url.Values{"sort": {"name desc"}}, "desc", nil, true},
+		{"injection", url.Values{"order_by": {"name; DROP TABLE accounts"}}, "asc", nil, true},
+		{"subquery", url.Values{"sort": {"(SELECT 1)"}}, "desc", nil, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sortFields, err := getSort(test.query, testQueryFields, test.order)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("getSort() error = %v, want error %v", err, test.wantErr)
+			}
+
+			if !reflect.DeepEqual(sortFields, test.want) {
+				t.Errorf("getSort() = %+v, want %+v", sortFields, test.want)
+			}
+		})
+	}
+}
+
+func TestGetSearchColumns(t *testing.T) {
+	tests := []struct {
+		name         string
+		searchFields []string
+		want         []string
+		wantErr      bool
+	}{
+		{"all searchable in a stable order", nil, []string{"accounts.code", "accounts.name"}, false},
+		{"picked", []string{"name"}, []string{"accounts.name"}, false},
+		{"picked in the order sent", []string{"name", "code"}, []string{"accounts.name", "accounts.code"}, false},
+		{"not searchable", []string{"type"}, nil, true},
+		{"not a string", []string{"amount"}, nil, true},
+		{"unknown", []string{"password"}, nil, true},
+		{"column name", []string{"accounts.name"}, nil, true},
+		{"injection", []string{"name) OR 1=1 --"}, nil, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			columns, err := getSearchColumns(test.searchFields, testQueryFields)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("getSearchColumns() error = %v, want error %v", err, test.wantErr)
+			}
+
+			if !reflect.DeepEqual(columns, test.want) {
+				t.Errorf("getSearchColumns() = %v, want %v", columns, test.want)
+			}
+		})
+	}
+}
+
+func TestEscapeLike(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"cash", "cash"},
+		{"100%", `100\%`},
+		{"a_b", `a\_b`},
+		{`back\slash`, `back\\slash`},
+		{`%_\`, `\%\_\\`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.value, func(t *testing.T) {
+			if got := EscapeLike(test.value); got != test.want {
+				t.Errorf("EscapeLike(%q) = %q, want %q", test.value, got, test.want)
+			}
+		})
+	}
+}
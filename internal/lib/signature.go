@@ -0,0 +1,45 @@
+package lib
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// requestSigningKeyLabel tells the signing key derived from a secret apart from any other use of the secret.
+const requestSigningKeyLabel = "fincore-request-signing"
+
+// RequestSigningKey derives the key requests are signed with from an api key secret, HMAC-SHA256 of the label
+// keyed with the secret. Clients derive it the same way, the secret itself never has to be sent.
+func RequestSigningKey(secret string) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(requestSigningKeyLabel))
+
+	return mac.Sum(nil)
+}
+
+// SignRequest returns the hex encoded HMAC-SHA256 of the request's method, path with its query string,
+// timestamp, nonce and hex encoded SHA-256 of its body, one per line.
+func SignRequest(
+	signingKey []byte,
+	method string,
+	requestURI string,
+	timestamp string,
+	nonce string,
+	body []byte,
+) string {
+	bodyHash := sha256.Sum256(body)
+	stringToSign := strings.Join([]string{
+		strings.ToUpper(method),
+		requestURI,
+		timestamp,
+		nonce,
+		hex.EncodeToString(bodyHash[:]),
+	}, "\n")
+
+	mac := hmac.New(sha256.New, signingKey)
+	mac.Write([]byte(stringToSign))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,61 @@
+package lib
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func TestRequestSigningKey(t *testing.T) {
+	want := "a57f1c75ba2d698caaa37a5332df97d100ad8937cff0fb40100d37d5874c31ee"
+
+	if got := hex.EncodeToString(RequestSigningKey("secret")); got != want {
+		t.Errorf("RequestSigningKey() = %s, want %s", got, want)
+	}
+}
+
+func TestSignRequest(t *testing.T) {
+	key := RequestSigningKey("secret")
+	body := []byte(`{"name":"Cash"}`)
+	signature := "f97dd4a9f5db55317fd7d404a8aeaa15f2499dc8fa3e2f2795b4c81384260c74"
+
+	tests := []struct {
+		name       string
+		key        []byte
+		method     string
+		requestURI string
+		timestamp  string
+		nonce      string
+		body       []byte
+		want       string
+		same       bool
+	}{
+		{"known request", key, "POST", "/api/v1/accounts?x=1", "1760000000", "nonce-1", body, signature, true},
+		{"lowercase method", key, "post", "/api/v1/accounts?x=1", "1760000000", "nonce-1", body, signature, true},
+		{
+			"empty body",
+			key, "GET", "/api/v1/accounts", "1760000000", "nonce-1", nil,
+			"908009a4acd4c15e21b13414e8080c0917815cff93b4e7083bf36693b6c6be58",
+			true,
+		},
+		{
+			"other key",
+			RequestSigningKey("other"), "POST", "/api/v1/accounts?x=1", "1760000000", "nonce-1", body,
+			signature,
+			false,
+		},
+		{"other method", key, "PUT", "/api/v1/accounts?x=1", "1760000000", "nonce-1", body, signature, false},
+		{"other query", key, "POST", "/api/v1/accounts?x=2", "1760000000", "nonce-1", body, signature, false},
+		{"other timestamp", key, "POST", "/api/v1/accounts?x=1", "1760000001", "nonce-1", body, signature, false},
+		{"other nonce", key, "POST", "/api/v1/accounts?x=1", "1760000000", "nonce-2", body, signature, false},
+		{"other body", key, "POST", "/api/v1/accounts?x=1", "1760000000", "nonce-1", []byte(`{}`), signature, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := SignRequest(test.key, test.method, test.requestURI, test.timestamp, test.nonce, test.body)
+			if (got == test.want) != test.same {
+				t.Errorf("SignRequest() = %s, want same as %s: %v", got, test.want, test.same)
+			}
+		})
+	}
+}
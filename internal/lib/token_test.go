@@ -0,0 +1,77 @@
+package lib
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseToken(t *testing.T) {
+	key := []byte("token-signing-key")
+	issuedAt := time.Unix(1760000000, 0)
+
+	claims := TokenClaims{
+		Issuer:    "fincore",
+		Subject:   "client-id",
+		ClientID:  "client-id",
+		ApiKeyID:  "api-key-id",
+		Scope:     "accounts:read",
+		ID:        "token-id",
+		IssuedAt:  issuedAt.Unix(),
+		ExpiresAt: issuedAt.Add(15 * time.Minute).Unix(),
+	}
+
+	token, err := SignToken(claims, key)
+	if err != nil {
+		t.Fatalf("SignToken() error = %v", err)
+	}
+
+	parts := strings.Split(token, ".")
+	forged, _ := SignToken(TokenClaims{Subject: "other-client", ExpiresAt: claims.ExpiresAt}, key)
+	forgedParts := strings.Split(forged, ".")
+	noneHeader := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none","typ":"JWT"}`))
+
+	tests := []struct {
+		name    string
+		token   string
+		key     []byte
+		now     time.Time
+		wantErr bool
+	}{
+		{"valid", token, key, issuedAt, false},
+		{"just before expiry", token, key, issuedAt.Add(15*time.Minute - time.Second), false},
+		{"at expiry", token, key, issuedAt.Add(15 * time.Minute), true},
+		{"expired", token, key, issuedAt.Add(time.Hour), true},
+		{"other key", token, []byte("other-key"), issuedAt, true},
+		{"tampered payload", parts[0] + "." + forgedParts[1] + "." + parts[2], key, issuedAt, true},
+		{"tampered signature", parts[0] + "." + parts[1] + "." + forgedParts[2], key, issuedAt, true},
+		{"no signature", parts[0] + "." + parts[1] + ".", key, issuedAt, true},
+		{"alg none", noneHeader + "." + parts[1] + ".", key, issuedAt, true},
+		{"too few parts", parts[0] + "." + parts[1], key, issuedAt, true},
+		{"too many parts", token + "." + parts[2], key, issuedAt, true},
+		{"empty", "", key, issuedAt, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			parsed, err := ParseToken(test.token, test.key, test.now)
+			if test.wantErr {
+				if !errors.Is(err, ErrInvalidToken) {
+					t.Fatalf("ParseToken() error = %v, want %v", err, ErrInvalidToken)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("ParseToken() error = %v", err)
+			}
+
+			if *parsed != claims {
+				t.Errorf("ParseToken() = %+v, want %+v", *parsed, claims)
+			}
+		})
+	}
+}
@@ -1,27 +1,122 @@
 package middleware
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
+	"strings"
 
 	"github.com/Bendomey/fincore-engine/internal/lib"
+	"github.com/Bendomey/fincore-engine/internal/models"
+	"github.com/Bendomey/fincore-engine/internal/services"
 	"github.com/Bendomey/fincore-engine/pkg"
 )
 
+// maxBufferedBodySize caps the bodies read into memory before the handler runs, to sign or replay them. It
+// leaves room for the largest body a route takes, a 10MB attachment upload and its multipart framing.
+const maxBufferedBodySize = 11 << 20
+
+// readBufferedBody reads the request body, capped at maxBufferedBodySize, and puts it back for the handler.
+func readBufferedBody(w http.ResponseWriter, r *http.Request) ([]byte, error) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBufferedBodySize))
+	if err != nil {
+		return nil, err
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	return body, nil
+}
+
+// bufferedBodyErrorStatus is the status for a body that could not be read, 413 when it is over the cap.
+func bufferedBodyErrorStatus(err error) int {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return http.StatusRequestEntityTooLarge
+	}
+
+	return http.StatusBadRequest
+}
+
 func VerifyAuthMiddleware(appCtx pkg.AppContext) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// access tokens from /oauth/token, verified without hashing a secret.
+			if token, isBearer := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); isBearer {
+				accessToken, err := appCtx.Services.AccessTokenService.AuthenticateAccessToken(r.Context(), token)
+				if err != nil {
+					w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+					http.Error(w, "Unauthorized", http.StatusUnauthorized)
+					return
+				}
+
+				ctx := lib.WithClient(r.Context(), &accessToken.ApiKey.Client)
+				ctx = lib.WithCredential(ctx, accessToken.ApiKey.CredentialID)
+				ctx = lib.WithCredentialExpiry(ctx, accessToken.ExpiresAt)
+				ctx = lib.WithScopes(ctx, accessToken.Scopes)
+				next.ServeHTTP(w, r.WithContext(ctx))
+				return
+			}
+
+			// signed requests, so the secret never travels.
+			if signature := r.Header.Get("X-FinCore-Signature"); signature != "" {
+				body, err := readBufferedBody(w, r)
+				if err != nil {
+					http.Error(w, "Invalid body", bufferedBodyErrorStatus(err))
+					return
+				}
+
+				apiKey, err := appCtx.Services.RequestSignatureService.AuthenticateSignedRequest(
+					r.Context(),
+					services.SignedRequest{
+						ClientID:   r.Header.Get("X-FinCore-Client-Id"),
+						KeyID:      r.Header.Get("X-FinCore-Key-Id"),
+						Method:     r.Method,
+						RequestURI: r.RequestURI,
+						Body:       body,
+						Timestamp:  r.Header.Get("X-FinCore-Timestamp"),
+						Nonce:      r.Header.Get("X-FinCore-Nonce"),
+						Signature:  signature,
+					},
+				)
+				if err != nil {
+					switch {
+					case errors.Is(err, services.ErrInvalidRequestSignature),
+						errors.Is(err, services.ErrStaleRequestTimestamp),
+						errors.Is(err, services.ErrRequestNonceReplayed):
+						http.Error(w, "Unauthorized: "+err.Error(), http.StatusUnauthorized)
+					default:
+						http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+					}
+					return
+				}
+
+				ctx := lib.WithClient(r.Context(), &apiKey.Client)
+				ctx = lib.WithCredential(ctx, apiKey.CredentialID)
+				ctx = withApiKeyExpiry(ctx, apiKey)
+				ctx = lib.WithScopes(ctx, apiKey.Scopes)
+				next.ServeHTTP(w, r.WithContext(ctx))
+				return
+			}
+
 			clientId := r.Header.Get("X-FinCore-Client-Id")
 			clientSecret := r.Header.Get("X-FinCore-Client-Secret")
 
 			if clientId != "" && clientSecret != "" {
-				client, err := appCtx.Services.ClientService.AuthenticateClient(r.Context(), clientId, clientSecret)
-				if err != nil || client == nil {
+				// the secret may be any of the client's active api keys.
+				apiKey, err := appCtx.Services.ApiKeyService.AuthenticateApiKey(r.Context(), clientId, clientSecret)
+				if err != nil || apiKey == nil {
 					http.Error(w, "Unauthorized", http.StatusUnauthorized)
 					return
 				}
 
-				// Attach client to context
-				ctx := lib.WithClient(r.Context(), client)
+				// Attach client, the credential used and what it may do to context
+				ctx := lib.WithClient(r.Context(), &apiKey.Client)
+				ctx = lib.WithCredential(ctx, apiKey.CredentialID)
+				ctx = withApiKeyExpiry(ctx, apiKey)
+				ctx = lib.WithScopes(ctx, apiKey.Scopes)
 				next.ServeHTTP(w, r.WithContext(ctx))
 				return
 			}
@@ -31,6 +126,15 @@ func VerifyAuthMiddleware(appCtx pkg.AppContext) func(http.Handler) http.Handler
 	}
 }
 
+// withApiKeyExpiry attaches the expiry of the api key that authenticated the request, when it has one.
+func withApiKeyExpiry(ctx context.Context, apiKey *models.ApiKey) context.Context {
+	if apiKey.ExpiresAt == nil {
+		return ctx
+	}
+
+	return lib.WithCredentialExpiry(ctx, *apiKey.ExpiresAt)
+}
+
 func CheckForAuthPresenceMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		client, ok := lib.ClientFromContext(r.Context())
@@ -42,3 +146,25 @@ func CheckForAuthPresenceMiddleware(next http.Handler) http.Handler {
 		next.ServeHTTP(w, r)
 	})
 }
+
+// RequireScopesMiddleware turns away requests whose credential wasn't granted every one of the scopes.
+func RequireScopesMiddleware(scopes ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			for _, scope := range scopes {
+				if !lib.HasScope(r.Context(), scope) {
+					w.Header().Set("Content-Type", "application/json")
+					w.WriteHeader(http.StatusForbidden)
+					json.NewEncoder(w).Encode(map[string]any{
+						"errors": map[string]string{
+							"message": "the credential used is missing the " + scope + " scope",
+						},
+					})
+					return
+				}
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
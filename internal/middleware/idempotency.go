@@ -0,0 +1,154 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/Bendomey/fincore-engine/internal/lib"
+	"github.com/Bendomey/fincore-engine/internal/services"
+	"github.com/Bendomey/fincore-engine/pkg"
+	"github.com/getsentry/raven-go"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+const maxIdempotencyKeyLength = 255
+
+// IdempotencyMiddleware makes writes sent with an Idempotency-Key header safe to retry. The first response is
+// stored per client and key, retries of the same request get it back with an Idempotent-Replayed header, and
+// the same key with a different request is rejected.
+func IdempotencyMiddleware(appCtx pkg.AppContext) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get("Idempotency-Key")
+			client, clientOk := lib.ClientFromContext(r.Context())
+
+			if key == "" || !clientOk || !isWriteMethod(r.Method) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if len(key) > maxIdempotencyKeyLength {
+				writeIdempotencyError(w, http.StatusBadRequest, "Idempotency-Key must be at most 255 characters")
+				return
+			}
+
+			body, err := readBufferedBody(w, r)
+			if err != nil {
+				writeIdempotencyError(w, bufferedBodyErrorStatus(err), err.Error())
+				return
+			}
+
+			idempotencyKey, isNew, err := appCtx.Services.IdempotencyKeyService.BeginIdempotentRequest(
+				r.Context(),
+				services.BeginIdempotentRequestInput{
+					ClientID:    client.ID.String(),
+					Key:         key,
+					RequestHash: hashIdempotentRequest(r, body),
+				},
+			)
+			if err != nil {
+				switch {
+				case errors.Is(err, services.ErrIdempotencyKeyReused):
+					writeIdempotencyError(w, http.StatusUnprocessableEntity, err.Error())
+				case errors.Is(err, services.ErrIdempotencyKeyInProgress):
+					writeIdempotencyError(w, http.StatusConflict, err.Error())
+				default:
+					writeIdempotencyError(w, http.StatusInternalServerError, err.Error())
+				}
+				return
+			}
+
+			if !isNew {
+				if idempotencyKey.ResponseContentType != nil && *idempotencyKey.ResponseContentType != "" {
+					w.Header().Set("Content-Type", *idempotencyKey.ResponseContentType)
+				}
+				w.Header().Set("Idempotent-Replayed", "true")
+				w.WriteHeader(*idempotencyKey.ResponseStatus)
+				w.Write(idempotencyKey.ResponseBody)
+				return
+			}
+
+			var response bytes.Buffer
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			ww.Tee(&response)
+
+			// free the key if the handler panics, Recoverer answers with a 500 further up.
+			completed := false
+			defer func() {
+				if completed {
+					return
+				}
+				releaseErr := appCtx.Services.IdempotencyKeyService.ReleaseIdempotentRequest(
+					r.Context(),
+					idempotencyKey,
+				)
+				if releaseErr != nil {
+					raven.CaptureError(releaseErr, nil)
+				}
+			}()
+
+			next.ServeHTTP(ww, r)
+
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+
+			// server errors are not replayed, the retry should get a chance to succeed.
+			if status >= http.StatusInternalServerError {
+				return
+			}
+
+			err = appCtx.Services.IdempotencyKeyService.CompleteIdempotentRequest(
+				r.Context(),
+				services.CompleteIdempotentRequestInput{
+					IdempotencyKey: idempotencyKey,
+					Status:         status,
+					ContentType:    ww.Header().Get("Content-Type"),
+					Body:           response.Bytes(),
+				},
+			)
+			if err != nil {
+				raven.CaptureError(err, nil)
+				return
+			}
+
+			completed = true
+		})
+	}
+}
+
+func isWriteMethod(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+
+	return false
+}
+
+func hashIdempotentRequest(r *http.Request, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(r.Method + " " + r.URL.Path + "\n"))
+	// the same write to another ledger is another request.
+	if ledgerID := r.Header.Get(LedgerHeader); ledgerID != "" {
+		hash.Write([]byte(LedgerHeader + ": " + ledgerID + "\n"))
+	}
+	hash.Write(body)
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+func writeIdempotencyError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]any{
+		"errors": map[string]string{
+			"message": message,
+		},
+	})
+}
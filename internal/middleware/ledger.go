@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/Bendomey/fincore-engine/internal/lib"
+	"github.com/Bendomey/fincore-engine/internal/services"
+	"github.com/Bendomey/fincore-engine/pkg"
+)
+
+// LedgerHeader selects the ledger of the client a request works in.
+const LedgerHeader = "X-FinCore-Ledger-Id"
+
+// SelectLedgerMiddleware attaches the ledger named by the X-FinCore-Ledger-Id header, or the client's default
+// ledger without one. It goes after CheckForAuthPresenceMiddleware.
+func SelectLedgerMiddleware(appCtx pkg.AppContext) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			client, clientOk := lib.ClientFromContext(r.Context())
+			if !clientOk {
+				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+				return
+			}
+
+			ledger, err := appCtx.Services.LedgerService.SelectLedger(
+				r.Context(),
+				client.ID.String(),
+				lib.NullOrString(r.Header.Get(LedgerHeader)),
+			)
+			if err != nil {
+				status := http.StatusInternalServerError
+				if errors.Is(err, services.ErrLedgerNotFound) {
+					status = http.StatusNotFound
+				}
+
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(status)
+				json.NewEncoder(w).Encode(map[string]any{
+					"errors": map[string]string{
+						"message": err.Error(),
+					},
+				})
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(lib.WithLedger(r.Context(), ledger)))
+		})
+	}
+}
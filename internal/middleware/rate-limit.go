@@ -4,6 +4,8 @@ import (
 	"net/http"
 	"sync"
 
+	"github.com/Bendomey/fincore-engine/internal/lib"
+
 	"golang.org/x/time/rate"
 )
 
@@ -27,10 +29,11 @@ func getLimiter(key string) *rate.Limiter {
 
 func RateLimitMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		client := r.Header.Get("X-FinCore-Client-Id")
+		// whichever way the request authenticated, it counts against its client.
+		client, ok := lib.ClientFromContext(r.Context())
 
-		if client != "" {
-			limiter := getLimiter(client)
+		if ok && client != nil {
+			limiter := getLimiter(client.ClientId)
 
 			if !limiter.Allow() {
 				http.Error(w, "Too Many Requests", http.StatusTooManyRequests)
@@ -11,7 +11,11 @@ type Account struct {
 	ClientID string `json:"client_id" gorm:"not null;index;"`
 	Client   Client
 
-	Code        string  `json:"code"        gorm:"not null;uniqueIndex;"`
+	// codes are numbered within the account's ledger.
+	LedgerID string `json:"ledger_id" gorm:"index;uniqueIndex:idx_accounts_ledger_id_code;"`
+	Ledger   Ledger
+
+	Code        string  `json:"code"        gorm:"not null;uniqueIndex:idx_accounts_ledger_id_code;"`
 	Name        string  `json:"name"        gorm:"not null;"`
 	Description *string `json:"description"`
 	Type        string  `json:"type"        gorm:"not null; index;"` // EXPENSE | LIABILITY | EQUITY | ASSET | INCOME
@@ -20,6 +24,14 @@ type Account struct {
 
 	ParentAccount   *Account
 	ParentAccountID *string `json:"parent_account_id"`
+
+	// DisallowNegativeBalance makes posting refuse entries that take the balance (on the account's normal side)
+	// below -OverdraftLimit.
+	DisallowNegativeBalance bool  `json:"disallow_negative_balance" gorm:"not null;default:false;"`
+	OverdraftLimit          int64 `json:"overdraft_limit"           gorm:"not null;default:0;"`
+
+	// Version is bumped on every change, it is the account's ETag.
+	Version int64 `json:"version" gorm:"not null;default:1;"`
 }
 
 func (acc *Account) BeforeDelete(tx *gorm.DB) (err error) {
@@ -42,3 +54,23 @@ func (acc *Account) BeforeDelete(tx *gorm.DB) (err error) {
 
 	return
 }
+
+// IsDebitNormal reports whether the account's balance increases with debits.
+// ASSET and EXPENSE accounts are debit normal, the rest are credit normal. Contra accounts flip their type's side.
+func (acc *Account) IsDebitNormal() bool {
+	debitNormal := acc.Type == "ASSET" || acc.Type == "EXPENSE"
+	if acc.IsContra {
+		return !debitNormal
+	}
+
+	return debitNormal
+}
+
+// Balance returns the signed balance of the account on its normal side for the given debit and credit totals.
+func (acc *Account) Balance(debit int64, credit int64) int64 {
+	if acc.IsDebitNormal() {
+		return debit - credit
+	}
+
+	return credit - debit
+}
@@ -0,0 +1,120 @@
+package models
+
+import (
+	"slices"
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+const (
+	ApiKeyStatusActive  = "ACTIVE"
+	ApiKeyStatusExpired = "EXPIRED"
+	ApiKeyStatusRevoked = "REVOKED"
+)
+
+// Scopes an api key can be granted, each allowing one kind of access to one resource.
+const (
+	ApiKeyScopeAccountsRead        = "accounts:read"
+	ApiKeyScopeAccountsWrite       = "accounts:write"
+	ApiKeyScopeJournalEntriesRead  = "journal_entries:read"
+	ApiKeyScopeJournalEntriesWrite = "journal_entries:write"
+	ApiKeyScopeJournalEntriesPost  = "journal_entries:post"
+	ApiKeyScopeReportsRead         = "reports:read"
+	ApiKeyScopeDimensionsRead      = "dimensions:read"
+	ApiKeyScopeDimensionsWrite     = "dimensions:write"
+	ApiKeyScopeBudgetsRead         = "budgets:read"
+	ApiKeyScopeBudgetsWrite        = "budgets:write"
+	ApiKeyScopeApprovalRulesRead   = "approval_rules:read"
+	ApiKeyScopeApprovalRulesWrite  = "approval_rules:write"
+	ApiKeyScopeTransfersRead       = "transfers:read"
+	ApiKeyScopeTransfersWrite      = "transfers:write"
+	ApiKeyScopeHoldsRead           = "holds:read"
+	ApiKeyScopeHoldsWrite          = "holds:write"
+	ApiKeyScopeWebhooksRead        = "webhooks:read"
+	ApiKeyScopeWebhooksWrite       = "webhooks:write"
+	ApiKeyScopeEventsRead          = "events:read"
+	ApiKeyScopeAuditEventsRead     = "audit_events:read"
+	ApiKeyScopeApiKeysRead         = "api_keys:read"
+	ApiKeyScopeApiKeysWrite        = "api_keys:write"
+	ApiKeyScopeLedgersRead         = "ledgers:read"
+	ApiKeyScopeLedgersWrite        = "ledgers:write"
+)
+
+// ApiKeyScopes are all the scopes, which keys get when created without any.
+var ApiKeyScopes = []string{
+	ApiKeyScopeAccountsRead,
+	ApiKeyScopeAccountsWrite,
+	ApiKeyScopeJournalEntriesRead,
+	ApiKeyScopeJournalEntriesWrite,
+	ApiKeyScopeJournalEntriesPost,
+	ApiKeyScopeReportsRead,
+	ApiKeyScopeDimensionsRead,
+	ApiKeyScopeDimensionsWrite,
+	ApiKeyScopeBudgetsRead,
+	ApiKeyScopeBudgetsWrite,
+	ApiKeyScopeApprovalRulesRead,
+	ApiKeyScopeApprovalRulesWrite,
+	ApiKeyScopeTransfersRead,
+	ApiKeyScopeTransfersWrite,
+	ApiKeyScopeHoldsRead,
+	ApiKeyScopeHoldsWrite,
+	ApiKeyScopeWebhooksRead,
+	ApiKeyScopeWebhooksWrite,
+	ApiKeyScopeEventsRead,
+	ApiKeyScopeAuditEventsRead,
+	ApiKeyScopeApiKeysRead,
+	ApiKeyScopeApiKeysWrite,
+	ApiKeyScopeLedgersRead,
+	ApiKeyScopeLedgersWrite,
+}
+
+// ApiKey is one of the client's credentials, sent as X-FinCore-Client-Secret. The secret is only shown when the key
+// is created or rotated, Prefix is its public start the key is found by. Keys moved over from the single client
+// secret have no prefix.
+type ApiKey struct {
+	BaseModelSoftDelete
+	ClientID string `json:"client_id" gorm:"not null;index;"`
+	Client   Client
+
+	Label      string     `json:"label"        gorm:"not null;"`
+	Prefix     *string    `json:"prefix"       gorm:"uniqueIndex;"`
+	SecretHash string     `json:"-"            gorm:"not null;"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+
+	// SigningKey is the key requests are signed with, derived from the secret and encrypted. Keys made before
+	// requests could be signed have none until they are rotated.
+	SigningKey *string `json:"-"`
+
+	// Scopes are what the key is allowed to do, see ApiKeyScopes.
+	Scopes datatypes.JSONSlice[string] `json:"scopes"`
+
+	// RotatedFromID is the key this one was rotated from, which stays usable until the end of the overlap window.
+	RotatedFromID *string `json:"rotated_from_id" gorm:"index;"`
+
+	// CredentialID identifies the credential the key is, the id of the first key it was rotated from. Requests are
+	// made on behalf of the credential, so rotating a key doesn't make it someone else to the maker-checker.
+	CredentialID string `json:"credential_id" gorm:"index;"`
+}
+
+func (k *ApiKey) Status(now time.Time) string {
+	if k.RevokedAt != nil {
+		return ApiKeyStatusRevoked
+	}
+
+	if k.ExpiresAt != nil && !k.ExpiresAt.After(now) {
+		return ApiKeyStatusExpired
+	}
+
+	return ApiKeyStatusActive
+}
+
+func (k *ApiKey) IsActive(now time.Time) bool {
+	return k.Status(now) == ApiKeyStatusActive
+}
+
+func (k *ApiKey) HasScope(scope string) bool {
+	return slices.Contains(k.Scopes, scope)
+}
@@ -0,0 +1,17 @@
+package models
+
+// ApprovalRule flags journal entries that must be approved by a second credential before they are posted.
+// A rule matches when the entry's total debits reach MinAmount (when set) and one of its lines touches
+// AccountID (when set). A rule without conditions matches every entry of its ledger.
+type ApprovalRule struct {
+	BaseModelSoftDelete
+	ClientID string `json:"client_id" gorm:"not null;index;"`
+	Client   Client
+	LedgerID string `json:"ledger_id" gorm:"index;"`
+
+	Name      string  `json:"name"       gorm:"not null;"`
+	MinAmount *int64  `json:"min_amount"`
+	AccountID *string `json:"account_id" gorm:"index;"`
+	Account   *Account
+	IsActive  bool `json:"is_active"  gorm:"not null;default:true;"`
+}
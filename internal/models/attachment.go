@@ -0,0 +1,16 @@
+package models
+
+// Attachment is a supporting document (receipt, invoice, ...) attached to a journal entry.
+// The file itself lives in the configured storage under StorageKey.
+type Attachment struct {
+	BaseModelSoftDelete
+	ClientID       string `json:"client_id"        gorm:"not null;index;"`
+	JournalEntryID string `json:"journal_entry_id" gorm:"not null;index;"`
+	JournalEntry   JournalEntry
+
+	FileName    string `json:"file_name"    gorm:"not null;"`
+	ContentType string `json:"content_type" gorm:"not null;"`
+	Size        int64  `json:"size"         gorm:"not null;"`
+	ContentHash string `json:"content_hash" gorm:"not null;"` // sha256, hex encoded
+	StorageKey  string `json:"-"            gorm:"not null;"`
+}
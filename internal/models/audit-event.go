@@ -0,0 +1,22 @@
+package models
+
+import "gorm.io/datatypes"
+
+// AuditEvent is an append-only record of a mutation, with snapshots of the resource before and after it.
+// Rows are never updated or deleted, the database rejects both (see the audit events migration job).
+type AuditEvent struct {
+	BaseModel
+	ClientID string `json:"client_id" gorm:"not null;index;"`
+
+	// LedgerID is the ledger the resource belongs to, nil for resources of the client as a whole (clients, api keys)
+	// and for events recorded before audit events carried their ledger.
+	LedgerID *string `json:"ledger_id" gorm:"index;"`
+
+	ActorID      *string         `json:"actor_id"      gorm:"index;"` // the credential that made the change
+	RequestID    *string         `json:"request_id"    gorm:"index;"`
+	Action       string          `json:"action"        gorm:"not null;index;"` // create, update, delete, post, reverse ...
+	ResourceType string          `json:"resource_type" gorm:"not null;index:idx_audit_events_resource;"`
+	ResourceID   string          `json:"resource_id"   gorm:"not null;index:idx_audit_events_resource;"`
+	Before       *datatypes.JSON `json:"before"`
+	After        *datatypes.JSON `json:"after"`
+}
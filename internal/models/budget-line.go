@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// BudgetLine is the budgeted amount of an account (optionally narrowed to a dimension value) for a period.
+// Amount is on the account's normal side, the same way balances are reported.
+type BudgetLine struct {
+	BaseModelSoftDelete
+	BudgetID string `json:"budget_id" gorm:"not null;index;"`
+	Budget   Budget
+
+	AccountID string `json:"account_id" gorm:"not null;index;"`
+	Account   Account
+
+	DimensionValueID *string `json:"dimension_value_id" gorm:"index;"`
+	DimensionValue   *DimensionValue
+
+	PeriodStart time.Time `json:"period_start" gorm:"not null;index;"`
+	PeriodEnd   time.Time `json:"period_end"   gorm:"not null;"`
+	Amount      int64     `json:"amount"       gorm:"not null; default: 0"`
+}
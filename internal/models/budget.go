@@ -0,0 +1,12 @@
+package models
+
+type Budget struct {
+	BaseModelSoftDelete
+	ClientID string `json:"client_id" gorm:"not null;index;"`
+	Client   Client
+
+	Name        string  `json:"name"        gorm:"not null;"`
+	Description *string `json:"description"`
+
+	BudgetLines []BudgetLine
+}
@@ -2,10 +2,14 @@ package models
 
 type Client struct {
 	BaseModelSoftDelete
-	Name             string `json:"name"               gorm:"not null;index"`
-	Email            string `json:"email"              gorm:"not null;uniqueIndex"`
-	ClientId         string `json:"client_id"          gorm:"not null;uniqueIndex;"`
-	ClientSecretHash string `json:"client_secret_hash"`
+	Name     string `json:"name"      gorm:"not null;index"`
+	Email    string `json:"email"     gorm:"not null;uniqueIndex"`
+	ClientId string `json:"client_id" gorm:"not null;uniqueIndex;"`
 
+	// Currency is the ISO 4217 code of the client's default ledger, set when the client registers.
+	Currency string `json:"currency" gorm:"not null;default:USD;"`
+
+	Ledgers  []Ledger
 	Accounts []Account
+	ApiKeys  []ApiKey
 }
@@ -0,0 +1,33 @@
+package models
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// DimensionType is a client defined analytical axis (eg. department, project, cost center) that the journal entry
+// lines of its ledger can be tagged with.
+type DimensionType struct {
+	BaseModelSoftDelete
+	ClientID string `json:"client_id" gorm:"not null;index;"`
+	Client   Client
+	LedgerID string `json:"ledger_id" gorm:"index;uniqueIndex:idx_dimension_types_ledger_code;"`
+
+	Code        string  `json:"code"        gorm:"not null;uniqueIndex:idx_dimension_types_ledger_code;"`
+	Name        string  `json:"name"        gorm:"not null;"`
+	Description *string `json:"description"`
+
+	DimensionValues []DimensionValue
+}
+
+func (dimensionType *DimensionType) BeforeDelete(tx *gorm.DB) (err error) {
+	// prevent deletion if the dimension still has values
+	var count int64
+	tx.Model(&DimensionValue{}).Where("dimension_type_id = ?", dimensionType.ID).Count(&count)
+	if count > 0 {
+		return errors.New("cannot delete a dimension that has values")
+	}
+
+	return
+}
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// DimensionValue is one allowed value of a DimensionType (eg. "sales" for the "department" dimension).
+type DimensionValue struct {
+	BaseModelSoftDelete
+	ClientID string `json:"client_id" gorm:"not null;index;"`
+	Client   Client
+	LedgerID string `json:"ledger_id" gorm:"index;"` // the dimension type's
+
+	DimensionTypeID string `json:"dimension_type_id" gorm:"not null;index;uniqueIndex:idx_dimension_values_type_code;"`
+	DimensionType   DimensionType
+
+	Code        string  `json:"code"        gorm:"not null;uniqueIndex:idx_dimension_values_type_code;"`
+	Name        string  `json:"name"        gorm:"not null;"`
+	Description *string `json:"description"`
+	IsActive    bool    `json:"is_active"   gorm:"not null;default:true;"`
+}
+
+func (value *DimensionValue) BeforeDelete(tx *gorm.DB) (err error) {
+	// prevent deletion if the value has been used to tag journal entry lines
+	var count int64
+	tx.Table("journal_entry_line_dimensions").Where("dimension_value_id = ?", value.ID).Count(&count)
+	if count > 0 {
+		return errors.New("cannot delete a dimension value that is used by journal entry lines, deactivate it instead")
+	}
+
+	return
+}
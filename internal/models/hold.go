@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+const (
+	HoldStatusPending  = "PENDING"
+	HoldStatusCaptured = "CAPTURED"
+	HoldStatusVoided   = "VOIDED"
+	HoldStatusExpired  = "EXPIRED"
+)
+
+// Hold reserves Amount of an account's balance for a move to DestinationAccountID that isn't final yet (card
+// authorizations, escrow). A pending hold lowers the account's available balance but not its ledger balance,
+// capturing it posts the move. It stops counting once ExpiresAt has passed.
+type Hold struct {
+	BaseModel
+	ClientID string `json:"client_id" gorm:"not null;index;"`
+	Client   Client
+	LedgerID string `json:"ledger_id" gorm:"index;"`
+
+	AccountID            string `json:"account_id"             gorm:"not null;index;"`
+	Account              Account
+	DestinationAccountID string `json:"destination_account_id" gorm:"not null;"`
+	DestinationAccount   Account
+
+	// Amount and CapturedAmount are in the minor unit of Currency, the currency of the hold's ledger.
+	Amount    int64           `json:"amount"     gorm:"not null;"`
+	Currency  string          `json:"currency"   gorm:"not null;default:USD;"`
+	Reference string          `json:"reference"  gorm:"not null;"`
+	Metadata  *datatypes.JSON `json:"metadata"`
+	Status    string          `json:"status"     gorm:"not null;index;default:PENDING;"` // PENDING, CAPTURED, VOIDED, EXPIRED
+	ExpiresAt *time.Time      `json:"expires_at" gorm:"index;"`
+
+	CapturedAmount int64      `json:"captured_amount" gorm:"not null;default:0;"`
+	CapturedAt     *time.Time `json:"captured_at"`
+	VoidedAt       *time.Time `json:"voided_at"`
+
+	// JournalEntryID is the entry the hold was captured into. While it awaits approval the hold stays pending and
+	// keeps counting, it is captured once the entry is posted and freed again when the entry is rejected or voided.
+	JournalEntryID *string `json:"journal_entry_id"`
+	JournalEntry   *JournalEntry
+}
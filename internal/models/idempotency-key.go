@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// IdempotencyKey remembers the first response to a write made with an Idempotency-Key header so retries of the
+// same request get it back instead of repeating the write. ResponseStatus is nil while the first request is
+// still in flight.
+type IdempotencyKey struct {
+	BaseModel
+	ClientID    string    `json:"client_id"    gorm:"not null;uniqueIndex:idx_idempotency_keys_client_id_key;"`
+	Key         string    `json:"key"          gorm:"not null;uniqueIndex:idx_idempotency_keys_client_id_key;"`
+	RequestHash string    `json:"request_hash" gorm:"not null;"` // sha256 over method, path and body, hex encoded
+	ExpiresAt   time.Time `json:"expires_at"   gorm:"not null;index;"`
+
+	ResponseStatus      *int    `json:"response_status"`
+	ResponseContentType *string `json:"response_content_type"`
+	ResponseBody        []byte  `json:"response_body"`
+}
@@ -0,0 +1,105 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"time"
+)
+
+const (
+	// ChainHashVersionLegacy hashed entries without their ledger or their lines' currencies.
+	ChainHashVersionLegacy = 1
+	// ChainHashVersion is the version entries are hashed with when they are posted.
+	ChainHashVersion = 2
+)
+
+// fields added by a version are left out of the hashes of older ones, so those still verify.
+type chainedJournalEntryLine struct {
+	ID        string `json:"id"`
+	AccountID string `json:"account_id"`
+	Debit     int64  `json:"debit"`
+	Credit    int64  `json:"credit"`
+	Currency  string `json:"currency,omitempty"`
+}
+
+type chainedJournalEntry struct {
+	PreviousHash    string                    `json:"previous_hash"`
+	LedgerSequence  int64                     `json:"ledger_sequence"`
+	ID              string                    `json:"id"`
+	ClientID        string                    `json:"client_id"`
+	LedgerID        string                    `json:"ledger_id,omitempty"`
+	Reference       string                    `json:"reference"`
+	TransactionDate string                    `json:"transaction_date"`
+	PostedAt        string                    `json:"posted_at"`
+	Lines           []chainedJournalEntryLine `json:"lines"`
+}
+
+// ComputeChainHash hashes the entry's canonical content (its ledger, lines, amounts, currencies, accounts and
+// dates) together with the hash of the previously posted entry of its ledger. Changing a posted entry, moving it
+// to another ledger or removing it breaks every hash after it. Dates are hashed at the microsecond precision
+// postgres stores them with. Entries are hashed with the content of their HashVersion, entries without one
+// being ChainHashVersionLegacy.
+func (journalEntry *JournalEntry) ComputeChainHash(
+	lines []JournalEntryLine,
+	sequence int64,
+	previousHash string,
+) string {
+	content := chainedJournalEntry{
+		PreviousHash:    previousHash,
+		LedgerSequence:  sequence,
+		ID:              journalEntry.ID.String(),
+		ClientID:        journalEntry.ClientID,
+		Reference:       journalEntry.Reference,
+		TransactionDate: canonicalTime(journalEntry.TransactionDate),
+		Lines:           make([]chainedJournalEntryLine, 0),
+	}
+
+	if journalEntry.PostedAt != nil {
+		content.PostedAt = canonicalTime(*journalEntry.PostedAt)
+	}
+
+	version := journalEntry.ChainHashVersion()
+	if version >= 2 {
+		content.LedgerID = journalEntry.LedgerID
+	}
+
+	for _, line := range lines {
+		chainedLine := chainedJournalEntryLine{
+			ID:        line.ID.String(),
+			AccountID: line.AccountID,
+			Debit:     line.Debit,
+			Credit:    line.Credit,
+		}
+
+		if version >= 2 {
+			chainedLine.Currency = line.Currency
+		}
+
+		content.Lines = append(content.Lines, chainedLine)
+	}
+
+	sort.Slice(content.Lines, func(i, j int) bool {
+		return content.Lines[i].ID < content.Lines[j].ID
+	})
+
+	// marshalling a struct is deterministic, so is the hash.
+	data, _ := json.Marshal(content)
+	sum := sha256.Sum256(data)
+
+	return hex.EncodeToString(sum[:])
+}
+
+// ChainHashVersion is the version of the content the entry is hashed with.
+func (journalEntry *JournalEntry) ChainHashVersion() int {
+	if journalEntry.HashVersion == nil {
+		return ChainHashVersionLegacy
+	}
+
+	return *journalEntry.HashVersion
+}
+
+func canonicalTime(t time.Time) string {
+	return t.UTC().Truncate(time.Microsecond).Format(time.RFC3339Nano)
+}
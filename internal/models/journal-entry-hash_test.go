@@ -0,0 +1,223 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+func TestComputeChainHash(t *testing.T) {
+	postedAt := time.Date(2026, 10, 19, 12, 30, 0, 123456789, time.UTC)
+
+	base := &JournalEntry{
+		ClientID:        "client-id",
+		LedgerID:        "ledger-id",
+		Status:          JournalEntryStatusPosted,
+		Reference:       "INV-1",
+		TransactionDate: time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC),
+		PostedAt:        &postedAt,
+		HashVersion:     intPointer(ChainHashVersion),
+	}
+	base.ID = uuid.Must(uuid.FromString("7e2e0544-931c-4c07-a761-5ae95202d4e1"))
+
+	baseLines := []JournalEntryLine{
+		{AccountID: "cash", Debit: 1050, Currency: "USD"},
+		{AccountID: "revenue", Credit: 1050, Currency: "USD"},
+	}
+	baseLines[0].ID = uuid.Must(uuid.FromString("0b8e8a4e-5d0c-4c1b-9c53-1f1d7e0a0001"))
+	baseLines[1].ID = uuid.Must(uuid.FromString("0b8e8a4e-5d0c-4c1b-9c53-1f1d7e0a0002"))
+
+	want := base.ComputeChainHash(baseLines, 2, "previous")
+
+	tests := []struct {
+		name         string
+		change       func(entry *JournalEntry, lines []JournalEntryLine) []JournalEntryLine
+		sequence     int64
+		previousHash string
+		same         bool
+	}{
+		{
+			"unchanged",
+			func(entry *JournalEntry, lines []JournalEntryLine) []JournalEntryLine { return lines },
+			2, "previous", true,
+		},
+		{
+			"lines in another order",
+			func(entry *JournalEntry, lines []JournalEntryLine) []JournalEntryLine {
+				return []JournalEntryLine{lines[1], lines[0]}
+			},
+			2, "previous", true,
+		},
+		{
+			"posted at below microseconds",
+			func(entry *JournalEntry, lines []JournalEntryLine) []JournalEntryLine {
+				truncated := entry.PostedAt.Truncate(time.Microsecond)
+				entry.PostedAt = &truncated
+				return lines
+			},
+			2, "previous", true,
+		},
+		{
+			"posted at in another zone",
+			func(entry *JournalEntry, lines []JournalEntryLine) []JournalEntryLine {
+				local := entry.PostedAt.In(time.FixedZone("GMT+1", 3600))
+				entry.PostedAt = &local
+				return lines
+			},
+			2, "previous", true,
+		},
+		{
+			"content outside the chain",
+			func(entry *JournalEntry, lines []JournalEntryLine) []JournalEntryLine {
+				entry.Status = JournalEntryStatusReversed
+				entry.Version = 9
+				return lines
+			},
+			2, "previous", true,
+		},
+		{
+			"other previous hash",
+			func(entry *JournalEntry, lines []JournalEntryLine) []JournalEntryLine { return lines },
+			2, "other", false,
+		},
+		{
+			"other sequence",
+			func(entry *JournalEntry, lines []JournalEntryLine) []JournalEntryLine { return lines },
+			3, "previous", false,
+		},
+		{
+			"other ledger",
+			func(entry *JournalEntry, lines []JournalEntryLine) []JournalEntryLine {
+				entry.LedgerID = "other-ledger-id"
+				return lines
+			},
+			2, "previous", false,
+		},
+		{
+			"other reference",
+			func(entry *JournalEntry, lines []JournalEntryLine) []JournalEntryLine {
+				entry.Reference = "INV-2"
+				return lines
+			},
+			2, "previous", false,
+		},
+		{
+			"other transaction date",
+			func(entry *JournalEntry, lines []JournalEntryLine) []JournalEntryLine {
+				entry.TransactionDate = entry.TransactionDate.AddDate(0, 0, 1)
+				return lines
+			},
+			2, "previous", false,
+		},
+		{
+			"not posted",
+			func(entry *JournalEntry, lines []JournalEntryLine) []JournalEntryLine {
+				entry.PostedAt = nil
+				return lines
+			},
+			2, "previous", false,
+		},
+		{
+			"other amount",
+			func(entry *JournalEntry, lines []JournalEntryLine) []JournalEntryLine {
+				lines[0].Debit, lines[1].Credit = 1051, 1051
+				return lines
+			},
+			2, "previous", false,
+		},
+		{
+			"other account",
+			func(entry *JournalEntry, lines []JournalEntryLine) []JournalEntryLine {
+				lines[1].AccountID = "other-revenue"
+				return lines
+			},
+			2, "previous", false,
+		},
+		{
+			"other currency",
+			func(entry *JournalEntry, lines []JournalEntryLine) []JournalEntryLine {
+				lines[0].Currency, lines[1].Currency = "EUR", "EUR"
+				return lines
+			},
+			2, "previous", false,
+		},
+		{
+			"line removed",
+			func(entry *JournalEntry, lines []JournalEntryLine) []JournalEntryLine { return lines[:1] },
+			2, "previous", false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			changed := *base
+			changedLines := test.change(&changed, append([]JournalEntryLine(nil), baseLines...))
+
+			got := changed.ComputeChainHash(changedLines, test.sequence, test.previousHash)
+			if (got == want) != test.same {
+				t.Errorf("ComputeChainHash() = %s, want same as %s: %v", got, want, test.same)
+			}
+
+			if len(got) != 64 {
+				t.Errorf("ComputeChainHash() is %d characters, want a 64 character sha256", len(got))
+			}
+		})
+	}
+}
+
+func TestComputeChainHashVersions(t *testing.T) {
+	postedAt := time.Date(2026, 10, 19, 12, 30, 0, 123456789, time.UTC)
+
+	lines := []JournalEntryLine{
+		{AccountID: "cash", Debit: 1050, Currency: "USD"},
+		{AccountID: "revenue", Credit: 1050, Currency: "USD"},
+	}
+	lines[0].ID = uuid.Must(uuid.FromString("0b8e8a4e-5d0c-4c1b-9c53-1f1d7e0a0001"))
+	lines[1].ID = uuid.Must(uuid.FromString("0b8e8a4e-5d0c-4c1b-9c53-1f1d7e0a0002"))
+
+	// the hash entries were chained with before versions, which must keep verifying.
+	legacyHash := "1058e119dc3d5b7e3e5fbf77cd63a51880e4ad71988a0fd953259d7c3bd9af53"
+
+	tests := []struct {
+		name        string
+		hashVersion *int
+		ledgerID    string
+		currency    string
+		same        bool
+	}{
+		{"no version is legacy", nil, "ledger-id", "USD", true},
+		{"legacy", intPointer(ChainHashVersionLegacy), "ledger-id", "USD", true},
+		{"legacy leaves out the ledger", intPointer(ChainHashVersionLegacy), "other-ledger-id", "USD", true},
+		{"legacy leaves out currencies", intPointer(ChainHashVersionLegacy), "ledger-id", "EUR", true},
+		{"current", intPointer(ChainHashVersion), "ledger-id", "USD", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			entry := &JournalEntry{
+				ClientID:        "client-id",
+				LedgerID:        test.ledgerID,
+				Reference:       "INV-1",
+				TransactionDate: time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC),
+				PostedAt:        &postedAt,
+				HashVersion:     test.hashVersion,
+			}
+			entry.ID = uuid.Must(uuid.FromString("7e2e0544-931c-4c07-a761-5ae95202d4e1"))
+
+			entryLines := append([]JournalEntryLine(nil), lines...)
+			for i := range entryLines {
+				entryLines[i].Currency = test.currency
+			}
+
+			got := entry.ComputeChainHash(entryLines, 2, "previous")
+			if (got == legacyHash) != test.same {
+				t.Errorf("ComputeChainHash() = %s, want same as %s: %v", got, legacyHash, test.same)
+			}
+		})
+	}
+}
+
+func intPointer(value int) *int {
+	return &value
+}
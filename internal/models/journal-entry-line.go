@@ -8,7 +8,12 @@ type JournalEntryLine struct {
 	AccountID string `json:"account_id" gorm:"not null;index;"`
 	Account   Account
 
-	Notes  *string `json:"notes"`
-	Debit  int64   `json:"debit"  gorm:"not null; default: 0"`
-	Credit int64   `json:"credit" gorm:"not null; default: 0"`
+	Notes *string `json:"notes"`
+
+	// Debit and Credit are in the minor unit of Currency, the currency of the line's entry.
+	Debit    int64  `json:"debit"    gorm:"not null; default: 0"`
+	Credit   int64  `json:"credit"   gorm:"not null; default: 0"`
+	Currency string `json:"currency" gorm:"not null;default:USD;"`
+
+	DimensionValues []DimensionValue `gorm:"many2many:journal_entry_line_dimensions;"`
 }
@@ -0,0 +1,13 @@
+package models
+
+// JournalEntryTransition records a change of status on a journal entry, who made it and when (CreatedAt).
+// FromStatus is empty for the status an entry was created with.
+type JournalEntryTransition struct {
+	BaseModel
+	JournalEntryID string `json:"journal_entry_id" gorm:"not null;index;"`
+
+	FromStatus *string `json:"from_status"`
+	ToStatus   string  `json:"to_status"   gorm:"not null;"`
+	ActorID    *string `json:"actor_id"`
+	Reason     *string `json:"reason"`
+}
@@ -6,17 +6,54 @@ import (
 	"gorm.io/datatypes"
 )
 
+const (
+	JournalEntryStatusDraft           = "DRAFT"
+	JournalEntryStatusPendingApproval = "PENDING_APPROVAL"
+	JournalEntryStatusPosted          = "POSTED"
+	JournalEntryStatusReversed        = "REVERSED"
+	JournalEntryStatusVoided          = "VOIDED"
+)
+
 type JournalEntry struct {
 	BaseModelSoftDelete
 	ClientID string `json:"client_id" gorm:"not null;index;"`
 	Client   Client
 
-	Status          string     `json:"status"           gorm:"not null; index; default: POSTED;"` // DRAFT, POSTED
+	LedgerID string `json:"ledger_id" gorm:"index;"`
+	Ledger   Ledger
+
+	Status          string     `json:"status"           gorm:"not null; index; default: POSTED;"` // DRAFT, PENDING_APPROVAL, POSTED, REVERSED, VOIDED
 	PostedAt        *time.Time `json:"posted_at"`
 	Reference       string     `json:"reference"        gorm:"not null;"`
 	TransactionDate time.Time  `json:"transaction_date" gorm:"not null;"`
 
-	Metadata *datatypes.JSON `json:"metadata"` // save any client related data.
+	// Currency of the amounts of the entry's lines, the ledger's currency.
+	Currency string `json:"currency" gorm:"not null;default:USD;"`
+
+	// save any client related data, indexed so entries can be looked up by it.
+	Metadata *datatypes.JSON `json:"metadata" gorm:"index:idx_journal_entries_metadata,type:gin;"`
+
+	// Version is bumped on every change, it is the entry's ETag.
+	Version int64 `json:"version" gorm:"not null;default:1;"`
+
+	// maker-checker, the credentials that created and approved/rejected the entry.
+	CreatedBy       *string    `json:"created_by"`
+	ApprovedBy      *string    `json:"approved_by"`
+	ApprovedAt      *time.Time `json:"approved_at"`
+	RejectedBy      *string    `json:"rejected_by"`
+	RejectedAt      *time.Time `json:"rejected_at"`
+	RejectionReason *string    `json:"rejection_reason"`
+
+	// ReversalOfID is set on the entry that was posted to reverse another one.
+	ReversalOfID *string `json:"reversal_of_id" gorm:"index;"`
+
+	// tamper evident chain over the ledger's posted entries, see ComputeChainHash.
+	LedgerSequence *int64  `json:"ledger_sequence" gorm:"index;"`
+	PreviousHash   *string `json:"previous_hash"`
+	Hash           *string `json:"hash"`
+	HashVersion    *int    `json:"hash_version"`
 
-	JournalEntryLines []JournalEntryLine
+	JournalEntryLines       []JournalEntryLine
+	JournalEntryTransitions []JournalEntryTransition
+	Attachments             []Attachment
 }
@@ -0,0 +1,58 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Ledger is a set of books of a client, e.g. one per legal entity it operates. Accounts and journal entries belong
+// to a ledger, which keeps its own currency, fiscal calendar and account code numbering.
+type Ledger struct {
+	BaseModelSoftDelete
+	// a client has a single default ledger.
+	ClientID string `json:"client_id" gorm:"not null;index;uniqueIndex:idx_ledgers_default_client_id,where:is_default;"`
+	Client   Client
+
+	Name        string  `json:"name"        gorm:"not null;"`
+	Description *string `json:"description"`
+
+	// Currency is the ISO 4217 code every amount of the ledger is kept in, in its minor unit.
+	Currency string `json:"currency" gorm:"not null;default:USD;"`
+
+	// FiscalYearStartMonth is the month, 1 to 12, the ledger's fiscal years start on the first of.
+	FiscalYearStartMonth int `json:"fiscal_year_start_month" gorm:"not null;default:1;"`
+
+	// IsDefault marks the ledger requests that don't select one work in.
+	IsDefault bool `json:"is_default" gorm:"not null;default:false;"`
+}
+
+func (l *Ledger) BeforeDelete(tx *gorm.DB) (err error) {
+	if l.IsDefault {
+		return errors.New("cannot delete the default ledger")
+	}
+
+	// prevent deletion if the ledger still has books in it
+	var accountsCount int64
+	tx.Model(&Account{}).Where("ledger_id = ?", l.ID).Count(&accountsCount)
+	if accountsCount > 0 {
+		return errors.New("cannot delete a ledger that has accounts")
+	}
+
+	var journalEntriesCount int64
+	tx.Model(&JournalEntry{}).Where("ledger_id = ?", l.ID).Count(&journalEntriesCount)
+	if journalEntriesCount > 0 {
+		return errors.New("cannot delete a ledger that has journal entries")
+	}
+
+	return
+}
+
+// FiscalYear returns the first and last instants of the ledger's fiscal year, which is named after the calendar
+// year it starts in.
+func (l *Ledger) FiscalYear(year int) (time.Time, time.Time) {
+	start := time.Date(year, time.Month(l.FiscalYearStartMonth), 1, 0, 0, 0, 0, time.UTC)
+
+	return start, start.AddDate(1, 0, 0).Add(-time.Microsecond)
+}
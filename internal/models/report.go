@@ -0,0 +1,89 @@
+package models
+
+import "time"
+
+// The types below are read models assembled by the report service, they are not stored.
+
+// DimensionSubtotal is the share of an amount that belongs to one value of the grouped dimension.
+// DimensionValue is nil for lines that are not tagged with the grouped dimension.
+type DimensionSubtotal struct {
+	DimensionValue *DimensionValue
+	Debit          int64
+	Credit         int64
+	Balance        int64
+}
+
+type AccountBalance struct {
+	Account   Account
+	Debit     int64
+	Credit    int64
+	Balance   int64
+	Subtotals []DimensionSubtotal
+
+	// Held is the amount of the account's pending holds, Available is the balance less what is held.
+	Held      int64
+	Available int64
+}
+
+type AccountBalancesReport struct {
+	Currency string
+	GroupBy  *DimensionType
+	Accounts []AccountBalance
+}
+
+type IncomeStatementSection struct {
+	Accounts []AccountBalance
+	Total    int64
+}
+
+type IncomeStatementSubtotal struct {
+	DimensionValue *DimensionValue
+	Income         int64
+	Expenses       int64
+	NetIncome      int64
+}
+
+type IncomeStatementReport struct {
+	Currency  string
+	GroupBy   *DimensionType
+	Income    IncomeStatementSection
+	Expenses  IncomeStatementSection
+	NetIncome int64
+	Subtotals []IncomeStatementSubtotal
+}
+
+type BudgetVsActualLine struct {
+	Account        Account
+	DimensionValue *DimensionValue
+	Budget         int64
+	Actual         int64
+	Variance       int64
+
+	// VariancePercent is the variance relative to the budget, nil when nothing was budgeted.
+	VariancePercent *float64
+}
+
+type BudgetVsActualPeriod struct {
+	PeriodStart time.Time
+	PeriodEnd   time.Time
+	Lines       []BudgetVsActualLine
+}
+
+type BudgetVsActualReport struct {
+	Currency string
+	Budget   Budget
+	Periods  []BudgetVsActualPeriod
+}
+
+// LedgerChainBreak is the first place where a client's hash chain stops adding up.
+type LedgerChainBreak struct {
+	JournalEntryID string
+	LedgerSequence int64
+	Reason         string
+}
+
+type LedgerVerification struct {
+	EntriesChecked int64
+	Valid          bool
+	FirstBreak     *LedgerChainBreak
+}
@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// RequestNonce remembers a nonce a signed request was sent with, so the request can't be replayed. It is only
+// kept while the request's timestamp would still be accepted.
+type RequestNonce struct {
+	BaseModel
+	ApiKeyID  string    `json:"api_key_id" gorm:"not null;uniqueIndex:idx_request_nonces_api_key_id_nonce;"`
+	Nonce     string    `json:"nonce"      gorm:"not null;uniqueIndex:idx_request_nonces_api_key_id_nonce;"`
+	ExpiresAt time.Time `json:"expires_at" gorm:"not null;index;"`
+}
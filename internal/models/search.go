@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+const (
+	SearchHitTypeAccount          = "account"
+	SearchHitTypeJournalEntry     = "journal_entry"
+	SearchHitTypeJournalEntryLine = "journal_entry_line"
+)
+
+// SearchHit is a read model of a resource matching a full-text search, it is not stored. It holds what is needed to
+// show the resource in a result list: Code is only set on accounts, JournalEntryID, Reference and TransactionDate
+// on entries and lines.
+type SearchHit struct {
+	Type            string
+	ID              string
+	Rank            float64
+	Text            string
+	Code            *string
+	JournalEntryID  *string
+	Reference       *string
+	TransactionDate *time.Time
+}
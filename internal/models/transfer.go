@@ -0,0 +1,26 @@
+package models
+
+import "gorm.io/datatypes"
+
+// Transfer moves Amount from one account to another. It is backed by a two line journal entry, created and
+// posted together with the transfer.
+type Transfer struct {
+	BaseModel
+	ClientID string `json:"client_id" gorm:"not null;index;"`
+	Client   Client
+	LedgerID string `json:"ledger_id" gorm:"index;"`
+
+	FromAccountID string `json:"from_account_id" gorm:"not null;index;"`
+	FromAccount   Account
+	ToAccountID   string `json:"to_account_id"   gorm:"not null;index;"`
+	ToAccount     Account
+
+	// Amount is in the minor unit of Currency, the currency of the transfer's ledger.
+	Amount    int64           `json:"amount"    gorm:"not null;"`
+	Currency  string          `json:"currency"  gorm:"not null;default:USD;"`
+	Reference string          `json:"reference" gorm:"not null;"`
+	Metadata  *datatypes.JSON `json:"metadata"`
+
+	JournalEntryID string `json:"journal_entry_id" gorm:"not null;uniqueIndex;"`
+	JournalEntry   JournalEntry
+}
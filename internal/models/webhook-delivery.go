@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+const (
+	WebhookDeliveryStatusPending   = "PENDING"
+	WebhookDeliveryStatusSucceeded = "SUCCEEDED"
+	WebhookDeliveryStatusFailed    = "FAILED"
+)
+
+// WebhookDelivery is the delivery log, one event sent to one endpoint. It keeps the outcome of the latest attempt,
+// NextAttemptAt is when a pending delivery is tried next.
+type WebhookDelivery struct {
+	BaseModel
+	ClientID          string `json:"client_id"           gorm:"not null;index;"`
+	WebhookEndpointID string `json:"webhook_endpoint_id" gorm:"not null;index;"`
+	WebhookEndpoint   WebhookEndpoint
+	WebhookEventID    string `json:"webhook_event_id"    gorm:"not null;index;"`
+	WebhookEvent      WebhookEvent
+
+	// RedeliveryOfID is set on deliveries made by hand with the redeliver endpoint.
+	RedeliveryOfID *string `json:"redelivery_of_id"`
+
+	// PENDING, SUCCEEDED, FAILED
+	Status         string     `json:"status"           gorm:"not null;index;default:PENDING;"`
+	Attempts       int        `json:"attempts"         gorm:"not null;default:0;"`
+	NextAttemptAt  *time.Time `json:"next_attempt_at"  gorm:"index;"`
+	LastAttemptAt  *time.Time `json:"last_attempt_at"`
+	ResponseStatus *int       `json:"response_status"`
+	ResponseBody   *string    `json:"response_body"`
+	Error          *string    `json:"error"`
+}
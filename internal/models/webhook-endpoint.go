@@ -0,0 +1,25 @@
+package models
+
+import "gorm.io/datatypes"
+
+const (
+	WebhookEventAccountCreated       = "account.created"
+	WebhookEventJournalEntryCreated  = "journal_entry.created"
+	WebhookEventJournalEntryPosted   = "journal_entry.posted"
+	WebhookEventJournalEntryReversed = "journal_entry.reversed"
+)
+
+// WebhookEndpoint is a url of the client's that is sent the events of its ledger it subscribed to.
+// Secret signs every delivery, it is only shown when the endpoint is created.
+type WebhookEndpoint struct {
+	BaseModelSoftDelete
+	ClientID string `json:"client_id" gorm:"not null;index;"`
+	Client   Client
+	LedgerID string `json:"ledger_id" gorm:"index;"`
+
+	URL         string                      `json:"url"         gorm:"not null;"`
+	Description *string                     `json:"description"`
+	Events      datatypes.JSONSlice[string] `json:"events"      gorm:"not null;"`
+	Secret      string                      `json:"-"           gorm:"not null;"`
+	IsActive    bool                        `json:"is_active"   gorm:"not null;default:true;index;"`
+}
@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// WebhookEvent is the transactional outbox, events are written in the same transaction as the change they
+// describe. DispatchedAt is set once the event has been fanned out into deliveries. Sequence orders the events,
+// the event stream uses it as its resume cursor.
+type WebhookEvent struct {
+	BaseModel
+	Sequence     int64          `json:"sequence"      gorm:"autoIncrement;uniqueIndex;"`
+	ClientID     string         `json:"client_id"     gorm:"not null;index;"`
+	LedgerID     string         `json:"ledger_id"     gorm:"index;"`
+	Type         string         `json:"type"          gorm:"not null;index;"`
+	Payload      datatypes.JSON `json:"payload"       gorm:"not null;"`
+	DispatchedAt *time.Time     `json:"dispatched_at" gorm:"index;"`
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/Bendomey/fincore-engine/internal/lib"
 	"github.com/Bendomey/fincore-engine/internal/models"
+	"github.com/Bendomey/fincore-engine/internal/transformations"
 	"gorm.io/gorm"
 )
 
@@ -15,8 +16,10 @@ type AccountRepository interface {
 	Delete(context context.Context, account *models.Account) error
 	FindAndDelete(context context.Context, id string) error
 	GetByID(context context.Context, id string, populate *[]string) (*models.Account, error)
-	GetByIDAndClientID(ctx context.Context, id string, clientID string, populate *[]string) (*models.Account, error)
+	GetByIDAndLedgerID(ctx context.Context, id string, ledgerID string, populate *[]string) (*models.Account, error)
+	GetByCodeAndLedgerID(ctx context.Context, code string, ledgerID string) (*models.Account, error)
 	List(context context.Context, filterQuery lib.FilterQuery, filters ListAccountsFilter) (*[]models.Account, error)
+	ListAllByLedgerID(context context.Context, ledgerID string) (*[]models.Account, error)
 	Count(context context.Context, filterQuery lib.FilterQuery, filters ListAccountsFilter) (int64, error)
 }
 
@@ -29,30 +32,56 @@ func NewAccountRepository(DB *gorm.DB) AccountRepository {
 }
 
 func (r *accountRepository) Create(ctx context.Context, account *models.Account) error {
-	return r.DB.WithContext(ctx).Create(account).Error
+	return contextDB(ctx, r.DB).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(account).Error; err != nil {
+			return err
+		}
+
+		return writeWebhookEvent(
+			tx,
+			account.ClientID,
+			account.LedgerID,
+			models.WebhookEventAccountCreated,
+			transformations.DBAccountToRestAccount(account, nil),
+		)
+	})
 }
 
+// Update saves the account if it has not changed since it was read, see saveVersioned.
 func (r *accountRepository) Update(ctx context.Context, account *models.Account) error {
 	account.UpdatedAt = time.Now()
-	return r.DB.WithContext(ctx).Save(account).Error
+	return saveVersioned(contextDB(ctx, r.DB), account, &account.Version)
 }
 
 func (r *accountRepository) Delete(ctx context.Context, account *models.Account) error {
-	return r.DB.WithContext(ctx).Delete(account).Error
+	return contextDB(ctx, r.DB).Delete(account).Error
 }
 
 func (r *accountRepository) FindAndDelete(ctx context.Context, id string) error {
 	var account models.Account
-	if err := r.DB.WithContext(ctx).Where("id = ?", id).First(&account).Error; err != nil {
+	if err := contextDB(ctx, r.DB).Where("id = ?", id).First(&account).Error; err != nil {
 		return err
 	}
 
-	return r.DB.WithContext(ctx).Delete(&account).Error
+	return contextDB(ctx, r.DB).Delete(&account).Error
 }
 
 func (r *accountRepository) GetByCode(ctx context.Context, code string) (*models.Account, error) {
 	var account models.Account
-	result := r.DB.WithContext(ctx).Where("code = ?", code).First(&account)
+	result := contextDB(ctx, r.DB).Where("code = ?", code).First(&account)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &account, nil
+}
+
+func (r *accountRepository) GetByCodeAndLedgerID(
+	ctx context.Context,
+	code string,
+	ledgerID string,
+) (*models.Account, error) {
+	var account models.Account
+	result := contextDB(ctx, r.DB).Where("code = ? AND ledger_id = ?", code, ledgerID).First(&account)
 	if result.Error != nil {
 		return nil, result.Error
 	}
@@ -61,7 +90,7 @@ func (r *accountRepository) GetByCode(ctx context.Context, code string) (*models
 
 func (r *accountRepository) GetByID(ctx context.Context, id string, populate *[]string) (*models.Account, error) {
 	var account models.Account
-	db := r.DB.WithContext(ctx)
+	db := contextDB(ctx, r.DB)
 
 	if populate != nil {
 		for _, field := range *populate {
@@ -78,14 +107,14 @@ func (r *accountRepository) GetByID(ctx context.Context, id string, populate *[]
 	return &account, nil
 }
 
-func (r *accountRepository) GetByIDAndClientID(
+func (r *accountRepository) GetByIDAndLedgerID(
 	ctx context.Context,
 	id string,
-	clientID string,
+	ledgerID string,
 	populate *[]string,
 ) (*models.Account, error) {
 	var account models.Account
-	db := r.DB.WithContext(ctx)
+	db := contextDB(ctx, r.DB)
 
 	if populate != nil {
 		for _, field := range *populate {
@@ -93,7 +122,7 @@ func (r *accountRepository) GetByIDAndClientID(
 		}
 	}
 
-	result := db.Where("id = ? AND client_id = ?", id, clientID).First(&account)
+	result := db.Where("id = ? AND ledger_id = ?", id, ledgerID).First(&account)
 
 	if result.Error != nil {
 		return nil, result.Error
@@ -104,12 +133,28 @@ func (r *accountRepository) GetByIDAndClientID(
 
 type ListAccountsFilter struct {
 	ClientId        string
+	LedgerId        string
 	ParentAccountId *string
 	AccountType     *string
 	IsContra        *bool
 	IsGroup         *bool
 }
 
+// AccountQueryFields allowlists the fields accounts can be filtered, sorted and searched on.
+var AccountQueryFields = lib.QueryFields{
+	"code":                      {Column: "accounts.code", Type: lib.FieldString, Searchable: true},
+	"name":                      {Column: "accounts.name", Type: lib.FieldString, Searchable: true},
+	"description":               {Column: "accounts.description", Type: lib.FieldString, Searchable: true},
+	"type":                      {Column: "accounts.type", Type: lib.FieldString},
+	"is_contra":                 {Column: "accounts.is_contra", Type: lib.FieldBool},
+	"is_group":                  {Column: "accounts.is_group", Type: lib.FieldBool},
+	"parent_account_id":         {Column: "accounts.parent_account_id", Type: lib.FieldUUID},
+	"disallow_negative_balance": {Column: "accounts.disallow_negative_balance", Type: lib.FieldBool},
+	"overdraft_limit":           {Column: "accounts.overdraft_limit", Type: lib.FieldNumber},
+	"created_at":                {Column: "accounts.created_at", Type: lib.FieldTime},
+	"updated_at":                {Column: "accounts.updated_at", Type: lib.FieldTime},
+}
+
 func (r *accountRepository) List(
 	ctx context.Context,
 	filterQuery lib.FilterQuery,
@@ -117,18 +162,19 @@ func (r *accountRepository) List(
 ) (*[]models.Account, error) {
 	var accounts []models.Account
 
-	db := r.DB.WithContext(ctx).
+	db := contextDB(ctx, r.DB).
 		Scopes(
 			DateRangeScope("accounts", filterQuery.DateRange),
 			ClientFilterScope("accounts", filters.ClientId),
+			LedgerFilterScope("accounts", filters.LedgerId),
 			ParentAccountFilterScope(filters.ParentAccountId),
 			AccountTypeFilterScope(filters.AccountType),
 			IsContraFilterScope(filters.IsContra),
-			IsGroupFilterScope(filters.IsContra),
-			SearchScope("accounts", filterQuery.Search),
+			IsGroupFilterScope(filters.IsGroup),
+			FieldFiltersScope(filterQuery.Filters),
+			SearchScope(filterQuery.Search),
 
-			PaginationScope(filterQuery.Page, filterQuery.PageSize),
-			OrderScope("accounts", filterQuery.OrderBy, filterQuery.Order),
+			PageScope("accounts", filterQuery),
 		)
 
 	if filterQuery.Populate != nil {
@@ -146,6 +192,18 @@ func (r *accountRepository) List(
 	return &accounts, nil
 }
 
+// ListAllByLedgerID returns every account of a ledger ordered by code, mostly used to build reports.
+func (r *accountRepository) ListAllByLedgerID(ctx context.Context, ledgerID string) (*[]models.Account, error) {
+	var accounts []models.Account
+
+	results := contextDB(ctx, r.DB).Where("ledger_id = ?", ledgerID).Order("code asc").Find(&accounts)
+	if results.Error != nil {
+		return nil, results.Error
+	}
+
+	return &accounts, nil
+}
+
 func (r *accountRepository) Count(
 	ctx context.Context,
 	filterQuery lib.FilterQuery,
@@ -153,17 +211,18 @@ func (r *accountRepository) Count(
 ) (int64, error) {
 	var count int64
 
-	result := r.DB.
-		WithContext(ctx).
+	result := contextDB(ctx, r.DB).
 		Model(&models.Account{}).
 		Scopes(
 			DateRangeScope("accounts", filterQuery.DateRange),
 			ClientFilterScope("accounts", filters.ClientId),
+			LedgerFilterScope("accounts", filters.LedgerId),
 			ParentAccountFilterScope(filters.ParentAccountId),
 			AccountTypeFilterScope(filters.AccountType),
 			IsContraFilterScope(filters.IsContra),
-			IsGroupFilterScope(filters.IsContra),
-			SearchScope("accounts", filterQuery.Search),
+			IsGroupFilterScope(filters.IsGroup),
+			FieldFiltersScope(filterQuery.Filters),
+			SearchScope(filterQuery.Search),
 		).
 		Count(&count)
 
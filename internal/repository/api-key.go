@@ -0,0 +1,212 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Bendomey/fincore-engine/internal/lib"
+	"github.com/Bendomey/fincore-engine/internal/models"
+	"gorm.io/gorm"
+)
+
+type ApiKeyRepository interface {
+	Create(context context.Context, apiKey *models.ApiKey) error
+	Update(context context.Context, apiKey *models.ApiKey) error
+	Rotate(context context.Context, apiKey *models.ApiKey, replacement *models.ApiKey) error
+	TouchLastUsed(context context.Context, apiKey *models.ApiKey, usedAt time.Time) error
+	GetByIDAndClientID(context context.Context, id string, clientID string) (*models.ApiKey, error)
+	GetByID(context context.Context, id string) (*models.ApiKey, error)
+	GetByPrefix(context context.Context, prefix string) (*models.ApiKey, error)
+	ListUnprefixedByClientID(context context.Context, clientID string) (*[]models.ApiKey, error)
+	CountActiveByClientID(context context.Context, clientID string) (int64, error)
+	List(context context.Context, filterQuery lib.FilterQuery, filters ListApiKeysFilter) (*[]models.ApiKey, error)
+	Count(context context.Context, filterQuery lib.FilterQuery, filters ListApiKeysFilter) (int64, error)
+}
+
+type apiKeyRepository struct {
+	DB *gorm.DB
+}
+
+func NewApiKeyRepository(DB *gorm.DB) ApiKeyRepository {
+	return &apiKeyRepository{DB}
+}
+
+func (r *apiKeyRepository) Create(ctx context.Context, apiKey *models.ApiKey) error {
+	return contextDB(ctx, r.DB).Create(apiKey).Error
+}
+
+func (r *apiKeyRepository) Update(ctx context.Context, apiKey *models.ApiKey) error {
+	apiKey.UpdatedAt = time.Now()
+	return contextDB(ctx, r.DB).Save(apiKey).Error
+}
+
+// Rotate saves the key, now expiring at the end of the overlap window, and creates its replacement together.
+func (r *apiKeyRepository) Rotate(ctx context.Context, apiKey *models.ApiKey, replacement *models.ApiKey) error {
+	return contextDB(ctx, r.DB).Transaction(func(tx *gorm.DB) error {
+		apiKey.UpdatedAt = time.Now()
+		if err := tx.Save(apiKey).Error; err != nil {
+			return err
+		}
+
+		return tx.Create(replacement).Error
+	})
+}
+
+// TouchLastUsed records when the key last authenticated a request, without bumping updated_at.
+func (r *apiKeyRepository) TouchLastUsed(ctx context.Context, apiKey *models.ApiKey, usedAt time.Time) error {
+	apiKey.LastUsedAt = &usedAt
+	return contextDB(ctx, r.DB).Model(apiKey).UpdateColumn("last_used_at", usedAt).Error
+}
+
+func (r *apiKeyRepository) GetByIDAndClientID(
+	ctx context.Context,
+	id string,
+	clientID string,
+) (*models.ApiKey, error) {
+	var apiKey models.ApiKey
+	result := contextDB(ctx, r.DB).Where("id = ? AND client_id = ?", id, clientID).First(&apiKey)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return &apiKey, nil
+}
+
+func (r *apiKeyRepository) GetByID(ctx context.Context, id string) (*models.ApiKey, error) {
+	var apiKey models.ApiKey
+	result := contextDB(ctx, r.DB).Preload("Client").Where("id = ?", id).First(&apiKey)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return &apiKey, nil
+}
+
+func (r *apiKeyRepository) GetByPrefix(ctx context.Context, prefix string) (*models.ApiKey, error) {
+	var apiKey models.ApiKey
+	result := contextDB(ctx, r.DB).Preload("Client").Where("prefix = ?", prefix).First(&apiKey)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return &apiKey, nil
+}
+
+// ListUnprefixedByClientID returns the client's keys moved over from its client secret, which can only be told
+// apart by comparing the secret against each.
+func (r *apiKeyRepository) ListUnprefixedByClientID(ctx context.Context, clientID string) (*[]models.ApiKey, error) {
+	var apiKeys []models.ApiKey
+	result := contextDB(ctx, r.DB).
+		Preload("Client").
+		Where("client_id = ? AND prefix IS NULL", clientID).
+		Find(&apiKeys)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return &apiKeys, nil
+}
+
+func (r *apiKeyRepository) CountActiveByClientID(ctx context.Context, clientID string) (int64, error) {
+	var count int64
+	isActive := true
+
+	result := contextDB(ctx, r.DB).
+		Model(&models.ApiKey{}).
+		Where("client_id = ?", clientID).
+		Scopes(ApiKeyActiveScope("api_keys", &isActive)).
+		Count(&count)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return count, nil
+}
+
+type ListApiKeysFilter struct {
+	ClientId string
+	IsActive *bool
+}
+
+// ApiKeyQueryFields allowlists the fields api keys can be filtered, sorted and searched on.
+var ApiKeyQueryFields = lib.QueryFields{
+	"label":        {Column: "api_keys.label", Type: lib.FieldString, Searchable: true},
+	"prefix":       {Column: "api_keys.prefix", Type: lib.FieldString},
+	"last_used_at": {Column: "api_keys.last_used_at", Type: lib.FieldTime},
+	"expires_at":   {Column: "api_keys.expires_at", Type: lib.FieldTime},
+	"revoked_at":   {Column: "api_keys.revoked_at", Type: lib.FieldTime},
+	"created_at":   {Column: "api_keys.created_at", Type: lib.FieldTime},
+	"updated_at":   {Column: "api_keys.updated_at", Type: lib.FieldTime},
+}
+
+func (r *apiKeyRepository) List(
+	ctx context.Context,
+	filterQuery lib.FilterQuery,
+	filters ListApiKeysFilter,
+) (*[]models.ApiKey, error) {
+	var apiKeys []models.ApiKey
+
+	results := contextDB(ctx, r.DB).
+		Scopes(
+			DateRangeScope("api_keys", filterQuery.DateRange),
+			ClientFilterScope("api_keys", filters.ClientId),
+			ApiKeyActiveScope("api_keys", filters.IsActive),
+			FieldFiltersScope(filterQuery.Filters),
+			SearchScope(filterQuery.Search),
+
+			PaginationScope(filterQuery.Page, filterQuery.PageSize),
+			SortScope("api_keys", filterQuery),
+		).
+		Find(&apiKeys)
+
+	if results.Error != nil {
+		return nil, results.Error
+	}
+
+	return &apiKeys, nil
+}
+
+func (r *apiKeyRepository) Count(
+	ctx context.Context,
+	filterQuery lib.FilterQuery,
+	filters ListApiKeysFilter,
+) (int64, error) {
+	var count int64
+
+	result := contextDB(ctx, r.DB).
+		Model(&models.ApiKey{}).
+		Scopes(
+			DateRangeScope("api_keys", filterQuery.DateRange),
+			ClientFilterScope("api_keys", filters.ClientId),
+			ApiKeyActiveScope("api_keys", filters.IsActive),
+			FieldFiltersScope(filterQuery.Filters),
+			SearchScope(filterQuery.Search),
+		).
+		Count(&count)
+
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return count, nil
+}
+
+// ApiKeyActiveScope keeps the keys that can authenticate right now, neither revoked nor expired, or the others.
+func ApiKeyActiveScope(tableName string, isActive *bool) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if isActive == nil {
+			return db
+		}
+
+		condition := fmt.Sprintf(
+			"(%[1]s.revoked_at IS NULL AND (%[1]s.expires_at IS NULL OR %[1]s.expires_at > now()))",
+			tableName,
+		)
+		if !*isActive {
+			condition = "NOT " + condition
+		}
+
+		return db.Where(condition)
+	}
+}
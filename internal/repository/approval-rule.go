@@ -0,0 +1,165 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/Bendomey/fincore-engine/internal/lib"
+	"github.com/Bendomey/fincore-engine/internal/models"
+	"gorm.io/gorm"
+)
+
+type ApprovalRuleRepository interface {
+	Create(context context.Context, approvalRule *models.ApprovalRule) error
+	Update(context context.Context, approvalRule *models.ApprovalRule) error
+	Delete(context context.Context, approvalRule *models.ApprovalRule) error
+	GetByIDAndLedgerID(
+		context context.Context,
+		id string,
+		ledgerID string,
+		populate *[]string,
+	) (*models.ApprovalRule, error)
+	ListActiveByLedgerID(context context.Context, ledgerID string) (*[]models.ApprovalRule, error)
+	List(
+		context context.Context,
+		filterQuery lib.FilterQuery,
+		filters ListApprovalRulesFilter,
+	) (*[]models.ApprovalRule, error)
+	Count(context context.Context, filterQuery lib.FilterQuery, filters ListApprovalRulesFilter) (int64, error)
+}
+
+type approvalRuleRepository struct {
+	DB *gorm.DB
+}
+
+func NewApprovalRuleRepository(DB *gorm.DB) ApprovalRuleRepository {
+	return &approvalRuleRepository{DB}
+}
+
+func (r *approvalRuleRepository) Create(ctx context.Context, approvalRule *models.ApprovalRule) error {
+	return contextDB(ctx, r.DB).Create(approvalRule).Error
+}
+
+func (r *approvalRuleRepository) Update(ctx context.Context, approvalRule *models.ApprovalRule) error {
+	approvalRule.UpdatedAt = time.Now()
+	return contextDB(ctx, r.DB).Save(approvalRule).Error
+}
+
+func (r *approvalRuleRepository) Delete(ctx context.Context, approvalRule *models.ApprovalRule) error {
+	return contextDB(ctx, r.DB).Delete(approvalRule).Error
+}
+
+func (r *approvalRuleRepository) GetByIDAndLedgerID(
+	ctx context.Context,
+	id string,
+	ledgerID string,
+	populate *[]string,
+) (*models.ApprovalRule, error) {
+	var approvalRule models.ApprovalRule
+	db := contextDB(ctx, r.DB)
+
+	if populate != nil {
+		for _, field := range *populate {
+			db = db.Preload(field)
+		}
+	}
+
+	result := db.Where("id = ? AND ledger_id = ?", id, ledgerID).First(&approvalRule)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return &approvalRule, nil
+}
+
+func (r *approvalRuleRepository) ListActiveByLedgerID(
+	ctx context.Context,
+	ledgerID string,
+) (*[]models.ApprovalRule, error) {
+	var approvalRules []models.ApprovalRule
+
+	results := contextDB(ctx, r.DB).Where("ledger_id = ? AND is_active = ?", ledgerID, true).Find(&approvalRules)
+	if results.Error != nil {
+		return nil, results.Error
+	}
+
+	return &approvalRules, nil
+}
+
+type ListApprovalRulesFilter struct {
+	ClientId string
+	LedgerId string
+	IsActive *bool
+}
+
+// ApprovalRuleQueryFields allowlists the fields approval rules can be filtered, sorted and searched on.
+var ApprovalRuleQueryFields = lib.QueryFields{
+	"name":       {Column: "approval_rules.name", Type: lib.FieldString, Searchable: true},
+	"min_amount": {Column: "approval_rules.min_amount", Type: lib.FieldNumber},
+	"account_id": {Column: "approval_rules.account_id", Type: lib.FieldUUID},
+	"is_active":  {Column: "approval_rules.is_active", Type: lib.FieldBool},
+	"created_at": {Column: "approval_rules.created_at", Type: lib.FieldTime},
+	"updated_at": {Column: "approval_rules.updated_at", Type: lib.FieldTime},
+}
+
+func (r *approvalRuleRepository) List(
+	ctx context.Context,
+	filterQuery lib.FilterQuery,
+	filters ListApprovalRulesFilter,
+) (*[]models.ApprovalRule, error) {
+	var approvalRules []models.ApprovalRule
+
+	db := contextDB(ctx, r.DB).
+		Scopes(
+			DateRangeScope("approval_rules", filterQuery.DateRange),
+			ClientFilterScope("approval_rules", filters.ClientId),
+			LedgerFilterScope("approval_rules", filters.LedgerId),
+			IsActiveFilterScope("approval_rules", filters.IsActive),
+			FieldFiltersScope(filterQuery.Filters),
+			SearchScope(filterQuery.Search),
+
+			PaginationScope(filterQuery.Page, filterQuery.PageSize),
+			SortScope("approval_rules", filterQuery),
+		)
+
+	if filterQuery.Populate != nil {
+		for _, field := range *filterQuery.Populate {
+			db = db.Preload(field)
+		}
+	}
+
+	results := db.Find(&approvalRules)
+
+	if results.Error != nil {
+		return nil, results.Error
+	}
+
+	return &approvalRules, nil
+}
+
+func (r *approvalRuleRepository) Count(
+	ctx context.Context,
+	filterQuery lib.FilterQuery,
+	filters ListApprovalRulesFilter,
+) (int64, error) {
+	var count int64
+
+	result := contextDB(ctx, r.DB).
+		Model(&models.ApprovalRule{}).
+		Scopes(
+			DateRangeScope("approval_rules", filterQuery.DateRange),
+			ClientFilterScope("approval_rules", filters.ClientId),
+			LedgerFilterScope("approval_rules", filters.LedgerId),
+			IsActiveFilterScope("approval_rules", filters.IsActive),
+			FieldFiltersScope(filterQuery.Filters),
+			SearchScope(filterQuery.Search),
+		).
+		Count(&count)
+
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return count, nil
+}
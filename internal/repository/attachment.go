@@ -0,0 +1,168 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Bendomey/fincore-engine/internal/lib"
+	"github.com/Bendomey/fincore-engine/internal/models"
+	"gorm.io/gorm"
+)
+
+type AttachmentRepository interface {
+	Create(context context.Context, attachment *models.Attachment) error
+	Delete(context context.Context, attachment *models.Attachment) error
+	GetByIDAndEntryID(
+		context context.Context,
+		id string,
+		journalEntryID string,
+		populate *[]string,
+	) (*models.Attachment, error)
+	List(
+		context context.Context,
+		filterQuery lib.FilterQuery,
+		filters ListAttachmentsFilter,
+	) (*[]models.Attachment, error)
+	Count(context context.Context, filterQuery lib.FilterQuery, filters ListAttachmentsFilter) (int64, error)
+}
+
+type attachmentRepository struct {
+	DB *gorm.DB
+}
+
+func NewAttachmentRepository(DB *gorm.DB) AttachmentRepository {
+	return &attachmentRepository{DB}
+}
+
+func (r *attachmentRepository) Create(ctx context.Context, attachment *models.Attachment) error {
+	return contextDB(ctx, r.DB).Create(attachment).Error
+}
+
+func (r *attachmentRepository) Delete(ctx context.Context, attachment *models.Attachment) error {
+	return contextDB(ctx, r.DB).Delete(attachment).Error
+}
+
+func (r *attachmentRepository) GetByIDAndEntryID(
+	ctx context.Context,
+	id string,
+	journalEntryID string,
+	populate *[]string,
+) (*models.Attachment, error) {
+	var attachment models.Attachment
+	db := contextDB(ctx, r.DB)
+
+	if populate != nil {
+		for _, field := range *populate {
+			db = db.Preload(field)
+		}
+	}
+
+	result := db.Where("id = ? AND journal_entry_id = ?", id, journalEntryID).First(&attachment)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return &attachment, nil
+}
+
+type ListAttachmentsFilter struct {
+	ClientId       string
+	LedgerId       string
+	JournalEntryId string
+}
+
+// AttachmentQueryFields allowlists the fields attachments can be filtered, sorted and searched on.
+var AttachmentQueryFields = lib.QueryFields{
+	"file_name":    {Column: "attachments.file_name", Type: lib.FieldString, Searchable: true},
+	"content_type": {Column: "attachments.content_type", Type: lib.FieldString},
+	"size":         {Column: "attachments.size", Type: lib.FieldNumber},
+	"content_hash": {Column: "attachments.content_hash", Type: lib.FieldString},
+	"created_at":   {Column: "attachments.created_at", Type: lib.FieldTime},
+	"updated_at":   {Column: "attachments.updated_at", Type: lib.FieldTime},
+}
+
+func (r *attachmentRepository) List(
+	ctx context.Context,
+	filterQuery lib.FilterQuery,
+	filters ListAttachmentsFilter,
+) (*[]models.Attachment, error) {
+	var attachments []models.Attachment
+
+	db := contextDB(ctx, r.DB).
+		Scopes(
+			DateRangeScope("attachments", filterQuery.DateRange),
+			ClientFilterScope("attachments", filters.ClientId),
+			JournalEntryFilterScope("attachments", filters.JournalEntryId),
+			JournalEntryLedgerFilterScope("attachments", filters.LedgerId),
+			FieldFiltersScope(filterQuery.Filters),
+			SearchScope(filterQuery.Search),
+
+			PaginationScope(filterQuery.Page, filterQuery.PageSize),
+			SortScope("attachments", filterQuery),
+		)
+
+	if filterQuery.Populate != nil {
+		for _, field := range *filterQuery.Populate {
+			db = db.Preload(field)
+		}
+	}
+
+	results := db.Find(&attachments)
+
+	if results.Error != nil {
+		return nil, results.Error
+	}
+
+	return &attachments, nil
+}
+
+func (r *attachmentRepository) Count(
+	ctx context.Context,
+	filterQuery lib.FilterQuery,
+	filters ListAttachmentsFilter,
+) (int64, error) {
+	var count int64
+
+	result := contextDB(ctx, r.DB).
+		Model(&models.Attachment{}).
+		Scopes(
+			DateRangeScope("attachments", filterQuery.DateRange),
+			ClientFilterScope("attachments", filters.ClientId),
+			JournalEntryFilterScope("attachments", filters.JournalEntryId),
+			JournalEntryLedgerFilterScope("attachments", filters.LedgerId),
+			FieldFiltersScope(filterQuery.Filters),
+			SearchScope(filterQuery.Search),
+		).
+		Count(&count)
+
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return count, nil
+}
+
+func JournalEntryFilterScope(tableName string, journalEntryId string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if journalEntryId == "" {
+			return db
+		}
+
+		return db.Where(tableName+".journal_entry_id = ?", journalEntryId)
+	}
+}
+
+// JournalEntryLedgerFilterScope keeps the rows attached to the entries of one ledger, every ledger when ledgerId is
+// empty.
+func JournalEntryLedgerFilterScope(tableName string, ledgerId string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if ledgerId == "" {
+			return db
+		}
+
+		return db.Where(
+			tableName+".journal_entry_id IN (SELECT id::text FROM journal_entries WHERE ledger_id = ?)",
+			ledgerId,
+		)
+	}
+}
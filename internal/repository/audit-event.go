@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Bendomey/fincore-engine/internal/lib"
+	"github.com/Bendomey/fincore-engine/internal/models"
+	"gorm.io/gorm"
+)
+
+// AuditEventRepository only appends and reads, audit events are never changed.
+type AuditEventRepository interface {
+	Create(context context.Context, auditEvent *models.AuditEvent) error
+	List(
+		context context.Context,
+		filterQuery lib.FilterQuery,
+		filters ListAuditEventsFilter,
+	) (*[]models.AuditEvent, error)
+	Count(context context.Context, filterQuery lib.FilterQuery, filters ListAuditEventsFilter) (int64, error)
+}
+
+type auditEventRepository struct {
+	DB *gorm.DB
+}
+
+func NewAuditEventRepository(DB *gorm.DB) AuditEventRepository {
+	return &auditEventRepository{DB}
+}
+
+func (r *auditEventRepository) Create(ctx context.Context, auditEvent *models.AuditEvent) error {
+	return contextDB(ctx, r.DB).Create(auditEvent).Error
+}
+
+type ListAuditEventsFilter struct {
+	ClientId     string
+	LedgerId     *string
+	ActorId      *string
+	RequestId    *string
+	Action       *string
+	ResourceType *string
+	ResourceId   *string
+}
+
+// AuditEventQueryFields allowlists the fields audit events can be filtered, sorted and searched on.
+var AuditEventQueryFields = lib.QueryFields{
+	"ledger_id":     {Column: "audit_events.ledger_id", Type: lib.FieldUUID},
+	"actor_id":      {Column: "audit_events.actor_id", Type: lib.FieldString},
+	"request_id":    {Column: "audit_events.request_id", Type: lib.FieldString},
+	"action":        {Column: "audit_events.action", Type: lib.FieldString},
+	"resource_type": {Column: "audit_events.resource_type", Type: lib.FieldString},
+	"resource_id":   {Column: "audit_events.resource_id", Type: lib.FieldString},
+	"created_at":    {Column: "audit_events.created_at", Type: lib.FieldTime},
+	"updated_at":    {Column: "audit_events.updated_at", Type: lib.FieldTime},
+}
+
+func (r *auditEventRepository) List(
+	ctx context.Context,
+	filterQuery lib.FilterQuery,
+	filters ListAuditEventsFilter,
+) (*[]models.AuditEvent, error) {
+	var auditEvents []models.AuditEvent
+
+	results := contextDB(ctx, r.DB).
+		Scopes(
+			DateRangeScope("audit_events", filterQuery.DateRange),
+			ClientFilterScope("audit_events", filters.ClientId),
+			AuditEventFiltersScope(filters),
+			FieldFiltersScope(filterQuery.Filters),
+
+			PaginationScope(filterQuery.Page, filterQuery.PageSize),
+			SortScope("audit_events", filterQuery),
+		).
+		Find(&auditEvents)
+
+	if results.Error != nil {
+		return nil, results.Error
+	}
+
+	return &auditEvents, nil
+}
+
+func (r *auditEventRepository) Count(
+	ctx context.Context,
+	filterQuery lib.FilterQuery,
+	filters ListAuditEventsFilter,
+) (int64, error) {
+	var count int64
+
+	result := contextDB(ctx, r.DB).
+		Model(&models.AuditEvent{}).
+		Scopes(
+			DateRangeScope("audit_events", filterQuery.DateRange),
+			ClientFilterScope("audit_events", filters.ClientId),
+			AuditEventFiltersScope(filters),
+			FieldFiltersScope(filterQuery.Filters),
+		).
+		Count(&count)
+
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return count, nil
+}
+
+func AuditEventFiltersScope(filters ListAuditEventsFilter) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if filters.LedgerId != nil {
+			db = db.Where("audit_events.ledger_id = ?", *filters.LedgerId)
+		}
+
+		if filters.ActorId != nil {
+			db = db.Where("audit_events.actor_id = ?", *filters.ActorId)
+		}
+
+		if filters.RequestId != nil {
+			db = db.Where("audit_events.request_id = ?", *filters.RequestId)
+		}
+
+		if filters.Action != nil {
+			db = db.Where("audit_events.action = ?", *filters.Action)
+		}
+
+		if filters.ResourceType != nil {
+			db = db.Where("audit_events.resource_type = ?", *filters.ResourceType)
+		}
+
+		if filters.ResourceId != nil {
+			db = db.Where("audit_events.resource_id = ?", *filters.ResourceId)
+		}
+
+		return db
+	}
+}
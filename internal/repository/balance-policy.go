@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Bendomey/fincore-engine/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrInsufficientBalance is returned when posting would take an account below the balance its policy allows.
+var ErrInsufficientBalance = errors.New("insufficient balance")
+
+type accountBalanceRow struct {
+	AccountID string
+	Debit     int64
+	Credit    int64
+}
+
+type accountHeldRow struct {
+	AccountID string
+	Held      int64
+}
+
+// enforceBalancePolicies refuses to post an entry that takes the available balance of an account disallowing
+// negative balances below -OverdraftLimit. The entry must already be stored as posted so it is part of the
+// balance.
+func enforceBalancePolicies(tx *gorm.DB, journalEntry *models.JournalEntry) error {
+	var lines []models.JournalEntryLine
+	if err := tx.Where("journal_entry_id = ?", journalEntry.ID).Find(&lines).Error; err != nil {
+		return err
+	}
+
+	deltas := map[string]accountBalanceRow{}
+	for _, line := range lines {
+		delta := deltas[line.AccountID]
+		delta.Debit += line.Debit
+		delta.Credit += line.Credit
+		deltas[line.AccountID] = delta
+	}
+
+	accountIDs := make([]string, 0, len(deltas))
+	for accountID := range deltas {
+		accountIDs = append(accountIDs, accountID)
+	}
+
+	accounts, err := lockPolicyAccounts(tx, accountIDs)
+	if err != nil || len(accounts) == 0 {
+		return err
+	}
+
+	available, err := availableBalances(tx, journalEntry.ClientID, accounts)
+	if err != nil {
+		return err
+	}
+
+	for _, account := range accounts {
+		delta := deltas[account.ID.String()]
+
+		// an entry that leaves the balance where it was or raises it is always allowed, even when the account
+		// is already below its floor (e.g. the policy was turned on afterwards).
+		if account.Balance(delta.Debit, delta.Credit) >= 0 {
+			continue
+		}
+
+		if err := checkBalanceFloor(&account, available[account.ID.String()]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// lockPolicyAccounts locks the accounts among accountIDs that disallow negative balances. They are locked in id
+// order, so concurrent posts can't deadlock, before their balance is read: writes touching the same account are
+// therefore checked one after the other.
+func lockPolicyAccounts(tx *gorm.DB, accountIDs []string) ([]models.Account, error) {
+	var accounts []models.Account
+	if len(accountIDs) == 0 {
+		return accounts, nil
+	}
+
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id IN ? AND disallow_negative_balance = ?", accountIDs, true).
+		Order("id").
+		Find(&accounts).Error
+
+	return accounts, err
+}
+
+// availableBalances returns the posted balance of the accounts, on their normal side, less their pending holds.
+func availableBalances(tx *gorm.DB, clientID string, accounts []models.Account) (map[string]int64, error) {
+	accountIDs := make([]string, 0, len(accounts))
+	for _, account := range accounts {
+		accountIDs = append(accountIDs, account.ID.String())
+	}
+
+	var rows []accountBalanceRow
+	err := tx.Model(&models.JournalEntryLine{}).
+		Scopes(PostedLinesScope(clientID, nil)).
+		Where("journal_entry_lines.account_id IN ?", accountIDs).
+		Select(
+			"journal_entry_lines.account_id AS account_id, " +
+				"SUM(journal_entry_lines.debit) AS debit, SUM(journal_entry_lines.credit) AS credit",
+		).
+		Group("journal_entry_lines.account_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var held []accountHeldRow
+	err = tx.Model(&models.Hold{}).
+		Scopes(ActiveHoldsScope(time.Now())).
+		Where("holds.account_id IN ?", accountIDs).
+		Select("holds.account_id AS account_id, SUM(holds.amount) AS held").
+		Group("holds.account_id").
+		Scan(&held).Error
+	if err != nil {
+		return nil, err
+	}
+
+	totals := map[string]accountBalanceRow{}
+	for _, row := range rows {
+		totals[row.AccountID] = row
+	}
+
+	available := map[string]int64{}
+	for _, account := range accounts {
+		total := totals[account.ID.String()]
+		available[account.ID.String()] = account.Balance(total.Debit, total.Credit)
+	}
+
+	for _, row := range held {
+		available[row.AccountID] -= row.Held
+	}
+
+	return available, nil
+}
+
+func checkBalanceFloor(account *models.Account, balance int64) error {
+	if balance >= -account.OverdraftLimit {
+		return nil
+	}
+
+	return fmt.Errorf(
+		"%w: account %s would be left with %d available, below its limit of %d",
+		ErrInsufficientBalance,
+		account.Code,
+		balance,
+		-account.OverdraftLimit,
+	)
+}
@@ -0,0 +1,225 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/Bendomey/fincore-engine/internal/lib"
+	"github.com/Bendomey/fincore-engine/internal/models"
+	"gorm.io/gorm"
+)
+
+type BudgetLineRepository interface {
+	Create(context context.Context, budgetLine *models.BudgetLine) error
+	Update(context context.Context, budgetLine *models.BudgetLine) error
+	Delete(context context.Context, budgetLine *models.BudgetLine) error
+	Upsert(context context.Context, budgetLines []models.BudgetLine) error
+	GetByIDAndBudgetID(
+		context context.Context,
+		id string,
+		budgetID string,
+		populate *[]string,
+	) (*models.BudgetLine, error)
+	ListAllByBudgetID(context context.Context, budgetID string) (*[]models.BudgetLine, error)
+	List(
+		context context.Context,
+		filterQuery lib.FilterQuery,
+		filters ListBudgetLinesFilter,
+	) (*[]models.BudgetLine, error)
+	Count(context context.Context, filterQuery lib.FilterQuery, filters ListBudgetLinesFilter) (int64, error)
+}
+
+type budgetLineRepository struct {
+	DB *gorm.DB
+}
+
+func NewBudgetLineRepository(DB *gorm.DB) BudgetLineRepository {
+	return &budgetLineRepository{DB}
+}
+
+func (r *budgetLineRepository) Create(ctx context.Context, budgetLine *models.BudgetLine) error {
+	return contextDB(ctx, r.DB).Create(budgetLine).Error
+}
+
+func (r *budgetLineRepository) Update(ctx context.Context, budgetLine *models.BudgetLine) error {
+	budgetLine.UpdatedAt = time.Now()
+	return contextDB(ctx, r.DB).Save(budgetLine).Error
+}
+
+func (r *budgetLineRepository) Delete(ctx context.Context, budgetLine *models.BudgetLine) error {
+	return contextDB(ctx, r.DB).Delete(budgetLine).Error
+}
+
+// Upsert saves the lines in a single transaction. A line replaces the amount of an existing line of the same
+// budget, account, dimension value and period.
+func (r *budgetLineRepository) Upsert(ctx context.Context, budgetLines []models.BudgetLine) error {
+	return contextDB(ctx, r.DB).Transaction(func(tx *gorm.DB) error {
+		for index := range budgetLines {
+			budgetLine := &budgetLines[index]
+
+			var existing models.BudgetLine
+			db := tx.Where(
+				"budget_id = ? AND account_id = ? AND period_start = ? AND period_end = ?",
+				budgetLine.BudgetID,
+				budgetLine.AccountID,
+				budgetLine.PeriodStart,
+				budgetLine.PeriodEnd,
+			)
+
+			if budgetLine.DimensionValueID == nil {
+				db = db.Where("dimension_value_id IS NULL")
+			} else {
+				db = db.Where("dimension_value_id = ?", *budgetLine.DimensionValueID)
+			}
+
+			result := db.Limit(1).Find(&existing)
+			if result.Error != nil {
+				return result.Error
+			}
+
+			if result.RowsAffected == 0 {
+				if err := tx.Create(budgetLine).Error; err != nil {
+					return err
+				}
+				continue
+			}
+
+			existing.Amount = budgetLine.Amount
+			existing.UpdatedAt = time.Now()
+			if err := tx.Save(&existing).Error; err != nil {
+				return err
+			}
+
+			*budgetLine = existing
+		}
+
+		return nil
+	})
+}
+
+func (r *budgetLineRepository) GetByIDAndBudgetID(
+	ctx context.Context,
+	id string,
+	budgetID string,
+	populate *[]string,
+) (*models.BudgetLine, error) {
+	var budgetLine models.BudgetLine
+	db := contextDB(ctx, r.DB)
+
+	if populate != nil {
+		for _, field := range *populate {
+			db = db.Preload(field)
+		}
+	}
+
+	result := db.Where("id = ? AND budget_id = ?", id, budgetID).First(&budgetLine)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return &budgetLine, nil
+}
+
+// ListAllByBudgetID returns every line of a budget ordered by period, mostly used to build reports.
+func (r *budgetLineRepository) ListAllByBudgetID(
+	ctx context.Context,
+	budgetID string,
+) (*[]models.BudgetLine, error) {
+	var budgetLines []models.BudgetLine
+
+	results := contextDB(ctx, r.DB).
+		Preload("Account").
+		Preload("DimensionValue").
+		Where("budget_id = ?", budgetID).
+		Order("period_start asc").
+		Find(&budgetLines)
+	if results.Error != nil {
+		return nil, results.Error
+	}
+
+	return &budgetLines, nil
+}
+
+type ListBudgetLinesFilter struct {
+	BudgetId         string
+	AccountId        *string
+	DimensionValueId *string
+}
+
+// BudgetLineQueryFields allowlists the fields budget lines can be filtered, sorted and searched on.
+var BudgetLineQueryFields = lib.QueryFields{
+	"account_id":         {Column: "budget_lines.account_id", Type: lib.FieldUUID},
+	"dimension_value_id": {Column: "budget_lines.dimension_value_id", Type: lib.FieldUUID},
+	"period_start":       {Column: "budget_lines.period_start", Type: lib.FieldTime},
+	"period_end":         {Column: "budget_lines.period_end", Type: lib.FieldTime},
+	"amount":             {Column: "budget_lines.amount", Type: lib.FieldNumber},
+	"created_at":         {Column: "budget_lines.created_at", Type: lib.FieldTime},
+	"updated_at":         {Column: "budget_lines.updated_at", Type: lib.FieldTime},
+}
+
+func (r *budgetLineRepository) List(
+	ctx context.Context,
+	filterQuery lib.FilterQuery,
+	filters ListBudgetLinesFilter,
+) (*[]models.BudgetLine, error) {
+	var budgetLines []models.BudgetLine
+
+	db := contextDB(ctx, r.DB).
+		Scopes(
+			BudgetLineFiltersScope(filters),
+			FieldFiltersScope(filterQuery.Filters),
+
+			PaginationScope(filterQuery.Page, filterQuery.PageSize),
+			SortScope("budget_lines", filterQuery),
+		)
+
+	if filterQuery.Populate != nil {
+		for _, field := range *filterQuery.Populate {
+			db = db.Preload(field)
+		}
+	}
+
+	results := db.Find(&budgetLines)
+
+	if results.Error != nil {
+		return nil, results.Error
+	}
+
+	return &budgetLines, nil
+}
+
+func (r *budgetLineRepository) Count(
+	ctx context.Context,
+	filterQuery lib.FilterQuery,
+	filters ListBudgetLinesFilter,
+) (int64, error) {
+	var count int64
+
+	result := contextDB(ctx, r.DB).
+		Model(&models.BudgetLine{}).
+		Scopes(BudgetLineFiltersScope(filters), FieldFiltersScope(filterQuery.Filters)).
+		Count(&count)
+
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return count, nil
+}
+
+func BudgetLineFiltersScope(filters ListBudgetLinesFilter) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		db = db.Where("budget_lines.budget_id = ?", filters.BudgetId)
+
+		if filters.AccountId != nil && *filters.AccountId != "" {
+			db = db.Where("budget_lines.account_id = ?", *filters.AccountId)
+		}
+
+		if filters.DimensionValueId != nil && *filters.DimensionValueId != "" {
+			db = db.Where("budget_lines.dimension_value_id = ?", *filters.DimensionValueId)
+		}
+
+		return db
+	}
+}
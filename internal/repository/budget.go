@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/Bendomey/fincore-engine/internal/lib"
+	"github.com/Bendomey/fincore-engine/internal/models"
+	"gorm.io/gorm"
+)
+
+type BudgetRepository interface {
+	Create(context context.Context, budget *models.Budget) error
+	Update(context context.Context, budget *models.Budget) error
+	Delete(context context.Context, budget *models.Budget) error
+	GetByIDAndClientID(
+		context context.Context,
+		id string,
+		clientID string,
+		populate *[]string,
+	) (*models.Budget, error)
+	List(context context.Context, filterQuery lib.FilterQuery, filters ListBudgetsFilter) (*[]models.Budget, error)
+	Count(context context.Context, filterQuery lib.FilterQuery, filters ListBudgetsFilter) (int64, error)
+}
+
+type budgetRepository struct {
+	DB *gorm.DB
+}
+
+func NewBudgetRepository(DB *gorm.DB) BudgetRepository {
+	return &budgetRepository{DB}
+}
+
+func (r *budgetRepository) Create(ctx context.Context, budget *models.Budget) error {
+	return contextDB(ctx, r.DB).Create(budget).Error
+}
+
+func (r *budgetRepository) Update(ctx context.Context, budget *models.Budget) error {
+	budget.UpdatedAt = time.Now()
+	return contextDB(ctx, r.DB).Save(budget).Error
+}
+
+// Delete removes the budget together with its lines.
+func (r *budgetRepository) Delete(ctx context.Context, budget *models.Budget) error {
+	return contextDB(ctx, r.DB).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("budget_id = ?", budget.ID.String()).Delete(&models.BudgetLine{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Delete(budget).Error
+	})
+}
+
+func (r *budgetRepository) GetByIDAndClientID(
+	ctx context.Context,
+	id string,
+	clientID string,
+	populate *[]string,
+) (*models.Budget, error) {
+	var budget models.Budget
+	db := contextDB(ctx, r.DB)
+
+	if populate != nil {
+		for _, field := range *populate {
+			db = db.Preload(field)
+		}
+	}
+
+	result := db.Where("id = ? AND client_id = ?", id, clientID).First(&budget)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return &budget, nil
+}
+
+type ListBudgetsFilter struct {
+	ClientId string
+}
+
+// BudgetQueryFields allowlists the fields budgets can be filtered, sorted and searched on.
+var BudgetQueryFields = lib.QueryFields{
+	"name":        {Column: "budgets.name", Type: lib.FieldString, Searchable: true},
+	"description": {Column: "budgets.description", Type: lib.FieldString, Searchable: true},
+	"created_at":  {Column: "budgets.created_at", Type: lib.FieldTime},
+	"updated_at":  {Column: "budgets.updated_at", Type: lib.FieldTime},
+}
+
+func (r *budgetRepository) List(
+	ctx context.Context,
+	filterQuery lib.FilterQuery,
+	filters ListBudgetsFilter,
+) (*[]models.Budget, error) {
+	var budgets []models.Budget
+
+	db := contextDB(ctx, r.DB).
+		Scopes(
+			DateRangeScope("budgets", filterQuery.DateRange),
+			ClientFilterScope("budgets", filters.ClientId),
+			FieldFiltersScope(filterQuery.Filters),
+			SearchScope(filterQuery.Search),
+
+			PaginationScope(filterQuery.Page, filterQuery.PageSize),
+			SortScope("budgets", filterQuery),
+		)
+
+	if filterQuery.Populate != nil {
+		for _, field := range *filterQuery.Populate {
+			db = db.Preload(field)
+		}
+	}
+
+	results := db.Find(&budgets)
+
+	if results.Error != nil {
+		return nil, results.Error
+	}
+
+	return &budgets, nil
+}
+
+func (r *budgetRepository) Count(
+	ctx context.Context,
+	filterQuery lib.FilterQuery,
+	filters ListBudgetsFilter,
+) (int64, error) {
+	var count int64
+
+	result := contextDB(ctx, r.DB).
+		Model(&models.Budget{}).
+		Scopes(
+			DateRangeScope("budgets", filterQuery.DateRange),
+			ClientFilterScope("budgets", filters.ClientId),
+			FieldFiltersScope(filterQuery.Filters),
+			SearchScope(filterQuery.Search),
+		).
+		Count(&count)
+
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return count, nil
+}
@@ -24,7 +24,7 @@ func NewClientRepository(DB *gorm.DB) ClientRepository {
 
 func (r *clientRepository) GetByClientID(ctx context.Context, clientId string) (*models.Client, error) {
 	var client models.Client
-	result := r.DB.WithContext(ctx).Where("client_id = ?", clientId).First(&client)
+	result := contextDB(ctx, r.DB).Where("client_id = ?", clientId).First(&client)
 	if result.Error != nil {
 		return nil, result.Error
 	}
@@ -33,7 +33,7 @@ func (r *clientRepository) GetByClientID(ctx context.Context, clientId string) (
 
 func (r *clientRepository) GetByClientEmail(ctx context.Context, email string) (*models.Client, error) {
 	var client models.Client
-	result := r.DB.WithContext(ctx).Where("email = ?", email).First(&client)
+	result := contextDB(ctx, r.DB).Where("email = ?", email).First(&client)
 	if result.Error != nil {
 		return nil, result.Error
 	}
@@ -43,7 +43,7 @@ func (r *clientRepository) GetByClientEmail(ctx context.Context, email string) (
 
 func (r *clientRepository) GetByID(ctx context.Context, id string) (*models.Client, error) {
 	var client models.Client
-	result := r.DB.WithContext(ctx).Where("id = ?", id).First(&client)
+	result := contextDB(ctx, r.DB).Where("id = ?", id).First(&client)
 	if result.Error != nil {
 		return nil, result.Error
 	}
@@ -51,5 +51,5 @@ func (r *clientRepository) GetByID(ctx context.Context, id string) (*models.Clie
 }
 
 func (r *clientRepository) Create(ctx context.Context, client *models.Client) error {
-	return r.DB.WithContext(ctx).Create(client).Error
+	return contextDB(ctx, r.DB).Create(client).Error
 }
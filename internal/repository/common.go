@@ -2,6 +2,7 @@ package repository
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/Bendomey/fincore-engine/internal/lib"
 	"gorm.io/gorm"
@@ -18,30 +19,47 @@ func DateRangeScope(tableName string, dateRange *lib.DateRangeType) func(db *gor
 	}
 }
 
-func SearchScope(tableName string, search *lib.Search) func(db *gorm.DB) *gorm.DB {
+// SearchScope matches the search against any of its columns. The alternatives are grouped, so they never widen
+// the conditions they are combined with, the client's scope above all.
+func SearchScope(search *lib.Search) func(db *gorm.DB) *gorm.DB {
 	return func(db *gorm.DB) *gorm.DB {
-		if search == nil || search.Query == "" {
+		if search == nil || search.Query == "" || len(search.Columns) == 0 {
 			return db
 		}
 
-		results := db
+		conditions := make([]string, 0, len(search.Columns))
+		values := make([]interface{}, 0, len(search.Columns))
+		for _, column := range search.Columns {
+			conditions = append(conditions, fmt.Sprintf("%s ILIKE ?", column))
+			values = append(values, fmt.Sprintf("%%%s%%", lib.EscapeLike(search.Query)))
+		}
+
+		return db.Where(fmt.Sprintf("(%s)", strings.Join(conditions, " OR ")), values...)
+	}
+}
 
-		for index, singleQuery := range search.SearchFields {
-			if index == 0 {
-				results = results.Where(
-					fmt.Sprintf("%s.%s ILIKE ?", tableName, singleQuery),
-					fmt.Sprintf("%%%s%%", search.Query),
+// FieldFiltersScope applies the `filter[...]` conditions of a list, all of which must hold.
+func FieldFiltersScope(filters []lib.FieldFilter) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		for _, filter := range filters {
+			switch filter.Operator {
+			case lib.OperatorEq:
+				db = db.Where(fmt.Sprintf("%s = ?", filter.Column), filter.Values[0])
+			case lib.OperatorIn:
+				db = db.Where(fmt.Sprintf("%s IN ?", filter.Column), filter.Values)
+			case lib.OperatorGte:
+				db = db.Where(fmt.Sprintf("%s >= ?", filter.Column), filter.Values[0])
+			case lib.OperatorLte:
+				db = db.Where(fmt.Sprintf("%s <= ?", filter.Column), filter.Values[0])
+			case lib.OperatorContains:
+				db = db.Where(
+					fmt.Sprintf("%s ILIKE ?", filter.Column),
+					fmt.Sprintf("%%%s%%", lib.EscapeLike(filter.Values[0].(string))),
 				)
-				continue
 			}
-
-			results = results.Or(
-				fmt.Sprintf("%s.%s ILIKE ?", tableName, singleQuery),
-				fmt.Sprintf("%%%s%%", search.Query),
-			)
 		}
 
-		return results
+		return db
 	}
 }
 
@@ -59,13 +77,78 @@ func PaginationScope(page int, pageSize int) func(db *gorm.DB) *gorm.DB {
 	}
 }
 
-func OrderScope(tableName string, orderBy string, order string) func(db *gorm.DB) *gorm.DB {
+// SortScope orders a list by its sort, or by created_at in the query's order when it has none.
+func SortScope(tableName string, filterQuery lib.FilterQuery) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if len(filterQuery.Sort) == 0 {
+			direction := "desc"
+			if filterQuery.Order == "asc" {
+				direction = "asc"
+			}
+
+			return db.Order(fmt.Sprintf("%s.created_at %s", tableName, direction))
+		}
+
+		for _, field := range filterQuery.Sort {
+			direction := "asc"
+			if field.Desc {
+				direction = "desc"
+			}
+
+			db = db.Order(fmt.Sprintf("%s %s", field.Column, direction))
+		}
+
+		return db
+	}
+}
+
+// PageScope paginates a list by page, or by cursor when the query asks for it. A cursor page fetches one row more
+// than its size so the caller can tell whether there is a next one.
+func PageScope(tableName string, filterQuery lib.FilterQuery) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if filterQuery.Cursor == nil {
+			return db.Scopes(
+				PaginationScope(filterQuery.Page, filterQuery.PageSize),
+				SortScope(tableName, filterQuery),
+			)
+		}
+
+		return db.Scopes(
+			KeysetPaginationScope(tableName, filterQuery.Cursor.After, filterQuery.PageSize, filterQuery.Order),
+		)
+	}
+}
+
+// KeysetPaginationScope returns the rows after the cursor, ordered by created_at then id so rows created at the
+// same instant still have a stable position.
+func KeysetPaginationScope(
+	tableName string,
+	after *lib.Cursor,
+	pageSize int,
+	order string,
+) func(db *gorm.DB) *gorm.DB {
 	return func(db *gorm.DB) *gorm.DB {
-		if orderBy == "" || order == "" {
-			return db.Order(fmt.Sprintf("%s.created_at desc", tableName))
+		if pageSize <= 0 {
+			pageSize = 10
+		}
+
+		direction, comparison := "desc", "<"
+		if order == "asc" {
+			direction, comparison = "asc", ">"
+		}
+
+		if after != nil {
+			db = db.Where(
+				fmt.Sprintf("(%s.created_at, %s.id) %s (?, ?::uuid)", tableName, tableName, comparison),
+				after.CreatedAt,
+				after.ID,
+			)
 		}
 
-		return db.Order(fmt.Sprintf("%s %s", orderBy, order))
+		return db.
+			Order(fmt.Sprintf("%s.created_at %s", tableName, direction)).
+			Order(fmt.Sprintf("%s.id %s", tableName, direction)).
+			Limit(pageSize + 1)
 	}
 }
 
@@ -77,3 +160,23 @@ func ClientFilterScope(tableName string, clientId string) func(db *gorm.DB) *gor
 		return db.Where(fmt.Sprintf("%s.client_id = ?", tableName), clientId)
 	}
 }
+
+// LedgerFilterScope keeps the rows of one ledger, rows of every ledger of the client when ledgerId is empty.
+func LedgerFilterScope(tableName string, ledgerId string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if ledgerId == "" {
+			return db
+		}
+		return db.Where(fmt.Sprintf("%s.ledger_id = ?", tableName), ledgerId)
+	}
+}
+
+func IsActiveFilterScope(tableName string, isActive *bool) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if isActive == nil {
+			return db
+		}
+
+		return db.Where(fmt.Sprintf("%s.is_active = ?", tableName), *isActive)
+	}
+}
@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/Bendomey/fincore-engine/internal/lib"
+	"github.com/Bendomey/fincore-engine/internal/models"
+	"gorm.io/gorm"
+)
+
+type DimensionTypeRepository interface {
+	Create(context context.Context, dimensionType *models.DimensionType) error
+	Update(context context.Context, dimensionType *models.DimensionType) error
+	Delete(context context.Context, dimensionType *models.DimensionType) error
+	GetByIDAndLedgerID(
+		context context.Context,
+		id string,
+		ledgerID string,
+		populate *[]string,
+	) (*models.DimensionType, error)
+	GetByCodeAndLedgerID(context context.Context, code string, ledgerID string) (*models.DimensionType, error)
+	List(
+		context context.Context,
+		filterQuery lib.FilterQuery,
+		filters ListDimensionTypesFilter,
+	) (*[]models.DimensionType, error)
+	Count(context context.Context, filterQuery lib.FilterQuery, filters ListDimensionTypesFilter) (int64, error)
+}
+
+type dimensionTypeRepository struct {
+	DB *gorm.DB
+}
+
+func NewDimensionTypeRepository(DB *gorm.DB) DimensionTypeRepository {
+	return &dimensionTypeRepository{DB}
+}
+
+func (r *dimensionTypeRepository) Create(ctx context.Context, dimensionType *models.DimensionType) error {
+	return contextDB(ctx, r.DB).Create(dimensionType).Error
+}
+
+func (r *dimensionTypeRepository) Update(ctx context.Context, dimensionType *models.DimensionType) error {
+	dimensionType.UpdatedAt = time.Now()
+	return contextDB(ctx, r.DB).Save(dimensionType).Error
+}
+
+func (r *dimensionTypeRepository) Delete(ctx context.Context, dimensionType *models.DimensionType) error {
+	return contextDB(ctx, r.DB).Delete(dimensionType).Error
+}
+
+func (r *dimensionTypeRepository) GetByIDAndLedgerID(
+	ctx context.Context,
+	id string,
+	ledgerID string,
+	populate *[]string,
+) (*models.DimensionType, error) {
+	var dimensionType models.DimensionType
+	db := contextDB(ctx, r.DB)
+
+	if populate != nil {
+		for _, field := range *populate {
+			db = db.Preload(field)
+		}
+	}
+
+	result := db.Where("id = ? AND ledger_id = ?", id, ledgerID).First(&dimensionType)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return &dimensionType, nil
+}
+
+func (r *dimensionTypeRepository) GetByCodeAndLedgerID(
+	ctx context.Context,
+	code string,
+	ledgerID string,
+) (*models.DimensionType, error) {
+	var dimensionType models.DimensionType
+	result := contextDB(ctx, r.DB).Where("code = ? AND ledger_id = ?", code, ledgerID).First(&dimensionType)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return &dimensionType, nil
+}
+
+type ListDimensionTypesFilter struct {
+	ClientId string
+	LedgerId string
+}
+
+// DimensionTypeQueryFields allowlists the fields dimension types can be filtered, sorted and searched on.
+var DimensionTypeQueryFields = lib.QueryFields{
+	"code":        {Column: "dimension_types.code", Type: lib.FieldString, Searchable: true},
+	"name":        {Column: "dimension_types.name", Type: lib.FieldString, Searchable: true},
+	"description": {Column: "dimension_types.description", Type: lib.FieldString, Searchable: true},
+	"created_at":  {Column: "dimension_types.created_at", Type: lib.FieldTime},
+	"updated_at":  {Column: "dimension_types.updated_at", Type: lib.FieldTime},
+}
+
+func (r *dimensionTypeRepository) List(
+	ctx context.Context,
+	filterQuery lib.FilterQuery,
+	filters ListDimensionTypesFilter,
+) (*[]models.DimensionType, error) {
+	var dimensionTypes []models.DimensionType
+
+	db := contextDB(ctx, r.DB).
+		Scopes(
+			DateRangeScope("dimension_types", filterQuery.DateRange),
+			ClientFilterScope("dimension_types", filters.ClientId),
+			LedgerFilterScope("dimension_types", filters.LedgerId),
+			FieldFiltersScope(filterQuery.Filters),
+			SearchScope(filterQuery.Search),
+
+			PaginationScope(filterQuery.Page, filterQuery.PageSize),
+			SortScope("dimension_types", filterQuery),
+		)
+
+	if filterQuery.Populate != nil {
+		for _, field := range *filterQuery.Populate {
+			db = db.Preload(field)
+		}
+	}
+
+	results := db.Find(&dimensionTypes)
+
+	if results.Error != nil {
+		return nil, results.Error
+	}
+
+	return &dimensionTypes, nil
+}
+
+func (r *dimensionTypeRepository) Count(
+	ctx context.Context,
+	filterQuery lib.FilterQuery,
+	filters ListDimensionTypesFilter,
+) (int64, error) {
+	var count int64
+
+	result := contextDB(ctx, r.DB).
+		Model(&models.DimensionType{}).
+		Scopes(
+			DateRangeScope("dimension_types", filterQuery.DateRange),
+			ClientFilterScope("dimension_types", filters.ClientId),
+			LedgerFilterScope("dimension_types", filters.LedgerId),
+			FieldFiltersScope(filterQuery.Filters),
+			SearchScope(filterQuery.Search),
+		).
+		Count(&count)
+
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return count, nil
+}
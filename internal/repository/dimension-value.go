@@ -0,0 +1,210 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/Bendomey/fincore-engine/internal/lib"
+	"github.com/Bendomey/fincore-engine/internal/models"
+	"gorm.io/gorm"
+)
+
+type DimensionValueRepository interface {
+	Create(context context.Context, dimensionValue *models.DimensionValue) error
+	Update(context context.Context, dimensionValue *models.DimensionValue) error
+	Delete(context context.Context, dimensionValue *models.DimensionValue) error
+	GetByIDAndTypeID(
+		context context.Context,
+		id string,
+		dimensionTypeID string,
+		populate *[]string,
+	) (*models.DimensionValue, error)
+	GetByIDAndLedgerID(
+		context context.Context,
+		id string,
+		ledgerID string,
+		populate *[]string,
+	) (*models.DimensionValue, error)
+	GetByCodeAndTypeID(context context.Context, code string, dimensionTypeID string) (*models.DimensionValue, error)
+	List(
+		context context.Context,
+		filterQuery lib.FilterQuery,
+		filters ListDimensionValuesFilter,
+	) (*[]models.DimensionValue, error)
+	Count(context context.Context, filterQuery lib.FilterQuery, filters ListDimensionValuesFilter) (int64, error)
+}
+
+type dimensionValueRepository struct {
+	DB *gorm.DB
+}
+
+func NewDimensionValueRepository(DB *gorm.DB) DimensionValueRepository {
+	return &dimensionValueRepository{DB}
+}
+
+func (r *dimensionValueRepository) Create(ctx context.Context, dimensionValue *models.DimensionValue) error {
+	return contextDB(ctx, r.DB).Create(dimensionValue).Error
+}
+
+func (r *dimensionValueRepository) Update(ctx context.Context, dimensionValue *models.DimensionValue) error {
+	dimensionValue.UpdatedAt = time.Now()
+	return contextDB(ctx, r.DB).Save(dimensionValue).Error
+}
+
+func (r *dimensionValueRepository) Delete(ctx context.Context, dimensionValue *models.DimensionValue) error {
+	return contextDB(ctx, r.DB).Delete(dimensionValue).Error
+}
+
+func (r *dimensionValueRepository) GetByIDAndTypeID(
+	ctx context.Context,
+	id string,
+	dimensionTypeID string,
+	populate *[]string,
+) (*models.DimensionValue, error) {
+	var dimensionValue models.DimensionValue
+	db := contextDB(ctx, r.DB)
+
+	if populate != nil {
+		for _, field := range *populate {
+			db = db.Preload(field)
+		}
+	}
+
+	result := db.Where("id = ? AND dimension_type_id = ?", id, dimensionTypeID).First(&dimensionValue)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return &dimensionValue, nil
+}
+
+func (r *dimensionValueRepository) GetByIDAndLedgerID(
+	ctx context.Context,
+	id string,
+	ledgerID string,
+	populate *[]string,
+) (*models.DimensionValue, error) {
+	var dimensionValue models.DimensionValue
+	db := contextDB(ctx, r.DB)
+
+	if populate != nil {
+		for _, field := range *populate {
+			db = db.Preload(field)
+		}
+	}
+
+	result := db.Where("id = ? AND ledger_id = ?", id, ledgerID).First(&dimensionValue)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return &dimensionValue, nil
+}
+
+func (r *dimensionValueRepository) GetByCodeAndTypeID(
+	ctx context.Context,
+	code string,
+	dimensionTypeID string,
+) (*models.DimensionValue, error) {
+	var dimensionValue models.DimensionValue
+	result := contextDB(ctx, r.DB).
+		Where("code = ? AND dimension_type_id = ?", code, dimensionTypeID).
+		First(&dimensionValue)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return &dimensionValue, nil
+}
+
+type ListDimensionValuesFilter struct {
+	ClientId        string
+	LedgerId        string
+	DimensionTypeId string
+	IsActive        *bool
+}
+
+// DimensionValueQueryFields allowlists the fields dimension values can be filtered, sorted and searched on.
+var DimensionValueQueryFields = lib.QueryFields{
+	"code":        {Column: "dimension_values.code", Type: lib.FieldString, Searchable: true},
+	"name":        {Column: "dimension_values.name", Type: lib.FieldString, Searchable: true},
+	"description": {Column: "dimension_values.description", Type: lib.FieldString, Searchable: true},
+	"is_active":   {Column: "dimension_values.is_active", Type: lib.FieldBool},
+	"created_at":  {Column: "dimension_values.created_at", Type: lib.FieldTime},
+	"updated_at":  {Column: "dimension_values.updated_at", Type: lib.FieldTime},
+}
+
+func (r *dimensionValueRepository) List(
+	ctx context.Context,
+	filterQuery lib.FilterQuery,
+	filters ListDimensionValuesFilter,
+) (*[]models.DimensionValue, error) {
+	var dimensionValues []models.DimensionValue
+
+	db := contextDB(ctx, r.DB).
+		Scopes(
+			DateRangeScope("dimension_values", filterQuery.DateRange),
+			ClientFilterScope("dimension_values", filters.ClientId),
+			LedgerFilterScope("dimension_values", filters.LedgerId),
+			DimensionTypeFilterScope(filters.DimensionTypeId),
+			IsActiveFilterScope("dimension_values", filters.IsActive),
+			FieldFiltersScope(filterQuery.Filters),
+			SearchScope(filterQuery.Search),
+
+			PaginationScope(filterQuery.Page, filterQuery.PageSize),
+			SortScope("dimension_values", filterQuery),
+		)
+
+	if filterQuery.Populate != nil {
+		for _, field := range *filterQuery.Populate {
+			db = db.Preload(field)
+		}
+	}
+
+	results := db.Find(&dimensionValues)
+
+	if results.Error != nil {
+		return nil, results.Error
+	}
+
+	return &dimensionValues, nil
+}
+
+func (r *dimensionValueRepository) Count(
+	ctx context.Context,
+	filterQuery lib.FilterQuery,
+	filters ListDimensionValuesFilter,
+) (int64, error) {
+	var count int64
+
+	result := contextDB(ctx, r.DB).
+		Model(&models.DimensionValue{}).
+		Scopes(
+			DateRangeScope("dimension_values", filterQuery.DateRange),
+			ClientFilterScope("dimension_values", filters.ClientId),
+			LedgerFilterScope("dimension_values", filters.LedgerId),
+			DimensionTypeFilterScope(filters.DimensionTypeId),
+			IsActiveFilterScope("dimension_values", filters.IsActive),
+			FieldFiltersScope(filterQuery.Filters),
+			SearchScope(filterQuery.Search),
+		).
+		Count(&count)
+
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return count, nil
+}
+
+func DimensionTypeFilterScope(dimensionTypeId string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if dimensionTypeId == "" {
+			return db
+		}
+
+		return db.Where("dimension_values.dimension_type_id = ?", dimensionTypeId)
+	}
+}
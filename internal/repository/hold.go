@@ -0,0 +1,350 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Bendomey/fincore-engine/internal/lib"
+	"github.com/Bendomey/fincore-engine/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	// ErrHoldNotPending is returned when a hold was already captured, voided or has expired.
+	ErrHoldNotPending = errors.New("hold is no longer pending")
+
+	// ErrHoldCaptureAwaitingApproval is returned when the hold's capture entry is still waiting for approval.
+	ErrHoldCaptureAwaitingApproval = errors.New("hold has a capture awaiting approval")
+)
+
+type HoldRepository interface {
+	Create(context context.Context, hold *models.Hold) error
+	Capture(context context.Context, hold *models.Hold, journalEntry *models.JournalEntry) error
+	Void(context context.Context, hold *models.Hold) error
+	ExpireDue(context context.Context, now time.Time) (int64, error)
+	GetByIDAndLedgerID(
+		context context.Context,
+		id string,
+		ledgerID string,
+		populate *[]string,
+	) (*models.Hold, error)
+	List(context context.Context, filterQuery lib.FilterQuery, filters ListHoldsFilter) (*[]models.Hold, error)
+	Count(context context.Context, filterQuery lib.FilterQuery, filters ListHoldsFilter) (int64, error)
+	SumActiveByLedgerID(context context.Context, ledgerID string) (map[string]int64, error)
+}
+
+type holdRepository struct {
+	DB *gorm.DB
+}
+
+func NewHoldRepository(DB *gorm.DB) HoldRepository {
+	return &holdRepository{DB}
+}
+
+// Create places the hold, refusing it when the account's policy doesn't leave enough available balance.
+func (r *holdRepository) Create(ctx context.Context, hold *models.Hold) error {
+	return contextDB(ctx, r.DB).Transaction(func(tx *gorm.DB) error {
+		accounts, err := lockPolicyAccounts(tx, []string{hold.AccountID})
+		if err != nil {
+			return err
+		}
+
+		if len(accounts) > 0 {
+			available, err := availableBalances(tx, hold.ClientID, accounts)
+			if err != nil {
+				return err
+			}
+
+			// a hold too large to be subtracted could never be covered.
+			remaining, err := lib.Money{Amount: available[hold.AccountID], Currency: hold.Currency}.
+				Sub(lib.Money{Amount: hold.Amount, Currency: hold.Currency})
+			if err != nil {
+				return err
+			}
+
+			if err := checkBalanceFloor(&accounts[0], remaining.Amount); err != nil {
+				return err
+			}
+		}
+
+		return tx.Omit(clause.Associations).Create(hold).Error
+	})
+}
+
+// Capture creates journalEntry, the move the hold was holding for, in one transaction with the hold. A posted entry
+// releases the hold straight away, one waiting for approval leaves it held until the entry is posted.
+func (r *holdRepository) Capture(ctx context.Context, hold *models.Hold, journalEntry *models.JournalEntry) error {
+	return contextDB(ctx, r.DB).Transaction(func(tx *gorm.DB) error {
+		if err := lockWebhookEvents(tx, hold.ClientID); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		updates := map[string]interface{}{
+			"captured_amount": hold.CapturedAmount,
+			"updated_at":      now,
+		}
+
+		// released before posting so the entry isn't checked against its own hold.
+		isPosted := journalEntry.Status == models.JournalEntryStatusPosted
+		if isPosted {
+			updates["status"] = models.HoldStatusCaptured
+			updates["captured_at"] = now
+		}
+
+		result := tx.Model(hold).
+			Scopes(ActiveHoldsScope(now)).
+			Where("holds.journal_entry_id IS NULL").
+			Updates(updates)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return ErrHoldNotPending
+		}
+
+		if err := createJournalEntry(tx, journalEntry); err != nil {
+			return err
+		}
+
+		journalEntryID := journalEntry.ID.String()
+		if err := tx.Model(hold).Update("journal_entry_id", journalEntryID).Error; err != nil {
+			return err
+		}
+
+		if isPosted {
+			hold.Status = models.HoldStatusCaptured
+			hold.CapturedAt = &now
+		}
+		hold.UpdatedAt = now
+		hold.JournalEntryID = &journalEntryID
+		hold.JournalEntry = journalEntry
+
+		return nil
+	})
+}
+
+// captureHolds settles the holds waiting on the capture entry that is being posted, before its balance policies
+// are checked so the entry isn't checked against its own hold.
+func captureHolds(tx *gorm.DB, journalEntry *models.JournalEntry) error {
+	now := time.Now()
+	return tx.Model(&models.Hold{}).
+		Where("journal_entry_id = ? AND status = ?", journalEntry.ID.String(), models.HoldStatusPending).
+		Updates(map[string]interface{}{
+			"status":      models.HoldStatusCaptured,
+			"captured_at": now,
+			"updated_at":  now,
+		}).Error
+}
+
+// detachHolds frees the holds waiting on a capture entry that was rejected or voided, so they can be captured
+// again or voided. They stay held meanwhile.
+func detachHolds(tx *gorm.DB, journalEntry *models.JournalEntry) error {
+	return tx.Model(&models.Hold{}).
+		Where("journal_entry_id = ? AND status = ?", journalEntry.ID.String(), models.HoldStatusPending).
+		Updates(map[string]interface{}{
+			"journal_entry_id": nil,
+			"captured_amount":  0,
+			"updated_at":       time.Now(),
+		}).Error
+}
+
+func (r *holdRepository) Void(ctx context.Context, hold *models.Hold) error {
+	now := time.Now()
+	result := contextDB(ctx, r.DB).
+		Model(hold).
+		Scopes(ActiveHoldsScope(now)).
+		Where("holds.journal_entry_id IS NULL").
+		Updates(map[string]interface{}{
+			"status":     models.HoldStatusVoided,
+			"voided_at":  now,
+			"updated_at": now,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrHoldNotPending
+	}
+
+	hold.Status = models.HoldStatusVoided
+	hold.VoidedAt = &now
+	hold.UpdatedAt = now
+
+	return nil
+}
+
+// ExpireDue marks the pending holds whose expiry has passed as expired. They already stopped counting against
+// the available balance, this only settles their status. Holds with a capture awaiting approval don't expire.
+func (r *holdRepository) ExpireDue(ctx context.Context, now time.Time) (int64, error) {
+	result := contextDB(ctx, r.DB).
+		Model(&models.Hold{}).
+		Where("status = ? AND expires_at <= ? AND journal_entry_id IS NULL", models.HoldStatusPending, now).
+		Updates(map[string]interface{}{
+			"status":     models.HoldStatusExpired,
+			"updated_at": now,
+		})
+
+	return result.RowsAffected, result.Error
+}
+
+func (r *holdRepository) GetByIDAndLedgerID(
+	ctx context.Context,
+	id string,
+	ledgerID string,
+	populate *[]string,
+) (*models.Hold, error) {
+	var hold models.Hold
+	db := contextDB(ctx, r.DB)
+
+	if populate != nil {
+		for _, field := range *populate {
+			db = db.Preload(field)
+		}
+	}
+
+	result := db.Where("id = ? AND ledger_id = ?", id, ledgerID).First(&hold)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return &hold, nil
+}
+
+type ListHoldsFilter struct {
+	ClientId  string
+	LedgerId  string
+	AccountId *string
+	Status    *string
+}
+
+// HoldQueryFields allowlists the fields holds can be filtered, sorted and searched on.
+var HoldQueryFields = lib.QueryFields{
+	"reference":              {Column: "holds.reference", Type: lib.FieldString, Searchable: true},
+	"account_id":             {Column: "holds.account_id", Type: lib.FieldUUID},
+	"destination_account_id": {Column: "holds.destination_account_id", Type: lib.FieldUUID},
+	"amount":                 {Column: "holds.amount", Type: lib.FieldNumber},
+	"status":                 {Column: "holds.status", Type: lib.FieldString},
+	"expires_at":             {Column: "holds.expires_at", Type: lib.FieldTime},
+	"captured_amount":        {Column: "holds.captured_amount", Type: lib.FieldNumber},
+	"captured_at":            {Column: "holds.captured_at", Type: lib.FieldTime},
+	"voided_at":              {Column: "holds.voided_at", Type: lib.FieldTime},
+	"created_at":             {Column: "holds.created_at", Type: lib.FieldTime},
+	"updated_at":             {Column: "holds.updated_at", Type: lib.FieldTime},
+}
+
+func (r *holdRepository) List(
+	ctx context.Context,
+	filterQuery lib.FilterQuery,
+	filters ListHoldsFilter,
+) (*[]models.Hold, error) {
+	var holds []models.Hold
+
+	db := contextDB(ctx, r.DB).
+		Scopes(
+			DateRangeScope("holds", filterQuery.DateRange),
+			ClientFilterScope("holds", filters.ClientId),
+			LedgerFilterScope("holds", filters.LedgerId),
+			HoldFiltersScope(filters),
+			FieldFiltersScope(filterQuery.Filters),
+			SearchScope(filterQuery.Search),
+
+			PaginationScope(filterQuery.Page, filterQuery.PageSize),
+			SortScope("holds", filterQuery),
+		)
+
+	if filterQuery.Populate != nil {
+		for _, field := range *filterQuery.Populate {
+			db = db.Preload(field)
+		}
+	}
+
+	results := db.Find(&holds)
+
+	if results.Error != nil {
+		return nil, results.Error
+	}
+
+	return &holds, nil
+}
+
+func (r *holdRepository) Count(
+	ctx context.Context,
+	filterQuery lib.FilterQuery,
+	filters ListHoldsFilter,
+) (int64, error) {
+	var count int64
+
+	result := contextDB(ctx, r.DB).
+		Model(&models.Hold{}).
+		Scopes(
+			DateRangeScope("holds", filterQuery.DateRange),
+			ClientFilterScope("holds", filters.ClientId),
+			LedgerFilterScope("holds", filters.LedgerId),
+			HoldFiltersScope(filters),
+			FieldFiltersScope(filterQuery.Filters),
+			SearchScope(filterQuery.Search),
+		).
+		Count(&count)
+
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return count, nil
+}
+
+// SumActiveByLedgerID returns the amount held on each of the ledger's accounts by its active holds.
+func (r *holdRepository) SumActiveByLedgerID(ctx context.Context, ledgerID string) (map[string]int64, error) {
+	var rows []accountHeldRow
+
+	result := contextDB(ctx, r.DB).
+		Model(&models.Hold{}).
+		Where("holds.ledger_id = ?", ledgerID).
+		Scopes(ActiveHoldsScope(time.Now())).
+		Select("holds.account_id AS account_id, SUM(holds.amount) AS held").
+		Group("holds.account_id").
+		Scan(&rows)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	held := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		held[row.AccountID] = row.Held
+	}
+
+	return held, nil
+}
+
+func HoldFiltersScope(filters ListHoldsFilter) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if filters.AccountId != nil {
+			db = db.Where("holds.account_id = ?", *filters.AccountId)
+		}
+
+		if filters.Status != nil {
+			db = db.Where("holds.status = ?", *filters.Status)
+		}
+
+		return db
+	}
+}
+
+// ActiveHoldsScope restricts holds to the ones still counting against their account's available balance. A hold
+// whose capture awaits approval keeps counting past its expiry.
+func ActiveHoldsScope(now time.Time) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where(
+			"holds.status = ? AND "+
+				"(holds.expires_at IS NULL OR holds.expires_at > ? OR holds.journal_entry_id IS NOT NULL)",
+			models.HoldStatusPending,
+			now,
+		)
+	}
+}
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/Bendomey/fincore-engine/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IdempotencyKeyRepository interface {
+	Reserve(context context.Context, idempotencyKey *models.IdempotencyKey) (bool, error)
+	GetByClientIDAndKey(context context.Context, clientID string, key string) (*models.IdempotencyKey, error)
+	Update(context context.Context, idempotencyKey *models.IdempotencyKey) error
+	Delete(context context.Context, idempotencyKey *models.IdempotencyKey) error
+}
+
+type idempotencyKeyRepository struct {
+	DB *gorm.DB
+}
+
+func NewIdempotencyKeyRepository(DB *gorm.DB) IdempotencyKeyRepository {
+	return &idempotencyKeyRepository{DB}
+}
+
+// Reserve claims the key for the client, it returns false when the key is already taken by a live request.
+// An expired claim on the same key is dropped first so the key can be reused.
+func (r *idempotencyKeyRepository) Reserve(ctx context.Context, idempotencyKey *models.IdempotencyKey) (bool, error) {
+	reserved := false
+
+	err := contextDB(ctx, r.DB).Transaction(func(tx *gorm.DB) error {
+		err := tx.Where(
+			"client_id = ? AND key = ? AND expires_at <= ?",
+			idempotencyKey.ClientID,
+			idempotencyKey.Key,
+			time.Now(),
+		).Delete(&models.IdempotencyKey{}).Error
+		if err != nil {
+			return err
+		}
+
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(idempotencyKey)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		reserved = result.RowsAffected > 0
+		return nil
+	})
+
+	return reserved, err
+}
+
+func (r *idempotencyKeyRepository) GetByClientIDAndKey(
+	ctx context.Context,
+	clientID string,
+	key string,
+) (*models.IdempotencyKey, error) {
+	var idempotencyKey models.IdempotencyKey
+
+	result := contextDB(ctx, r.DB).Where("client_id = ? AND key = ?", clientID, key).First(&idempotencyKey)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return &idempotencyKey, nil
+}
+
+func (r *idempotencyKeyRepository) Update(ctx context.Context, idempotencyKey *models.IdempotencyKey) error {
+	return contextDB(ctx, r.DB).Save(idempotencyKey).Error
+}
+
+func (r *idempotencyKeyRepository) Delete(ctx context.Context, idempotencyKey *models.IdempotencyKey) error {
+	return contextDB(ctx, r.DB).Delete(idempotencyKey).Error
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"time"
 
+	"github.com/Bendomey/fincore-engine/internal/lib"
 	"github.com/Bendomey/fincore-engine/internal/models"
 	"gorm.io/gorm"
 )
@@ -17,6 +18,17 @@ type JournalEntryLineRepository interface {
 	) (*models.JournalEntryLine, error)
 	GetByID(context context.Context, id string, populate *[]string) (*models.JournalEntryLine, error)
 	Update(ctx context.Context, journalEntryLine *models.JournalEntryLine) error
+	ReplaceDimensionValues(
+		ctx context.Context,
+		journalEntryLine *models.JournalEntryLine,
+		dimensionValues []models.DimensionValue,
+	) error
+	List(
+		context context.Context,
+		filterQuery lib.FilterQuery,
+		filters ListJournalEntryLinesFilter,
+	) (*[]models.JournalEntryLine, error)
+	Count(context context.Context, filterQuery lib.FilterQuery, filters ListJournalEntryLinesFilter) (int64, error)
 }
 
 type journalEntryLineRepository struct {
@@ -25,7 +37,16 @@ type journalEntryLineRepository struct {
 
 func (r *journalEntryLineRepository) Update(ctx context.Context, journalEntryLine *models.JournalEntryLine) error {
 	journalEntryLine.UpdatedAt = time.Now()
-	return r.DB.WithContext(ctx).Save(journalEntryLine).Error
+	return contextDB(ctx, r.DB).Save(journalEntryLine).Error
+}
+
+// ReplaceDimensionValues swaps the dimension tags of a line for the given values.
+func (r *journalEntryLineRepository) ReplaceDimensionValues(
+	ctx context.Context,
+	journalEntryLine *models.JournalEntryLine,
+	dimensionValues []models.DimensionValue,
+) error {
+	return contextDB(ctx, r.DB).Model(journalEntryLine).Association("DimensionValues").Replace(dimensionValues)
 }
 
 func NewJournalEntryLineRepository(DB *gorm.DB) JournalEntryLineRepository {
@@ -39,7 +60,7 @@ func (r *journalEntryLineRepository) GetByIDAndEntryID(
 	populate *[]string,
 ) (*models.JournalEntryLine, error) {
 	var journalEntryLine models.JournalEntryLine
-	db := r.DB.WithContext(context)
+	db := contextDB(context, r.DB)
 
 	if populate != nil {
 		for _, field := range *populate {
@@ -62,7 +83,7 @@ func (r *journalEntryLineRepository) GetByID(
 	populate *[]string,
 ) (*models.JournalEntryLine, error) {
 	var journalEntryLine models.JournalEntryLine
-	db := r.DB.WithContext(ctx)
+	db := contextDB(ctx, r.DB)
 
 	if populate != nil {
 		for _, field := range *populate {
@@ -78,3 +99,146 @@ func (r *journalEntryLineRepository) GetByID(
 
 	return &journalEntryLine, nil
 }
+
+// ListJournalEntryLinesFilter filters lines across the client's entries. DateRange is on the entries' transaction
+// date.
+type ListJournalEntryLinesFilter struct {
+	ClientId  string
+	LedgerId  string
+	AccountId *string
+	Side      *string
+	MinAmount *int64
+	MaxAmount *int64
+	Status    *string
+	Notes     *string
+	DateRange *lib.DateRangeType
+}
+
+// JournalEntryLineQueryFields allowlists the fields journal entry lines can be filtered, sorted and searched on.
+var JournalEntryLineQueryFields = lib.QueryFields{
+	"notes":            {Column: "journal_entry_lines.notes", Type: lib.FieldString, Searchable: true},
+	"account_id":       {Column: "journal_entry_lines.account_id", Type: lib.FieldUUID},
+	"journal_entry_id": {Column: "journal_entry_lines.journal_entry_id", Type: lib.FieldUUID},
+	"debit":            {Column: "journal_entry_lines.debit", Type: lib.FieldNumber},
+	"credit":           {Column: "journal_entry_lines.credit", Type: lib.FieldNumber},
+	"reference":        {Column: "journal_entries.reference", Type: lib.FieldString, Searchable: true},
+	"status":           {Column: "journal_entries.status", Type: lib.FieldString},
+	"transaction_date": {Column: "journal_entries.transaction_date", Type: lib.FieldTime},
+	"created_at":       {Column: "journal_entry_lines.created_at", Type: lib.FieldTime},
+	"updated_at":       {Column: "journal_entry_lines.updated_at", Type: lib.FieldTime},
+}
+
+// List returns the lines with their entry, which every row carries the reference and date of.
+func (r *journalEntryLineRepository) List(
+	ctx context.Context,
+	filterQuery lib.FilterQuery,
+	filters ListJournalEntryLinesFilter,
+) (*[]models.JournalEntryLine, error) {
+	var journalEntryLines []models.JournalEntryLine
+
+	db := contextDB(ctx, r.DB).
+		Preload("JournalEntry").
+		Scopes(
+			JournalEntryLineFiltersScope(filters),
+			FieldFiltersScope(filterQuery.Filters),
+			SearchScope(filterQuery.Search),
+
+			PageScope("journal_entry_lines", filterQuery),
+		)
+
+	if filterQuery.Populate != nil {
+		for _, field := range *filterQuery.Populate {
+			db = db.Preload(field)
+		}
+	}
+
+	results := db.Find(&journalEntryLines)
+
+	if results.Error != nil {
+		return nil, results.Error
+	}
+
+	return &journalEntryLines, nil
+}
+
+func (r *journalEntryLineRepository) Count(
+	ctx context.Context,
+	filterQuery lib.FilterQuery,
+	filters ListJournalEntryLinesFilter,
+) (int64, error) {
+	var count int64
+
+	result := contextDB(ctx, r.DB).
+		Model(&models.JournalEntryLine{}).
+		Scopes(
+			JournalEntryLineFiltersScope(filters),
+			FieldFiltersScope(filterQuery.Filters),
+			SearchScope(filterQuery.Search),
+		).
+		Count(&count)
+
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return count, nil
+}
+
+// JournalEntryLineFiltersScope joins the lines to their entries, which hold the client and ledger they belong to, and
+// applies the filters of the line listing.
+func JournalEntryLineFiltersScope(filters ListJournalEntryLinesFilter) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		db = db.
+			Joins("JOIN journal_entries ON journal_entries.id = journal_entry_lines.journal_entry_id::uuid").
+			Where("journal_entries.client_id = ? AND journal_entries.deleted_at IS NULL", filters.ClientId).
+			Scopes(LedgerFilterScope("journal_entries", filters.LedgerId))
+
+		if filters.AccountId != nil {
+			// a group account stands for every account below it.
+			db = db.Where(
+				"journal_entry_lines.account_id IN ("+
+					"WITH RECURSIVE tree AS ("+
+					"SELECT id FROM accounts WHERE id = ?::uuid AND client_id = ? "+
+					"UNION ALL SELECT accounts.id FROM accounts "+
+					"JOIN tree ON accounts.parent_account_id = tree.id::text"+
+					") SELECT id::text FROM tree)",
+				*filters.AccountId,
+				filters.ClientId,
+			)
+		}
+
+		if filters.Side != nil {
+			if *filters.Side == "DEBIT" {
+				db = db.Where("journal_entry_lines.debit > 0")
+			} else {
+				db = db.Where("journal_entry_lines.credit > 0")
+			}
+		}
+
+		if filters.MinAmount != nil {
+			db = db.Where("journal_entry_lines.debit + journal_entry_lines.credit >= ?", *filters.MinAmount)
+		}
+
+		if filters.MaxAmount != nil {
+			db = db.Where("journal_entry_lines.debit + journal_entry_lines.credit <= ?", *filters.MaxAmount)
+		}
+
+		if filters.Status != nil {
+			db = db.Where("journal_entries.status = ?", *filters.Status)
+		}
+
+		if filters.Notes != nil {
+			db = db.Where("journal_entry_lines.notes ILIKE ?", "%"+lib.EscapeLike(*filters.Notes)+"%")
+		}
+
+		if filters.DateRange != nil {
+			db = db.Where(
+				"journal_entries.transaction_date BETWEEN ? AND ?",
+				filters.DateRange.StartTime,
+				filters.DateRange.EndTime,
+			)
+		}
+
+		return db
+	}
+}
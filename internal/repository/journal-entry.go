@@ -11,13 +11,24 @@ import (
 
 type JournalEntryRepository interface {
 	Create(context context.Context, journalEntry *models.JournalEntry) error
-	Update(context context.Context, journalEntry *models.JournalEntry) error
+	Update(context context.Context, journalEntry *models.JournalEntry, lines []JournalEntryLineUpdate) error
 	Delete(context context.Context, journalEntry *models.JournalEntry) error
 	FindAndDelete(context context.Context, id string) error
-	GetByIDAndClientID(
+	Transition(
+		context context.Context,
+		journalEntry *models.JournalEntry,
+		transition *models.JournalEntryTransition,
+	) error
+	Reverse(
+		context context.Context,
+		journalEntry *models.JournalEntry,
+		transition *models.JournalEntryTransition,
+		reversal *models.JournalEntry,
+	) error
+	GetByIDAndLedgerID(
 		context context.Context,
 		id string,
-		clientID string,
+		ledgerID string,
 		populate *[]string,
 	) (*models.JournalEntry, error)
 	GetByID(context context.Context, id string, populate *[]string) (*models.JournalEntry, error)
@@ -27,6 +38,12 @@ type JournalEntryRepository interface {
 		filters ListJournalEntriesFilter,
 	) (*[]models.JournalEntry, error)
 	Count(context context.Context, filterQuery lib.FilterQuery, filters ListJournalEntriesFilter) (int64, error)
+	ListChainedByLedgerID(
+		context context.Context,
+		ledgerID string,
+		afterSequence int64,
+		limit int,
+	) (*[]models.JournalEntry, error)
 }
 
 type journalEntryRepository struct {
@@ -38,35 +55,227 @@ func NewJournalEntryRepository(DB *gorm.DB) JournalEntryRepository {
 }
 
 func (r *journalEntryRepository) Create(ctx context.Context, journalEntry *models.JournalEntry) error {
-	return r.DB.WithContext(ctx).Create(journalEntry).Error
+	return contextDB(ctx, r.DB).Transaction(func(tx *gorm.DB) error {
+		return createJournalEntry(tx, journalEntry)
+	})
 }
 
-func (r *journalEntryRepository) Update(ctx context.Context, journalEntry *models.JournalEntry) error {
-	journalEntry.UpdatedAt = time.Now()
-	return r.DB.WithContext(ctx).Save(journalEntry).Error
+// createJournalEntry creates the entry, posting it right away when it is created posted.
+func createJournalEntry(tx *gorm.DB, journalEntry *models.JournalEntry) error {
+	if err := lockWebhookEvents(tx, journalEntry.ClientID); err != nil {
+		return err
+	}
+
+	// entries are in their ledger's currency, lines in their entry's.
+	if journalEntry.Currency == "" {
+		err := tx.Model(&models.Ledger{}).
+			Where("id = ?", journalEntry.LedgerID).
+			Pluck("currency", &journalEntry.Currency).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	for i := range journalEntry.JournalEntryLines {
+		journalEntry.JournalEntryLines[i].Currency = journalEntry.Currency
+	}
+
+	if err := tx.Create(journalEntry).Error; err != nil {
+		return err
+	}
+
+	if err := writeJournalEntryWebhookEvent(tx, journalEntry, models.WebhookEventJournalEntryCreated); err != nil {
+		return err
+	}
+
+	if journalEntry.Status != models.JournalEntryStatusPosted {
+		return nil
+	}
+
+	return postJournalEntry(tx, journalEntry)
+}
+
+// postJournalEntry does the bookkeeping of an entry that just became posted.
+func postJournalEntry(tx *gorm.DB, journalEntry *models.JournalEntry) error {
+	if err := captureHolds(tx, journalEntry); err != nil {
+		return err
+	}
+
+	if err := enforceBalancePolicies(tx, journalEntry); err != nil {
+		return err
+	}
+
+	if err := chainJournalEntry(tx, journalEntry); err != nil {
+		return err
+	}
+
+	return writeJournalEntryWebhookEvent(tx, journalEntry, models.WebhookEventJournalEntryPosted)
+}
+
+// chainJournalEntry appends a freshly posted entry to its ledger's hash chain. Entries are chained one ledger at
+// a time (advisory lock) so concurrent posts can't fork the chain.
+func chainJournalEntry(tx *gorm.DB, journalEntry *models.JournalEntry) error {
+	err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", "journal_entry_chain:"+journalEntry.LedgerID).Error
+	if err != nil {
+		return err
+	}
+
+	// hash what was stored rather than what is in memory.
+	var stored models.JournalEntry
+	err = tx.Preload("JournalEntryLines").Where("id = ?", journalEntry.ID).First(&stored).Error
+	if err != nil {
+		return err
+	}
+
+	var previous models.JournalEntry
+	result := tx.Unscoped().
+		Where("ledger_id = ? AND ledger_sequence IS NOT NULL", journalEntry.LedgerID).
+		Order("ledger_sequence DESC").
+		Limit(1).
+		Find(&previous)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	sequence := int64(1)
+	previousHash := ""
+	if result.RowsAffected > 0 {
+		sequence = *previous.LedgerSequence + 1
+		previousHash = *previous.Hash
+	}
+
+	hashVersion := models.ChainHashVersion
+	stored.HashVersion = &hashVersion
+	hash := stored.ComputeChainHash(stored.JournalEntryLines, sequence, previousHash)
+
+	journalEntry.LedgerSequence = &sequence
+	journalEntry.PreviousHash = &previousHash
+	journalEntry.Hash = &hash
+	journalEntry.HashVersion = &hashVersion
+
+	return tx.Model(&models.JournalEntry{}).
+		Where("id = ?", journalEntry.ID).
+		Updates(map[string]interface{}{
+			"ledger_sequence": sequence,
+			"previous_hash":   previousHash,
+			"hash":            hash,
+			"hash_version":    hashVersion,
+		}).Error
+}
+
+// JournalEntryLineUpdate is a line saved along with its entry. Dimensions replaces the line's tags when set.
+type JournalEntryLineUpdate struct {
+	Line       *models.JournalEntryLine
+	Dimensions *[]models.DimensionValue
+}
+
+// Update saves the entry and its changed lines together, provided the entry has not changed since it was read.
+// The entry goes first so a concurrent editor is turned away before any of its lines are touched.
+func (r *journalEntryRepository) Update(
+	ctx context.Context,
+	journalEntry *models.JournalEntry,
+	lines []JournalEntryLineUpdate,
+) error {
+	return contextDB(ctx, r.DB).Transaction(func(tx *gorm.DB) error {
+		journalEntry.UpdatedAt = time.Now()
+		if err := saveVersioned(tx, journalEntry, &journalEntry.Version); err != nil {
+			return err
+		}
+
+		for _, line := range lines {
+			line.Line.UpdatedAt = time.Now()
+			if err := tx.Save(line.Line).Error; err != nil {
+				return err
+			}
+
+			if line.Dimensions == nil {
+				continue
+			}
+
+			err := tx.Model(line.Line).Association("DimensionValues").Replace(*line.Dimensions)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
 }
 
 func (r *journalEntryRepository) Delete(ctx context.Context, journalEntry *models.JournalEntry) error {
-	return r.DB.WithContext(ctx).Delete(journalEntry).Error
+	return contextDB(ctx, r.DB).Delete(journalEntry).Error
 }
 
 func (r *journalEntryRepository) FindAndDelete(ctx context.Context, id string) error {
 	var journalEntry models.JournalEntry
-	if err := r.DB.WithContext(ctx).Where("id = ?", id).First(&journalEntry).Error; err != nil {
+	if err := contextDB(ctx, r.DB).Where("id = ?", id).First(&journalEntry).Error; err != nil {
+		return err
+	}
+
+	return contextDB(ctx, r.DB).Delete(&journalEntry).Error
+}
+
+// Transition saves the entry's new status together with the record of the transition.
+func (r *journalEntryRepository) Transition(
+	ctx context.Context,
+	journalEntry *models.JournalEntry,
+	transition *models.JournalEntryTransition,
+) error {
+	return contextDB(ctx, r.DB).Transaction(func(tx *gorm.DB) error {
+		return saveTransition(tx, journalEntry, transition)
+	})
+}
+
+// Reverse transitions the entry and creates the entry reversing it in a single transaction.
+func (r *journalEntryRepository) Reverse(
+	ctx context.Context,
+	journalEntry *models.JournalEntry,
+	transition *models.JournalEntryTransition,
+	reversal *models.JournalEntry,
+) error {
+	return contextDB(ctx, r.DB).Transaction(func(tx *gorm.DB) error {
+		if err := createJournalEntry(tx, reversal); err != nil {
+			return err
+		}
+
+		return saveTransition(tx, journalEntry, transition)
+	})
+}
+
+func saveTransition(tx *gorm.DB, journalEntry *models.JournalEntry, transition *models.JournalEntryTransition) error {
+	if err := lockWebhookEvents(tx, journalEntry.ClientID); err != nil {
 		return err
 	}
 
-	return r.DB.WithContext(ctx).Delete(&journalEntry).Error
+	journalEntry.UpdatedAt = time.Now()
+	if err := saveVersioned(tx, journalEntry, &journalEntry.Version); err != nil {
+		return err
+	}
+
+	if err := tx.Create(transition).Error; err != nil {
+		return err
+	}
+
+	switch transition.ToStatus {
+	case models.JournalEntryStatusPosted:
+		return postJournalEntry(tx, journalEntry)
+	case models.JournalEntryStatusDraft, models.JournalEntryStatusVoided:
+		return detachHolds(tx, journalEntry)
+	case models.JournalEntryStatusReversed:
+		return writeJournalEntryWebhookEvent(tx, journalEntry, models.WebhookEventJournalEntryReversed)
+	}
+
+	return nil
 }
 
-func (r *journalEntryRepository) GetByIDAndClientID(
+func (r *journalEntryRepository) GetByIDAndLedgerID(
 	context context.Context,
 	id string,
-	clientID string,
+	ledgerID string,
 	populate *[]string,
 ) (*models.JournalEntry, error) {
 	var journalEntry models.JournalEntry
-	db := r.DB.WithContext(context)
+	db := contextDB(context, r.DB)
 
 	if populate != nil {
 		for _, field := range *populate {
@@ -74,7 +283,7 @@ func (r *journalEntryRepository) GetByIDAndClientID(
 		}
 	}
 
-	result := db.Where("id = ? AND client_id = ?", id, clientID).First(&journalEntry)
+	result := db.Where("id = ? AND ledger_id = ?", id, ledgerID).First(&journalEntry)
 
 	if result.Error != nil {
 		return nil, result.Error
@@ -89,7 +298,7 @@ func (r *journalEntryRepository) GetByID(
 	populate *[]string,
 ) (*models.JournalEntry, error) {
 	var journalEntry models.JournalEntry
-	db := r.DB.WithContext(ctx)
+	db := contextDB(ctx, r.DB)
 
 	if populate != nil {
 		for _, field := range *populate {
@@ -106,9 +315,53 @@ func (r *journalEntryRepository) GetByID(
 	return &journalEntry, nil
 }
 
+// ListChainedByLedgerID returns the next page of the ledger's chained entries, with their lines, in chain order.
+// Soft deleted entries are included, removing a posted entry is tampering too.
+func (r *journalEntryRepository) ListChainedByLedgerID(
+	ctx context.Context,
+	ledgerID string,
+	afterSequence int64,
+	limit int,
+) (*[]models.JournalEntry, error) {
+	var journalEntries []models.JournalEntry
+
+	results := contextDB(ctx, r.DB).
+		Unscoped().
+		Preload("JournalEntryLines", func(db *gorm.DB) *gorm.DB {
+			// only the lines the entry was hashed with, a removed line is a break.
+			return db.Where("deleted_at IS NULL")
+		}).
+		Where("ledger_id = ? AND ledger_sequence > ?", ledgerID, afterSequence).
+		Order("ledger_sequence ASC").
+		Limit(limit).
+		Find(&journalEntries)
+
+	if results.Error != nil {
+		return nil, results.Error
+	}
+
+	return &journalEntries, nil
+}
+
 type ListJournalEntriesFilter struct {
 	ClientId string
+	LedgerId string
 	Status   *string
+	Metadata []MetadataFilter
+}
+
+// JournalEntryQueryFields allowlists the fields journal entries can be filtered, sorted and searched on.
+var JournalEntryQueryFields = lib.QueryFields{
+	"reference":        {Column: "journal_entries.reference", Type: lib.FieldString, Searchable: true},
+	"status":           {Column: "journal_entries.status", Type: lib.FieldString},
+	"transaction_date": {Column: "journal_entries.transaction_date", Type: lib.FieldTime},
+	"posted_at":        {Column: "journal_entries.posted_at", Type: lib.FieldTime},
+	"created_by":       {Column: "journal_entries.created_by", Type: lib.FieldString},
+	"approved_by":      {Column: "journal_entries.approved_by", Type: lib.FieldString},
+	"reversal_of_id":   {Column: "journal_entries.reversal_of_id", Type: lib.FieldUUID},
+	"ledger_sequence":  {Column: "journal_entries.ledger_sequence", Type: lib.FieldNumber},
+	"created_at":       {Column: "journal_entries.created_at", Type: lib.FieldTime},
+	"updated_at":       {Column: "journal_entries.updated_at", Type: lib.FieldTime},
 }
 
 func (r *journalEntryRepository) List(
@@ -118,15 +371,17 @@ func (r *journalEntryRepository) List(
 ) (*[]models.JournalEntry, error) {
 	var journalEntries []models.JournalEntry
 
-	db := r.DB.WithContext(ctx).
+	db := contextDB(ctx, r.DB).
 		Scopes(
 			DateRangeScope("journal_entries", filterQuery.DateRange),
 			ClientFilterScope("journal_entries", filters.ClientId),
+			LedgerFilterScope("journal_entries", filters.LedgerId),
 			StatusFilterScope(filters.Status),
-			SearchScope("journal_entries", filterQuery.Search),
+			MetadataFilterScope("journal_entries", filters.Metadata),
+			FieldFiltersScope(filterQuery.Filters),
+			SearchScope(filterQuery.Search),
 
-			PaginationScope(filterQuery.Page, filterQuery.PageSize),
-			OrderScope("journal_entries", filterQuery.OrderBy, filterQuery.Order),
+			PageScope("journal_entries", filterQuery),
 		)
 
 	if filterQuery.Populate != nil {
@@ -151,14 +406,16 @@ func (r *journalEntryRepository) Count(
 ) (int64, error) {
 	var count int64
 
-	result := r.DB.
-		WithContext(ctx).
+	result := contextDB(ctx, r.DB).
 		Model(&models.JournalEntry{}).
 		Scopes(
 			DateRangeScope("journal_entries", filterQuery.DateRange),
 			ClientFilterScope("journal_entries", filters.ClientId),
+			LedgerFilterScope("journal_entries", filters.LedgerId),
 			StatusFilterScope(filters.Status),
-			SearchScope("journal_entries", filterQuery.Search),
+			MetadataFilterScope("journal_entries", filters.Metadata),
+			FieldFiltersScope(filterQuery.Filters),
+			SearchScope(filterQuery.Search),
 		).
 		Count(&count)
 
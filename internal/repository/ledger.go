@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/Bendomey/fincore-engine/internal/lib"
+	"github.com/Bendomey/fincore-engine/internal/models"
+	"gorm.io/gorm"
+)
+
+type LedgerRepository interface {
+	Create(context context.Context, ledger *models.Ledger) error
+	Update(context context.Context, ledger *models.Ledger) error
+	Delete(context context.Context, ledger *models.Ledger) error
+	GetByIDAndClientID(context context.Context, id string, clientID string) (*models.Ledger, error)
+	GetDefaultByClientID(context context.Context, clientID string) (*models.Ledger, error)
+	List(context context.Context, filterQuery lib.FilterQuery, filters ListLedgersFilter) (*[]models.Ledger, error)
+	Count(context context.Context, filterQuery lib.FilterQuery, filters ListLedgersFilter) (int64, error)
+}
+
+type ledgerRepository struct {
+	DB *gorm.DB
+}
+
+func NewLedgerRepository(DB *gorm.DB) LedgerRepository {
+	return &ledgerRepository{DB}
+}
+
+// Create saves the ledger, which takes the default over from the client's other ledgers when it is the default.
+func (r *ledgerRepository) Create(ctx context.Context, ledger *models.Ledger) error {
+	return contextDB(ctx, r.DB).Transaction(func(tx *gorm.DB) error {
+		if err := unsetDefaultLedger(tx, ledger); err != nil {
+			return err
+		}
+
+		return tx.Create(ledger).Error
+	})
+}
+
+// Update saves the ledger, which takes the default over from the client's other ledgers when it is the default.
+func (r *ledgerRepository) Update(ctx context.Context, ledger *models.Ledger) error {
+	return contextDB(ctx, r.DB).Transaction(func(tx *gorm.DB) error {
+		if err := unsetDefaultLedger(tx, ledger); err != nil {
+			return err
+		}
+
+		ledger.UpdatedAt = time.Now()
+		return tx.Save(ledger).Error
+	})
+}
+
+func unsetDefaultLedger(tx *gorm.DB, ledger *models.Ledger) error {
+	if !ledger.IsDefault {
+		return nil
+	}
+
+	db := tx.Model(&models.Ledger{}).Where("client_id = ? AND is_default", ledger.ClientID)
+	if !ledger.ID.IsNil() {
+		db = db.Where("id <> ?", ledger.ID)
+	}
+
+	return db.Update("is_default", false).Error
+}
+
+func (r *ledgerRepository) Delete(ctx context.Context, ledger *models.Ledger) error {
+	return contextDB(ctx, r.DB).Delete(ledger).Error
+}
+
+func (r *ledgerRepository) GetByIDAndClientID(
+	ctx context.Context,
+	id string,
+	clientID string,
+) (*models.Ledger, error) {
+	var ledger models.Ledger
+
+	result := contextDB(ctx, r.DB).Where("id = ? AND client_id = ?", id, clientID).First(&ledger)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return &ledger, nil
+}
+
+func (r *ledgerRepository) GetDefaultByClientID(ctx context.Context, clientID string) (*models.Ledger, error) {
+	var ledger models.Ledger
+
+	result := contextDB(ctx, r.DB).Where("client_id = ? AND is_default", clientID).First(&ledger)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return &ledger, nil
+}
+
+type ListLedgersFilter struct {
+	ClientId string
+}
+
+// LedgerQueryFields allowlists the fields ledgers can be filtered, sorted and searched on.
+var LedgerQueryFields = lib.QueryFields{
+	"name":                    {Column: "ledgers.name", Type: lib.FieldString, Searchable: true},
+	"description":             {Column: "ledgers.description", Type: lib.FieldString, Searchable: true},
+	"currency":                {Column: "ledgers.currency", Type: lib.FieldString},
+	"fiscal_year_start_month": {Column: "ledgers.fiscal_year_start_month", Type: lib.FieldNumber},
+	"is_default":              {Column: "ledgers.is_default", Type: lib.FieldBool},
+	"created_at":              {Column: "ledgers.created_at", Type: lib.FieldTime},
+	"updated_at":              {Column: "ledgers.updated_at", Type: lib.FieldTime},
+}
+
+func (r *ledgerRepository) List(
+	ctx context.Context,
+	filterQuery lib.FilterQuery,
+	filters ListLedgersFilter,
+) (*[]models.Ledger, error) {
+	var ledgers []models.Ledger
+
+	results := contextDB(ctx, r.DB).
+		Scopes(
+			DateRangeScope("ledgers", filterQuery.DateRange),
+			ClientFilterScope("ledgers", filters.ClientId),
+			FieldFiltersScope(filterQuery.Filters),
+			SearchScope(filterQuery.Search),
+
+			PaginationScope(filterQuery.Page, filterQuery.PageSize),
+			SortScope("ledgers", filterQuery),
+		).
+		Find(&ledgers)
+
+	if results.Error != nil {
+		return nil, results.Error
+	}
+
+	return &ledgers, nil
+}
+
+func (r *ledgerRepository) Count(
+	ctx context.Context,
+	filterQuery lib.FilterQuery,
+	filters ListLedgersFilter,
+) (int64, error) {
+	var count int64
+
+	result := contextDB(ctx, r.DB).
+		Model(&models.Ledger{}).
+		Scopes(
+			DateRangeScope("ledgers", filterQuery.DateRange),
+			ClientFilterScope("ledgers", filters.ClientId),
+			FieldFiltersScope(filterQuery.Filters),
+			SearchScope(filterQuery.Search),
+		).
+		Count(&count)
+
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return count, nil
+}
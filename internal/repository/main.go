@@ -7,6 +7,25 @@ type Repository struct {
 	AccountRepository          AccountRepository
 	JournalEntryRepository     JournalEntryRepository
 	JournalEntryLineRepository JournalEntryLineRepository
+	DimensionTypeRepository    DimensionTypeRepository
+	DimensionValueRepository   DimensionValueRepository
+	ReportRepository           ReportRepository
+	BudgetRepository           BudgetRepository
+	BudgetLineRepository       BudgetLineRepository
+	ApprovalRuleRepository     ApprovalRuleRepository
+	AttachmentRepository       AttachmentRepository
+	AuditEventRepository       AuditEventRepository
+	IdempotencyKeyRepository   IdempotencyKeyRepository
+	WebhookEndpointRepository  WebhookEndpointRepository
+	WebhookEventRepository     WebhookEventRepository
+	WebhookDeliveryRepository  WebhookDeliveryRepository
+	TransferRepository         TransferRepository
+	HoldRepository             HoldRepository
+	SearchRepository           SearchRepository
+	ApiKeyRepository           ApiKeyRepository
+	RequestNonceRepository     RequestNonceRepository
+	LedgerRepository           LedgerRepository
+	Transactor                 Transactor
 }
 
 func NewRepository(db *gorm.DB) Repository {
@@ -14,11 +33,50 @@ func NewRepository(db *gorm.DB) Repository {
 	accountRepository := NewAccountRepository(db)
 	journalEntryRepository := NewJournalEntryRepository(db)
 	journalEntryLineRepository := NewJournalEntryLineRepository(db)
+	dimensionTypeRepository := NewDimensionTypeRepository(db)
+	dimensionValueRepository := NewDimensionValueRepository(db)
+	reportRepository := NewReportRepository(db)
+	budgetRepository := NewBudgetRepository(db)
+	budgetLineRepository := NewBudgetLineRepository(db)
+	approvalRuleRepository := NewApprovalRuleRepository(db)
+	attachmentRepository := NewAttachmentRepository(db)
+	auditEventRepository := NewAuditEventRepository(db)
+	idempotencyKeyRepository := NewIdempotencyKeyRepository(db)
+	webhookEndpointRepository := NewWebhookEndpointRepository(db)
+	webhookEventRepository := NewWebhookEventRepository(db)
+	webhookDeliveryRepository := NewWebhookDeliveryRepository(db)
+	transferRepository := NewTransferRepository(db)
+	holdRepository := NewHoldRepository(db)
+
+	searchRepository := NewSearchRepository(db)
+	apiKeyRepository := NewApiKeyRepository(db)
+	requestNonceRepository := NewRequestNonceRepository(db)
+	ledgerRepository := NewLedgerRepository(db)
+	transactor := NewTransactor(db)
 
 	return Repository{
 		ClientRepository:           clientRepository,
 		AccountRepository:          accountRepository,
 		JournalEntryRepository:     journalEntryRepository,
 		JournalEntryLineRepository: journalEntryLineRepository,
+		DimensionTypeRepository:    dimensionTypeRepository,
+		DimensionValueRepository:   dimensionValueRepository,
+		ReportRepository:           reportRepository,
+		BudgetRepository:           budgetRepository,
+		BudgetLineRepository:       budgetLineRepository,
+		ApprovalRuleRepository:     approvalRuleRepository,
+		AttachmentRepository:       attachmentRepository,
+		AuditEventRepository:       auditEventRepository,
+		IdempotencyKeyRepository:   idempotencyKeyRepository,
+		WebhookEndpointRepository:  webhookEndpointRepository,
+		WebhookEventRepository:     webhookEventRepository,
+		WebhookDeliveryRepository:  webhookDeliveryRepository,
+		TransferRepository:         transferRepository,
+		HoldRepository:             holdRepository,
+		SearchRepository:           searchRepository,
+		ApiKeyRepository:           apiKeyRepository,
+		RequestNonceRepository:     requestNonceRepository,
+		LedgerRepository:           ledgerRepository,
+		Transactor:                 transactor,
 	}
 }
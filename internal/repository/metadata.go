@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// MetadataFilter is a condition on a resource's metadata at Path, a key per nesting level. It either matches the
+// value at the path exactly or, with Exists set, whether the path is there at all.
+type MetadataFilter struct {
+	Path   []string
+	Value  string
+	Exists *bool
+}
+
+// MetadataFilterScope applies the metadata filters of a list, all of which must hold. Values are matched by
+// containment, which the GIN index on the column serves. Paths are checked with jsonb_path_exists rather than the
+// @? operator, gorm reading the ? in it as a placeholder.
+func MetadataFilterScope(tableName string, filters []MetadataFilter) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		column := fmt.Sprintf("%s.metadata", tableName)
+
+		for _, filter := range filters {
+			if filter.Exists != nil {
+				condition := fmt.Sprintf("jsonb_path_exists(%s, ?::jsonpath)", column)
+				if !*filter.Exists {
+					condition = fmt.Sprintf("(%s IS NULL OR NOT jsonb_path_exists(%s, ?::jsonpath))", column, column)
+				}
+
+				db = db.Where(condition, metadataJSONPath(filter.Path))
+				continue
+			}
+
+			conditions := []string{}
+			values := []interface{}{}
+			for _, value := range metadataValues(filter.Value) {
+				document, _ := json.Marshal(metadataDocument(filter.Path, value))
+				conditions = append(conditions, fmt.Sprintf("%s @> ?::jsonb", column))
+				values = append(values, string(document))
+			}
+
+			db = db.Where(fmt.Sprintf("(%s)", strings.Join(conditions, " OR ")), values...)
+		}
+
+		return db
+	}
+}
+
+// metadataValues returns what a value sent in a query string may have been stored as. Correlation ids in
+// particular are as often numbers as strings.
+func metadataValues(value string) []interface{} {
+	values := []interface{}{value}
+
+	var number json.Number
+	if err := json.Unmarshal([]byte(value), &number); err == nil && value == number.String() {
+		values = append(values, number)
+	}
+
+	switch value {
+	case "true", "false":
+		values = append(values, value == "true")
+	case "null":
+		values = append(values, nil)
+	}
+
+	return values
+}
+
+// metadataDocument nests value under path, e.g. {"customer": {"id": value}}, for a containment match.
+func metadataDocument(path []string, value interface{}) interface{} {
+	document := value
+	for i := len(path) - 1; i >= 0; i-- {
+		document = map[string]interface{}{path[i]: document}
+	}
+
+	return document
+}
+
+// metadataJSONPath writes path as a JSON path, quoting every key so none is read as path syntax.
+func metadataJSONPath(path []string) string {
+	var builder strings.Builder
+	builder.WriteString("$")
+
+	for _, key := range path {
+		quoted, _ := json.Marshal(key)
+		builder.WriteString(".")
+		builder.Write(quoted)
+	}
+
+	return builder.String()
+}
@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Bendomey/fincore-engine/internal/models"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// dryRunDB renders statements for postgres without connecting to one.
+func dryRunDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	db, err := gorm.Open(
+		postgres.New(postgres.Config{DSN: "host=localhost dbname=fincore"}),
+		&gorm.Config{DryRun: true, DisableAutomaticPing: true},
+	)
+	if err != nil {
+		t.Fatalf("gorm.Open() error = %v", err)
+	}
+
+	return db
+}
+
+func TestMetadataFilterScope(t *testing.T) {
+	exists, missing := true, false
+
+	tests := []struct {
+		name    string
+		filters []MetadataFilter
+		where   []string
+		vars    []interface{}
+	}{
+		{
+			"value",
+			[]MetadataFilter{{Path: []string{"order_id"}, Value: "abc"}},
+			[]string{`(journal_entries.metadata @> $1::jsonb)`},
+			[]interface{}{`{"order_id":"abc"}`},
+		},
+		{
+			"value that may be a number",
+			[]MetadataFilter{{Path: []string{"customer", "id"}, Value: "42"}},
+			[]string{`(journal_entries.metadata @> $1::jsonb OR journal_entries.metadata @> $2::jsonb)`},
+			[]interface{}{`{"customer":{"id":"42"}}`, `{"customer":{"id":42}}`},
+		},
+		{
+			"value that may be a bool",
+			[]MetadataFilter{{Path: []string{"settled"}, Value: "true"}},
+			[]string{`(journal_entries.metadata @> $1::jsonb OR journal_entries.metadata @> $2::jsonb)`},
+			[]interface{}{`{"settled":"true"}`, `{"settled":true}`},
+		},
+		{
+			"exists",
+			[]MetadataFilter{{Path: []string{"customer", "id"}, Exists: &exists}},
+			[]string{`jsonb_path_exists(journal_entries.metadata, $1::jsonpath)`},
+			[]interface{}{`$."customer"."id"`},
+		},
+		{
+			"missing",
+			[]MetadataFilter{{Path: []string{"refund"}, Exists: &missing}},
+			[]string{
+				`(journal_entries.metadata IS NULL OR NOT jsonb_path_exists(journal_entries.metadata, $1::jsonpath))`,
+			},
+			[]interface{}{`$."refund"`},
+		},
+		{
+			"path syntax in a key",
+			[]MetadataFilter{{Path: []string{`a.b[*] ? (@ == "x")`}, Exists: &exists}},
+			[]string{`jsonb_path_exists(journal_entries.metadata, $1::jsonpath)`},
+			[]interface{}{`$."a.b[*] ? (@ == \"x\")"`},
+		},
+		{
+			"all must hold",
+			[]MetadataFilter{
+				{Path: []string{"order_id"}, Value: "abc"},
+				{Path: []string{"refund"}, Exists: &missing},
+			},
+			[]string{
+				`journal_entries.metadata @> $1::jsonb`,
+				`(journal_entries.metadata IS NULL OR NOT jsonb_path_exists(journal_entries.metadata, $2::jsonpath))`,
+			},
+			[]interface{}{`{"order_id":"abc"}`, `$."refund"`},
+		},
+	}
+
+	db := dryRunDB(t)
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var journalEntries []models.JournalEntry
+			statement := db.Scopes(MetadataFilterScope("journal_entries", test.filters)).
+				Find(&journalEntries).
+				Statement
+
+			sql := statement.SQL.String()
+			for _, condition := range test.where {
+				if !strings.Contains(sql, condition) {
+					t.Errorf("statement = %s, want it to hold %s", sql, condition)
+				}
+			}
+
+			// every value is bound, a ? left in the statement is one gorm took for a placeholder.
+			if strings.Contains(sql, "?") {
+				t.Errorf("statement = %s, has a ? left unbound", sql)
+			}
+
+			if !reflect.DeepEqual(statement.Vars, test.vars) {
+				got, _ := json.Marshal(statement.Vars)
+				t.Errorf("vars = %s, want %v", got, test.vars)
+			}
+		})
+	}
+}
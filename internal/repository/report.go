@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Bendomey/fincore-engine/internal/lib"
+	"github.com/Bendomey/fincore-engine/internal/models"
+	"gorm.io/gorm"
+)
+
+type ReportRepository interface {
+	AggregateLines(context context.Context, filters LineAggregationFilter) (*[]LineAggregate, error)
+}
+
+type reportRepository struct {
+	DB *gorm.DB
+}
+
+func NewReportRepository(DB *gorm.DB) ReportRepository {
+	return &reportRepository{DB}
+}
+
+type LineAggregationFilter struct {
+	ClientId string
+	LedgerId string
+
+	// DateRange filters on the journal entry's transaction date.
+	DateRange *lib.DateRangeType
+
+	// DimensionValueIds narrows the lines to the ones tagged with the given values.
+	// Values of the same dimension are OR'ed, different dimensions are AND'ed.
+	DimensionValueIds map[string][]string
+
+	// GroupByDimensionTypeId subtotals each account by the values of this dimension.
+	GroupByDimensionTypeId *string
+}
+
+// LineAggregate holds the debit and credit totals of posted lines for an account,
+// and optionally for one value of the grouped dimension (nil when the line is untagged).
+type LineAggregate struct {
+	AccountID        string
+	DimensionValueID *string
+	Debit            int64
+	Credit           int64
+}
+
+// AggregateLines sums the posted journal entry lines per account.
+func (r *reportRepository) AggregateLines(
+	ctx context.Context,
+	filters LineAggregationFilter,
+) (*[]LineAggregate, error) {
+	var aggregates []LineAggregate
+
+	db := contextDB(ctx, r.DB).
+		Table("journal_entry_lines").
+		Scopes(PostedLinesScope(filters.ClientId, filters.DateRange)).
+		Scopes(LedgerFilterScope("journal_entries", filters.LedgerId)).
+		Scopes(LineDimensionsFilterScope(filters.DimensionValueIds))
+
+	selects := "journal_entry_lines.account_id AS account_id, " +
+		"SUM(journal_entry_lines.debit) AS debit, SUM(journal_entry_lines.credit) AS credit"
+	groups := "journal_entry_lines.account_id"
+
+	if filters.GroupByDimensionTypeId != nil {
+		db = db.Joins(
+			"LEFT JOIN journal_entry_line_dimensions AS grouped_dimensions "+
+				"ON grouped_dimensions.journal_entry_line_id = journal_entry_lines.id "+
+				"AND grouped_dimensions.dimension_value_id IN "+
+				"(SELECT id FROM dimension_values WHERE dimension_type_id = ?)",
+			*filters.GroupByDimensionTypeId,
+		)
+		selects += ", grouped_dimensions.dimension_value_id::text AS dimension_value_id"
+		groups += ", grouped_dimensions.dimension_value_id"
+	}
+
+	results := db.Select(selects).Group(groups).Scan(&aggregates)
+	if results.Error != nil {
+		return nil, results.Error
+	}
+
+	return &aggregates, nil
+}
+
+// PostedLinesScope restricts journal_entry_lines to live lines of the client's posted entries. Reversed entries
+// are kept since the entry reversing them is posted and cancels them out.
+func PostedLinesScope(clientId string, dateRange *lib.DateRangeType) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		db = db.
+			Joins("JOIN journal_entries ON journal_entries.id = journal_entry_lines.journal_entry_id::uuid").
+			Where("journal_entries.client_id = ?", clientId).
+			Where(
+				"journal_entries.status IN ?",
+				[]string{models.JournalEntryStatusPosted, models.JournalEntryStatusReversed},
+			).
+			Where("journal_entries.deleted_at IS NULL AND journal_entry_lines.deleted_at IS NULL")
+
+		if dateRange != nil {
+			db = db.Where(
+				"journal_entries.transaction_date BETWEEN ? AND ?",
+				dateRange.StartTime,
+				dateRange.EndTime,
+			)
+		}
+
+		return db
+	}
+}
+
+func LineDimensionsFilterScope(dimensionValueIds map[string][]string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		for _, valueIds := range dimensionValueIds {
+			db = db.Where(
+				"EXISTS (SELECT 1 FROM journal_entry_line_dimensions "+
+					"WHERE journal_entry_line_dimensions.journal_entry_line_id = journal_entry_lines.id "+
+					"AND journal_entry_line_dimensions.dimension_value_id IN ?)",
+				valueIds,
+			)
+		}
+
+		return db
+	}
+}
@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/Bendomey/fincore-engine/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RequestNonceRepository interface {
+	Reserve(context context.Context, requestNonce *models.RequestNonce) (bool, error)
+}
+
+type requestNonceRepository struct {
+	DB *gorm.DB
+}
+
+func NewRequestNonceRepository(DB *gorm.DB) RequestNonceRepository {
+	return &requestNonceRepository{DB}
+}
+
+// Reserve records the nonce for the api key, it returns false when the key already sent it. The key's expired
+// nonces are dropped first, so they don't pile up.
+func (r *requestNonceRepository) Reserve(ctx context.Context, requestNonce *models.RequestNonce) (bool, error) {
+	reserved := false
+
+	err := contextDB(ctx, r.DB).Transaction(func(tx *gorm.DB) error {
+		err := tx.Where(
+			"api_key_id = ? AND expires_at <= ?",
+			requestNonce.ApiKeyID,
+			time.Now(),
+		).Delete(&models.RequestNonce{}).Error
+		if err != nil {
+			return err
+		}
+
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(requestNonce)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		reserved = result.RowsAffected > 0
+		return nil
+	})
+
+	return reserved, err
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Bendomey/fincore-engine/internal/models"
+	"gorm.io/gorm"
+)
+
+type SearchRepository interface {
+	Search(context context.Context, filters SearchFilter) (*[]models.SearchHit, error)
+}
+
+type searchRepository struct {
+	DB *gorm.DB
+}
+
+func NewSearchRepository(DB *gorm.DB) SearchRepository {
+	return &searchRepository{DB}
+}
+
+type SearchFilter struct {
+	ClientId string
+	LedgerId string
+	Query    string
+	// Types restricts the hits to the given models.SearchHitType*, every type when empty.
+	Types []string
+	Limit int
+}
+
+// searchSources select the hits of each type from its search_vector, matched against the query `q` of the search
+// CTE.
+var searchSources = map[string]string{
+	models.SearchHitTypeAccount: `
+		SELECT 'account' AS type, accounts.id::text AS id, ts_rank(accounts.search_vector, q) AS rank,
+			accounts.name AS text, accounts.code AS code, NULL::text AS journal_entry_id, NULL::text AS reference,
+			NULL::timestamptz AS transaction_date
+		FROM accounts CROSS JOIN search
+		WHERE accounts.client_id = @client_id AND accounts.ledger_id = @ledger_id AND accounts.deleted_at IS NULL
+			AND accounts.search_vector @@ q`,
+	models.SearchHitTypeJournalEntry: `
+		SELECT 'journal_entry' AS type, journal_entries.id::text AS id,
+			ts_rank(journal_entries.search_vector, q) AS rank, journal_entries.reference AS text, NULL::text AS code,
+			journal_entries.id::text AS journal_entry_id, journal_entries.reference AS reference,
+			journal_entries.transaction_date AS transaction_date
+		FROM journal_entries CROSS JOIN search
+		WHERE journal_entries.client_id = @client_id AND journal_entries.ledger_id = @ledger_id
+			AND journal_entries.deleted_at IS NULL AND journal_entries.search_vector @@ q`,
+	models.SearchHitTypeJournalEntryLine: `
+		SELECT 'journal_entry_line' AS type, journal_entry_lines.id::text AS id,
+			ts_rank(journal_entry_lines.search_vector, q) AS rank, journal_entry_lines.notes AS text,
+			NULL::text AS code, journal_entry_lines.journal_entry_id AS journal_entry_id,
+			journal_entries.reference AS reference, journal_entries.transaction_date AS transaction_date
+		FROM journal_entry_lines
+		JOIN journal_entries ON journal_entries.id = journal_entry_lines.journal_entry_id::uuid
+		CROSS JOIN search
+		WHERE journal_entries.client_id = @client_id AND journal_entries.ledger_id = @ledger_id
+			AND journal_entries.deleted_at IS NULL AND journal_entry_lines.deleted_at IS NULL
+			AND journal_entry_lines.search_vector @@ q`,
+}
+
+// Search matches the query, in web search syntax ("quoted phrases", or, -excluded), against the search vectors of
+// the ledger's accounts, journal entries and lines. Hits come best ranked first.
+func (r *searchRepository) Search(ctx context.Context, filters SearchFilter) (*[]models.SearchHit, error) {
+	hits := make([]models.SearchHit, 0)
+
+	types := filters.Types
+	if len(types) == 0 {
+		types = []string{models.SearchHitTypeAccount, models.SearchHitTypeJournalEntry, models.SearchHitTypeJournalEntryLine}
+	}
+
+	sources := make([]string, 0, len(types))
+	seen := map[string]bool{}
+	for _, hitType := range types {
+		if source, ok := searchSources[hitType]; ok && !seen[hitType] {
+			sources = append(sources, source)
+			seen[hitType] = true
+		}
+	}
+
+	result := contextDB(ctx, r.DB).Raw(
+		"WITH search AS (SELECT websearch_to_tsquery('simple', @query) AS q) "+
+			"SELECT * FROM ("+strings.Join(sources, " UNION ALL ")+") AS hits "+
+			"ORDER BY rank DESC, id LIMIT @limit",
+		map[string]interface{}{
+			"query":     filters.Query,
+			"client_id": filters.ClientId,
+			"ledger_id": filters.LedgerId,
+			"limit":     filters.Limit,
+		},
+	).Scan(&hits)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return &hits, nil
+}
@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+type transactionKey struct{}
+
+// Transactor runs changes made through several repositories in one transaction, e.g. a mutation and its audit
+// event, so neither is kept without the other.
+type Transactor interface {
+	// Transaction runs fn in a transaction. The repositories called with the context fn is given join it, their
+	// own transactions becoming savepoints within it.
+	Transaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type transactor struct {
+	DB *gorm.DB
+}
+
+func NewTransactor(DB *gorm.DB) Transactor {
+	return &transactor{DB}
+}
+
+func (t *transactor) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return contextDB(ctx, t.DB).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, transactionKey{}, tx))
+	})
+}
+
+// contextDB returns db bound to ctx, or the transaction ctx was given by Transactor.Transaction when there is one.
+func contextDB(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := ctx.Value(transactionKey{}).(*gorm.DB); ok {
+		return tx.WithContext(ctx)
+	}
+
+	return db.WithContext(ctx)
+}
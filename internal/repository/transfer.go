@@ -0,0 +1,207 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Bendomey/fincore-engine/internal/lib"
+	"github.com/Bendomey/fincore-engine/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TransferRepository interface {
+	Create(context context.Context, transfer *models.Transfer) error
+	GetByIDAndClientID(
+		context context.Context,
+		id string,
+		clientID string,
+		populate *[]string,
+	) (*models.Transfer, error)
+	GetByIDAndLedgerID(
+		context context.Context,
+		id string,
+		ledgerID string,
+		populate *[]string,
+	) (*models.Transfer, error)
+	List(
+		context context.Context,
+		filterQuery lib.FilterQuery,
+		filters ListTransfersFilter,
+	) (*[]models.Transfer, error)
+	Count(context context.Context, filterQuery lib.FilterQuery, filters ListTransfersFilter) (int64, error)
+}
+
+type transferRepository struct {
+	DB *gorm.DB
+}
+
+func NewTransferRepository(DB *gorm.DB) TransferRepository {
+	return &transferRepository{DB}
+}
+
+// Create stores the transfer together with its journal entry (transfer.JournalEntry), posting the entry when it
+// is created posted. Either both are stored or neither is.
+func (r *transferRepository) Create(ctx context.Context, transfer *models.Transfer) error {
+	return contextDB(ctx, r.DB).Transaction(func(tx *gorm.DB) error {
+		if err := createJournalEntry(tx, &transfer.JournalEntry); err != nil {
+			return err
+		}
+
+		transfer.JournalEntryID = transfer.JournalEntry.ID.String()
+
+		return tx.Omit(clause.Associations).Create(transfer).Error
+	})
+}
+
+func (r *transferRepository) GetByIDAndClientID(
+	ctx context.Context,
+	id string,
+	clientID string,
+	populate *[]string,
+) (*models.Transfer, error) {
+	var transfer models.Transfer
+	// the transfer's status is its entry's.
+	db := contextDB(ctx, r.DB).Preload("JournalEntry")
+
+	if populate != nil {
+		for _, field := range *populate {
+			db = db.Preload(field)
+		}
+	}
+
+	result := db.Where("id = ? AND client_id = ?", id, clientID).First(&transfer)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return &transfer, nil
+}
+
+func (r *transferRepository) GetByIDAndLedgerID(
+	ctx context.Context,
+	id string,
+	ledgerID string,
+	populate *[]string,
+) (*models.Transfer, error) {
+	var transfer models.Transfer
+	// the transfer's status is its entry's.
+	db := contextDB(ctx, r.DB).Preload("JournalEntry")
+
+	if populate != nil {
+		for _, field := range *populate {
+			db = db.Preload(field)
+		}
+	}
+
+	result := db.Where("id = ? AND ledger_id = ?", id, ledgerID).First(&transfer)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return &transfer, nil
+}
+
+type ListTransfersFilter struct {
+	ClientId      string
+	LedgerId      string
+	AccountId     *string
+	FromAccountId *string
+	ToAccountId   *string
+}
+
+// TransferQueryFields allowlists the fields transfers can be filtered, sorted and searched on.
+var TransferQueryFields = lib.QueryFields{
+	"reference":        {Column: "transfers.reference", Type: lib.FieldString, Searchable: true},
+	"from_account_id":  {Column: "transfers.from_account_id", Type: lib.FieldUUID},
+	"to_account_id":    {Column: "transfers.to_account_id", Type: lib.FieldUUID},
+	"amount":           {Column: "transfers.amount", Type: lib.FieldNumber},
+	"journal_entry_id": {Column: "transfers.journal_entry_id", Type: lib.FieldUUID},
+	"created_at":       {Column: "transfers.created_at", Type: lib.FieldTime},
+	"updated_at":       {Column: "transfers.updated_at", Type: lib.FieldTime},
+}
+
+func (r *transferRepository) List(
+	ctx context.Context,
+	filterQuery lib.FilterQuery,
+	filters ListTransfersFilter,
+) (*[]models.Transfer, error) {
+	var transfers []models.Transfer
+
+	db := contextDB(ctx, r.DB).
+		Scopes(
+			DateRangeScope("transfers", filterQuery.DateRange),
+			ClientFilterScope("transfers", filters.ClientId),
+			LedgerFilterScope("transfers", filters.LedgerId),
+			TransferFiltersScope(filters),
+			FieldFiltersScope(filterQuery.Filters),
+			SearchScope(filterQuery.Search),
+
+			PaginationScope(filterQuery.Page, filterQuery.PageSize),
+			SortScope("transfers", filterQuery),
+		)
+
+	if filterQuery.Populate != nil {
+		for _, field := range *filterQuery.Populate {
+			db = db.Preload(field)
+		}
+	}
+
+	results := db.Preload("JournalEntry").Find(&transfers)
+
+	if results.Error != nil {
+		return nil, results.Error
+	}
+
+	return &transfers, nil
+}
+
+func (r *transferRepository) Count(
+	ctx context.Context,
+	filterQuery lib.FilterQuery,
+	filters ListTransfersFilter,
+) (int64, error) {
+	var count int64
+
+	result := contextDB(ctx, r.DB).
+		Model(&models.Transfer{}).
+		Scopes(
+			DateRangeScope("transfers", filterQuery.DateRange),
+			ClientFilterScope("transfers", filters.ClientId),
+			LedgerFilterScope("transfers", filters.LedgerId),
+			TransferFiltersScope(filters),
+			FieldFiltersScope(filterQuery.Filters),
+			SearchScope(filterQuery.Search),
+		).
+		Count(&count)
+
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return count, nil
+}
+
+func TransferFiltersScope(filters ListTransfersFilter) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		// either side of the transfer
+		if filters.AccountId != nil {
+			db = db.Where(
+				"(transfers.from_account_id = ? OR transfers.to_account_id = ?)",
+				*filters.AccountId,
+				*filters.AccountId,
+			)
+		}
+
+		if filters.FromAccountId != nil {
+			db = db.Where("transfers.from_account_id = ?", *filters.FromAccountId)
+		}
+
+		if filters.ToAccountId != nil {
+			db = db.Where("transfers.to_account_id = ?", *filters.ToAccountId)
+		}
+
+		return db
+	}
+}
@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrVersionConflict is returned when a versioned row was changed by someone else since it was read.
+var ErrVersionConflict = errors.New("resource was modified by another request, fetch it again and retry")
+
+// saveVersioned saves the row only if it is still at the version it was read at, bumping the version.
+// Associations are left alone, callers save them in the same transaction.
+func saveVersioned(tx *gorm.DB, value interface{}, version *int64) error {
+	expected := *version
+	*version = expected + 1
+
+	result := tx.Model(value).
+		Where("version = ?", expected).
+		Select("*").
+		Omit(clause.Associations).
+		Updates(value)
+
+	if result.Error != nil {
+		*version = expected
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		*version = expected
+		return ErrVersionConflict
+	}
+
+	return nil
+}
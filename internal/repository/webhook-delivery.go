@@ -0,0 +1,225 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/Bendomey/fincore-engine/internal/lib"
+	"github.com/Bendomey/fincore-engine/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type WebhookDeliveryRepository interface {
+	Create(context context.Context, webhookDelivery *models.WebhookDelivery) error
+	Update(context context.Context, webhookDelivery *models.WebhookDelivery) error
+	GetByIDAndEndpointID(
+		context context.Context,
+		id string,
+		webhookEndpointID string,
+		populate *[]string,
+	) (*models.WebhookDelivery, error)
+	// ClaimDue hands out pending deliveries that are due, with their endpoint and event. Claimed deliveries are
+	// pushed back by lease so no other worker picks them up while they are being sent.
+	ClaimDue(context context.Context, lease time.Duration, limit int) (*[]models.WebhookDelivery, error)
+	List(
+		context context.Context,
+		filterQuery lib.FilterQuery,
+		filters ListWebhookDeliveriesFilter,
+	) (*[]models.WebhookDelivery, error)
+	Count(context context.Context, filterQuery lib.FilterQuery, filters ListWebhookDeliveriesFilter) (int64, error)
+}
+
+type webhookDeliveryRepository struct {
+	DB *gorm.DB
+}
+
+func NewWebhookDeliveryRepository(DB *gorm.DB) WebhookDeliveryRepository {
+	return &webhookDeliveryRepository{DB}
+}
+
+func (r *webhookDeliveryRepository) Create(ctx context.Context, webhookDelivery *models.WebhookDelivery) error {
+	return contextDB(ctx, r.DB).Omit(clause.Associations).Create(webhookDelivery).Error
+}
+
+func (r *webhookDeliveryRepository) Update(ctx context.Context, webhookDelivery *models.WebhookDelivery) error {
+	webhookDelivery.UpdatedAt = time.Now()
+	return contextDB(ctx, r.DB).Omit(clause.Associations).Save(webhookDelivery).Error
+}
+
+func (r *webhookDeliveryRepository) GetByIDAndEndpointID(
+	ctx context.Context,
+	id string,
+	webhookEndpointID string,
+	populate *[]string,
+) (*models.WebhookDelivery, error) {
+	var webhookDelivery models.WebhookDelivery
+	db := contextDB(ctx, r.DB)
+
+	if populate != nil {
+		for _, field := range *populate {
+			db = db.Preload(field)
+		}
+	}
+
+	result := db.Where("id = ? AND webhook_endpoint_id = ?", id, webhookEndpointID).First(&webhookDelivery)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return &webhookDelivery, nil
+}
+
+func (r *webhookDeliveryRepository) ClaimDue(
+	ctx context.Context,
+	lease time.Duration,
+	limit int,
+) (*[]models.WebhookDelivery, error) {
+	var webhookDeliveries []models.WebhookDelivery
+
+	err := contextDB(ctx, r.DB).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", models.WebhookDeliveryStatusPending, now).
+			Order("next_attempt_at ASC").
+			Limit(limit).
+			Find(&webhookDeliveries).Error
+		if err != nil || len(webhookDeliveries) == 0 {
+			return err
+		}
+
+		ids := make([]string, 0)
+		for _, webhookDelivery := range webhookDeliveries {
+			ids = append(ids, webhookDelivery.ID.String())
+		}
+
+		err = tx.Model(&models.WebhookDelivery{}).
+			Where("id IN ?", ids).
+			Update("next_attempt_at", now.Add(lease)).Error
+		if err != nil {
+			return err
+		}
+
+		// endpoints removed since the event was dispatched still load, the worker gives up on them.
+		return tx.Preload("WebhookEndpoint", func(db *gorm.DB) *gorm.DB {
+			return db.Unscoped()
+		}).
+			Preload("WebhookEvent").
+			Where("id IN ?", ids).
+			Order("next_attempt_at ASC").
+			Find(&webhookDeliveries).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &webhookDeliveries, nil
+}
+
+type ListWebhookDeliveriesFilter struct {
+	ClientId          string
+	LedgerId          string
+	WebhookEndpointId string
+	Status            *string
+	EventType         *string
+}
+
+// WebhookDeliveryQueryFields allowlists the fields webhook deliveries can be filtered, sorted and searched on.
+var WebhookDeliveryQueryFields = lib.QueryFields{
+	"webhook_endpoint_id": {Column: "webhook_deliveries.webhook_endpoint_id", Type: lib.FieldUUID},
+	"webhook_event_id":    {Column: "webhook_deliveries.webhook_event_id", Type: lib.FieldUUID},
+	"status":              {Column: "webhook_deliveries.status", Type: lib.FieldString},
+	"attempts":            {Column: "webhook_deliveries.attempts", Type: lib.FieldNumber},
+	"next_attempt_at":     {Column: "webhook_deliveries.next_attempt_at", Type: lib.FieldTime},
+	"last_attempt_at":     {Column: "webhook_deliveries.last_attempt_at", Type: lib.FieldTime},
+	"response_status":     {Column: "webhook_deliveries.response_status", Type: lib.FieldNumber},
+	"created_at":          {Column: "webhook_deliveries.created_at", Type: lib.FieldTime},
+	"updated_at":          {Column: "webhook_deliveries.updated_at", Type: lib.FieldTime},
+}
+
+func (r *webhookDeliveryRepository) List(
+	ctx context.Context,
+	filterQuery lib.FilterQuery,
+	filters ListWebhookDeliveriesFilter,
+) (*[]models.WebhookDelivery, error) {
+	var webhookDeliveries []models.WebhookDelivery
+
+	db := contextDB(ctx, r.DB).
+		Scopes(
+			DateRangeScope("webhook_deliveries", filterQuery.DateRange),
+			ClientFilterScope("webhook_deliveries", filters.ClientId),
+			WebhookDeliveryFiltersScope(filters),
+			FieldFiltersScope(filterQuery.Filters),
+
+			PaginationScope(filterQuery.Page, filterQuery.PageSize),
+			SortScope("webhook_deliveries", filterQuery),
+		)
+
+	if filterQuery.Populate != nil {
+		for _, field := range *filterQuery.Populate {
+			db = db.Preload(field)
+		}
+	}
+
+	results := db.Find(&webhookDeliveries)
+
+	if results.Error != nil {
+		return nil, results.Error
+	}
+
+	return &webhookDeliveries, nil
+}
+
+func (r *webhookDeliveryRepository) Count(
+	ctx context.Context,
+	filterQuery lib.FilterQuery,
+	filters ListWebhookDeliveriesFilter,
+) (int64, error) {
+	var count int64
+
+	result := contextDB(ctx, r.DB).
+		Model(&models.WebhookDelivery{}).
+		Scopes(
+			DateRangeScope("webhook_deliveries", filterQuery.DateRange),
+			ClientFilterScope("webhook_deliveries", filters.ClientId),
+			WebhookDeliveryFiltersScope(filters),
+			FieldFiltersScope(filterQuery.Filters),
+		).
+		Count(&count)
+
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return count, nil
+}
+
+func WebhookDeliveryFiltersScope(filters ListWebhookDeliveriesFilter) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		db = db.Where("webhook_deliveries.webhook_endpoint_id = ?", filters.WebhookEndpointId)
+
+		// the endpoint's ledger
+		if filters.LedgerId != "" {
+			db = db.Where(
+				"webhook_deliveries.webhook_endpoint_id IN "+
+					"(SELECT id::text FROM webhook_endpoints WHERE ledger_id = ?)",
+				filters.LedgerId,
+			)
+		}
+
+		if filters.Status != nil {
+			db = db.Where("webhook_deliveries.status = ?", *filters.Status)
+		}
+
+		if filters.EventType != nil {
+			db = db.Where(
+				"webhook_deliveries.webhook_event_id IN (SELECT id::text FROM webhook_events WHERE type = ?)",
+				*filters.EventType,
+			)
+		}
+
+		return db
+	}
+}
@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/Bendomey/fincore-engine/internal/lib"
+	"github.com/Bendomey/fincore-engine/internal/models"
+	"gorm.io/gorm"
+)
+
+type WebhookEndpointRepository interface {
+	Create(context context.Context, webhookEndpoint *models.WebhookEndpoint) error
+	Update(context context.Context, webhookEndpoint *models.WebhookEndpoint) error
+	Delete(context context.Context, webhookEndpoint *models.WebhookEndpoint) error
+	GetByIDAndLedgerID(
+		context context.Context,
+		id string,
+		ledgerID string,
+		populate *[]string,
+	) (*models.WebhookEndpoint, error)
+	List(
+		context context.Context,
+		filterQuery lib.FilterQuery,
+		filters ListWebhookEndpointsFilter,
+	) (*[]models.WebhookEndpoint, error)
+	Count(context context.Context, filterQuery lib.FilterQuery, filters ListWebhookEndpointsFilter) (int64, error)
+}
+
+type webhookEndpointRepository struct {
+	DB *gorm.DB
+}
+
+func NewWebhookEndpointRepository(DB *gorm.DB) WebhookEndpointRepository {
+	return &webhookEndpointRepository{DB}
+}
+
+func (r *webhookEndpointRepository) Create(ctx context.Context, webhookEndpoint *models.WebhookEndpoint) error {
+	return contextDB(ctx, r.DB).Create(webhookEndpoint).Error
+}
+
+func (r *webhookEndpointRepository) Update(ctx context.Context, webhookEndpoint *models.WebhookEndpoint) error {
+	webhookEndpoint.UpdatedAt = time.Now()
+	return contextDB(ctx, r.DB).Save(webhookEndpoint).Error
+}
+
+func (r *webhookEndpointRepository) Delete(ctx context.Context, webhookEndpoint *models.WebhookEndpoint) error {
+	return contextDB(ctx, r.DB).Delete(webhookEndpoint).Error
+}
+
+func (r *webhookEndpointRepository) GetByIDAndLedgerID(
+	ctx context.Context,
+	id string,
+	ledgerID string,
+	populate *[]string,
+) (*models.WebhookEndpoint, error) {
+	var webhookEndpoint models.WebhookEndpoint
+	db := contextDB(ctx, r.DB)
+
+	if populate != nil {
+		for _, field := range *populate {
+			db = db.Preload(field)
+		}
+	}
+
+	result := db.Where("id = ? AND ledger_id = ?", id, ledgerID).First(&webhookEndpoint)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return &webhookEndpoint, nil
+}
+
+type ListWebhookEndpointsFilter struct {
+	ClientId string
+	LedgerId string
+	IsActive *bool
+}
+
+// WebhookEndpointQueryFields allowlists the fields webhook endpoints can be filtered, sorted and searched on.
+var WebhookEndpointQueryFields = lib.QueryFields{
+	"url":         {Column: "webhook_endpoints.url", Type: lib.FieldString, Searchable: true},
+	"description": {Column: "webhook_endpoints.description", Type: lib.FieldString, Searchable: true},
+	"is_active":   {Column: "webhook_endpoints.is_active", Type: lib.FieldBool},
+	"created_at":  {Column: "webhook_endpoints.created_at", Type: lib.FieldTime},
+	"updated_at":  {Column: "webhook_endpoints.updated_at", Type: lib.FieldTime},
+}
+
+func (r *webhookEndpointRepository) List(
+	ctx context.Context,
+	filterQuery lib.FilterQuery,
+	filters ListWebhookEndpointsFilter,
+) (*[]models.WebhookEndpoint, error) {
+	var webhookEndpoints []models.WebhookEndpoint
+
+	results := contextDB(ctx, r.DB).
+		Scopes(
+			DateRangeScope("webhook_endpoints", filterQuery.DateRange),
+			ClientFilterScope("webhook_endpoints", filters.ClientId),
+			LedgerFilterScope("webhook_endpoints", filters.LedgerId),
+			IsActiveFilterScope("webhook_endpoints", filters.IsActive),
+			FieldFiltersScope(filterQuery.Filters),
+			SearchScope(filterQuery.Search),
+
+			PaginationScope(filterQuery.Page, filterQuery.PageSize),
+			SortScope("webhook_endpoints", filterQuery),
+		).
+		Find(&webhookEndpoints)
+
+	if results.Error != nil {
+		return nil, results.Error
+	}
+
+	return &webhookEndpoints, nil
+}
+
+func (r *webhookEndpointRepository) Count(
+	ctx context.Context,
+	filterQuery lib.FilterQuery,
+	filters ListWebhookEndpointsFilter,
+) (int64, error) {
+	var count int64
+
+	result := contextDB(ctx, r.DB).
+		Model(&models.WebhookEndpoint{}).
+		Scopes(
+			DateRangeScope("webhook_endpoints", filterQuery.DateRange),
+			ClientFilterScope("webhook_endpoints", filters.ClientId),
+			LedgerFilterScope("webhook_endpoints", filters.LedgerId),
+			IsActiveFilterScope("webhook_endpoints", filters.IsActive),
+			FieldFiltersScope(filterQuery.Filters),
+			SearchScope(filterQuery.Search),
+		).
+		Count(&count)
+
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return count, nil
+}
@@ -0,0 +1,203 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/Bendomey/fincore-engine/internal/models"
+	"github.com/Bendomey/fincore-engine/internal/transformations"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// WebhookEventRepository reads the outbox. Events are only written by the repositories making the change they
+// describe, inside the same transaction, see writeWebhookEvent.
+type WebhookEventRepository interface {
+	// Dispatch fans undispatched events out into a pending delivery per subscribed endpoint. It returns how many
+	// events were dispatched, several workers can dispatch at once.
+	Dispatch(context context.Context, limit int) (int, error)
+	ListAfterSequence(
+		context context.Context,
+		clientID string,
+		afterSequence int64,
+		limit int,
+	) (*[]models.WebhookEvent, error)
+	GetLatestSequence(context context.Context, clientID string) (int64, error)
+}
+
+// WebhookEventsChannel is the postgres channel every new event is announced on, see writeWebhookEvent.
+const WebhookEventsChannel = "fincore_webhook_events"
+
+// WebhookEventNotification is the payload of a notification on WebhookEventsChannel.
+type WebhookEventNotification struct {
+	ClientID string `json:"client_id"`
+	Sequence int64  `json:"sequence"`
+}
+
+type webhookEventRepository struct {
+	DB *gorm.DB
+}
+
+func NewWebhookEventRepository(DB *gorm.DB) WebhookEventRepository {
+	return &webhookEventRepository{DB}
+}
+
+func (r *webhookEventRepository) Dispatch(ctx context.Context, limit int) (int, error) {
+	dispatched := 0
+
+	err := contextDB(ctx, r.DB).Transaction(func(tx *gorm.DB) error {
+		var webhookEvents []models.WebhookEvent
+
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("dispatched_at IS NULL").
+			Order("sequence ASC").
+			Limit(limit).
+			Find(&webhookEvents).Error
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		for _, webhookEvent := range webhookEvents {
+			var webhookEndpoints []models.WebhookEndpoint
+
+			err := tx.Where(
+				"ledger_id = ? AND is_active = ? AND events @> ?::jsonb",
+				webhookEvent.LedgerID,
+				true,
+				datatypes.NewJSONSlice([]string{webhookEvent.Type}),
+			).Find(&webhookEndpoints).Error
+			if err != nil {
+				return err
+			}
+
+			for _, webhookEndpoint := range webhookEndpoints {
+				delivery := models.WebhookDelivery{
+					ClientID:          webhookEvent.ClientID,
+					WebhookEndpointID: webhookEndpoint.ID.String(),
+					WebhookEventID:    webhookEvent.ID.String(),
+					Status:            models.WebhookDeliveryStatusPending,
+					NextAttemptAt:     &now,
+				}
+
+				if err := tx.Omit(clause.Associations).Create(&delivery).Error; err != nil {
+					return err
+				}
+			}
+
+			err = tx.Model(&models.WebhookEvent{}).
+				Where("id = ?", webhookEvent.ID).
+				Update("dispatched_at", now).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		dispatched = len(webhookEvents)
+		return nil
+	})
+
+	return dispatched, err
+}
+
+// ListAfterSequence lists the client's events after the sequence, oldest first. The client's events commit in
+// sequence order (see lockWebhookEvents), so none can appear later behind the last one listed.
+func (r *webhookEventRepository) ListAfterSequence(
+	ctx context.Context,
+	clientID string,
+	afterSequence int64,
+	limit int,
+) (*[]models.WebhookEvent, error) {
+	var webhookEvents []models.WebhookEvent
+
+	results := contextDB(ctx, r.DB).
+		Where("client_id = ? AND sequence > ?", clientID, afterSequence).
+		Order("sequence ASC").
+		Limit(limit).
+		Find(&webhookEvents)
+
+	if results.Error != nil {
+		return nil, results.Error
+	}
+
+	return &webhookEvents, nil
+}
+
+func (r *webhookEventRepository) GetLatestSequence(ctx context.Context, clientID string) (int64, error) {
+	var sequence int64
+
+	result := contextDB(ctx, r.DB).
+		Model(&models.WebhookEvent{}).
+		Where("client_id = ?", clientID).
+		Select("COALESCE(MAX(sequence), 0)").
+		Scan(&sequence)
+
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return sequence, nil
+}
+
+// lockWebhookEvents holds the client's events until the transaction ends, so the transactions writing them take
+// their sequences one after the other and commit them in order. Without it an event could commit after one with a
+// higher sequence, and a stream resuming from that sequence would skip it. Transactions that lock rows (accounts,
+// holds, entries) before writing an event take it first, so they can't deadlock with one another.
+func lockWebhookEvents(tx *gorm.DB, clientID string) error {
+	return tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", "webhook_events:"+clientID).Error
+}
+
+// writeWebhookEvent adds an event of the ledger to the outbox as part of the transaction making the change. The
+// event is also announced on WebhookEventsChannel, postgres only delivers the notification if the transaction
+// commits.
+func writeWebhookEvent(tx *gorm.DB, clientID string, ledgerID string, eventType string, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	if err := lockWebhookEvents(tx, clientID); err != nil {
+		return err
+	}
+
+	webhookEvent := models.WebhookEvent{
+		ClientID: clientID,
+		LedgerID: ledgerID,
+		Type:     eventType,
+		Payload:  datatypes.JSON(payload),
+	}
+
+	if err := tx.Create(&webhookEvent).Error; err != nil {
+		return err
+	}
+
+	notification, err := json.Marshal(WebhookEventNotification{
+		ClientID: webhookEvent.ClientID,
+		Sequence: webhookEvent.Sequence,
+	})
+	if err != nil {
+		return err
+	}
+
+	return tx.Exec("SELECT pg_notify(?, ?)", WebhookEventsChannel, string(notification)).Error
+}
+
+// writeJournalEntryWebhookEvent sends the entry as stored, lines included, whatever the caller has loaded.
+func writeJournalEntryWebhookEvent(tx *gorm.DB, journalEntry *models.JournalEntry, eventType string) error {
+	var stored models.JournalEntry
+
+	err := tx.Preload("JournalEntryLines").Where("id = ?", journalEntry.ID).First(&stored).Error
+	if err != nil {
+		return err
+	}
+
+	return writeWebhookEvent(
+		tx,
+		stored.ClientID,
+		stored.LedgerID,
+		eventType,
+		transformations.DBJournalEntryToRestJournalEntry(&stored, nil),
+	)
+}
@@ -2,6 +2,7 @@ package router
 
 import (
 	"github.com/Bendomey/fincore-engine/internal/middleware"
+	"github.com/Bendomey/fincore-engine/internal/models"
 	"github.com/Bendomey/fincore-engine/pkg"
 	"github.com/go-chi/chi/v5"
 )
@@ -12,12 +13,23 @@ func NewAccountRouter(appCtx pkg.AppContext) *chi.Mux {
 	// ensure auth is present
 	r.Use(middleware.CheckForAuthPresenceMiddleware)
 
-	r.Post("/", appCtx.Handlers.AccountHandler.CreateAccount)
-	r.Get("/", appCtx.Handlers.AccountHandler.ListAccounts)
+	// work in the selected ledger
+	r.Use(middleware.SelectLedgerMiddleware(appCtx))
 
-	r.Get("/{account_id}", appCtx.Handlers.AccountHandler.GetAccount)
-	r.Patch("/{account_id}", appCtx.Handlers.AccountHandler.UpdateAccount)
-	r.Delete("/{account_id}", appCtx.Handlers.AccountHandler.DeleteAccount)
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.RequireScopesMiddleware(models.ApiKeyScopeAccountsRead))
+
+		r.Get("/", appCtx.Handlers.AccountHandler.ListAccounts)
+		r.Get("/{account_id}", appCtx.Handlers.AccountHandler.GetAccount)
+	})
+
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.RequireScopesMiddleware(models.ApiKeyScopeAccountsWrite))
+
+		r.Post("/", appCtx.Handlers.AccountHandler.CreateAccount)
+		r.Patch("/{account_id}", appCtx.Handlers.AccountHandler.UpdateAccount)
+		r.Delete("/{account_id}", appCtx.Handlers.AccountHandler.DeleteAccount)
+	})
 
 	return r
 }
@@ -0,0 +1,32 @@
+package router
+
+import (
+	"github.com/Bendomey/fincore-engine/internal/middleware"
+	"github.com/Bendomey/fincore-engine/internal/models"
+	"github.com/Bendomey/fincore-engine/pkg"
+	"github.com/go-chi/chi/v5"
+)
+
+func NewApiKeyRouter(appCtx pkg.AppContext) *chi.Mux {
+	r := chi.NewRouter()
+
+	// ensure auth is present
+	r.Use(middleware.CheckForAuthPresenceMiddleware)
+
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.RequireScopesMiddleware(models.ApiKeyScopeApiKeysRead))
+
+		r.Get("/", appCtx.Handlers.ApiKeyHandler.ListApiKeys)
+		r.Get("/{api_key_id}", appCtx.Handlers.ApiKeyHandler.GetApiKey)
+	})
+
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.RequireScopesMiddleware(models.ApiKeyScopeApiKeysWrite))
+
+		r.Post("/", appCtx.Handlers.ApiKeyHandler.CreateApiKey)
+		r.Post("/{api_key_id}/rotate", appCtx.Handlers.ApiKeyHandler.RotateApiKey)
+		r.Post("/{api_key_id}/revoke", appCtx.Handlers.ApiKeyHandler.RevokeApiKey)
+	})
+
+	return r
+}
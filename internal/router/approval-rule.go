@@ -0,0 +1,35 @@
+package router
+
+import (
+	"github.com/Bendomey/fincore-engine/internal/middleware"
+	"github.com/Bendomey/fincore-engine/internal/models"
+	"github.com/Bendomey/fincore-engine/pkg"
+	"github.com/go-chi/chi/v5"
+)
+
+func NewApprovalRuleRouter(appCtx pkg.AppContext) *chi.Mux {
+	r := chi.NewRouter()
+
+	// ensure auth is present
+	r.Use(middleware.CheckForAuthPresenceMiddleware)
+
+	// work in the selected ledger
+	r.Use(middleware.SelectLedgerMiddleware(appCtx))
+
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.RequireScopesMiddleware(models.ApiKeyScopeApprovalRulesRead))
+
+		r.Get("/", appCtx.Handlers.ApprovalRuleHandler.ListApprovalRules)
+		r.Get("/{approval_rule_id}", appCtx.Handlers.ApprovalRuleHandler.GetApprovalRule)
+	})
+
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.RequireScopesMiddleware(models.ApiKeyScopeApprovalRulesWrite))
+
+		r.Post("/", appCtx.Handlers.ApprovalRuleHandler.CreateApprovalRule)
+		r.Patch("/{approval_rule_id}", appCtx.Handlers.ApprovalRuleHandler.UpdateApprovalRule)
+		r.Delete("/{approval_rule_id}", appCtx.Handlers.ApprovalRuleHandler.DeleteApprovalRule)
+	})
+
+	return r
+}
@@ -0,0 +1,22 @@
+package router
+
+import (
+	"github.com/Bendomey/fincore-engine/internal/middleware"
+	"github.com/Bendomey/fincore-engine/internal/models"
+	"github.com/Bendomey/fincore-engine/pkg"
+	"github.com/go-chi/chi/v5"
+)
+
+func NewAuditEventRouter(appCtx pkg.AppContext) *chi.Mux {
+	r := chi.NewRouter()
+
+	// ensure auth is present
+	r.Use(middleware.CheckForAuthPresenceMiddleware)
+
+	// ensure the credential was granted the scope
+	r.Use(middleware.RequireScopesMiddleware(models.ApiKeyScopeAuditEventsRead))
+
+	r.Get("/", appCtx.Handlers.AuditEventHandler.ListAuditEvents)
+
+	return r
+}
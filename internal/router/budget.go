@@ -0,0 +1,41 @@
+package router
+
+import (
+	"github.com/Bendomey/fincore-engine/internal/middleware"
+	"github.com/Bendomey/fincore-engine/internal/models"
+	"github.com/Bendomey/fincore-engine/pkg"
+	"github.com/go-chi/chi/v5"
+)
+
+func NewBudgetRouter(appCtx pkg.AppContext) *chi.Mux {
+	r := chi.NewRouter()
+
+	// ensure auth is present
+	r.Use(middleware.CheckForAuthPresenceMiddleware)
+
+	// work in the selected ledger
+	r.Use(middleware.SelectLedgerMiddleware(appCtx))
+
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.RequireScopesMiddleware(models.ApiKeyScopeBudgetsRead))
+
+		r.Get("/", appCtx.Handlers.BudgetHandler.ListBudgets)
+		r.Get("/{budget_id}", appCtx.Handlers.BudgetHandler.GetBudget)
+		r.Get("/{budget_id}/lines", appCtx.Handlers.BudgetHandler.ListBudgetLines)
+	})
+
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.RequireScopesMiddleware(models.ApiKeyScopeBudgetsWrite))
+
+		r.Post("/", appCtx.Handlers.BudgetHandler.CreateBudget)
+		r.Patch("/{budget_id}", appCtx.Handlers.BudgetHandler.UpdateBudget)
+		r.Delete("/{budget_id}", appCtx.Handlers.BudgetHandler.DeleteBudget)
+
+		r.Post("/{budget_id}/lines", appCtx.Handlers.BudgetHandler.CreateBudgetLine)
+		r.Post("/{budget_id}/lines/upload", appCtx.Handlers.BudgetHandler.UploadBudgetLines)
+		r.Patch("/{budget_id}/lines/{budget_line_id}", appCtx.Handlers.BudgetHandler.UpdateBudgetLine)
+		r.Delete("/{budget_id}/lines/{budget_line_id}", appCtx.Handlers.BudgetHandler.DeleteBudgetLine)
+	})
+
+	return r
+}
@@ -0,0 +1,51 @@
+package router
+
+import (
+	"github.com/Bendomey/fincore-engine/internal/middleware"
+	"github.com/Bendomey/fincore-engine/internal/models"
+	"github.com/Bendomey/fincore-engine/pkg"
+	"github.com/go-chi/chi/v5"
+)
+
+func NewDimensionRouter(appCtx pkg.AppContext) *chi.Mux {
+	r := chi.NewRouter()
+
+	// ensure auth is present
+	r.Use(middleware.CheckForAuthPresenceMiddleware)
+
+	// work in the selected ledger
+	r.Use(middleware.SelectLedgerMiddleware(appCtx))
+
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.RequireScopesMiddleware(models.ApiKeyScopeDimensionsRead))
+
+		r.Get("/", appCtx.Handlers.DimensionHandler.ListDimensionTypes)
+		r.Get("/{dimension_type_id}", appCtx.Handlers.DimensionHandler.GetDimensionType)
+
+		r.Get("/{dimension_type_id}/values", appCtx.Handlers.DimensionHandler.ListDimensionValues)
+		r.Get(
+			"/{dimension_type_id}/values/{dimension_value_id}",
+			appCtx.Handlers.DimensionHandler.GetDimensionValue,
+		)
+	})
+
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.RequireScopesMiddleware(models.ApiKeyScopeDimensionsWrite))
+
+		r.Post("/", appCtx.Handlers.DimensionHandler.CreateDimensionType)
+		r.Patch("/{dimension_type_id}", appCtx.Handlers.DimensionHandler.UpdateDimensionType)
+		r.Delete("/{dimension_type_id}", appCtx.Handlers.DimensionHandler.DeleteDimensionType)
+
+		r.Post("/{dimension_type_id}/values", appCtx.Handlers.DimensionHandler.CreateDimensionValue)
+		r.Patch(
+			"/{dimension_type_id}/values/{dimension_value_id}",
+			appCtx.Handlers.DimensionHandler.UpdateDimensionValue,
+		)
+		r.Delete(
+			"/{dimension_type_id}/values/{dimension_value_id}",
+			appCtx.Handlers.DimensionHandler.DeleteDimensionValue,
+		)
+	})
+
+	return r
+}
@@ -0,0 +1,41 @@
+package router
+
+import (
+	"github.com/Bendomey/fincore-engine/internal/middleware"
+	"github.com/Bendomey/fincore-engine/internal/models"
+	"github.com/Bendomey/fincore-engine/pkg"
+	"github.com/go-chi/chi/v5"
+)
+
+func NewHoldRouter(appCtx pkg.AppContext) *chi.Mux {
+	r := chi.NewRouter()
+
+	// ensure auth is present
+	r.Use(middleware.CheckForAuthPresenceMiddleware)
+
+	// work in the selected ledger
+	r.Use(middleware.SelectLedgerMiddleware(appCtx))
+
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.RequireScopesMiddleware(models.ApiKeyScopeHoldsRead))
+
+		r.Get("/", appCtx.Handlers.HoldHandler.ListHolds)
+		r.Get("/{hold_id}", appCtx.Handlers.HoldHandler.GetHold)
+	})
+
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.RequireScopesMiddleware(models.ApiKeyScopeHoldsWrite))
+
+		r.Post("/", appCtx.Handlers.HoldHandler.CreateHold)
+		r.Patch("/{hold_id}/void", appCtx.Handlers.HoldHandler.VoidHold)
+	})
+
+	r.Group(func(r chi.Router) {
+		// capturing posts the held amount
+		r.Use(middleware.RequireScopesMiddleware(models.ApiKeyScopeHoldsWrite, models.ApiKeyScopeJournalEntriesPost))
+
+		r.Patch("/{hold_id}/capture", appCtx.Handlers.HoldHandler.CaptureHold)
+	})
+
+	return r
+}
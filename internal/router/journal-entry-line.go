@@ -0,0 +1,25 @@
+package router
+
+import (
+	"github.com/Bendomey/fincore-engine/internal/middleware"
+	"github.com/Bendomey/fincore-engine/internal/models"
+	"github.com/Bendomey/fincore-engine/pkg"
+	"github.com/go-chi/chi/v5"
+)
+
+func NewJournalEntryLineRouter(appCtx pkg.AppContext) *chi.Mux {
+	r := chi.NewRouter()
+
+	// ensure auth is present
+	r.Use(middleware.CheckForAuthPresenceMiddleware)
+
+	// work in the selected ledger
+	r.Use(middleware.SelectLedgerMiddleware(appCtx))
+
+	// ensure the credential was granted the scope
+	r.Use(middleware.RequireScopesMiddleware(models.ApiKeyScopeJournalEntriesRead))
+
+	r.Get("/", appCtx.Handlers.JournalEntryLineHandler.ListJournalEntryLines)
+
+	return r
+}
@@ -2,6 +2,7 @@ package router
 
 import (
 	"github.com/Bendomey/fincore-engine/internal/middleware"
+	"github.com/Bendomey/fincore-engine/internal/models"
 	"github.com/Bendomey/fincore-engine/pkg"
 	"github.com/go-chi/chi/v5"
 )
@@ -12,13 +13,48 @@ func NewJournalEntryRouter(appCtx pkg.AppContext) *chi.Mux {
 	// ensure auth is present
 	r.Use(middleware.CheckForAuthPresenceMiddleware)
 
-	r.Post("/", appCtx.Handlers.JournalEntryHandler.CreateJournalEntry)
-	r.Get("/", appCtx.Handlers.JournalEntryHandler.ListJournalEntries)
+	// work in the selected ledger
+	r.Use(middleware.SelectLedgerMiddleware(appCtx))
 
-	r.Get("/{journal_entry_id}", appCtx.Handlers.JournalEntryHandler.GetJournalEntry)
-	r.Patch("/{account_id}", appCtx.Handlers.JournalEntryHandler.UpdateJournalEntry)
-	r.Patch("/{journal_entry_id}/post", appCtx.Handlers.JournalEntryHandler.PostJournalEntry)
-	r.Delete("/{account_id}", appCtx.Handlers.JournalEntryHandler.DeleteJournalEntry)
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.RequireScopesMiddleware(models.ApiKeyScopeJournalEntriesRead))
+
+		r.Get("/", appCtx.Handlers.JournalEntryHandler.ListJournalEntries)
+		r.Get("/{journal_entry_id}", appCtx.Handlers.JournalEntryHandler.GetJournalEntry)
+
+		r.Get("/{journal_entry_id}/attachments", appCtx.Handlers.AttachmentHandler.ListAttachments)
+		r.Get("/{journal_entry_id}/attachments/{attachment_id}", appCtx.Handlers.AttachmentHandler.GetAttachment)
+		r.Get(
+			"/{journal_entry_id}/attachments/{attachment_id}/download",
+			appCtx.Handlers.AttachmentHandler.DownloadAttachment,
+		)
+	})
+
+	r.Group(func(r chi.Router) {
+		// creating an entry straight as POSTED also takes journal_entries:post, checked by the handler
+		r.Use(middleware.RequireScopesMiddleware(models.ApiKeyScopeJournalEntriesWrite))
+
+		r.Post("/", appCtx.Handlers.JournalEntryHandler.CreateJournalEntry)
+		r.Patch("/{journal_entry_id}", appCtx.Handlers.JournalEntryHandler.UpdateJournalEntry)
+		r.Patch("/{journal_entry_id}/submit", appCtx.Handlers.JournalEntryHandler.SubmitJournalEntry)
+		r.Patch("/{journal_entry_id}/void", appCtx.Handlers.JournalEntryHandler.VoidJournalEntry)
+		r.Delete("/{journal_entry_id}", appCtx.Handlers.JournalEntryHandler.DeleteJournalEntry)
+
+		r.Post("/{journal_entry_id}/attachments", appCtx.Handlers.AttachmentHandler.UploadAttachment)
+		r.Delete(
+			"/{journal_entry_id}/attachments/{attachment_id}",
+			appCtx.Handlers.AttachmentHandler.DeleteAttachment,
+		)
+	})
+
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.RequireScopesMiddleware(models.ApiKeyScopeJournalEntriesPost))
+
+		r.Patch("/{journal_entry_id}/post", appCtx.Handlers.JournalEntryHandler.PostJournalEntry)
+		r.Patch("/{journal_entry_id}/approve", appCtx.Handlers.JournalEntryHandler.ApproveJournalEntry)
+		r.Patch("/{journal_entry_id}/reject", appCtx.Handlers.JournalEntryHandler.RejectJournalEntry)
+		r.Patch("/{journal_entry_id}/reverse", appCtx.Handlers.JournalEntryHandler.ReverseJournalEntry)
+	})
 
 	return r
 }
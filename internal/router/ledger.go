@@ -0,0 +1,49 @@
+package router
+
+import (
+	"github.com/Bendomey/fincore-engine/internal/middleware"
+	"github.com/Bendomey/fincore-engine/internal/models"
+	"github.com/Bendomey/fincore-engine/pkg"
+	"github.com/go-chi/chi/v5"
+)
+
+func NewLedgerRouter(appCtx pkg.AppContext) *chi.Mux {
+	r := chi.NewRouter()
+
+	// ensure auth is present
+	r.Use(middleware.CheckForAuthPresenceMiddleware)
+
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.RequireScopesMiddleware(models.ApiKeyScopeLedgersRead))
+
+		r.Get("/", appCtx.Handlers.LedgerHandler.ListLedgers)
+		r.Get("/{ledger_id}", appCtx.Handlers.LedgerHandler.GetLedger)
+	})
+
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.RequireScopesMiddleware(models.ApiKeyScopeLedgersWrite))
+
+		r.Post("/", appCtx.Handlers.LedgerHandler.CreateLedger)
+		r.Patch("/{ledger_id}", appCtx.Handlers.LedgerHandler.UpdateLedger)
+		r.Delete("/{ledger_id}", appCtx.Handlers.LedgerHandler.DeleteLedger)
+	})
+
+	return r
+}
+
+func NewLedgerVerificationRouter(appCtx pkg.AppContext) *chi.Mux {
+	r := chi.NewRouter()
+
+	// ensure auth is present
+	r.Use(middleware.CheckForAuthPresenceMiddleware)
+
+	// work in the selected ledger
+	r.Use(middleware.SelectLedgerMiddleware(appCtx))
+
+	// ensure the credential was granted the scope
+	r.Use(middleware.RequireScopesMiddleware(models.ApiKeyScopeReportsRead))
+
+	r.Get("/verify", appCtx.Handlers.LedgerHandler.VerifyLedger)
+
+	return r
+}
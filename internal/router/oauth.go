@@ -0,0 +1,15 @@
+package router
+
+import (
+	"github.com/Bendomey/fincore-engine/pkg"
+	"github.com/go-chi/chi/v5"
+)
+
+func NewOAuthRouter(appCtx pkg.AppContext) *chi.Mux {
+	r := chi.NewRouter()
+
+	// the client authenticates with its api key in the request itself
+	r.Post("/token", appCtx.Handlers.OAuthHandler.IssueToken)
+
+	return r
+}
@@ -0,0 +1,27 @@
+package router
+
+import (
+	"github.com/Bendomey/fincore-engine/internal/middleware"
+	"github.com/Bendomey/fincore-engine/internal/models"
+	"github.com/Bendomey/fincore-engine/pkg"
+	"github.com/go-chi/chi/v5"
+)
+
+func NewReportRouter(appCtx pkg.AppContext) *chi.Mux {
+	r := chi.NewRouter()
+
+	// ensure auth is present
+	r.Use(middleware.CheckForAuthPresenceMiddleware)
+
+	// work in the selected ledger
+	r.Use(middleware.SelectLedgerMiddleware(appCtx))
+
+	// ensure the credential was granted the scope
+	r.Use(middleware.RequireScopesMiddleware(models.ApiKeyScopeReportsRead))
+
+	r.Get("/account-balances", appCtx.Handlers.ReportHandler.GetAccountBalances)
+	r.Get("/income-statement", appCtx.Handlers.ReportHandler.GetIncomeStatement)
+	r.Get("/budget-vs-actual", appCtx.Handlers.ReportHandler.GetBudgetVsActual)
+
+	return r
+}
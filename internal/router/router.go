@@ -25,7 +25,7 @@ func New(appCtx pkg.AppContext) *chi.Mux {
 		// AllowedOrigins:   []string{"https://foo.com"}, // Use this to allow specific origin hosts
 		AllowedOrigins: []string{"https://*", "http://*"},
 		// AllowOriginFunc:  func(r *http.Request, origin string) bool { return true },
-		AllowedMethods: []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
+		AllowedMethods: []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
 		AllowedHeaders: []string{
 			"User-Agent",
 			"Content-Type",
@@ -41,8 +41,17 @@ func New(appCtx pkg.AppContext) *chi.Mux {
 			"Referer",
 			"X-FinCore-Client-Id",
 			"X-FinCore-Client-Secret",
+			"X-FinCore-Key-Id",
+			"X-FinCore-Timestamp",
+			"X-FinCore-Nonce",
+			"X-FinCore-Signature",
+			"X-FinCore-Ledger-Id",
+			"Authorization",
+			"Idempotency-Key",
+			"If-Match",
+			"Last-Event-ID",
 		},
-		ExposedHeaders:   []string{"Link"},
+		ExposedHeaders:   []string{"Link", "Idempotent-Replayed", "ETag"},
 		AllowCredentials: false,
 		MaxAge:           300, // Maximum value not ignored by any of major browsers
 	}))
@@ -56,7 +65,13 @@ func New(appCtx pkg.AppContext) *chi.Mux {
 	r.Use(appMiddleware.RateLimitMiddleware)
 
 	r.Use(middleware.AllowContentEncoding("deflate", "gzip"))
-	r.Use(middleware.AllowContentType("application/json"))
+	// text/csv for budget uploads, multipart/form-data for attachments, form encoding for oauth tokens
+	r.Use(middleware.AllowContentType(
+		"application/json",
+		"text/csv",
+		"multipart/form-data",
+		"application/x-www-form-urlencoded",
+	))
 	r.Use(middleware.RequestID)
 	r.Use(middleware.Logger)
 	r.Use(middleware.Recoverer)
@@ -65,10 +80,30 @@ func New(appCtx pkg.AppContext) *chi.Mux {
 	// health check
 	r.Use(middleware.Heartbeat("/"))
 
+	// oauth2 access tokens
+	r.Mount("/oauth", NewOAuthRouter(appCtx))
+
 	r.Route("/api/v1", func(r chi.Router) {
-		r.Mount("/clients", NewClientRouter(appCtx))               // clients
-		r.Mount("/accounts", NewAccountRouter(appCtx))             // accounts
-		r.Mount("/journal-entries", NewJournalEntryRouter(appCtx)) // journalentries
+		// replay writes retried with the same Idempotency-Key
+		r.Use(appMiddleware.IdempotencyMiddleware(appCtx))
+
+		r.Mount("/clients", NewClientRouter(appCtx))                       // clients
+		r.Mount("/accounts", NewAccountRouter(appCtx))                     // accounts
+		r.Mount("/journal-entries", NewJournalEntryRouter(appCtx))         // journalentries
+		r.Mount("/dimensions", NewDimensionRouter(appCtx))                 // dimensions
+		r.Mount("/reports", NewReportRouter(appCtx))                       // reports
+		r.Mount("/budgets", NewBudgetRouter(appCtx))                       // budgets
+		r.Mount("/approval-rules", NewApprovalRuleRouter(appCtx))          // approval rules
+		r.Mount("/audit-events", NewAuditEventRouter(appCtx))              // audit events
+		r.Mount("/ledger", NewLedgerVerificationRouter(appCtx))            // ledger verification
+		r.Mount("/webhook-endpoints", NewWebhookRouter(appCtx))            // webhooks
+		r.Mount("/events", NewEventRouter(appCtx))                         // event stream
+		r.Mount("/transfers", NewTransferRouter(appCtx))                   // transfers
+		r.Mount("/holds", NewHoldRouter(appCtx))                           // holds
+		r.Mount("/journal-entry-lines", NewJournalEntryLineRouter(appCtx)) // journal entry lines
+		r.Mount("/search", NewSearchRouter(appCtx))                        // full-text search
+		r.Mount("/api-keys", NewApiKeyRouter(appCtx))                      // api keys
+		r.Mount("/ledgers", NewLedgerRouter(appCtx))                       // ledgers
 	})
 
 	// serve openapi.yaml + docs
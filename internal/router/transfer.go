@@ -0,0 +1,37 @@
+package router
+
+import (
+	"github.com/Bendomey/fincore-engine/internal/middleware"
+	"github.com/Bendomey/fincore-engine/internal/models"
+	"github.com/Bendomey/fincore-engine/pkg"
+	"github.com/go-chi/chi/v5"
+)
+
+func NewTransferRouter(appCtx pkg.AppContext) *chi.Mux {
+	r := chi.NewRouter()
+
+	// ensure auth is present
+	r.Use(middleware.CheckForAuthPresenceMiddleware)
+
+	// work in the selected ledger
+	r.Use(middleware.SelectLedgerMiddleware(appCtx))
+
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.RequireScopesMiddleware(models.ApiKeyScopeTransfersRead))
+
+		r.Get("/", appCtx.Handlers.TransferHandler.ListTransfers)
+		r.Get("/{transfer_id}", appCtx.Handlers.TransferHandler.GetTransfer)
+	})
+
+	r.Group(func(r chi.Router) {
+		// a transfer posts its entry straight away
+		r.Use(middleware.RequireScopesMiddleware(
+			models.ApiKeyScopeTransfersWrite,
+			models.ApiKeyScopeJournalEntriesPost,
+		))
+
+		r.Post("/", appCtx.Handlers.TransferHandler.CreateTransfer)
+	})
+
+	return r
+}
@@ -0,0 +1,46 @@
+package router
+
+import (
+	"github.com/Bendomey/fincore-engine/internal/middleware"
+	"github.com/Bendomey/fincore-engine/internal/models"
+	"github.com/Bendomey/fincore-engine/pkg"
+	"github.com/go-chi/chi/v5"
+)
+
+func NewWebhookRouter(appCtx pkg.AppContext) *chi.Mux {
+	r := chi.NewRouter()
+
+	// ensure auth is present
+	r.Use(middleware.CheckForAuthPresenceMiddleware)
+
+	// work in the selected ledger
+	r.Use(middleware.SelectLedgerMiddleware(appCtx))
+
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.RequireScopesMiddleware(models.ApiKeyScopeWebhooksRead))
+
+		r.Get("/", appCtx.Handlers.WebhookHandler.ListWebhookEndpoints)
+		r.Get("/{webhook_endpoint_id}", appCtx.Handlers.WebhookHandler.GetWebhookEndpoint)
+
+		r.Get("/{webhook_endpoint_id}/deliveries", appCtx.Handlers.WebhookHandler.ListWebhookDeliveries)
+		r.Get(
+			"/{webhook_endpoint_id}/deliveries/{webhook_delivery_id}",
+			appCtx.Handlers.WebhookHandler.GetWebhookDelivery,
+		)
+	})
+
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.RequireScopesMiddleware(models.ApiKeyScopeWebhooksWrite))
+
+		r.Post("/", appCtx.Handlers.WebhookHandler.CreateWebhookEndpoint)
+		r.Patch("/{webhook_endpoint_id}", appCtx.Handlers.WebhookHandler.UpdateWebhookEndpoint)
+		r.Delete("/{webhook_endpoint_id}", appCtx.Handlers.WebhookHandler.DeleteWebhookEndpoint)
+
+		r.Post(
+			"/{webhook_endpoint_id}/deliveries/{webhook_delivery_id}/redeliver",
+			appCtx.Handlers.WebhookHandler.RedeliverWebhookDelivery,
+		)
+	})
+
+	return r
+}
@@ -0,0 +1,130 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Bendomey/fincore-engine/internal/lib"
+	"github.com/Bendomey/fincore-engine/internal/models"
+	"github.com/Bendomey/fincore-engine/internal/repository"
+	"github.com/gofrs/uuid"
+)
+
+// AccessTokenLifetime is how long an access token works, short as it can't be revoked on its own. Revoking the
+// api key it was issued with stops it straight away.
+const AccessTokenLifetime = 15 * time.Minute
+
+const accessTokenIssuer = "fincore-engine"
+
+var ErrInvalidScope = errors.New("the api key isn't granted every scope asked for")
+
+type AccessTokenService interface {
+	IssueAccessToken(ctx context.Context, input IssueAccessTokenInput) (*AccessToken, error)
+	AuthenticateAccessToken(ctx context.Context, token string) (*AuthenticatedAccessToken, error)
+}
+
+type accessTokenService struct {
+	apiKeyService ApiKeyService
+	apiKey        repository.ApiKeyRepository
+	signingKey    []byte
+}
+
+func NewAccessTokenService(
+	apiKeyService ApiKeyService,
+	apiKey repository.ApiKeyRepository,
+	signingKey []byte,
+) AccessTokenService {
+	return &accessTokenService{apiKeyService, apiKey, signingKey}
+}
+
+type IssueAccessTokenInput struct {
+	ClientID     string
+	ClientSecret string
+
+	// Scopes narrow the token down to some of the api key's scopes, all of them when nil.
+	Scopes *[]string
+}
+
+type AccessToken struct {
+	Token     string
+	Scopes    []string
+	ExpiresAt time.Time
+}
+
+// IssueAccessToken exchanges an api key for a signed access token, the OAuth2 client credentials grant. Requests
+// made with the token skip hashing the secret again.
+func (s *accessTokenService) IssueAccessToken(ctx context.Context, input IssueAccessTokenInput) (*AccessToken, error) {
+	apiKey, err := s.apiKeyService.AuthenticateApiKey(ctx, input.ClientID, input.ClientSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	scopes := []string(apiKey.Scopes)
+	if input.Scopes != nil {
+		for _, scope := range *input.Scopes {
+			if !apiKey.HasScope(scope) {
+				return nil, ErrInvalidScope
+			}
+		}
+
+		scopes = slices.Compact(slices.Sorted(slices.Values(*input.Scopes)))
+	}
+
+	tokenID, err := uuid.NewV4()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(AccessTokenLifetime)
+
+	token, err := lib.SignToken(lib.TokenClaims{
+		Issuer:    accessTokenIssuer,
+		Subject:   apiKey.Client.ClientId,
+		ClientID:  apiKey.Client.ClientId,
+		ApiKeyID:  apiKey.ID.String(),
+		Scope:     strings.Join(scopes, " "),
+		ID:        tokenID.String(),
+		IssuedAt:  now.Unix(),
+		ExpiresAt: expiresAt.Unix(),
+	}, s.signingKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AccessToken{Token: token, Scopes: scopes, ExpiresAt: expiresAt}, nil
+}
+
+type AuthenticatedAccessToken struct {
+	ApiKey    models.ApiKey
+	Scopes    []string
+	ExpiresAt time.Time
+}
+
+// AuthenticateAccessToken verifies the token and that the api key it was issued with is still active, with the
+// key's client loaded.
+func (s *accessTokenService) AuthenticateAccessToken(
+	ctx context.Context,
+	token string,
+) (*AuthenticatedAccessToken, error) {
+	now := time.Now()
+
+	claims, err := lib.ParseToken(token, s.signingKey, now)
+	if err != nil || claims.Issuer != accessTokenIssuer {
+		return nil, lib.ErrInvalidToken
+	}
+
+	apiKey, err := s.apiKey.GetByID(ctx, claims.ApiKeyID)
+	if err != nil || apiKey.Client.ClientId != claims.Subject || !apiKey.IsActive(now) {
+		return nil, lib.ErrInvalidToken
+	}
+
+	return &AuthenticatedAccessToken{
+		ApiKey:    *apiKey,
+		Scopes:    strings.Fields(claims.Scope),
+		ExpiresAt: time.Unix(claims.ExpiresAt, 0),
+	}, nil
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/Bendomey/fincore-engine/internal/lib"
 	"github.com/Bendomey/fincore-engine/internal/models"
 	"github.com/Bendomey/fincore-engine/internal/repository"
+	"github.com/Bendomey/fincore-engine/internal/transformations"
 )
 
 type AccountService interface {
@@ -28,11 +29,17 @@ type AccountService interface {
 }
 
 type accountService struct {
-	repo repository.AccountRepository
+	repo       repository.AccountRepository
+	auditEvent repository.AuditEventRepository
+	transactor repository.Transactor
 }
 
-func NewAccountService(repo repository.AccountRepository) AccountService {
-	return &accountService{repo}
+func NewAccountService(
+	repo repository.AccountRepository,
+	auditEvent repository.AuditEventRepository,
+	transactor repository.Transactor,
+) AccountService {
+	return &accountService{repo, auditEvent, transactor}
 }
 
 type CreateAccountInput struct {
@@ -41,9 +48,13 @@ type CreateAccountInput struct {
 	IsContra    bool
 	IsGroup     bool
 	ClientID    string
+	LedgerID    string
 
 	ParentAccountID *string
 	Description     *string
+
+	DisallowNegativeBalance bool
+	OverdraftLimit          int64
 }
 
 func (s *accountService) CreateAccount(ctx context.Context, input CreateAccountInput) (*models.Account, error) {
@@ -55,7 +66,8 @@ func (s *accountService) CreateAccount(ctx context.Context, input CreateAccountI
 
 	if input.ParentAccountID != nil {
 
-		parentAccount, parentAccountErr := s.repo.GetByID(ctx, *input.ParentAccountID, nil)
+		// the parent is looked up in the account's own ledger.
+		parentAccount, parentAccountErr := s.repo.GetByIDAndLedgerID(ctx, *input.ParentAccountID, input.LedgerID, nil)
 		if parentAccountErr != nil {
 			return nil, parentAccountErr
 		}
@@ -66,7 +78,7 @@ func (s *accountService) CreateAccount(ctx context.Context, input CreateAccountI
 
 	}
 
-	code, codeErr := GenerateAccountCode(s.repo, input.AccountType)
+	code, codeErr := GenerateAccountCode(s.repo, input.LedgerID, input.AccountType)
 	if codeErr != nil {
 		return nil, codeErr
 	}
@@ -79,10 +91,27 @@ func (s *accountService) CreateAccount(ctx context.Context, input CreateAccountI
 		IsGroup:         input.IsGroup,
 		ParentAccountID: input.ParentAccountID,
 		ClientID:        input.ClientID,
+		LedgerID:        input.LedgerID,
 		Description:     input.Description,
+
+		DisallowNegativeBalance: input.DisallowNegativeBalance,
+		OverdraftLimit:          input.OverdraftLimit,
 	}
 
-	err := s.repo.Create(ctx, account)
+	err := s.transactor.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.Create(ctx, account); err != nil {
+			return err
+		}
+
+		return recordAuditEvent(ctx, s.auditEvent, auditEventInput{
+			ClientID:     account.ClientID,
+			LedgerID:     account.LedgerID,
+			Action:       "create",
+			ResourceType: "account",
+			ResourceID:   account.ID.String(),
+			After:        auditSnapshot(transformations.DBAccountToRestAccount(account, nil)),
+		})
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -90,7 +119,9 @@ func (s *accountService) CreateAccount(ctx context.Context, input CreateAccountI
 	return account, nil
 }
 
-func GenerateAccountCode(repo repository.AccountRepository, accountType string) (*string, error) {
+// GenerateAccountCode numbers the next account of the type in the ledger, every ledger numbers its accounts on
+// its own.
+func GenerateAccountCode(repo repository.AccountRepository, ledgerID string, accountType string) (*string, error) {
 	generatedCode := "1000"
 	switch accountType {
 	case "ASSET":
@@ -113,6 +144,7 @@ func GenerateAccountCode(repo repository.AccountRepository, accountType string)
 		Order:    "desc",
 		OrderBy:  "created_at",
 	}, repository.ListAccountsFilter{
+		LedgerId:    ledgerID,
 		AccountType: &accountType,
 	})
 	if err != nil {
@@ -137,8 +169,17 @@ func GenerateAccountCode(repo repository.AccountRepository, accountType string)
 }
 
 type UpdateAccountInput struct {
+	ClientID string
+	LedgerID string
+
+	// Version is the version the caller last read (If-Match), the update is refused when the account moved on.
+	Version int64
+
 	Name        *string
 	Description *string
+
+	DisallowNegativeBalance *bool
+	OverdraftLimit          *int64
 }
 
 func (s *accountService) UpdateAccount(
@@ -146,18 +187,46 @@ func (s *accountService) UpdateAccount(
 	accountId string,
 	input UpdateAccountInput,
 ) (*models.Account, error) {
-	account, err := s.repo.GetByID(ctx, accountId, nil)
+	account, err := s.repo.GetByIDAndLedgerID(ctx, accountId, input.LedgerID, nil)
 	if err != nil {
 		return nil, err
 	}
 
+	if account.Version != input.Version {
+		return nil, repository.ErrVersionConflict
+	}
+
+	before := auditSnapshot(transformations.DBAccountToRestAccount(account, nil))
+
 	if input.Name != nil {
 		account.Name = *input.Name
 	}
 
 	account.Description = input.Description
 
-	err = s.repo.Update(ctx, account)
+	if input.DisallowNegativeBalance != nil {
+		account.DisallowNegativeBalance = *input.DisallowNegativeBalance
+	}
+
+	if input.OverdraftLimit != nil {
+		account.OverdraftLimit = *input.OverdraftLimit
+	}
+
+	err = s.transactor.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.Update(ctx, account); err != nil {
+			return err
+		}
+
+		return recordAuditEvent(ctx, s.auditEvent, auditEventInput{
+			ClientID:     account.ClientID,
+			LedgerID:     account.LedgerID,
+			Action:       "update",
+			ResourceType: "account",
+			ResourceID:   account.ID.String(),
+			Before:       before,
+			After:        auditSnapshot(transformations.DBAccountToRestAccount(account, nil)),
+		})
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -167,26 +236,41 @@ func (s *accountService) UpdateAccount(
 
 type DeleteAccountInput struct {
 	ClientID string
+	LedgerID string
 	ID       string
 }
 
 func (s *accountService) DeleteAccount(ctx context.Context, input DeleteAccountInput) error {
-	account, err := s.repo.GetByIDAndClientID(ctx, input.ID, input.ClientID, nil)
+	account, err := s.repo.GetByIDAndLedgerID(ctx, input.ID, input.LedgerID, nil)
 	if err != nil {
 		return err
 	}
 
-	return s.repo.Delete(ctx, account)
+	return s.transactor.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.Delete(ctx, account); err != nil {
+			return err
+		}
+
+		return recordAuditEvent(ctx, s.auditEvent, auditEventInput{
+			ClientID:     account.ClientID,
+			LedgerID:     account.LedgerID,
+			Action:       "delete",
+			ResourceType: "account",
+			ResourceID:   account.ID.String(),
+			Before:       auditSnapshot(transformations.DBAccountToRestAccount(account, nil)),
+		})
+	})
 }
 
 type GetAccountInput struct {
 	ClientID string
+	LedgerID string
 	ID       string
 	Populate *[]string
 }
 
 func (s *accountService) GetAccount(ctx context.Context, input GetAccountInput) (*models.Account, error) {
-	account, err := s.repo.GetByIDAndClientID(ctx, input.ID, input.ClientID, input.Populate)
+	account, err := s.repo.GetByIDAndLedgerID(ctx, input.ID, input.LedgerID, input.Populate)
 	if err != nil {
 		return nil, err
 	}
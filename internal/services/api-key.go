@@ -0,0 +1,439 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Bendomey/fincore-engine/internal/lib"
+	"github.com/Bendomey/fincore-engine/internal/models"
+	"github.com/Bendomey/fincore-engine/internal/repository"
+	"github.com/Bendomey/fincore-engine/internal/transformations"
+	"github.com/getsentry/raven-go"
+	"github.com/gofrs/uuid"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/datatypes"
+)
+
+// apiKeyPrefix starts every api key secret, followed by the key's public prefix and the secret proper after a dot.
+const apiKeyPrefix = "fk_"
+
+// DefaultApiKeyRotationOverlap is how long a rotated key keeps working when no overlap is asked for, time enough
+// to roll its replacement out.
+const DefaultApiKeyRotationOverlap = 24 * time.Hour
+
+// apiKeyLastUsedPrecision is how stale last_used_at may get, so authenticating doesn't write on every request.
+const apiKeyLastUsedPrecision = time.Minute
+
+var (
+	ErrInvalidClientCredentials = errors.New("invalid client credentials")
+	ErrApiKeyScopesExceeded     = errors.New("the credential used is missing some of the api key's scopes")
+)
+
+type ApiKeyService interface {
+	AuthenticateApiKey(ctx context.Context, clientId string, secret string) (*models.ApiKey, error)
+	CreateApiKey(ctx context.Context, input CreateApiKeyInput) (*CreateApiKeyResponse, error)
+	RotateApiKey(ctx context.Context, input RotateApiKeyInput) (*CreateApiKeyResponse, error)
+	RevokeApiKey(ctx context.Context, input GetApiKeyInput) (*models.ApiKey, error)
+	GetApiKey(ctx context.Context, input GetApiKeyInput) (*models.ApiKey, error)
+	ListApiKeys(
+		ctx context.Context,
+		filterQuery lib.FilterQuery,
+		filters repository.ListApiKeysFilter,
+	) ([]models.ApiKey, error)
+	CountApiKeys(ctx context.Context, filterQuery lib.FilterQuery, filters repository.ListApiKeysFilter) (int64, error)
+}
+
+type apiKeyService struct {
+	repo          repository.ApiKeyRepository
+	client        repository.ClientRepository
+	auditEvent    repository.AuditEventRepository
+	transactor    repository.Transactor
+	encryptionKey []byte
+}
+
+func NewApiKeyService(
+	repo repository.ApiKeyRepository,
+	client repository.ClientRepository,
+	auditEvent repository.AuditEventRepository,
+	transactor repository.Transactor,
+	encryptionKey []byte,
+) ApiKeyService {
+	return &apiKeyService{repo, client, auditEvent, transactor, encryptionKey}
+}
+
+// AuthenticateApiKey returns the active key of the client the secret belongs to, with its client loaded.
+func (s *apiKeyService) AuthenticateApiKey(
+	ctx context.Context,
+	clientId string,
+	secret string,
+) (*models.ApiKey, error) {
+	apiKey, err := s.findApiKey(ctx, clientId, secret)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if !apiKey.IsActive(now) {
+		return nil, ErrInvalidClientCredentials
+	}
+
+	touchApiKeyLastUsed(ctx, s.repo, apiKey, now)
+
+	return apiKey, nil
+}
+
+// touchApiKeyLastUsed records the key authenticated a request, at most once per apiKeyLastUsedPrecision.
+func touchApiKeyLastUsed(ctx context.Context, repo repository.ApiKeyRepository, apiKey *models.ApiKey, now time.Time) {
+	if apiKey.LastUsedAt != nil && now.Sub(*apiKey.LastUsedAt) <= apiKeyLastUsedPrecision {
+		return
+	}
+
+	if err := repo.TouchLastUsed(ctx, apiKey, now); err != nil {
+		// not worth turning the request away for.
+		raven.CaptureError(err, map[string]string{
+			"function": "touchApiKeyLastUsed",
+			"action":   "recording last use",
+		})
+	}
+}
+
+// findApiKey finds the key by the prefix of the secret, or among the client's unprefixed keys for secrets issued
+// before keys had one.
+func (s *apiKeyService) findApiKey(ctx context.Context, clientId string, secret string) (*models.ApiKey, error) {
+	if prefix, _, ok := strings.Cut(secret, "."); ok && strings.HasPrefix(prefix, apiKeyPrefix) {
+		apiKey, err := s.repo.GetByPrefix(ctx, prefix)
+		if err != nil || apiKey.Client.ClientId != clientId || !verifyApiKeySecret(apiKey.SecretHash, secret) {
+			return nil, ErrInvalidClientCredentials
+		}
+
+		return apiKey, nil
+	}
+
+	client, err := s.client.GetByClientID(ctx, clientId)
+	if err != nil {
+		return nil, ErrInvalidClientCredentials
+	}
+
+	apiKeys, err := s.repo.ListUnprefixedByClientID(ctx, client.ID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	for _, apiKey := range *apiKeys {
+		if verifyApiKeySecret(apiKey.SecretHash, secret) {
+			return &apiKey, nil
+		}
+	}
+
+	return nil, ErrInvalidClientCredentials
+}
+
+type CreateApiKeyInput struct {
+	ClientID string
+	Label    string
+
+	// Scopes are those of the credential making the key when nil, a key is never granted scopes it lacks.
+	Scopes []string
+
+	// ExpiresAt is brought forward to when the credential making the key expires, if that is sooner.
+	ExpiresAt *time.Time
+}
+
+type CreateApiKeyResponse struct {
+	ApiKey models.ApiKey
+	Secret string
+}
+
+func (s *apiKeyService) CreateApiKey(ctx context.Context, input CreateApiKeyInput) (*CreateApiKeyResponse, error) {
+	if input.ExpiresAt != nil && !input.ExpiresAt.After(time.Now()) {
+		return nil, errors.New("expires_at must be in the future")
+	}
+
+	scopes := input.Scopes
+	if scopes == nil {
+		scopes, _ = lib.ScopesFromContext(ctx)
+	}
+
+	if !coversApiKeyScopes(ctx, scopes) {
+		return nil, ErrApiKeyScopesExceeded
+	}
+
+	apiKey, secret, err := newApiKey(input.Label, scopes, credentialBoundExpiry(ctx, input.ExpiresAt), s.encryptionKey)
+	if err != nil {
+		return nil, err
+	}
+
+	apiKey.ClientID = input.ClientID
+	err = s.transactor.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.Create(ctx, apiKey); err != nil {
+			return err
+		}
+
+		return s.recordAuditEvent(ctx, "create", apiKey, nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &CreateApiKeyResponse{ApiKey: *apiKey, Secret: secret}, nil
+}
+
+type RotateApiKeyInput struct {
+	ClientID string
+	ID       string
+
+	// Overlap is how long the rotated key keeps working next to its replacement, DefaultApiKeyRotationOverlap
+	// when not set.
+	Overlap *time.Duration
+}
+
+// RotateApiKey replaces the key by a new one with the same label, scopes and expiry, the expiry being brought forward
+// to the credential's when that is sooner. The old key keeps working for the overlap window so the new secret can be
+// rolled out without downtime. Only credentials holding every scope of the key can rotate it.
+func (s *apiKeyService) RotateApiKey(ctx context.Context, input RotateApiKeyInput) (*CreateApiKeyResponse, error) {
+	apiKey, err := s.repo.GetByIDAndClientID(ctx, input.ID, input.ClientID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !coversApiKeyScopes(ctx, apiKey.Scopes) {
+		return nil, ErrApiKeyScopesExceeded
+	}
+
+	now := time.Now()
+	if !apiKey.IsActive(now) {
+		return nil, errors.New("only active api keys can be rotated")
+	}
+
+	before := auditSnapshot(transformations.DBApiKeyToRestApiKey(apiKey, nil))
+
+	replacement, secret, err := newApiKey(
+		apiKey.Label,
+		apiKey.Scopes,
+		credentialBoundExpiry(ctx, apiKey.ExpiresAt),
+		s.encryptionKey,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	apiKeyID := apiKey.ID.String()
+	replacement.ClientID = apiKey.ClientID
+	replacement.RotatedFromID = &apiKeyID
+	replacement.CredentialID = apiKey.CredentialID
+
+	overlap := DefaultApiKeyRotationOverlap
+	if input.Overlap != nil {
+		overlap = *input.Overlap
+	}
+
+	// the overlap never extends a key that was to expire sooner anyway.
+	overlapEnd := now.Add(overlap)
+	if apiKey.ExpiresAt == nil || overlapEnd.Before(*apiKey.ExpiresAt) {
+		apiKey.ExpiresAt = &overlapEnd
+	}
+
+	err = s.transactor.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.Rotate(ctx, apiKey, replacement); err != nil {
+			return err
+		}
+
+		if err := s.recordAuditEvent(ctx, "rotate", apiKey, before); err != nil {
+			return err
+		}
+
+		return s.recordAuditEvent(ctx, "create", replacement, nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &CreateApiKeyResponse{ApiKey: *replacement, Secret: secret}, nil
+}
+
+type GetApiKeyInput struct {
+	ClientID string
+	ID       string
+}
+
+// RevokeApiKey stops the key from authenticating straight away. The client's last active key can't be revoked,
+// that would lock it out for good, and neither can keys broader than the credential used.
+func (s *apiKeyService) RevokeApiKey(ctx context.Context, input GetApiKeyInput) (*models.ApiKey, error) {
+	apiKey, err := s.repo.GetByIDAndClientID(ctx, input.ID, input.ClientID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !coversApiKeyScopes(ctx, apiKey.Scopes) {
+		return nil, ErrApiKeyScopesExceeded
+	}
+
+	if apiKey.RevokedAt != nil {
+		return nil, errors.New("api key is already revoked")
+	}
+
+	if apiKey.IsActive(time.Now()) {
+		activeCount, err := s.repo.CountActiveByClientID(ctx, input.ClientID)
+		if err != nil {
+			return nil, err
+		}
+
+		if activeCount <= 1 {
+			return nil, errors.New("cannot revoke the last active api key, create another one first")
+		}
+	}
+
+	before := auditSnapshot(transformations.DBApiKeyToRestApiKey(apiKey, nil))
+
+	now := time.Now()
+	apiKey.RevokedAt = &now
+	err = s.transactor.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.Update(ctx, apiKey); err != nil {
+			return err
+		}
+
+		return s.recordAuditEvent(ctx, "revoke", apiKey, before)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return apiKey, nil
+}
+
+func (s *apiKeyService) GetApiKey(ctx context.Context, input GetApiKeyInput) (*models.ApiKey, error) {
+	return s.repo.GetByIDAndClientID(ctx, input.ID, input.ClientID)
+}
+
+func (s *apiKeyService) ListApiKeys(
+	ctx context.Context,
+	filterQuery lib.FilterQuery,
+	filters repository.ListApiKeysFilter,
+) ([]models.ApiKey, error) {
+	apiKeys, err := s.repo.List(ctx, filterQuery, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	return *apiKeys, nil
+}
+
+func (s *apiKeyService) CountApiKeys(
+	ctx context.Context,
+	filterQuery lib.FilterQuery,
+	filters repository.ListApiKeysFilter,
+) (int64, error) {
+	return s.repo.Count(ctx, filterQuery, filters)
+}
+
+// coversApiKeyScopes tells whether the credential of the request holds every scope of a key. A credential only
+// makes and manages keys no broader than itself, as rotating a key hands out a secret with all of its scopes.
+func coversApiKeyScopes(ctx context.Context, scopes []string) bool {
+	for _, scope := range scopes {
+		if !lib.HasScope(ctx, scope) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// credentialBoundExpiry brings expiresAt forward to the expiry of the credential of the request, so a key it makes
+// never outlives it.
+func credentialBoundExpiry(ctx context.Context, expiresAt *time.Time) *time.Time {
+	credentialExpiresAt, ok := lib.CredentialExpiryFromContext(ctx)
+	if !ok || (expiresAt != nil && !expiresAt.After(credentialExpiresAt)) {
+		return expiresAt
+	}
+
+	return &credentialExpiresAt
+}
+
+func (s *apiKeyService) recordAuditEvent(
+	ctx context.Context,
+	action string,
+	apiKey *models.ApiKey,
+	before *datatypes.JSON,
+) error {
+	return recordAuditEvent(ctx, s.auditEvent, auditEventInput{
+		ClientID:     apiKey.ClientID,
+		Action:       action,
+		ResourceType: "api_key",
+		ResourceID:   apiKey.ID.String(),
+		Before:       before,
+		After:        auditSnapshot(transformations.DBApiKeyToRestApiKey(apiKey, nil)),
+	})
+}
+
+// newApiKey makes a key with a new secret, only its hash and the signing key derived from it, encrypted with
+// encryptionKey, being kept. The secret is returned to be shown once.
+func newApiKey(
+	label string,
+	scopes []string,
+	expiresAt *time.Time,
+	encryptionKey []byte,
+) (*models.ApiKey, string, error) {
+	prefixBytes := make([]byte, 6)
+	secretBytes := make([]byte, 32)
+	for _, randomBytes := range [][]byte{prefixBytes, secretBytes} {
+		if _, err := rand.Read(randomBytes); err != nil {
+			raven.CaptureError(err, map[string]string{
+				"function": "newApiKey",
+				"action":   "generating random bytes",
+			})
+			return nil, "", err
+		}
+	}
+
+	prefix := apiKeyPrefix + hex.EncodeToString(prefixBytes)
+	secret := prefix + "." + base64.RawURLEncoding.EncodeToString(secretBytes) // URL-safe
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(secret), bcrypt.DefaultCost)
+	if err != nil {
+		raven.CaptureError(err, map[string]string{
+			"function": "newApiKey",
+			"action":   "hashing secret",
+		})
+		return nil, "", err
+	}
+
+	signingKey, err := lib.Encrypt(lib.RequestSigningKey(secret), encryptionKey)
+	if err != nil {
+		raven.CaptureError(err, map[string]string{
+			"function": "newApiKey",
+			"action":   "encrypting signing key",
+		})
+		return nil, "", err
+	}
+
+	// kept sorted and without duplicates, so keys with the same scopes list them the same.
+	scopes = slices.Compact(slices.Sorted(slices.Values(scopes)))
+
+	// a new key is a new credential, rotations carry it over.
+	id, err := uuid.NewV4()
+	if err != nil {
+		return nil, "", err
+	}
+
+	return &models.ApiKey{
+		BaseModelSoftDelete: models.BaseModelSoftDelete{BaseModel: models.BaseModel{ID: id}},
+		CredentialID:        id.String(),
+		Label:               label,
+		Prefix:              &prefix,
+		SecretHash:          string(hash),
+		SigningKey:          &signingKey,
+		Scopes:              scopes,
+		ExpiresAt:           expiresAt,
+	}, secret, nil
+}
+
+// verifyApiKeySecret checks plaintext secret against hashed version
+func verifyApiKeySecret(hash, secret string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(secret)) == nil
+}
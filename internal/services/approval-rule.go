@@ -0,0 +1,202 @@
+package services
+
+import (
+	"context"
+
+	"github.com/Bendomey/fincore-engine/internal/lib"
+	"github.com/Bendomey/fincore-engine/internal/models"
+	"github.com/Bendomey/fincore-engine/internal/repository"
+)
+
+type ApprovalRuleService interface {
+	CreateApprovalRule(ctx context.Context, input CreateApprovalRuleInput) (*models.ApprovalRule, error)
+	UpdateApprovalRule(ctx context.Context, input UpdateApprovalRuleInput) (*models.ApprovalRule, error)
+	DeleteApprovalRule(ctx context.Context, input GetApprovalRuleInput) error
+	GetApprovalRule(ctx context.Context, input GetApprovalRuleInput) (*models.ApprovalRule, error)
+	ListApprovalRules(
+		ctx context.Context,
+		filterQuery lib.FilterQuery,
+		filters repository.ListApprovalRulesFilter,
+	) ([]models.ApprovalRule, error)
+	CountApprovalRules(
+		ctx context.Context,
+		filterQuery lib.FilterQuery,
+		filters repository.ListApprovalRulesFilter,
+	) (int64, error)
+}
+
+type approvalRuleService struct {
+	repo    repository.ApprovalRuleRepository
+	account repository.AccountRepository
+}
+
+func NewApprovalRuleService(
+	repo repository.ApprovalRuleRepository,
+	account repository.AccountRepository,
+) ApprovalRuleService {
+	return &approvalRuleService{repo, account}
+}
+
+type CreateApprovalRuleInput struct {
+	ClientID  string
+	LedgerID  string
+	Name      string
+	MinAmount *int64
+	AccountID *string
+}
+
+func (s *approvalRuleService) CreateApprovalRule(
+	ctx context.Context,
+	input CreateApprovalRuleInput,
+) (*models.ApprovalRule, error) {
+	if input.AccountID != nil {
+		_, err := s.account.GetByIDAndLedgerID(ctx, *input.AccountID, input.LedgerID, nil)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	approvalRule := &models.ApprovalRule{
+		ClientID:  input.ClientID,
+		LedgerID:  input.LedgerID,
+		Name:      input.Name,
+		MinAmount: input.MinAmount,
+		AccountID: input.AccountID,
+		IsActive:  true,
+	}
+
+	err := s.repo.Create(ctx, approvalRule)
+	if err != nil {
+		return nil, err
+	}
+
+	return approvalRule, nil
+}
+
+type UpdateApprovalRuleInput struct {
+	ClientID  string
+	LedgerID  string
+	ID        string
+	Name      *string
+	MinAmount *int64
+	AccountID *string
+	IsActive  *bool
+}
+
+func (s *approvalRuleService) UpdateApprovalRule(
+	ctx context.Context,
+	input UpdateApprovalRuleInput,
+) (*models.ApprovalRule, error) {
+	approvalRule, err := s.repo.GetByIDAndLedgerID(ctx, input.ID, input.LedgerID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.AccountID != nil {
+		_, err := s.account.GetByIDAndLedgerID(ctx, *input.AccountID, input.LedgerID, nil)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if input.Name != nil {
+		approvalRule.Name = *input.Name
+	}
+
+	if input.IsActive != nil {
+		approvalRule.IsActive = *input.IsActive
+	}
+
+	if input.MinAmount != nil {
+		approvalRule.MinAmount = input.MinAmount
+	}
+
+	if input.AccountID != nil {
+		approvalRule.AccountID = input.AccountID
+	}
+
+	err = s.repo.Update(ctx, approvalRule)
+	if err != nil {
+		return nil, err
+	}
+
+	return approvalRule, nil
+}
+
+type GetApprovalRuleInput struct {
+	ClientID string
+	LedgerID string
+	ID       string
+	Populate *[]string
+}
+
+func (s *approvalRuleService) DeleteApprovalRule(ctx context.Context, input GetApprovalRuleInput) error {
+	approvalRule, err := s.repo.GetByIDAndLedgerID(ctx, input.ID, input.LedgerID, nil)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.Delete(ctx, approvalRule)
+}
+
+func (s *approvalRuleService) GetApprovalRule(
+	ctx context.Context,
+	input GetApprovalRuleInput,
+) (*models.ApprovalRule, error) {
+	return s.repo.GetByIDAndLedgerID(ctx, input.ID, input.LedgerID, input.Populate)
+}
+
+func (s *approvalRuleService) ListApprovalRules(
+	ctx context.Context,
+	filterQuery lib.FilterQuery,
+	filters repository.ListApprovalRulesFilter,
+) ([]models.ApprovalRule, error) {
+	approvalRules, err := s.repo.List(ctx, filterQuery, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	return *approvalRules, nil
+}
+
+func (s *approvalRuleService) CountApprovalRules(
+	ctx context.Context,
+	filterQuery lib.FilterQuery,
+	filters repository.ListApprovalRulesFilter,
+) (int64, error) {
+	return s.repo.Count(ctx, filterQuery, filters)
+}
+
+// requiresApproval reports whether any of the ledger's active approval rules matches the lines.
+func requiresApproval(
+	ctx context.Context,
+	approvalRuleRepo repository.ApprovalRuleRepository,
+	ledgerID string,
+	lines []models.JournalEntryLine,
+) (bool, error) {
+	approvalRules, err := approvalRuleRepo.ListActiveByLedgerID(ctx, ledgerID)
+	if err != nil {
+		return false, err
+	}
+
+	total := int64(0)
+	accounts := make(map[string]bool)
+	for _, line := range lines {
+		total += line.Debit
+		accounts[line.AccountID] = true
+	}
+
+	for _, approvalRule := range *approvalRules {
+		if approvalRule.MinAmount != nil && total < *approvalRule.MinAmount {
+			continue
+		}
+
+		if approvalRule.AccountID != nil && !accounts[*approvalRule.AccountID] {
+			continue
+		}
+
+		return true, nil
+	}
+
+	return false, nil
+}
@@ -0,0 +1,194 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/Bendomey/fincore-engine/internal/lib"
+	"github.com/Bendomey/fincore-engine/internal/models"
+	"github.com/Bendomey/fincore-engine/internal/repository"
+	"github.com/Bendomey/fincore-engine/internal/storage"
+	"github.com/gofrs/uuid"
+)
+
+type AttachmentService interface {
+	UploadAttachment(ctx context.Context, input UploadAttachmentInput) (*models.Attachment, error)
+	DeleteAttachment(ctx context.Context, input GetAttachmentInput) error
+	GetAttachment(ctx context.Context, input GetAttachmentInput) (*models.Attachment, error)
+	DownloadAttachment(ctx context.Context, input GetAttachmentInput) (*models.Attachment, io.ReadCloser, error)
+	ListAttachments(
+		ctx context.Context,
+		filterQuery lib.FilterQuery,
+		filters repository.ListAttachmentsFilter,
+	) ([]models.Attachment, error)
+	CountAttachments(
+		ctx context.Context,
+		filterQuery lib.FilterQuery,
+		filters repository.ListAttachmentsFilter,
+	) (int64, error)
+}
+
+type attachmentService struct {
+	repo         repository.AttachmentRepository
+	journalEntry repository.JournalEntryRepository
+	storage      storage.Storage
+}
+
+func NewAttachmentService(
+	repo repository.AttachmentRepository,
+	journalEntry repository.JournalEntryRepository,
+	storage storage.Storage,
+) AttachmentService {
+	return &attachmentService{repo, journalEntry, storage}
+}
+
+// getMutableJournalEntry fetches the entry, making sure its attachments can still change. Once an entry is
+// posted its supporting documents are part of the record.
+func (s *attachmentService) getMutableJournalEntry(
+	ctx context.Context,
+	journalEntryID string,
+	ledgerID string,
+) (*models.JournalEntry, error) {
+	entry, err := s.journalEntry.GetByIDAndLedgerID(ctx, journalEntryID, ledgerID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if entry.Status != models.JournalEntryStatusDraft && entry.Status != models.JournalEntryStatusPendingApproval {
+		return nil, fmt.Errorf("attachments of a %s journal entry cannot be changed", entry.Status)
+	}
+
+	return entry, nil
+}
+
+type UploadAttachmentInput struct {
+	ClientID       string
+	LedgerID       string
+	JournalEntryID string
+	FileName       string
+	ContentType    string
+	Content        io.Reader
+}
+
+func (s *attachmentService) UploadAttachment(
+	ctx context.Context,
+	input UploadAttachmentInput,
+) (*models.Attachment, error) {
+	entry, err := s.getMutableJournalEntry(ctx, input.JournalEntryID, input.LedgerID)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := io.ReadAll(input.Content)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(content) == 0 {
+		return nil, errors.New("attachment is empty")
+	}
+
+	id, err := uuid.NewV4()
+	if err != nil {
+		return nil, err
+	}
+
+	hash := sha256.Sum256(content)
+	attachment := models.Attachment{
+		ClientID:       input.ClientID,
+		JournalEntryID: entry.ID.String(),
+		FileName:       input.FileName,
+		ContentType:    input.ContentType,
+		Size:           int64(len(content)),
+		ContentHash:    hex.EncodeToString(hash[:]),
+		StorageKey:     fmt.Sprintf("%s/journal-entries/%s/%s", input.ClientID, entry.ID.String(), id.String()),
+	}
+	attachment.ID = id
+
+	err = s.storage.Put(ctx, attachment.StorageKey, content, attachment.ContentType)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.repo.Create(ctx, &attachment)
+	if err != nil {
+		// don't leave files behind that no attachment points to.
+		_ = s.storage.Delete(ctx, attachment.StorageKey)
+		return nil, err
+	}
+
+	return &attachment, nil
+}
+
+type GetAttachmentInput struct {
+	ClientID       string
+	LedgerID       string
+	JournalEntryID string
+	ID             string
+}
+
+// DeleteAttachment detaches the document from the entry. The stored file is kept with the soft deleted record.
+func (s *attachmentService) DeleteAttachment(ctx context.Context, input GetAttachmentInput) error {
+	_, err := s.getMutableJournalEntry(ctx, input.JournalEntryID, input.LedgerID)
+	if err != nil {
+		return err
+	}
+
+	attachment, err := s.repo.GetByIDAndEntryID(ctx, input.ID, input.JournalEntryID, nil)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.Delete(ctx, attachment)
+}
+
+func (s *attachmentService) GetAttachment(ctx context.Context, input GetAttachmentInput) (*models.Attachment, error) {
+	_, err := s.journalEntry.GetByIDAndLedgerID(ctx, input.JournalEntryID, input.LedgerID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetByIDAndEntryID(ctx, input.ID, input.JournalEntryID, nil)
+}
+
+func (s *attachmentService) DownloadAttachment(
+	ctx context.Context,
+	input GetAttachmentInput,
+) (*models.Attachment, io.ReadCloser, error) {
+	attachment, err := s.GetAttachment(ctx, input)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	content, err := s.storage.Get(ctx, attachment.StorageKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return attachment, content, nil
+}
+
+func (s *attachmentService) ListAttachments(
+	ctx context.Context,
+	filterQuery lib.FilterQuery,
+	filters repository.ListAttachmentsFilter,
+) ([]models.Attachment, error) {
+	attachments, err := s.repo.List(ctx, filterQuery, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	return *attachments, nil
+}
+
+func (s *attachmentService) CountAttachments(
+	ctx context.Context,
+	filterQuery lib.FilterQuery,
+	filters repository.ListAttachmentsFilter,
+) (int64, error) {
+	return s.repo.Count(ctx, filterQuery, filters)
+}
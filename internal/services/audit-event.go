@@ -0,0 +1,108 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/Bendomey/fincore-engine/internal/lib"
+	"github.com/Bendomey/fincore-engine/internal/models"
+	"github.com/Bendomey/fincore-engine/internal/repository"
+	"github.com/getsentry/raven-go"
+	"gorm.io/datatypes"
+)
+
+type AuditEventService interface {
+	ListAuditEvents(
+		ctx context.Context,
+		filterQuery lib.FilterQuery,
+		filters repository.ListAuditEventsFilter,
+	) ([]models.AuditEvent, error)
+	CountAuditEvents(
+		ctx context.Context,
+		filterQuery lib.FilterQuery,
+		filters repository.ListAuditEventsFilter,
+	) (int64, error)
+}
+
+type auditEventService struct {
+	repo repository.AuditEventRepository
+}
+
+func NewAuditEventService(repo repository.AuditEventRepository) AuditEventService {
+	return &auditEventService{repo}
+}
+
+func (s *auditEventService) ListAuditEvents(
+	ctx context.Context,
+	filterQuery lib.FilterQuery,
+	filters repository.ListAuditEventsFilter,
+) ([]models.AuditEvent, error) {
+	auditEvents, err := s.repo.List(ctx, filterQuery, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	return *auditEvents, nil
+}
+
+func (s *auditEventService) CountAuditEvents(
+	ctx context.Context,
+	filterQuery lib.FilterQuery,
+	filters repository.ListAuditEventsFilter,
+) (int64, error) {
+	return s.repo.Count(ctx, filterQuery, filters)
+}
+
+// auditSnapshot captures the resource as it is now, so later changes to it don't leak into the snapshot.
+func auditSnapshot(resource interface{}) *datatypes.JSON {
+	if resource == nil {
+		return nil
+	}
+
+	data, err := json.Marshal(resource)
+	if err != nil {
+		raven.CaptureError(err, map[string]string{"function": "auditSnapshot"})
+		return nil
+	}
+
+	snapshot := datatypes.JSON(data)
+	return &snapshot
+}
+
+type auditEventInput struct {
+	ClientID     string
+	LedgerID     string // empty for resources outside any ledger
+	Action       string
+	ResourceType string
+	ResourceID   string
+	Before       *datatypes.JSON
+	After        *datatypes.JSON
+}
+
+// recordAuditEvent appends an audit event for a mutation, attributing it to the credential and request in the
+// context. It is called within the mutation's transaction (see repository.Transactor), failing to record undoing
+// the mutation with it.
+func recordAuditEvent(ctx context.Context, repo repository.AuditEventRepository, input auditEventInput) error {
+	auditEvent := models.AuditEvent{
+		ClientID:     input.ClientID,
+		Action:       input.Action,
+		ResourceType: input.ResourceType,
+		ResourceID:   input.ResourceID,
+		Before:       input.Before,
+		After:        input.After,
+	}
+
+	if input.LedgerID != "" {
+		auditEvent.LedgerID = &input.LedgerID
+	}
+
+	if credentialID, ok := lib.CredentialFromContext(ctx); ok {
+		auditEvent.ActorID = &credentialID
+	}
+
+	if requestID, ok := lib.RequestIDFromContext(ctx); ok {
+		auditEvent.RequestID = &requestID
+	}
+
+	return repo.Create(ctx, &auditEvent)
+}
@@ -0,0 +1,466 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Bendomey/fincore-engine/internal/lib"
+	"github.com/Bendomey/fincore-engine/internal/models"
+	"github.com/Bendomey/fincore-engine/internal/repository"
+	"gorm.io/gorm"
+)
+
+type BudgetService interface {
+	CreateBudget(ctx context.Context, input CreateBudgetInput) (*models.Budget, error)
+	UpdateBudget(ctx context.Context, input UpdateBudgetInput) (*models.Budget, error)
+	DeleteBudget(ctx context.Context, input GetBudgetInput) error
+	GetBudget(ctx context.Context, input GetBudgetInput) (*models.Budget, error)
+	ListBudgets(
+		ctx context.Context,
+		filterQuery lib.FilterQuery,
+		filters repository.ListBudgetsFilter,
+	) ([]models.Budget, error)
+	CountBudgets(ctx context.Context, filterQuery lib.FilterQuery, filters repository.ListBudgetsFilter) (int64, error)
+
+	CreateBudgetLine(ctx context.Context, input CreateBudgetLineInput) (*models.BudgetLine, error)
+	UpdateBudgetLine(ctx context.Context, input UpdateBudgetLineInput) (*models.BudgetLine, error)
+	DeleteBudgetLine(ctx context.Context, input GetBudgetLineInput) error
+	UploadBudgetLines(ctx context.Context, input UploadBudgetLinesInput) ([]models.BudgetLine, error)
+	ListBudgetLines(
+		ctx context.Context,
+		input GetBudgetInput,
+		filterQuery lib.FilterQuery,
+		filters repository.ListBudgetLinesFilter,
+	) ([]models.BudgetLine, error)
+	CountBudgetLines(
+		ctx context.Context,
+		filterQuery lib.FilterQuery,
+		filters repository.ListBudgetLinesFilter,
+	) (int64, error)
+}
+
+type budgetService struct {
+	repo           repository.BudgetRepository
+	budgetLine     repository.BudgetLineRepository
+	account        repository.AccountRepository
+	dimensionType  repository.DimensionTypeRepository
+	dimensionValue repository.DimensionValueRepository
+}
+
+func NewBudgetService(
+	repo repository.BudgetRepository,
+	budgetLine repository.BudgetLineRepository,
+	account repository.AccountRepository,
+	dimensionType repository.DimensionTypeRepository,
+	dimensionValue repository.DimensionValueRepository,
+) BudgetService {
+	return &budgetService{repo, budgetLine, account, dimensionType, dimensionValue}
+}
+
+type CreateBudgetInput struct {
+	ClientID    string
+	Name        string
+	Description *string
+	Lines       []CreateBudgetLineInput
+}
+
+func (s *budgetService) CreateBudget(ctx context.Context, input CreateBudgetInput) (*models.Budget, error) {
+	lines := make([]models.BudgetLine, 0)
+	for _, line := range input.Lines {
+		budgetLine, err := s.buildBudgetLine(ctx, line)
+		if err != nil {
+			return nil, err
+		}
+
+		lines = append(lines, *budgetLine)
+	}
+
+	budget := models.Budget{
+		ClientID:    input.ClientID,
+		Name:        input.Name,
+		Description: input.Description,
+		BudgetLines: lines,
+	}
+
+	err := s.repo.Create(ctx, &budget)
+	if err != nil {
+		return nil, err
+	}
+
+	return &budget, nil
+}
+
+type UpdateBudgetInput struct {
+	ClientID    string
+	ID          string
+	Name        *string
+	Description *string
+}
+
+func (s *budgetService) UpdateBudget(ctx context.Context, input UpdateBudgetInput) (*models.Budget, error) {
+	budget, err := s.repo.GetByIDAndClientID(ctx, input.ID, input.ClientID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.Name != nil {
+		budget.Name = *input.Name
+	}
+
+	budget.Description = input.Description
+
+	err = s.repo.Update(ctx, budget)
+	if err != nil {
+		return nil, err
+	}
+
+	return budget, nil
+}
+
+type GetBudgetInput struct {
+	ClientID string
+	ID       string
+	Populate *[]string
+}
+
+func (s *budgetService) DeleteBudget(ctx context.Context, input GetBudgetInput) error {
+	budget, err := s.repo.GetByIDAndClientID(ctx, input.ID, input.ClientID, nil)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.Delete(ctx, budget)
+}
+
+func (s *budgetService) GetBudget(ctx context.Context, input GetBudgetInput) (*models.Budget, error) {
+	return s.repo.GetByIDAndClientID(ctx, input.ID, input.ClientID, input.Populate)
+}
+
+func (s *budgetService) ListBudgets(
+	ctx context.Context,
+	filterQuery lib.FilterQuery,
+	filters repository.ListBudgetsFilter,
+) ([]models.Budget, error) {
+	budgets, err := s.repo.List(ctx, filterQuery, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	return *budgets, nil
+}
+
+func (s *budgetService) CountBudgets(
+	ctx context.Context,
+	filterQuery lib.FilterQuery,
+	filters repository.ListBudgetsFilter,
+) (int64, error) {
+	return s.repo.Count(ctx, filterQuery, filters)
+}
+
+type CreateBudgetLineInput struct {
+	ClientID string
+	BudgetID string
+
+	// LedgerID is the ledger the account and dimension value are looked up in.
+	LedgerID         string
+	AccountID        string
+	DimensionValueID *string
+	PeriodStart      string
+	PeriodEnd        string
+	Amount           int64
+}
+
+// buildBudgetLine validates a budget line input against the ledger's accounts and dimensions.
+func (s *budgetService) buildBudgetLine(
+	ctx context.Context,
+	input CreateBudgetLineInput,
+) (*models.BudgetLine, error) {
+	periodStart, periodEnd, err := parseBudgetPeriod(input.PeriodStart, input.PeriodEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	account, err := s.account.GetByIDAndLedgerID(ctx, input.AccountID, input.LedgerID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.DimensionValueID != nil {
+		dimensionValue, dimensionValueErr := s.dimensionValue.GetByIDAndLedgerID(
+			ctx,
+			*input.DimensionValueID,
+			input.LedgerID,
+			nil,
+		)
+		if dimensionValueErr != nil {
+			return nil, dimensionValueErr
+		}
+
+		input.DimensionValueID = lib.NullOrString(dimensionValue.ID.String())
+	}
+
+	return &models.BudgetLine{
+		BudgetID:         input.BudgetID,
+		AccountID:        account.ID.String(),
+		DimensionValueID: input.DimensionValueID,
+		PeriodStart:      *periodStart,
+		PeriodEnd:        *periodEnd,
+		Amount:           input.Amount,
+	}, nil
+}
+
+func parseBudgetPeriod(start string, end string) (*time.Time, *time.Time, error) {
+	periodStart, err := time.Parse(time.DateOnly, start)
+	if err != nil {
+		return nil, nil, errors.New("invalid period start format, expected YYYY-MM-DD")
+	}
+
+	periodEnd, err := time.Parse(time.DateOnly, end)
+	if err != nil {
+		return nil, nil, errors.New("invalid period end format, expected YYYY-MM-DD")
+	}
+
+	if periodEnd.Before(periodStart) {
+		return nil, nil, errors.New("period end must not be before period start")
+	}
+
+	return &periodStart, &periodEnd, nil
+}
+
+func (s *budgetService) CreateBudgetLine(
+	ctx context.Context,
+	input CreateBudgetLineInput,
+) (*models.BudgetLine, error) {
+	budget, err := s.repo.GetByIDAndClientID(ctx, input.BudgetID, input.ClientID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	input.BudgetID = budget.ID.String()
+	budgetLine, err := s.buildBudgetLine(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.budgetLine.Create(ctx, budgetLine)
+	if err != nil {
+		return nil, err
+	}
+
+	return budgetLine, nil
+}
+
+type UpdateBudgetLineInput struct {
+	ClientID string
+	BudgetID string
+	ID       string
+	Amount   *int64
+}
+
+func (s *budgetService) UpdateBudgetLine(
+	ctx context.Context,
+	input UpdateBudgetLineInput,
+) (*models.BudgetLine, error) {
+	budgetLine, err := s.getBudgetLine(ctx, GetBudgetLineInput{
+		ClientID: input.ClientID,
+		BudgetID: input.BudgetID,
+		ID:       input.ID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if input.Amount != nil {
+		budgetLine.Amount = *input.Amount
+	}
+
+	err = s.budgetLine.Update(ctx, budgetLine)
+	if err != nil {
+		return nil, err
+	}
+
+	return budgetLine, nil
+}
+
+type GetBudgetLineInput struct {
+	ClientID string
+	BudgetID string
+	ID       string
+}
+
+func (s *budgetService) getBudgetLine(ctx context.Context, input GetBudgetLineInput) (*models.BudgetLine, error) {
+	budget, err := s.repo.GetByIDAndClientID(ctx, input.BudgetID, input.ClientID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.budgetLine.GetByIDAndBudgetID(ctx, input.ID, budget.ID.String(), nil)
+}
+
+func (s *budgetService) DeleteBudgetLine(ctx context.Context, input GetBudgetLineInput) error {
+	budgetLine, err := s.getBudgetLine(ctx, input)
+	if err != nil {
+		return err
+	}
+
+	return s.budgetLine.Delete(ctx, budgetLine)
+}
+
+type UploadBudgetLinesInput struct {
+	ClientID string
+	BudgetID string
+
+	// LedgerID is the ledger the account codes of the file are looked up in.
+	LedgerID string
+	File     io.Reader
+}
+
+// budgetCSVColumns are the columns a budget CSV upload understands. dimension and dimension_value are optional.
+var budgetCSVColumns = []string{"account_code", "period_start", "period_end", "amount", "dimension", "dimension_value"}
+
+// UploadBudgetLines reads budget lines from a CSV file with a header row. Existing lines for the same account,
+// dimension value and period get their amount replaced. Either every row is saved or none is.
+func (s *budgetService) UploadBudgetLines(
+	ctx context.Context,
+	input UploadBudgetLinesInput,
+) ([]models.BudgetLine, error) {
+	budget, err := s.repo.GetByIDAndClientID(ctx, input.BudgetID, input.ClientID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	reader := csv.NewReader(input.File)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("csv file is empty")
+		}
+
+		return nil, err
+	}
+
+	columns := make(map[string]int)
+	for index, column := range header {
+		columns[strings.ToLower(strings.TrimSpace(column))] = index
+	}
+
+	for _, column := range budgetCSVColumns[:4] {
+		if _, ok := columns[column]; !ok {
+			return nil, fmt.Errorf("csv file is missing the '%s' column", column)
+		}
+	}
+
+	value := func(record []string, column string) string {
+		index, ok := columns[column]
+		if !ok || index >= len(record) {
+			return ""
+		}
+
+		return strings.TrimSpace(record[index])
+	}
+
+	lines := make([]models.BudgetLine, 0)
+	for row := 2; ; row++ {
+		record, readErr := reader.Read()
+		if errors.Is(readErr, io.EOF) {
+			break
+		}
+
+		if readErr != nil {
+			return nil, fmt.Errorf("row %d: %v", row, readErr)
+		}
+
+		amount, amountErr := strconv.ParseInt(value(record, "amount"), 10, 64)
+		if amountErr != nil {
+			return nil, fmt.Errorf("row %d: amount must be a whole number in minor units", row)
+		}
+
+		account, accountErr := s.account.GetByCodeAndLedgerID(ctx, value(record, "account_code"), input.LedgerID)
+		if accountErr != nil {
+			if errors.Is(accountErr, gorm.ErrRecordNotFound) {
+				return nil, fmt.Errorf("row %d: unknown account code '%s'", row, value(record, "account_code"))
+			}
+
+			return nil, accountErr
+		}
+
+		lineInput := CreateBudgetLineInput{
+			ClientID:    input.ClientID,
+			BudgetID:    budget.ID.String(),
+			LedgerID:    input.LedgerID,
+			AccountID:   account.ID.String(),
+			PeriodStart: value(record, "period_start"),
+			PeriodEnd:   value(record, "period_end"),
+			Amount:      amount,
+		}
+
+		if dimension := value(record, "dimension"); dimension != "" {
+			dimensionValues, dimensionsErr := resolveLineDimensions(
+				ctx,
+				s.dimensionType,
+				s.dimensionValue,
+				input.LedgerID,
+				map[string]string{dimension: value(record, "dimension_value")},
+			)
+			if dimensionsErr != nil {
+				return nil, fmt.Errorf("row %d: %v", row, dimensionsErr)
+			}
+
+			lineInput.DimensionValueID = lib.NullOrString(dimensionValues[0].ID.String())
+		}
+
+		budgetLine, lineErr := s.buildBudgetLine(ctx, lineInput)
+		if lineErr != nil {
+			return nil, fmt.Errorf("row %d: %v", row, lineErr)
+		}
+
+		lines = append(lines, *budgetLine)
+	}
+
+	if len(lines) == 0 {
+		return nil, errors.New("csv file has no budget lines")
+	}
+
+	err = s.budgetLine.Upsert(ctx, lines)
+	if err != nil {
+		return nil, err
+	}
+
+	return lines, nil
+}
+
+func (s *budgetService) ListBudgetLines(
+	ctx context.Context,
+	input GetBudgetInput,
+	filterQuery lib.FilterQuery,
+	filters repository.ListBudgetLinesFilter,
+) ([]models.BudgetLine, error) {
+	budget, err := s.repo.GetByIDAndClientID(ctx, input.ID, input.ClientID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	filters.BudgetId = budget.ID.String()
+	budgetLines, err := s.budgetLine.List(ctx, filterQuery, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	return *budgetLines, nil
+}
+
+func (s *budgetService) CountBudgetLines(
+	ctx context.Context,
+	filterQuery lib.FilterQuery,
+	filters repository.ListBudgetLinesFilter,
+) (int64, error) {
+	return s.budgetLine.Count(ctx, filterQuery, filters)
+}
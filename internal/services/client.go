@@ -2,52 +2,37 @@ package services
 
 import (
 	"context"
-	"crypto/rand"
-	"encoding/base64"
 	"errors"
+	"fmt"
 
+	"github.com/Bendomey/fincore-engine/internal/lib"
 	"github.com/Bendomey/fincore-engine/internal/models"
 	"github.com/Bendomey/fincore-engine/internal/repository"
+	"github.com/Bendomey/fincore-engine/internal/transformations"
 	"github.com/getsentry/raven-go"
 	"github.com/gofrs/uuid"
-	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 )
 
 type ClientService interface {
-	AuthenticateClient(ctx context.Context, clientId string, clientSecret string) (*models.Client, error)
 	GetClient(ctx context.Context, clientId string) (*models.Client, error)
 	CreateClient(ctx context.Context, input CreateUserInput) (*CreateUserResponse, error)
 }
 
 type clientService struct {
-	repo repository.ClientRepository
+	repo          repository.ClientRepository
+	auditEvent    repository.AuditEventRepository
+	transactor    repository.Transactor
+	encryptionKey []byte
 }
 
-func NewClientService(repo repository.ClientRepository) ClientService {
-	return &clientService{repo}
-}
-
-func (s *clientService) AuthenticateClient(
-	ctx context.Context,
-	clientId string,
-	clientSecret string,
-) (*models.Client, error) {
-	client, err := s.repo.GetByClientID(ctx, clientId)
-	if err != nil {
-		return nil, err
-	}
-
-	if client == nil {
-		return nil, errors.New("invalid client credentials")
-	}
-
-	isVerified := VerifyClientSecret(client.ClientSecretHash, clientSecret)
-	if !isVerified {
-		return nil, errors.New("invalid client credentials")
-	}
-
-	return client, nil
+func NewClientService(
+	repo repository.ClientRepository,
+	auditEvent repository.AuditEventRepository,
+	transactor repository.Transactor,
+	encryptionKey []byte,
+) ClientService {
+	return &clientService{repo, auditEvent, transactor, encryptionKey}
 }
 
 func (s *clientService) GetClient(ctx context.Context, clientId string) (*models.Client, error) {
@@ -57,6 +42,9 @@ func (s *clientService) GetClient(ctx context.Context, clientId string) (*models
 type CreateUserInput struct {
 	Name  string
 	Email string
+
+	// Currency the client keeps its amounts in, lib.DefaultCurrency when not set.
+	Currency *string
 }
 
 type CreateUserResponse struct {
@@ -79,6 +67,15 @@ func (s *clientService) CreateClient(ctx context.Context, input CreateUserInput)
 		return nil, errors.New("email already in use")
 	}
 
+	currency := lib.DefaultCurrency
+	if input.Currency != nil {
+		if !lib.IsCurrency(*input.Currency) {
+			return nil, fmt.Errorf("%w %s", lib.ErrUnknownCurrency, *input.Currency)
+		}
+
+		currency = *input.Currency
+	}
+
 	uuidV4, err := uuid.NewV4()
 	if err != nil {
 		raven.CaptureError(err, map[string]string{
@@ -91,35 +88,39 @@ func (s *clientService) CreateClient(ctx context.Context, input CreateUserInput)
 
 	clientID := "c_" + uuidV4.String()
 
-	// Generate 32 random bytes for secret
-	secretBytes := make([]byte, 32)
-	if _, err := rand.Read(secretBytes); err != nil {
-		raven.CaptureError(err, map[string]string{
-			"function": "CreateClient",
-			"action":   "generating random bytes",
-		})
-		return nil, err
-	}
-
-	clientSecret := base64.RawURLEncoding.EncodeToString(secretBytes) // URL-safe
-
-	hash, err := bcrypt.GenerateFromPassword([]byte(clientSecret), bcrypt.DefaultCost)
+	// the client starts with one api key allowed everything, more can be made once authenticated.
+	apiKey, clientSecret, err := newApiKey("default", models.ApiKeyScopes, nil, s.encryptionKey)
 	if err != nil {
-		raven.CaptureError(err, map[string]string{
-			"function": "CreateClient",
-			"action":   "hashing random bytes",
-		})
 		return nil, err
 	}
 
 	client := &models.Client{
-		Name:             input.Name,
-		Email:            input.Email,
-		ClientId:         clientID,
-		ClientSecretHash: string(hash),
+		Name:     input.Name,
+		Email:    input.Email,
+		ClientId: clientID,
+		Currency: currency,
+		ApiKeys:  []models.ApiKey{*apiKey},
+
+		// the books the client works in until it makes more ledgers.
+		Ledgers: []models.Ledger{
+			{Name: "Default", Currency: currency, FiscalYearStartMonth: 1, IsDefault: true},
+		},
 	}
 
-	if err := s.repo.Create(ctx, client); err != nil {
+	err = s.transactor.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.Create(ctx, client); err != nil {
+			return err
+		}
+
+		return recordAuditEvent(ctx, s.auditEvent, auditEventInput{
+			ClientID:     client.ID.String(),
+			Action:       "create",
+			ResourceType: "client",
+			ResourceID:   client.ID.String(),
+			After:        auditSnapshot(transformations.DBClientToRestClient(client, nil)),
+		})
+	})
+	if err != nil {
 		return nil, err
 	}
 
@@ -128,8 +129,3 @@ func (s *clientService) CreateClient(ctx context.Context, input CreateUserInput)
 		Secret: clientSecret,
 	}, nil
 }
-
-// VerifyClientSecret checks plaintext secret against hashed version
-func VerifyClientSecret(hash, secret string) bool {
-	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(secret)) == nil
-}
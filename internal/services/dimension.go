@@ -0,0 +1,351 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/Bendomey/fincore-engine/internal/lib"
+	"github.com/Bendomey/fincore-engine/internal/models"
+	"github.com/Bendomey/fincore-engine/internal/repository"
+	"gorm.io/gorm"
+)
+
+type DimensionService interface {
+	CreateDimensionType(ctx context.Context, input CreateDimensionTypeInput) (*models.DimensionType, error)
+	UpdateDimensionType(ctx context.Context, input UpdateDimensionTypeInput) (*models.DimensionType, error)
+	DeleteDimensionType(ctx context.Context, input GetDimensionTypeInput) error
+	GetDimensionType(ctx context.Context, input GetDimensionTypeInput) (*models.DimensionType, error)
+	ListDimensionTypes(
+		ctx context.Context,
+		filterQuery lib.FilterQuery,
+		filters repository.ListDimensionTypesFilter,
+	) ([]models.DimensionType, error)
+	CountDimensionTypes(
+		ctx context.Context,
+		filterQuery lib.FilterQuery,
+		filters repository.ListDimensionTypesFilter,
+	) (int64, error)
+
+	CreateDimensionValue(ctx context.Context, input CreateDimensionValueInput) (*models.DimensionValue, error)
+	UpdateDimensionValue(ctx context.Context, input UpdateDimensionValueInput) (*models.DimensionValue, error)
+	DeleteDimensionValue(ctx context.Context, input GetDimensionValueInput) error
+	GetDimensionValue(ctx context.Context, input GetDimensionValueInput) (*models.DimensionValue, error)
+	ListDimensionValues(
+		ctx context.Context,
+		filterQuery lib.FilterQuery,
+		filters repository.ListDimensionValuesFilter,
+	) ([]models.DimensionValue, error)
+	CountDimensionValues(
+		ctx context.Context,
+		filterQuery lib.FilterQuery,
+		filters repository.ListDimensionValuesFilter,
+	) (int64, error)
+}
+
+type dimensionService struct {
+	repo      repository.DimensionTypeRepository
+	valueRepo repository.DimensionValueRepository
+}
+
+func NewDimensionService(
+	repo repository.DimensionTypeRepository,
+	valueRepo repository.DimensionValueRepository,
+) DimensionService {
+	return &dimensionService{repo, valueRepo}
+}
+
+type CreateDimensionTypeInput struct {
+	ClientID    string
+	LedgerID    string
+	Code        string
+	Name        string
+	Description *string
+}
+
+func (s *dimensionService) CreateDimensionType(
+	ctx context.Context,
+	input CreateDimensionTypeInput,
+) (*models.DimensionType, error) {
+	_, existingErr := s.repo.GetByCodeAndLedgerID(ctx, input.Code, input.LedgerID)
+	if existingErr == nil {
+		return nil, errors.New("dimension code already in use")
+	}
+
+	if !errors.Is(existingErr, gorm.ErrRecordNotFound) {
+		return nil, existingErr
+	}
+
+	dimensionType := &models.DimensionType{
+		ClientID:    input.ClientID,
+		LedgerID:    input.LedgerID,
+		Code:        input.Code,
+		Name:        input.Name,
+		Description: input.Description,
+	}
+
+	err := s.repo.Create(ctx, dimensionType)
+	if err != nil {
+		return nil, err
+	}
+
+	return dimensionType, nil
+}
+
+type UpdateDimensionTypeInput struct {
+	ClientID    string
+	LedgerID    string
+	ID          string
+	Name        *string
+	Description *string
+}
+
+func (s *dimensionService) UpdateDimensionType(
+	ctx context.Context,
+	input UpdateDimensionTypeInput,
+) (*models.DimensionType, error) {
+	dimensionType, err := s.repo.GetByIDAndLedgerID(ctx, input.ID, input.LedgerID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.Name != nil {
+		dimensionType.Name = *input.Name
+	}
+
+	dimensionType.Description = input.Description
+
+	err = s.repo.Update(ctx, dimensionType)
+	if err != nil {
+		return nil, err
+	}
+
+	return dimensionType, nil
+}
+
+type GetDimensionTypeInput struct {
+	ClientID string
+	LedgerID string
+	ID       string
+	Populate *[]string
+}
+
+func (s *dimensionService) DeleteDimensionType(ctx context.Context, input GetDimensionTypeInput) error {
+	dimensionType, err := s.repo.GetByIDAndLedgerID(ctx, input.ID, input.LedgerID, nil)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.Delete(ctx, dimensionType)
+}
+
+func (s *dimensionService) GetDimensionType(
+	ctx context.Context,
+	input GetDimensionTypeInput,
+) (*models.DimensionType, error) {
+	return s.repo.GetByIDAndLedgerID(ctx, input.ID, input.LedgerID, input.Populate)
+}
+
+func (s *dimensionService) ListDimensionTypes(
+	ctx context.Context,
+	filterQuery lib.FilterQuery,
+	filters repository.ListDimensionTypesFilter,
+) ([]models.DimensionType, error) {
+	dimensionTypes, err := s.repo.List(ctx, filterQuery, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	return *dimensionTypes, nil
+}
+
+func (s *dimensionService) CountDimensionTypes(
+	ctx context.Context,
+	filterQuery lib.FilterQuery,
+	filters repository.ListDimensionTypesFilter,
+) (int64, error) {
+	return s.repo.Count(ctx, filterQuery, filters)
+}
+
+type CreateDimensionValueInput struct {
+	ClientID        string
+	LedgerID        string
+	DimensionTypeID string
+	Code            string
+	Name            string
+	Description     *string
+}
+
+func (s *dimensionService) CreateDimensionValue(
+	ctx context.Context,
+	input CreateDimensionValueInput,
+) (*models.DimensionValue, error) {
+	dimensionType, err := s.repo.GetByIDAndLedgerID(ctx, input.DimensionTypeID, input.LedgerID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	_, existingErr := s.valueRepo.GetByCodeAndTypeID(ctx, input.Code, dimensionType.ID.String())
+	if existingErr == nil {
+		return nil, errors.New("dimension value code already in use")
+	}
+
+	if !errors.Is(existingErr, gorm.ErrRecordNotFound) {
+		return nil, existingErr
+	}
+
+	dimensionValue := &models.DimensionValue{
+		ClientID:        input.ClientID,
+		LedgerID:        dimensionType.LedgerID,
+		DimensionTypeID: dimensionType.ID.String(),
+		Code:            input.Code,
+		Name:            input.Name,
+		Description:     input.Description,
+		IsActive:        true,
+	}
+
+	err = s.valueRepo.Create(ctx, dimensionValue)
+	if err != nil {
+		return nil, err
+	}
+
+	return dimensionValue, nil
+}
+
+type UpdateDimensionValueInput struct {
+	ClientID        string
+	LedgerID        string
+	DimensionTypeID string
+	ID              string
+	Name            *string
+	Description     *string
+	IsActive        *bool
+}
+
+func (s *dimensionService) UpdateDimensionValue(
+	ctx context.Context,
+	input UpdateDimensionValueInput,
+) (*models.DimensionValue, error) {
+	dimensionValue, err := s.GetDimensionValue(ctx, GetDimensionValueInput{
+		ClientID:        input.ClientID,
+		LedgerID:        input.LedgerID,
+		DimensionTypeID: input.DimensionTypeID,
+		ID:              input.ID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if input.Name != nil {
+		dimensionValue.Name = *input.Name
+	}
+
+	if input.IsActive != nil {
+		dimensionValue.IsActive = *input.IsActive
+	}
+
+	dimensionValue.Description = input.Description
+
+	err = s.valueRepo.Update(ctx, dimensionValue)
+	if err != nil {
+		return nil, err
+	}
+
+	return dimensionValue, nil
+}
+
+type GetDimensionValueInput struct {
+	ClientID        string
+	LedgerID        string
+	DimensionTypeID string
+	ID              string
+	Populate        *[]string
+}
+
+func (s *dimensionService) DeleteDimensionValue(ctx context.Context, input GetDimensionValueInput) error {
+	dimensionValue, err := s.GetDimensionValue(ctx, input)
+	if err != nil {
+		return err
+	}
+
+	return s.valueRepo.Delete(ctx, dimensionValue)
+}
+
+func (s *dimensionService) GetDimensionValue(
+	ctx context.Context,
+	input GetDimensionValueInput,
+) (*models.DimensionValue, error) {
+	dimensionType, err := s.repo.GetByIDAndLedgerID(ctx, input.DimensionTypeID, input.LedgerID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.valueRepo.GetByIDAndTypeID(ctx, input.ID, dimensionType.ID.String(), input.Populate)
+}
+
+func (s *dimensionService) ListDimensionValues(
+	ctx context.Context,
+	filterQuery lib.FilterQuery,
+	filters repository.ListDimensionValuesFilter,
+) ([]models.DimensionValue, error) {
+	dimensionValues, err := s.valueRepo.List(ctx, filterQuery, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	return *dimensionValues, nil
+}
+
+func (s *dimensionService) CountDimensionValues(
+	ctx context.Context,
+	filterQuery lib.FilterQuery,
+	filters repository.ListDimensionValuesFilter,
+) (int64, error) {
+	return s.valueRepo.Count(ctx, filterQuery, filters)
+}
+
+// resolveLineDimensions validates a line's dimension tags (dimension code => value code) against the ledger's
+// dimensions and returns the matching values. Only active values can be used.
+func resolveLineDimensions(
+	ctx context.Context,
+	typeRepo repository.DimensionTypeRepository,
+	valueRepo repository.DimensionValueRepository,
+	ledgerID string,
+	tags map[string]string,
+) ([]models.DimensionValue, error) {
+	typeCodes := make([]string, 0, len(tags))
+	for typeCode := range tags {
+		typeCodes = append(typeCodes, typeCode)
+	}
+	sort.Strings(typeCodes)
+
+	values := make([]models.DimensionValue, 0, len(tags))
+	for _, typeCode := range typeCodes {
+		dimensionType, err := typeRepo.GetByCodeAndLedgerID(ctx, typeCode, ledgerID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, fmt.Errorf("unknown dimension '%s'", typeCode)
+			}
+
+			return nil, err
+		}
+
+		value, err := valueRepo.GetByCodeAndTypeID(ctx, tags[typeCode], dimensionType.ID.String())
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, fmt.Errorf("'%s' is not an allowed value for dimension '%s'", tags[typeCode], typeCode)
+			}
+
+			return nil, err
+		}
+
+		if !value.IsActive {
+			return nil, fmt.Errorf("value '%s' of dimension '%s' is inactive", tags[typeCode], typeCode)
+		}
+
+		values = append(values, *value)
+	}
+
+	return values, nil
+}
@@ -0,0 +1,53 @@
+package services
+
+import (
+	"context"
+
+	"github.com/Bendomey/fincore-engine/internal/models"
+	"github.com/Bendomey/fincore-engine/internal/repository"
+)
+
+// EventSubscriber tells a stream its client has new events, see events.Broker.
+type EventSubscriber interface {
+	Subscribe(clientID string) (<-chan struct{}, func())
+}
+
+// EventService reads the client's ledger events (the webhook outbox) for the event stream.
+type EventService interface {
+	// ListEventsAfter returns up to limit events past the cursor, oldest first.
+	ListEventsAfter(ctx context.Context, clientID string, afterSequence int64, limit int) ([]models.WebhookEvent, error)
+	// LatestEventSequence is the cursor of a stream that only wants events from now on.
+	LatestEventSequence(ctx context.Context, clientID string) (int64, error)
+	Subscribe(clientID string) (<-chan struct{}, func())
+}
+
+type eventService struct {
+	repo       repository.WebhookEventRepository
+	subscriber EventSubscriber
+}
+
+func NewEventService(repo repository.WebhookEventRepository, subscriber EventSubscriber) EventService {
+	return &eventService{repo, subscriber}
+}
+
+func (s *eventService) ListEventsAfter(
+	ctx context.Context,
+	clientID string,
+	afterSequence int64,
+	limit int,
+) ([]models.WebhookEvent, error) {
+	webhookEvents, err := s.repo.ListAfterSequence(ctx, clientID, afterSequence, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return *webhookEvents, nil
+}
+
+func (s *eventService) LatestEventSequence(ctx context.Context, clientID string) (int64, error) {
+	return s.repo.GetLatestSequence(ctx, clientID)
+}
+
+func (s *eventService) Subscribe(clientID string) (<-chan struct{}, func()) {
+	return s.subscriber.Subscribe(clientID)
+}
@@ -0,0 +1,300 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Bendomey/fincore-engine/internal/lib"
+	"github.com/Bendomey/fincore-engine/internal/models"
+	"github.com/Bendomey/fincore-engine/internal/repository"
+	"github.com/Bendomey/fincore-engine/internal/transformations"
+	"gorm.io/datatypes"
+)
+
+// HoldService places holds on account balances and settles them, by capturing them into a posted entry, voiding
+// them or letting them expire.
+type HoldService interface {
+	CreateHold(ctx context.Context, input CreateHoldInput) (*models.Hold, error)
+	CaptureHold(ctx context.Context, input CaptureHoldInput) (*models.Hold, error)
+	VoidHold(ctx context.Context, input GetHoldInput) (*models.Hold, error)
+	ExpireHolds(ctx context.Context) (int64, error)
+	GetHold(ctx context.Context, input GetHoldInput) (*models.Hold, error)
+	ListHolds(
+		ctx context.Context,
+		filterQuery lib.FilterQuery,
+		filters repository.ListHoldsFilter,
+	) ([]models.Hold, error)
+	CountHolds(
+		ctx context.Context,
+		filterQuery lib.FilterQuery,
+		filters repository.ListHoldsFilter,
+	) (int64, error)
+}
+
+type holdService struct {
+	repo         repository.HoldRepository
+	account      repository.AccountRepository
+	approvalRule repository.ApprovalRuleRepository
+	auditEvent   repository.AuditEventRepository
+	transactor   repository.Transactor
+}
+
+func NewHoldService(
+	repo repository.HoldRepository,
+	account repository.AccountRepository,
+	approvalRule repository.ApprovalRuleRepository,
+	auditEvent repository.AuditEventRepository,
+	transactor repository.Transactor,
+) HoldService {
+	return &holdService{repo, account, approvalRule, auditEvent, transactor}
+}
+
+type CreateHoldInput struct {
+	ClientID string
+	LedgerID string
+
+	AccountID            string
+	DestinationAccountID string
+
+	// Amount is in the minor unit of Currency, which must be the ledger's.
+	Amount    int64
+	Currency  string
+	Reference string
+	ExpiresAt *string
+	Metadata  *map[string]interface{}
+}
+
+func (s *holdService) CreateHold(ctx context.Context, input CreateHoldInput) (*models.Hold, error) {
+	// the accounts must be able to take the transfer the hold is captured into.
+	_, _, err := getTransferAccounts(ctx, s.account, input.LedgerID, input.AccountID, input.DestinationAccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	amount, err := lib.NewMoney(input.Amount, input.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	hold := models.Hold{
+		ClientID:             input.ClientID,
+		LedgerID:             input.LedgerID,
+		AccountID:            input.AccountID,
+		DestinationAccountID: input.DestinationAccountID,
+		Amount:               amount.Amount,
+		Currency:             amount.Currency,
+		Reference:            input.Reference,
+		Status:               models.HoldStatusPending,
+	}
+
+	if input.ExpiresAt != nil {
+		expiresAt, err := time.Parse(time.RFC3339, *input.ExpiresAt)
+		if err != nil {
+			return nil, errors.New("invalid expires_at format")
+		}
+
+		if !expiresAt.After(time.Now()) {
+			return nil, errors.New("expires_at must be in the future")
+		}
+
+		hold.ExpiresAt = &expiresAt
+	}
+
+	if input.Metadata != nil {
+		metadata, err := lib.InterfaceToJSON(*input.Metadata)
+		if err != nil {
+			return nil, errors.New("invalid metadata format")
+		}
+
+		hold.Metadata = metadata
+	}
+
+	err = s.transactor.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.Create(ctx, &hold); err != nil {
+			return err
+		}
+
+		return s.recordAuditEvent(ctx, "create", &hold, nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &hold, nil
+}
+
+type CaptureHoldInput struct {
+	ClientID string
+	LedgerID string
+	ActorID  string
+	ID       string
+
+	// Amount is the part of the hold to capture, the whole hold when nil. The rest is released.
+	Amount *int64
+}
+
+// CaptureHold posts the move from the held account to the destination account. Like any other entry it waits
+// for approval instead when it matches an approval rule, the hold staying in place until the entry is posted.
+func (s *holdService) CaptureHold(ctx context.Context, input CaptureHoldInput) (*models.Hold, error) {
+	hold, err := s.repo.GetByIDAndLedgerID(ctx, input.ID, input.LedgerID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if hold.JournalEntryID != nil && hold.Status == models.HoldStatusPending {
+		return nil, repository.ErrHoldCaptureAwaitingApproval
+	}
+
+	before := auditSnapshot(transformations.DBHoldToRestHold(hold, nil))
+
+	captured := lib.Money{Amount: hold.Amount, Currency: hold.Currency}
+	if input.Amount != nil {
+		if *input.Amount > hold.Amount {
+			return nil, errors.New("cannot capture more than the held amount")
+		}
+
+		captured.Amount = *input.Amount
+	}
+	hold.CapturedAmount = captured.Amount
+
+	from, to, err := getTransferAccounts(ctx, s.account, hold.LedgerID, hold.AccountID, hold.DestinationAccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := transferLines(from, to, captured)
+
+	journalEntry := models.JournalEntry{
+		ClientID:          input.ClientID,
+		LedgerID:          hold.LedgerID,
+		Currency:          captured.Currency,
+		Status:            models.JournalEntryStatusPosted,
+		Reference:         hold.Reference,
+		Metadata:          hold.Metadata,
+		CreatedBy:         &input.ActorID,
+		JournalEntryLines: lines,
+	}
+
+	needsApproval, err := requiresApproval(ctx, s.approvalRule, hold.LedgerID, lines)
+	if err != nil {
+		return nil, err
+	}
+
+	if needsApproval {
+		journalEntry.Status = models.JournalEntryStatusPendingApproval
+	} else {
+		now := time.Now()
+		journalEntry.PostedAt = &now
+	}
+
+	journalEntry.JournalEntryTransitions = []models.JournalEntryTransition{
+		{ToStatus: journalEntry.Status, ActorID: &input.ActorID},
+	}
+
+	err = s.transactor.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.Capture(ctx, hold, &journalEntry); err != nil {
+			return err
+		}
+
+		if err := s.recordAuditEvent(ctx, "capture", hold, before); err != nil {
+			return err
+		}
+
+		return recordAuditEvent(ctx, s.auditEvent, auditEventInput{
+			ClientID:     journalEntry.ClientID,
+			LedgerID:     journalEntry.LedgerID,
+			Action:       "create",
+			ResourceType: "journal_entry",
+			ResourceID:   journalEntry.ID.String(),
+			After:        auditSnapshot(transformations.DBJournalEntryToRestJournalEntry(&journalEntry, nil)),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return hold, nil
+}
+
+// VoidHold releases the hold without moving anything. A hold whose capture awaits approval is settled by
+// approving, rejecting or voiding the capture entry instead.
+func (s *holdService) VoidHold(ctx context.Context, input GetHoldInput) (*models.Hold, error) {
+	hold, err := s.repo.GetByIDAndLedgerID(ctx, input.ID, input.LedgerID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if hold.JournalEntryID != nil && hold.Status == models.HoldStatusPending {
+		return nil, repository.ErrHoldCaptureAwaitingApproval
+	}
+
+	before := auditSnapshot(transformations.DBHoldToRestHold(hold, nil))
+
+	err = s.transactor.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.Void(ctx, hold); err != nil {
+			return err
+		}
+
+		return s.recordAuditEvent(ctx, "void", hold, before)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return hold, nil
+}
+
+func (s *holdService) recordAuditEvent(
+	ctx context.Context,
+	action string,
+	hold *models.Hold,
+	before *datatypes.JSON,
+) error {
+	return recordAuditEvent(ctx, s.auditEvent, auditEventInput{
+		ClientID:     hold.ClientID,
+		LedgerID:     hold.LedgerID,
+		Action:       action,
+		ResourceType: "hold",
+		ResourceID:   hold.ID.String(),
+		Before:       before,
+		After:        auditSnapshot(transformations.DBHoldToRestHold(hold, nil)),
+	})
+}
+
+// ExpireHolds settles the status of the holds whose expiry has passed.
+func (s *holdService) ExpireHolds(ctx context.Context) (int64, error) {
+	return s.repo.ExpireDue(ctx, time.Now())
+}
+
+type GetHoldInput struct {
+	ClientID string
+	LedgerID string
+	ID       string
+	Populate *[]string
+}
+
+func (s *holdService) GetHold(ctx context.Context, input GetHoldInput) (*models.Hold, error) {
+	return s.repo.GetByIDAndLedgerID(ctx, input.ID, input.LedgerID, input.Populate)
+}
+
+func (s *holdService) ListHolds(
+	ctx context.Context,
+	filterQuery lib.FilterQuery,
+	filters repository.ListHoldsFilter,
+) ([]models.Hold, error) {
+	holds, err := s.repo.List(ctx, filterQuery, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	return *holds, nil
+}
+
+func (s *holdService) CountHolds(
+	ctx context.Context,
+	filterQuery lib.FilterQuery,
+	filters repository.ListHoldsFilter,
+) (int64, error) {
+	return s.repo.Count(ctx, filterQuery, filters)
+}
@@ -0,0 +1,102 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Bendomey/fincore-engine/internal/models"
+	"github.com/Bendomey/fincore-engine/internal/repository"
+)
+
+// how long a stored response is replayed for, after that the key can be used again.
+const idempotencyKeyTTL = 24 * time.Hour
+
+var (
+	ErrIdempotencyKeyReused     = errors.New("idempotency key was already used with a different request")
+	ErrIdempotencyKeyInProgress = errors.New("a request with this idempotency key is still in progress")
+)
+
+type IdempotencyKeyService interface {
+	// BeginIdempotentRequest claims the key for the request. It returns the stored key and false when the same
+	// request was already completed, its response should be replayed.
+	BeginIdempotentRequest(ctx context.Context, input BeginIdempotentRequestInput) (*models.IdempotencyKey, bool, error)
+	CompleteIdempotentRequest(ctx context.Context, input CompleteIdempotentRequestInput) error
+	ReleaseIdempotentRequest(ctx context.Context, idempotencyKey *models.IdempotencyKey) error
+}
+
+type idempotencyKeyService struct {
+	repo repository.IdempotencyKeyRepository
+}
+
+func NewIdempotencyKeyService(repo repository.IdempotencyKeyRepository) IdempotencyKeyService {
+	return &idempotencyKeyService{repo}
+}
+
+type BeginIdempotentRequestInput struct {
+	ClientID    string
+	Key         string
+	RequestHash string
+}
+
+func (s *idempotencyKeyService) BeginIdempotentRequest(
+	ctx context.Context,
+	input BeginIdempotentRequestInput,
+) (*models.IdempotencyKey, bool, error) {
+	idempotencyKey := models.IdempotencyKey{
+		ClientID:    input.ClientID,
+		Key:         input.Key,
+		RequestHash: input.RequestHash,
+		ExpiresAt:   time.Now().Add(idempotencyKeyTTL),
+	}
+
+	reserved, err := s.repo.Reserve(ctx, &idempotencyKey)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if reserved {
+		return &idempotencyKey, true, nil
+	}
+
+	existing, err := s.repo.GetByClientIDAndKey(ctx, input.ClientID, input.Key)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if existing.RequestHash != input.RequestHash {
+		return nil, false, ErrIdempotencyKeyReused
+	}
+
+	if existing.ResponseStatus == nil {
+		return nil, false, ErrIdempotencyKeyInProgress
+	}
+
+	return existing, false, nil
+}
+
+type CompleteIdempotentRequestInput struct {
+	IdempotencyKey *models.IdempotencyKey
+	Status         int
+	ContentType    string
+	Body           []byte
+}
+
+func (s *idempotencyKeyService) CompleteIdempotentRequest(
+	ctx context.Context,
+	input CompleteIdempotentRequestInput,
+) error {
+	input.IdempotencyKey.ResponseStatus = &input.Status
+	input.IdempotencyKey.ResponseContentType = &input.ContentType
+	input.IdempotencyKey.ResponseBody = input.Body
+
+	return s.repo.Update(ctx, input.IdempotencyKey)
+}
+
+// ReleaseIdempotentRequest frees the key when the request failed on our side, so a retry runs it again.
+func (s *idempotencyKeyService) ReleaseIdempotentRequest(
+	ctx context.Context,
+	idempotencyKey *models.IdempotencyKey,
+) error {
+	return s.repo.Delete(ctx, idempotencyKey)
+}
@@ -0,0 +1,52 @@
+package services
+
+import (
+	"context"
+
+	"github.com/Bendomey/fincore-engine/internal/lib"
+	"github.com/Bendomey/fincore-engine/internal/models"
+	"github.com/Bendomey/fincore-engine/internal/repository"
+)
+
+// JournalEntryLineService looks up lines across a client's journal entries.
+type JournalEntryLineService interface {
+	ListJournalEntryLines(
+		ctx context.Context,
+		filterQuery lib.FilterQuery,
+		filters repository.ListJournalEntryLinesFilter,
+	) ([]models.JournalEntryLine, error)
+	CountJournalEntryLines(
+		ctx context.Context,
+		filterQuery lib.FilterQuery,
+		filters repository.ListJournalEntryLinesFilter,
+	) (int64, error)
+}
+
+type journalEntryLineService struct {
+	repo repository.JournalEntryLineRepository
+}
+
+func NewJournalEntryLineService(repo repository.JournalEntryLineRepository) JournalEntryLineService {
+	return &journalEntryLineService{repo}
+}
+
+func (s *journalEntryLineService) ListJournalEntryLines(
+	ctx context.Context,
+	filterQuery lib.FilterQuery,
+	filters repository.ListJournalEntryLinesFilter,
+) ([]models.JournalEntryLine, error) {
+	journalEntryLines, err := s.repo.List(ctx, filterQuery, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	return *journalEntryLines, nil
+}
+
+func (s *journalEntryLineService) CountJournalEntryLines(
+	ctx context.Context,
+	filterQuery lib.FilterQuery,
+	filters repository.ListJournalEntryLinesFilter,
+) (int64, error) {
+	return s.repo.Count(ctx, filterQuery, filters)
+}
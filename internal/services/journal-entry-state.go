@@ -0,0 +1,78 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/Bendomey/fincore-engine/internal/models"
+)
+
+// journalEntryTransitions lists, for every journal entry status, the statuses it can move to.
+// POSTED entries are never edited, they can only be reversed by posting an opposite entry.
+var journalEntryTransitions = map[string][]string{
+	models.JournalEntryStatusDraft: {
+		models.JournalEntryStatusPendingApproval,
+		models.JournalEntryStatusPosted,
+		models.JournalEntryStatusVoided,
+	},
+	models.JournalEntryStatusPendingApproval: {
+		models.JournalEntryStatusDraft,
+		models.JournalEntryStatusPosted,
+		models.JournalEntryStatusVoided,
+	},
+	models.JournalEntryStatusPosted: {
+		models.JournalEntryStatusReversed,
+	},
+	models.JournalEntryStatusReversed: {},
+	models.JournalEntryStatusVoided:   {},
+}
+
+func canTransitionJournalEntry(from string, to string) bool {
+	return slices.Contains(journalEntryTransitions[from], to)
+}
+
+// newJournalEntryTransition validates moving the entry to the given status and applies it, returning the record
+// of the transition to persist alongside the entry.
+func newJournalEntryTransition(
+	entry *models.JournalEntry,
+	to string,
+	actorID string,
+	reason *string,
+) (*models.JournalEntryTransition, error) {
+	from := entry.Status
+	if !canTransitionJournalEntry(from, to) {
+		return nil, fmt.Errorf("journal entry cannot move from %s to %s", from, to)
+	}
+
+	entry.Status = to
+	if to == models.JournalEntryStatusPosted {
+		now := time.Now()
+		entry.PostedAt = &now
+	}
+
+	return &models.JournalEntryTransition{
+		JournalEntryID: entry.ID.String(),
+		FromStatus:     &from,
+		ToStatus:       to,
+		ActorID:        &actorID,
+		Reason:         reason,
+	}, nil
+}
+
+// transition moves the entry to the given status and records who did it.
+func (s *journalEntryService) transition(
+	ctx context.Context,
+	entry *models.JournalEntry,
+	to string,
+	actorID string,
+	reason *string,
+) error {
+	transition, err := newJournalEntryTransition(entry, to, actorID, reason)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.Transition(ctx, entry, transition)
+}
@@ -3,11 +3,14 @@ package services
 import (
 	"context"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/Bendomey/fincore-engine/internal/lib"
 	"github.com/Bendomey/fincore-engine/internal/models"
 	"github.com/Bendomey/fincore-engine/internal/repository"
+	"github.com/Bendomey/fincore-engine/internal/transformations"
+	"gorm.io/datatypes"
 )
 
 type JournalEntryService interface {
@@ -17,8 +20,12 @@ type JournalEntryService interface {
 		journalEntryId string,
 		input UpdateJournalEntryInput,
 	) (*models.JournalEntry, error)
-	PostJournalEntry(ctx context.Context, input GetJournalEntryInput) (*models.JournalEntry, error)
-	DeleteJournalEntry(ctx context.Context, input GetJournalEntryInput) error
+	SubmitJournalEntry(ctx context.Context, input TransitionJournalEntryInput) (*models.JournalEntry, error)
+	PostJournalEntry(ctx context.Context, input TransitionJournalEntryInput) (*models.JournalEntry, error)
+	ApproveJournalEntry(ctx context.Context, input TransitionJournalEntryInput) (*models.JournalEntry, error)
+	RejectJournalEntry(ctx context.Context, input TransitionJournalEntryInput) (*models.JournalEntry, error)
+	VoidJournalEntry(ctx context.Context, input TransitionJournalEntryInput) (*models.JournalEntry, error)
+	ReverseJournalEntry(ctx context.Context, input TransitionJournalEntryInput) (*models.JournalEntry, error)
 	GetJournalEntry(ctx context.Context, input GetJournalEntryInput) (*models.JournalEntry, error)
 	ListJournalEntries(
 		ctx context.Context,
@@ -33,28 +40,46 @@ type JournalEntryService interface {
 }
 
 type journalEntryService struct {
-	repo      repository.JournalEntryRepository
-	account   repository.AccountRepository
-	entryLine repository.JournalEntryLineRepository
+	repo           repository.JournalEntryRepository
+	account        repository.AccountRepository
+	entryLine      repository.JournalEntryLineRepository
+	dimensionType  repository.DimensionTypeRepository
+	dimensionValue repository.DimensionValueRepository
+	approvalRule   repository.ApprovalRuleRepository
+	auditEvent     repository.AuditEventRepository
+	transactor     repository.Transactor
 }
 
 func NewJournalEntryService(
 	repo repository.JournalEntryRepository,
 	account repository.AccountRepository,
 	entryLine repository.JournalEntryLineRepository,
+	dimensionType repository.DimensionTypeRepository,
+	dimensionValue repository.DimensionValueRepository,
+	approvalRule repository.ApprovalRuleRepository,
+	auditEvent repository.AuditEventRepository,
+	transactor repository.Transactor,
 ) JournalEntryService {
-	return &journalEntryService{repo, account, entryLine}
+	return &journalEntryService{
+		repo, account, entryLine, dimensionType, dimensionValue, approvalRule, auditEvent, transactor,
+	}
 }
 
 type CreateJournalEntryLineInput struct {
-	AccountID string
-	Notes     *string
-	Debit     int64
-	Credit    int64
+	AccountID  string
+	Notes      *string
+	Debit      int64
+	Credit     int64
+	Dimensions map[string]string
 }
 
 type CreateJournalEntryInput struct {
 	ClientID string
+	LedgerID string
+	ActorID  string
+
+	// Currency of the line amounts, which must be the ledger's.
+	Currency string
 
 	Status          string
 	Reference       string
@@ -70,29 +95,54 @@ func (s *journalEntryService) CreateJournalEntry(
 	// create journal entry and lines in a transaction
 	lines := make([]models.JournalEntryLine, 0)
 	for _, line := range input.Lines {
+		dimensionValues, dimensionsErr := resolveLineDimensions(
+			ctx,
+			s.dimensionType,
+			s.dimensionValue,
+			input.LedgerID,
+			line.Dimensions,
+		)
+		if dimensionsErr != nil {
+			return nil, dimensionsErr
+		}
+
 		lines = append(lines, models.JournalEntryLine{
-			AccountID: line.AccountID,
-			Notes:     line.Notes,
-			Debit:     line.Debit,
-			Credit:    line.Credit,
+			AccountID:       line.AccountID,
+			Notes:           line.Notes,
+			Debit:           line.Debit,
+			Credit:          line.Credit,
+			DimensionValues: dimensionValues,
 		})
 	}
 
-	validateLinesErr := validateLines(s.account, ctx, lines)
+	validateLinesErr := validateLines(s.account, ctx, input.LedgerID, input.Currency, lines)
 	if validateLinesErr != nil {
 		return nil, validateLinesErr
 	}
 
 	journalEntry := models.JournalEntry{
 		ClientID:          input.ClientID,
+		LedgerID:          input.LedgerID,
+		Currency:          input.Currency,
 		Status:            input.Status,
 		Reference:         input.Reference,
+		CreatedBy:         &input.ActorID,
 		JournalEntryLines: lines,
 	}
 
-	if input.Status == "POSTED" {
-		now := time.Now()
-		journalEntry.PostedAt = &now
+	if input.Status == models.JournalEntryStatusPosted {
+		// entries matching an approval rule wait for a second credential instead of posting straight away.
+		needsApproval, err := requiresApproval(ctx, s.approvalRule, input.LedgerID, lines)
+		if err != nil {
+			return nil, err
+		}
+
+		if needsApproval {
+			journalEntry.Status = models.JournalEntryStatusPendingApproval
+		} else {
+			now := time.Now()
+			journalEntry.PostedAt = &now
+		}
 	}
 
 	if input.TransactionDate != nil {
@@ -113,7 +163,17 @@ func (s *journalEntryService) CreateJournalEntry(
 		journalEntry.Metadata = metadata
 	}
 
-	err := s.repo.Create(ctx, &journalEntry)
+	journalEntry.JournalEntryTransitions = []models.JournalEntryTransition{
+		{ToStatus: journalEntry.Status, ActorID: &input.ActorID},
+	}
+
+	err := s.transactor.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.Create(ctx, &journalEntry); err != nil {
+			return err
+		}
+
+		return s.recordAuditEvent(ctx, "create", &journalEntry, nil)
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -124,27 +184,41 @@ func (s *journalEntryService) CreateJournalEntry(
 func validateLines(
 	accountRepo repository.AccountRepository,
 	ctx context.Context,
+	ledgerID string,
+	currency string,
 	lines []models.JournalEntryLine,
 ) error {
-	// make sure debits equal credits
-	debitTotal := int64(0)
-	creditTotal := int64(0)
+	// make sure debits equal credits, totals too large to be kept are refused rather than wrapped around.
+	debitTotal, err := lib.NewMoney(0, currency)
+	if err != nil {
+		return err
+	}
+	creditTotal := debitTotal
 
 	for _, line := range lines {
-		debitTotal += line.Debit
-		creditTotal += line.Credit
+		if debitTotal, err = debitTotal.Add(lib.Money{Amount: line.Debit, Currency: currency}); err != nil {
+			return err
+		}
+
+		if creditTotal, err = creditTotal.Add(lib.Money{Amount: line.Credit, Currency: currency}); err != nil {
+			return err
+		}
 	}
 
 	if debitTotal != creditTotal {
 		return errors.New("debit and credit totals must be equal")
 	}
 
-	// make sure accounts exist and belong to the client
+	// make sure accounts exist and belong to the entry's ledger
 	for _, line := range lines {
-		_, err := accountRepo.GetByID(ctx, line.AccountID, nil)
+		account, err := accountRepo.GetByID(ctx, line.AccountID, nil)
 		if err != nil {
 			return err
 		}
+
+		if account.LedgerID != ledgerID {
+			return fmt.Errorf("account %s is not in the entry's ledger", account.Code)
+		}
 	}
 
 	return nil
@@ -156,10 +230,17 @@ type UpdateJournalEntryLineInput struct {
 	Notes     *string
 	Debit     *int64
 	Credit    *int64
+
+	// Dimensions replaces the line's dimension tags when set.
+	Dimensions *map[string]string
 }
 
 type UpdateJournalEntryInput struct {
 	ClientID string
+	LedgerID string
+
+	// Version is the version the caller last read (If-Match), the update is refused when the entry moved on.
+	Version int64
 
 	ID              string
 	Reference       *string
@@ -173,15 +254,26 @@ func (s *journalEntryService) UpdateJournalEntry(
 	journalEntryId string,
 	input UpdateJournalEntryInput,
 ) (*models.JournalEntry, error) {
-	entry, err := s.repo.GetByIDAndClientID(ctx, input.ID, input.ClientID, nil)
+	entry, err := s.repo.GetByIDAndLedgerID(ctx, input.ID, input.LedgerID, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	if entry.Status == "POSTED" {
-		return nil, errors.New("journal entry is already posted")
+	if entry.Version != input.Version {
+		return nil, repository.ErrVersionConflict
+	}
+
+	if entry.Status != models.JournalEntryStatusDraft {
+		return nil, errors.New("only draft journal entries can be updated")
 	}
 
+	current, err := s.getJournalEntryWithLines(ctx, entry)
+	if err != nil {
+		return nil, err
+	}
+
+	before := auditSnapshot(transformations.DBJournalEntryToRestJournalEntry(current, nil))
+
 	if input.Reference != nil {
 		entry.Reference = *input.Reference
 	}
@@ -204,10 +296,29 @@ func (s *journalEntryService) UpdateJournalEntry(
 		entry.Metadata = metadata
 	}
 
+	lineUpdates := make([]repository.JournalEntryLineUpdate, 0)
 	if input.Lines != nil && len(*input.Lines) > 0 {
 		// fetch existing lines and create new lines(without ID)
 		lines := make([]models.JournalEntryLine, 0)
+		linesDimensions := make([]*[]models.DimensionValue, 0)
 		for _, line := range *input.Lines {
+			var dimensionValues *[]models.DimensionValue
+			if line.Dimensions != nil {
+				values, dimensionsErr := resolveLineDimensions(
+					ctx,
+					s.dimensionType,
+					s.dimensionValue,
+					entry.LedgerID,
+					*line.Dimensions,
+				)
+				if dimensionsErr != nil {
+					return nil, dimensionsErr
+				}
+
+				dimensionValues = &values
+			}
+			linesDimensions = append(linesDimensions, dimensionValues)
+
 			if line.ID != nil {
 				lineEntry, err := s.entryLine.GetByIDAndEntryID(ctx, *line.ID, input.ID, nil)
 				if err != nil {
@@ -239,6 +350,7 @@ func (s *journalEntryService) UpdateJournalEntry(
 					Notes:          line.Notes,
 					Debit:          *line.Debit,
 					Credit:         *line.Credit,
+					Currency:       entry.Currency,
 					JournalEntryID: input.ID,
 				}
 
@@ -247,53 +359,167 @@ func (s *journalEntryService) UpdateJournalEntry(
 		}
 
 		// validate lines
-		validateLinesErr := validateLines(s.account, ctx, lines)
+		validateLinesErr := validateLines(s.account, ctx, entry.LedgerID, entry.Currency, lines)
 		if validateLinesErr != nil {
 			return nil, validateLinesErr
 		}
 
-		// save lines
-		for _, line := range lines {
-			err = s.entryLine.Update(ctx, &line)
-			if err != nil {
-				return nil, err
-			}
+		for index := range lines {
+			lineUpdates = append(lineUpdates, repository.JournalEntryLineUpdate{
+				Line:       &lines[index],
+				Dimensions: linesDimensions[index],
+			})
 		}
 	}
 
-	// save entry.
-	err = s.repo.Update(ctx, entry)
+	var updated *models.JournalEntry
+	err = s.transactor.Transaction(ctx, func(ctx context.Context) error {
+		// save entry and lines.
+		if err := s.repo.Update(ctx, entry, lineUpdates); err != nil {
+			return err
+		}
+
+		var err error
+		updated, err = s.getJournalEntryWithLines(ctx, entry)
+		if err != nil {
+			return err
+		}
+
+		return s.recordAuditEvent(ctx, "update", updated, before)
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	return entry, nil
+	// the stored entry, with the lines and version it now has.
+	return updated, nil
 }
 
 type GetJournalEntryInput struct {
 	ClientID string
+	LedgerID string
 	ID       string
 	Populate *[]string
 }
 
+type TransitionJournalEntryInput struct {
+	ClientID string
+	LedgerID string
+	ID       string
+	ActorID  string
+	Reason   *string
+}
+
+func (s *journalEntryService) SubmitJournalEntry(
+	ctx context.Context,
+	input TransitionJournalEntryInput,
+) (*models.JournalEntry, error) {
+	entry, err := s.repo.GetByIDAndLedgerID(ctx, input.ID, input.LedgerID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if entry.Status != models.JournalEntryStatusDraft {
+		return nil, errors.New("only draft journal entries can be submitted for approval")
+	}
+
+	before := auditSnapshot(transformations.DBJournalEntryToRestJournalEntry(entry, nil))
+
+	err = s.transactor.Transaction(ctx, func(ctx context.Context) error {
+		err := s.transition(ctx, entry, models.JournalEntryStatusPendingApproval, input.ActorID, input.Reason)
+		if err != nil {
+			return err
+		}
+
+		return s.recordAuditEvent(ctx, "submit", entry, before)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
 func (s *journalEntryService) PostJournalEntry(
 	ctx context.Context,
-	input GetJournalEntryInput,
+	input TransitionJournalEntryInput,
 ) (*models.JournalEntry, error) {
-	entry, err := s.repo.GetByIDAndClientID(ctx, input.ID, input.ClientID, input.Populate)
+	entry, err := s.repo.GetByIDAndLedgerID(ctx, input.ID, input.LedgerID, &[]string{"JournalEntryLines"})
 	if err != nil {
 		return nil, err
 	}
 
-	if entry.Status == "POSTED" {
+	switch entry.Status {
+	case models.JournalEntryStatusPosted:
 		return nil, errors.New("journal entry is already posted")
+	case models.JournalEntryStatusPendingApproval:
+		return s.approve(ctx, entry, input.ActorID)
+	}
+
+	needsApproval, err := requiresApproval(ctx, s.approvalRule, entry.LedgerID, entry.JournalEntryLines)
+	if err != nil {
+		return nil, err
+	}
+
+	if needsApproval {
+		return nil, errors.New("journal entry requires approval, submit it for approval instead")
+	}
+
+	before := auditSnapshot(transformations.DBJournalEntryToRestJournalEntry(entry, nil))
+
+	err = s.transactor.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.transition(ctx, entry, models.JournalEntryStatusPosted, input.ActorID, input.Reason); err != nil {
+			return err
+		}
+
+		return s.recordAuditEvent(ctx, "post", entry, before)
+	})
+	if err != nil {
+		return nil, err
 	}
 
+	return entry, nil
+}
+
+func (s *journalEntryService) ApproveJournalEntry(
+	ctx context.Context,
+	input TransitionJournalEntryInput,
+) (*models.JournalEntry, error) {
+	entry, err := s.repo.GetByIDAndLedgerID(ctx, input.ID, input.LedgerID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if entry.Status != models.JournalEntryStatusPendingApproval {
+		return nil, errors.New("journal entry is not pending approval")
+	}
+
+	return s.approve(ctx, entry, input.ActorID)
+}
+
+// approve posts a pending entry on behalf of a credential other than the one that created it.
+func (s *journalEntryService) approve(
+	ctx context.Context,
+	entry *models.JournalEntry,
+	actorID string,
+) (*models.JournalEntry, error) {
+	if entry.CreatedBy != nil && *entry.CreatedBy == actorID {
+		return nil, errors.New("journal entry must be approved by a different credential than its creator")
+	}
+
+	before := auditSnapshot(transformations.DBJournalEntryToRestJournalEntry(entry, nil))
+
 	now := time.Now()
-	entry.Status = "POSTED"
-	entry.PostedAt = &now
+	entry.ApprovedBy = &actorID
+	entry.ApprovedAt = &now
+
+	err := s.transactor.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.transition(ctx, entry, models.JournalEntryStatusPosted, actorID, nil); err != nil {
+			return err
+		}
 
-	err = s.repo.Update(ctx, entry)
+		return s.recordAuditEvent(ctx, "approve", entry, before)
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -301,24 +527,173 @@ func (s *journalEntryService) PostJournalEntry(
 	return entry, nil
 }
 
-func (s *journalEntryService) DeleteJournalEntry(ctx context.Context, input GetJournalEntryInput) error {
-	entry, err := s.repo.GetByIDAndClientID(ctx, input.ID, input.ClientID, nil)
+func (s *journalEntryService) RejectJournalEntry(
+	ctx context.Context,
+	input TransitionJournalEntryInput,
+) (*models.JournalEntry, error) {
+	entry, err := s.repo.GetByIDAndLedgerID(ctx, input.ID, input.LedgerID, nil)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	if entry.Status == "POSTED" {
-		return errors.New("journal entry is already posted")
+	if entry.Status != models.JournalEntryStatusPendingApproval {
+		return nil, errors.New("journal entry is not pending approval")
 	}
 
-	return s.repo.Delete(ctx, entry)
+	before := auditSnapshot(transformations.DBJournalEntryToRestJournalEntry(entry, nil))
+
+	// rejected entries go back to draft so the maker can correct and resubmit them.
+	now := time.Now()
+	entry.RejectedBy = &input.ActorID
+	entry.RejectedAt = &now
+	entry.RejectionReason = input.Reason
+
+	err = s.transactor.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.transition(ctx, entry, models.JournalEntryStatusDraft, input.ActorID, input.Reason); err != nil {
+			return err
+		}
+
+		return s.recordAuditEvent(ctx, "reject", entry, before)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+// VoidJournalEntry cancels an entry that was never posted. The entry is kept, with its history, for audit.
+func (s *journalEntryService) VoidJournalEntry(
+	ctx context.Context,
+	input TransitionJournalEntryInput,
+) (*models.JournalEntry, error) {
+	entry, err := s.repo.GetByIDAndLedgerID(ctx, input.ID, input.LedgerID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	before := auditSnapshot(transformations.DBJournalEntryToRestJournalEntry(entry, nil))
+
+	err = s.transactor.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.transition(ctx, entry, models.JournalEntryStatusVoided, input.ActorID, input.Reason); err != nil {
+			return err
+		}
+
+		return s.recordAuditEvent(ctx, "void", entry, before)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+// ReverseJournalEntry marks a posted entry as reversed and posts a new entry with debits and credits swapped,
+// returning the reversing entry.
+func (s *journalEntryService) ReverseJournalEntry(
+	ctx context.Context,
+	input TransitionJournalEntryInput,
+) (*models.JournalEntry, error) {
+	entry, err := s.repo.GetByIDAndLedgerID(
+		ctx,
+		input.ID,
+		input.LedgerID,
+		&[]string{"JournalEntryLines", "JournalEntryLines.DimensionValues"},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	before := auditSnapshot(transformations.DBJournalEntryToRestJournalEntry(entry, nil))
+
+	transition, err := newJournalEntryTransition(entry, models.JournalEntryStatusReversed, input.ActorID, input.Reason)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := make([]models.JournalEntryLine, 0)
+	for _, line := range entry.JournalEntryLines {
+		lines = append(lines, models.JournalEntryLine{
+			AccountID:       line.AccountID,
+			Notes:           line.Notes,
+			Debit:           line.Credit,
+			Credit:          line.Debit,
+			Currency:        line.Currency,
+			DimensionValues: line.DimensionValues,
+		})
+	}
+
+	now := time.Now()
+	entryID := entry.ID.String()
+	reversal := models.JournalEntry{
+		ClientID:          entry.ClientID,
+		LedgerID:          entry.LedgerID,
+		Currency:          entry.Currency,
+		Status:            models.JournalEntryStatusPosted,
+		PostedAt:          &now,
+		Reference:         "REV-" + entry.Reference,
+		TransactionDate:   now,
+		CreatedBy:         &input.ActorID,
+		ReversalOfID:      &entryID,
+		JournalEntryLines: lines,
+		JournalEntryTransitions: []models.JournalEntryTransition{
+			{ToStatus: models.JournalEntryStatusPosted, ActorID: &input.ActorID, Reason: input.Reason},
+		},
+	}
+
+	err = s.transactor.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.Reverse(ctx, entry, transition, &reversal); err != nil {
+			return err
+		}
+
+		if err := s.recordAuditEvent(ctx, "reverse", entry, before); err != nil {
+			return err
+		}
+
+		return s.recordAuditEvent(ctx, "create", &reversal, nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &reversal, nil
+}
+
+// getJournalEntryWithLines reloads the entry together with its current lines, for its audit snapshots.
+func (s *journalEntryService) getJournalEntryWithLines(
+	ctx context.Context,
+	entry *models.JournalEntry,
+) (*models.JournalEntry, error) {
+	return s.repo.GetByIDAndLedgerID(
+		ctx,
+		entry.ID.String(),
+		entry.LedgerID,
+		&[]string{"JournalEntryLines", "JournalEntryLines.DimensionValues"},
+	)
+}
+
+func (s *journalEntryService) recordAuditEvent(
+	ctx context.Context,
+	action string,
+	entry *models.JournalEntry,
+	before *datatypes.JSON,
+) error {
+	return recordAuditEvent(ctx, s.auditEvent, auditEventInput{
+		ClientID:     entry.ClientID,
+		LedgerID:     entry.LedgerID,
+		Action:       action,
+		ResourceType: "journal_entry",
+		ResourceID:   entry.ID.String(),
+		Before:       before,
+		After:        auditSnapshot(transformations.DBJournalEntryToRestJournalEntry(entry, nil)),
+	})
 }
 
 func (s *journalEntryService) GetJournalEntry(
 	ctx context.Context,
 	input GetJournalEntryInput,
 ) (*models.JournalEntry, error) {
-	entry, err := s.repo.GetByIDAndClientID(ctx, input.ID, input.ClientID, input.Populate)
+	entry, err := s.repo.GetByIDAndLedgerID(ctx, input.ID, input.LedgerID, input.Populate)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,335 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Bendomey/fincore-engine/internal/lib"
+	"github.com/Bendomey/fincore-engine/internal/models"
+	"github.com/Bendomey/fincore-engine/internal/repository"
+	"github.com/Bendomey/fincore-engine/internal/transformations"
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	LedgerChainBreakSequenceGap          = "sequence_gap"
+	LedgerChainBreakDeleted              = "deleted"
+	LedgerChainBreakPreviousHashMismatch = "previous_hash_mismatch"
+	LedgerChainBreakHashMismatch         = "hash_mismatch"
+)
+
+// entries are replayed in pages so long ledgers don't have to fit in memory.
+const ledgerVerificationPageSize = 500
+
+var ErrLedgerNotFound = errors.New("ledger not found")
+
+type LedgerService interface {
+	CreateLedger(ctx context.Context, input CreateLedgerInput) (*models.Ledger, error)
+	UpdateLedger(ctx context.Context, input UpdateLedgerInput) (*models.Ledger, error)
+	DeleteLedger(ctx context.Context, input GetLedgerInput) error
+	GetLedger(ctx context.Context, input GetLedgerInput) (*models.Ledger, error)
+	SelectLedger(ctx context.Context, clientID string, ledgerID *string) (*models.Ledger, error)
+	ListLedgers(
+		ctx context.Context,
+		filterQuery lib.FilterQuery,
+		filters repository.ListLedgersFilter,
+	) ([]models.Ledger, error)
+	CountLedgers(ctx context.Context, filterQuery lib.FilterQuery, filters repository.ListLedgersFilter) (int64, error)
+	VerifyLedger(ctx context.Context, ledgerID string) (*models.LedgerVerification, error)
+}
+
+type ledgerService struct {
+	repo         repository.LedgerRepository
+	journalEntry repository.JournalEntryRepository
+	auditEvent   repository.AuditEventRepository
+	transactor   repository.Transactor
+}
+
+func NewLedgerService(
+	repo repository.LedgerRepository,
+	journalEntry repository.JournalEntryRepository,
+	auditEvent repository.AuditEventRepository,
+	transactor repository.Transactor,
+) LedgerService {
+	return &ledgerService{repo, journalEntry, auditEvent, transactor}
+}
+
+type CreateLedgerInput struct {
+	ClientID    string
+	Name        string
+	Description *string
+
+	// Currency the ledger keeps its amounts in.
+	Currency string
+
+	// FiscalYearStartMonth is the month the ledger's fiscal years start in, January when not set.
+	FiscalYearStartMonth *int
+
+	// IsDefault makes the ledger the client's default, in place of the current one.
+	IsDefault bool
+}
+
+func (s *ledgerService) CreateLedger(ctx context.Context, input CreateLedgerInput) (*models.Ledger, error) {
+	if !lib.IsCurrency(input.Currency) {
+		return nil, fmt.Errorf("%w %s", lib.ErrUnknownCurrency, input.Currency)
+	}
+
+	ledger := &models.Ledger{
+		ClientID:             input.ClientID,
+		Name:                 input.Name,
+		Description:          input.Description,
+		Currency:             input.Currency,
+		FiscalYearStartMonth: 1,
+		IsDefault:            input.IsDefault,
+	}
+
+	if input.FiscalYearStartMonth != nil {
+		ledger.FiscalYearStartMonth = *input.FiscalYearStartMonth
+	}
+
+	err := s.transactor.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.Create(ctx, ledger); err != nil {
+			return err
+		}
+
+		return recordAuditEvent(ctx, s.auditEvent, auditEventInput{
+			ClientID:     ledger.ClientID,
+			LedgerID:     ledger.ID.String(),
+			Action:       "create",
+			ResourceType: "ledger",
+			ResourceID:   ledger.ID.String(),
+			After:        auditSnapshot(transformations.DBLedgerToRestLedger(ledger)),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return ledger, nil
+}
+
+type UpdateLedgerInput struct {
+	ClientID    string
+	ID          string
+	Name        *string
+	Description *string
+
+	// Currency may only change while the ledger has no journal entries.
+	Currency             *string
+	FiscalYearStartMonth *int
+
+	// IsDefault can only be turned on, the default moves by making another ledger the default.
+	IsDefault *bool
+}
+
+func (s *ledgerService) UpdateLedger(ctx context.Context, input UpdateLedgerInput) (*models.Ledger, error) {
+	ledger, err := s.repo.GetByIDAndClientID(ctx, input.ID, input.ClientID)
+	if err != nil {
+		return nil, err
+	}
+
+	before := auditSnapshot(transformations.DBLedgerToRestLedger(ledger))
+
+	if input.Name != nil {
+		ledger.Name = *input.Name
+	}
+
+	if input.Description != nil {
+		ledger.Description = input.Description
+	}
+
+	if input.Currency != nil && *input.Currency != ledger.Currency {
+		if !lib.IsCurrency(*input.Currency) {
+			return nil, fmt.Errorf("%w %s", lib.ErrUnknownCurrency, *input.Currency)
+		}
+
+		// the amounts already booked are in the current currency.
+		journalEntriesCount, err := s.journalEntry.Count(ctx, lib.FilterQuery{}, repository.ListJournalEntriesFilter{
+			ClientId: ledger.ClientID,
+			LedgerId: ledger.ID.String(),
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if journalEntriesCount > 0 {
+			return nil, errors.New("cannot change the currency of a ledger that has journal entries")
+		}
+
+		ledger.Currency = *input.Currency
+	}
+
+	if input.FiscalYearStartMonth != nil {
+		ledger.FiscalYearStartMonth = *input.FiscalYearStartMonth
+	}
+
+	if input.IsDefault != nil {
+		if !*input.IsDefault && ledger.IsDefault {
+			return nil, errors.New("a client always has a default ledger, make another ledger the default instead")
+		}
+
+		ledger.IsDefault = *input.IsDefault
+	}
+
+	err = s.transactor.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.Update(ctx, ledger); err != nil {
+			return err
+		}
+
+		return recordAuditEvent(ctx, s.auditEvent, auditEventInput{
+			ClientID:     ledger.ClientID,
+			LedgerID:     ledger.ID.String(),
+			Action:       "update",
+			ResourceType: "ledger",
+			ResourceID:   ledger.ID.String(),
+			Before:       before,
+			After:        auditSnapshot(transformations.DBLedgerToRestLedger(ledger)),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return ledger, nil
+}
+
+type GetLedgerInput struct {
+	ClientID string
+	ID       string
+}
+
+// DeleteLedger removes a ledger that was never used, the default ledger stays.
+func (s *ledgerService) DeleteLedger(ctx context.Context, input GetLedgerInput) error {
+	ledger, err := s.repo.GetByIDAndClientID(ctx, input.ID, input.ClientID)
+	if err != nil {
+		return err
+	}
+
+	return s.transactor.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.Delete(ctx, ledger); err != nil {
+			return err
+		}
+
+		return recordAuditEvent(ctx, s.auditEvent, auditEventInput{
+			ClientID:     ledger.ClientID,
+			LedgerID:     ledger.ID.String(),
+			Action:       "delete",
+			ResourceType: "ledger",
+			ResourceID:   ledger.ID.String(),
+			Before:       auditSnapshot(transformations.DBLedgerToRestLedger(ledger)),
+		})
+	})
+}
+
+func (s *ledgerService) GetLedger(ctx context.Context, input GetLedgerInput) (*models.Ledger, error) {
+	return s.repo.GetByIDAndClientID(ctx, input.ID, input.ClientID)
+}
+
+// SelectLedger returns the ledger of the client a request works in, the one it asked for or else the client's
+// default ledger.
+func (s *ledgerService) SelectLedger(ctx context.Context, clientID string, ledgerID *string) (*models.Ledger, error) {
+	if ledgerID == nil {
+		return s.repo.GetDefaultByClientID(ctx, clientID)
+	}
+
+	if _, err := uuid.FromString(*ledgerID); err != nil {
+		return nil, ErrLedgerNotFound
+	}
+
+	ledger, err := s.repo.GetByIDAndClientID(ctx, *ledgerID, clientID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrLedgerNotFound
+		}
+
+		return nil, err
+	}
+
+	return ledger, nil
+}
+
+func (s *ledgerService) ListLedgers(
+	ctx context.Context,
+	filterQuery lib.FilterQuery,
+	filters repository.ListLedgersFilter,
+) ([]models.Ledger, error) {
+	ledgers, err := s.repo.List(ctx, filterQuery, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	return *ledgers, nil
+}
+
+func (s *ledgerService) CountLedgers(
+	ctx context.Context,
+	filterQuery lib.FilterQuery,
+	filters repository.ListLedgersFilter,
+) (int64, error) {
+	return s.repo.Count(ctx, filterQuery, filters)
+}
+
+// VerifyLedger replays the ledger's hash chain from the first posted entry and reports the first break.
+// Entries posted before the chain existed carry no hash and are not part of it.
+func (s *ledgerService) VerifyLedger(ctx context.Context, ledgerID string) (*models.LedgerVerification, error) {
+	verification := models.LedgerVerification{Valid: true}
+
+	previousSequence := int64(0)
+	previousHash := ""
+
+	for {
+		journalEntries, err := s.journalEntry.ListChainedByLedgerID(
+			ctx,
+			ledgerID,
+			previousSequence,
+			ledgerVerificationPageSize,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, journalEntry := range *journalEntries {
+			verification.EntriesChecked++
+
+			reason := verifyChainedJournalEntry(&journalEntry, previousSequence+1, previousHash)
+			if reason != nil {
+				verification.Valid = false
+				verification.FirstBreak = &models.LedgerChainBreak{
+					JournalEntryID: journalEntry.ID.String(),
+					LedgerSequence: *journalEntry.LedgerSequence,
+					Reason:         *reason,
+				}
+				return &verification, nil
+			}
+
+			previousSequence = *journalEntry.LedgerSequence
+			previousHash = *journalEntry.Hash
+		}
+
+		if len(*journalEntries) < ledgerVerificationPageSize {
+			return &verification, nil
+		}
+	}
+}
+
+func verifyChainedJournalEntry(journalEntry *models.JournalEntry, sequence int64, previousHash string) *string {
+	reason := ""
+
+	switch {
+	case *journalEntry.LedgerSequence != sequence:
+		reason = LedgerChainBreakSequenceGap
+	case journalEntry.DeletedAt.Valid:
+		reason = LedgerChainBreakDeleted
+	case journalEntry.PreviousHash == nil || *journalEntry.PreviousHash != previousHash:
+		reason = LedgerChainBreakPreviousHashMismatch
+	case journalEntry.Hash == nil ||
+		*journalEntry.Hash != journalEntry.ComputeChainHash(journalEntry.JournalEntryLines, sequence, previousHash):
+		reason = LedgerChainBreakHashMismatch
+	default:
+		return nil
+	}
+
+	return &reason
+}
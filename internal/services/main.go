@@ -2,26 +2,151 @@ package services
 
 import (
 	"github.com/Bendomey/fincore-engine/internal/repository"
+	"github.com/Bendomey/fincore-engine/internal/storage"
 )
 
 type Services struct {
-	ClientService       ClientService
-	AccountService      AccountService
-	JournalEntryService JournalEntryService
+	ClientService           ClientService
+	AccountService          AccountService
+	JournalEntryService     JournalEntryService
+	DimensionService        DimensionService
+	ReportService           ReportService
+	BudgetService           BudgetService
+	ApprovalRuleService     ApprovalRuleService
+	AttachmentService       AttachmentService
+	AuditEventService       AuditEventService
+	LedgerService           LedgerService
+	IdempotencyKeyService   IdempotencyKeyService
+	WebhookService          WebhookService
+	EventService            EventService
+	TransferService         TransferService
+	HoldService             HoldService
+	JournalEntryLineService JournalEntryLineService
+	SearchService           SearchService
+	ApiKeyService           ApiKeyService
+	AccessTokenService      AccessTokenService
+	RequestSignatureService RequestSignatureService
 }
 
-func NewServices(repository repository.Repository) Services {
-	clientService := NewClientService(repository.ClientRepository)
-	accountService := NewAccountService(repository.AccountRepository)
+func NewServices(
+	repository repository.Repository,
+	storage storage.Storage,
+	eventSubscriber EventSubscriber,
+	tokenSigningKey []byte,
+	encryptionKey []byte,
+) Services {
+	clientService := NewClientService(
+		repository.ClientRepository,
+		repository.AuditEventRepository,
+		repository.Transactor,
+		encryptionKey,
+	)
+	accountService := NewAccountService(
+		repository.AccountRepository,
+		repository.AuditEventRepository,
+		repository.Transactor,
+	)
 	journalEntryService := NewJournalEntryService(
 		repository.JournalEntryRepository,
 		repository.AccountRepository,
 		repository.JournalEntryLineRepository,
+		repository.DimensionTypeRepository,
+		repository.DimensionValueRepository,
+		repository.ApprovalRuleRepository,
+		repository.AuditEventRepository,
+		repository.Transactor,
+	)
+	dimensionService := NewDimensionService(
+		repository.DimensionTypeRepository,
+		repository.DimensionValueRepository,
+	)
+	reportService := NewReportService(
+		repository.ReportRepository,
+		repository.AccountRepository,
+		repository.DimensionTypeRepository,
+		repository.DimensionValueRepository,
+		repository.BudgetRepository,
+		repository.BudgetLineRepository,
+		repository.HoldRepository,
+	)
+	budgetService := NewBudgetService(
+		repository.BudgetRepository,
+		repository.BudgetLineRepository,
+		repository.AccountRepository,
+		repository.DimensionTypeRepository,
+		repository.DimensionValueRepository,
+	)
+	approvalRuleService := NewApprovalRuleService(
+		repository.ApprovalRuleRepository,
+		repository.AccountRepository,
+	)
+	attachmentService := NewAttachmentService(
+		repository.AttachmentRepository,
+		repository.JournalEntryRepository,
+		storage,
+	)
+	auditEventService := NewAuditEventService(repository.AuditEventRepository)
+	ledgerService := NewLedgerService(
+		repository.LedgerRepository,
+		repository.JournalEntryRepository,
+		repository.AuditEventRepository,
+		repository.Transactor,
+	)
+	idempotencyKeyService := NewIdempotencyKeyService(repository.IdempotencyKeyRepository)
+	webhookService := NewWebhookService(repository.WebhookEndpointRepository, repository.WebhookDeliveryRepository)
+	eventService := NewEventService(repository.WebhookEventRepository, eventSubscriber)
+	transferService := NewTransferService(
+		repository.TransferRepository,
+		repository.AccountRepository,
+		repository.ApprovalRuleRepository,
+		repository.AuditEventRepository,
+		repository.Transactor,
+	)
+
+	holdService := NewHoldService(
+		repository.HoldRepository,
+		repository.AccountRepository,
+		repository.ApprovalRuleRepository,
+		repository.AuditEventRepository,
+		repository.Transactor,
+	)
+
+	journalEntryLineService := NewJournalEntryLineService(repository.JournalEntryLineRepository)
+	searchService := NewSearchService(repository.SearchRepository)
+	apiKeyService := NewApiKeyService(
+		repository.ApiKeyRepository,
+		repository.ClientRepository,
+		repository.AuditEventRepository,
+		repository.Transactor,
+		encryptionKey,
+	)
+	accessTokenService := NewAccessTokenService(apiKeyService, repository.ApiKeyRepository, tokenSigningKey)
+	requestSignatureService := NewRequestSignatureService(
+		repository.ApiKeyRepository,
+		repository.RequestNonceRepository,
+		encryptionKey,
 	)
 
 	return Services{
-		ClientService:       clientService,
-		AccountService:      accountService,
-		JournalEntryService: journalEntryService,
+		ClientService:           clientService,
+		AccountService:          accountService,
+		JournalEntryService:     journalEntryService,
+		DimensionService:        dimensionService,
+		ReportService:           reportService,
+		BudgetService:           budgetService,
+		ApprovalRuleService:     approvalRuleService,
+		AttachmentService:       attachmentService,
+		AuditEventService:       auditEventService,
+		LedgerService:           ledgerService,
+		IdempotencyKeyService:   idempotencyKeyService,
+		WebhookService:          webhookService,
+		EventService:            eventService,
+		TransferService:         transferService,
+		HoldService:             holdService,
+		JournalEntryLineService: journalEntryLineService,
+		SearchService:           searchService,
+		ApiKeyService:           apiKeyService,
+		AccessTokenService:      accessTokenService,
+		RequestSignatureService: requestSignatureService,
 	}
 }
@@ -0,0 +1,523 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/Bendomey/fincore-engine/internal/lib"
+	"github.com/Bendomey/fincore-engine/internal/models"
+	"github.com/Bendomey/fincore-engine/internal/repository"
+	"gorm.io/gorm"
+)
+
+type ReportService interface {
+	GetAccountBalances(ctx context.Context, input ReportInput) (*models.AccountBalancesReport, error)
+	GetIncomeStatement(ctx context.Context, input ReportInput) (*models.IncomeStatementReport, error)
+	GetBudgetVsActual(ctx context.Context, input BudgetVsActualInput) (*models.BudgetVsActualReport, error)
+}
+
+type reportService struct {
+	repo           repository.ReportRepository
+	account        repository.AccountRepository
+	dimensionType  repository.DimensionTypeRepository
+	dimensionValue repository.DimensionValueRepository
+	budget         repository.BudgetRepository
+	budgetLine     repository.BudgetLineRepository
+	hold           repository.HoldRepository
+}
+
+func NewReportService(
+	repo repository.ReportRepository,
+	account repository.AccountRepository,
+	dimensionType repository.DimensionTypeRepository,
+	dimensionValue repository.DimensionValueRepository,
+	budget repository.BudgetRepository,
+	budgetLine repository.BudgetLineRepository,
+	hold repository.HoldRepository,
+) ReportService {
+	return &reportService{repo, account, dimensionType, dimensionValue, budget, budgetLine, hold}
+}
+
+type ReportInput struct {
+	ClientID  string
+	LedgerID  string
+	DateRange *lib.DateRangeType
+
+	// Currency is the ledger's, the one every amount of the report is in.
+	Currency string
+
+	// Dimensions filters lines by dimension code => allowed value codes.
+	Dimensions map[string][]string
+
+	// GroupBy is the code of the dimension to subtotal by.
+	GroupBy *string
+}
+
+func (s *reportService) GetAccountBalances(
+	ctx context.Context,
+	input ReportInput,
+) (*models.AccountBalancesReport, error) {
+	balances, groupBy, err := s.aggregateAccountBalances(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	// holds are only ever pending now, whatever the period of the report.
+	held, err := s.hold.SumActiveByLedgerID(ctx, input.LedgerID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range balances {
+		balances[i].Held = held[balances[i].Account.ID.String()]
+		balances[i].Available, err = subAmounts(input.Currency, balances[i].Balance, balances[i].Held)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &models.AccountBalancesReport{
+		Currency: input.Currency,
+		GroupBy:  groupBy,
+		Accounts: balances,
+	}, nil
+}
+
+func (s *reportService) GetIncomeStatement(
+	ctx context.Context,
+	input ReportInput,
+) (*models.IncomeStatementReport, error) {
+	balances, groupBy, err := s.aggregateAccountBalances(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	report := models.IncomeStatementReport{
+		Currency: input.Currency,
+		GroupBy:  groupBy,
+		Income:   models.IncomeStatementSection{Accounts: make([]models.AccountBalance, 0)},
+		Expenses: models.IncomeStatementSection{Accounts: make([]models.AccountBalance, 0)},
+	}
+
+	subtotals := make([]models.IncomeStatementSubtotal, 0)
+	subtotalIndex := make(map[string]int)
+
+	for _, balance := range balances {
+		if balance.Account.IsGroup {
+			continue
+		}
+
+		// contra accounts are netted against their section, eg. sales returns reduce income.
+		switch balance.Account.Type {
+		case "INCOME":
+			amount, err := subAmounts(input.Currency, balance.Credit, balance.Debit)
+			if err != nil {
+				return nil, err
+			}
+
+			report.Income.Accounts = append(report.Income.Accounts, balance)
+			if report.Income.Total, err = addAmounts(input.Currency, report.Income.Total, amount); err != nil {
+				return nil, err
+			}
+		case "EXPENSE":
+			amount, err := subAmounts(input.Currency, balance.Debit, balance.Credit)
+			if err != nil {
+				return nil, err
+			}
+
+			report.Expenses.Accounts = append(report.Expenses.Accounts, balance)
+			if report.Expenses.Total, err = addAmounts(input.Currency, report.Expenses.Total, amount); err != nil {
+				return nil, err
+			}
+		default:
+			continue
+		}
+
+		for _, subtotal := range balance.Subtotals {
+			key := ""
+			if subtotal.DimensionValue != nil {
+				key = subtotal.DimensionValue.ID.String()
+			}
+
+			index, ok := subtotalIndex[key]
+			if !ok {
+				subtotals = append(subtotals, models.IncomeStatementSubtotal{DimensionValue: subtotal.DimensionValue})
+				index = len(subtotals) - 1
+				subtotalIndex[key] = index
+			}
+
+			err := addIncomeStatementSubtotal(input.Currency, &subtotals[index], balance.Account.Type, subtotal)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	report.NetIncome, err = subAmounts(input.Currency, report.Income.Total, report.Expenses.Total)
+	if err != nil {
+		return nil, err
+	}
+
+	if groupBy != nil {
+		report.Subtotals = subtotals
+	}
+
+	return &report, nil
+}
+
+// addIncomeStatementSubtotal adds an account's share of a dimension value to that value's subtotal.
+func addIncomeStatementSubtotal(
+	currency string,
+	subtotal *models.IncomeStatementSubtotal,
+	accountType string,
+	share models.DimensionSubtotal,
+) error {
+	var err error
+	if accountType == "INCOME" {
+		amount, amountErr := subAmounts(currency, share.Credit, share.Debit)
+		if amountErr != nil {
+			return amountErr
+		}
+
+		subtotal.Income, err = addAmounts(currency, subtotal.Income, amount)
+	} else {
+		amount, amountErr := subAmounts(currency, share.Debit, share.Credit)
+		if amountErr != nil {
+			return amountErr
+		}
+
+		subtotal.Expenses, err = addAmounts(currency, subtotal.Expenses, amount)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	subtotal.NetIncome, err = subAmounts(currency, subtotal.Income, subtotal.Expenses)
+	return err
+}
+
+// aggregateAccountBalances returns the posted balance of every account of the ledger, subtotalled by the grouped
+// dimension when requested.
+func (s *reportService) aggregateAccountBalances(
+	ctx context.Context,
+	input ReportInput,
+) ([]models.AccountBalance, *models.DimensionType, error) {
+	filters := repository.LineAggregationFilter{
+		ClientId:          input.ClientID,
+		LedgerId:          input.LedgerID,
+		DateRange:         input.DateRange,
+		DimensionValueIds: make(map[string][]string),
+	}
+
+	for typeCode, valueCodes := range input.Dimensions {
+		dimensionType, err := s.getDimensionTypeByCode(ctx, input.LedgerID, typeCode)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		valueIds := make([]string, 0, len(valueCodes))
+		for _, valueCode := range valueCodes {
+			value, valueErr := s.dimensionValue.GetByCodeAndTypeID(ctx, valueCode, dimensionType.ID.String())
+			if valueErr != nil {
+				if errors.Is(valueErr, gorm.ErrRecordNotFound) {
+					return nil, nil, fmt.Errorf("'%s' is not a value of dimension '%s'", valueCode, typeCode)
+				}
+
+				return nil, nil, valueErr
+			}
+
+			valueIds = append(valueIds, value.ID.String())
+		}
+
+		filters.DimensionValueIds[dimensionType.ID.String()] = valueIds
+	}
+
+	var groupBy *models.DimensionType
+	if input.GroupBy != nil {
+		dimensionType, err := s.getDimensionTypeByCode(ctx, input.LedgerID, *input.GroupBy)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		groupBy = dimensionType
+		groupById := dimensionType.ID.String()
+		filters.GroupByDimensionTypeId = &groupById
+	}
+
+	aggregates, err := s.repo.AggregateLines(ctx, filters)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	accounts, err := s.account.ListAllByLedgerID(ctx, input.LedgerID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	dimensionValues := make(map[string]*models.DimensionValue)
+	balances := make([]models.AccountBalance, 0, len(*accounts))
+	balanceIndex := make(map[string]int)
+
+	for _, account := range *accounts {
+		balances = append(balances, models.AccountBalance{Account: account, Subtotals: make([]models.DimensionSubtotal, 0)})
+		balanceIndex[account.ID.String()] = len(balances) - 1
+	}
+
+	for _, aggregate := range *aggregates {
+		index, ok := balanceIndex[aggregate.AccountID]
+		if !ok {
+			continue
+		}
+
+		balance := &balances[index]
+		if balance.Debit, err = addAmounts(input.Currency, balance.Debit, aggregate.Debit); err != nil {
+			return nil, nil, err
+		}
+
+		if balance.Credit, err = addAmounts(input.Currency, balance.Credit, aggregate.Credit); err != nil {
+			return nil, nil, err
+		}
+
+		balance.Balance = balance.Account.Balance(balance.Debit, balance.Credit)
+
+		if groupBy == nil {
+			continue
+		}
+
+		subtotal := models.DimensionSubtotal{
+			Debit:   aggregate.Debit,
+			Credit:  aggregate.Credit,
+			Balance: balance.Account.Balance(aggregate.Debit, aggregate.Credit),
+		}
+
+		if aggregate.DimensionValueID != nil {
+			value, cached := dimensionValues[*aggregate.DimensionValueID]
+			if !cached {
+				value, err = s.dimensionValue.GetByIDAndTypeID(
+					ctx,
+					*aggregate.DimensionValueID,
+					groupBy.ID.String(),
+					nil,
+				)
+				if err != nil {
+					return nil, nil, err
+				}
+				dimensionValues[*aggregate.DimensionValueID] = value
+			}
+
+			subtotal.DimensionValue = value
+		}
+
+		balance.Subtotals = append(balance.Subtotals, subtotal)
+	}
+
+	return balances, groupBy, nil
+}
+
+type BudgetVsActualInput struct {
+	ClientID string
+	BudgetID string
+
+	// actuals are the ledger's, in its currency.
+	LedgerID string
+	Currency string
+
+	// Interval is ReportIntervalMonth to compare month by month, nil to compare each line over its own period.
+	Interval *string
+}
+
+// ReportIntervalMonth compares budget lines month by month, see budgetLinePeriods.
+const ReportIntervalMonth = "month"
+
+// budgetPeriod is the part of a budget line that falls in one period of the report.
+type budgetPeriod struct {
+	Start  time.Time
+	End    time.Time
+	Budget int64
+}
+
+// budgetLinePeriods are the periods budgetLine is compared over. By month, the line is cut at the end of every
+// calendar month it covers and its amount allocated in proportion to the days of each part, so an annual line
+// compares to each month's actuals and its parts still add up to the amount budgeted.
+func budgetLinePeriods(budgetLine models.BudgetLine, currency string, interval *string) ([]budgetPeriod, error) {
+	if interval == nil || *interval != ReportIntervalMonth {
+		return []budgetPeriod{
+			{Start: budgetLine.PeriodStart, End: budgetLine.PeriodEnd, Budget: budgetLine.Amount},
+		}, nil
+	}
+
+	periods := make([]budgetPeriod, 0)
+	days := make([]int64, 0)
+	for start := budgetLine.PeriodStart; !start.After(budgetLine.PeriodEnd); {
+		nextMonth := time.Date(start.Year(), start.Month()+1, 1, 0, 0, 0, 0, start.Location())
+
+		end := nextMonth.AddDate(0, 0, -1)
+		if end.After(budgetLine.PeriodEnd) {
+			end = budgetLine.PeriodEnd
+		}
+
+		periods = append(periods, budgetPeriod{Start: start, End: end})
+		days = append(days, int64(math.Round(end.Sub(start).Hours()/24))+1)
+		start = nextMonth
+	}
+
+	if len(periods) == 0 {
+		return periods, nil
+	}
+
+	shares, err := lib.Money{Amount: budgetLine.Amount, Currency: currency}.Allocate(days...)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range periods {
+		periods[i].Budget = shares[i].Amount
+	}
+
+	return periods, nil
+}
+
+// GetBudgetVsActual compares every budget line to the posted activity of its account (and dimension value)
+// within the line's period. Actuals are aggregated the same way the balance reports are.
+func (s *reportService) GetBudgetVsActual(
+	ctx context.Context,
+	input BudgetVsActualInput,
+) (*models.BudgetVsActualReport, error) {
+	budget, err := s.budget.GetByIDAndClientID(ctx, input.BudgetID, input.ClientID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	budgetLines, err := s.budgetLine.ListAllByBudgetID(ctx, budget.ID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	report := models.BudgetVsActualReport{
+		Currency: input.Currency,
+		Budget:   *budget,
+		Periods:  make([]models.BudgetVsActualPeriod, 0),
+	}
+
+	periodIndex := make(map[string]int)
+
+	// actuals are cached per period and dimension value => account id => aggregate
+	actuals := make(map[string]map[string]repository.LineAggregate)
+
+	for _, budgetLine := range *budgetLines {
+		periods, err := budgetLinePeriods(budgetLine, input.Currency, input.Interval)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, period := range periods {
+			periodKey := period.Start.Format(time.DateOnly) + "/" + period.End.Format(time.DateOnly)
+			index, ok := periodIndex[periodKey]
+			if !ok {
+				report.Periods = append(report.Periods, models.BudgetVsActualPeriod{
+					PeriodStart: period.Start,
+					PeriodEnd:   period.End,
+					Lines:       make([]models.BudgetVsActualLine, 0),
+				})
+				index = len(report.Periods) - 1
+				periodIndex[periodKey] = index
+			}
+
+			actualsKey := periodKey
+			filters := repository.LineAggregationFilter{
+				ClientId: input.ClientID,
+				LedgerId: input.LedgerID,
+				DateRange: &lib.DateRangeType{
+					StartTime: period.Start,
+					// periods are inclusive of their last day
+					EndTime: period.End.AddDate(0, 0, 1).Add(-time.Microsecond),
+				},
+			}
+
+			if budgetLine.DimensionValue != nil {
+				actualsKey += "/" + budgetLine.DimensionValue.ID.String()
+				filters.DimensionValueIds = map[string][]string{
+					budgetLine.DimensionValue.DimensionTypeID: {budgetLine.DimensionValue.ID.String()},
+				}
+			}
+
+			accountActuals, cached := actuals[actualsKey]
+			if !cached {
+				aggregates, aggregateErr := s.repo.AggregateLines(ctx, filters)
+				if aggregateErr != nil {
+					return nil, aggregateErr
+				}
+
+				accountActuals = make(map[string]repository.LineAggregate)
+				for _, aggregate := range *aggregates {
+					accountActuals[aggregate.AccountID] = aggregate
+				}
+				actuals[actualsKey] = accountActuals
+			}
+
+			aggregate := accountActuals[budgetLine.AccountID]
+			actual := budgetLine.Account.Balance(aggregate.Debit, aggregate.Credit)
+
+			variance, err := subAmounts(input.Currency, actual, period.Budget)
+			if err != nil {
+				return nil, err
+			}
+
+			line := models.BudgetVsActualLine{
+				Account:        budgetLine.Account,
+				DimensionValue: budgetLine.DimensionValue,
+				Budget:         period.Budget,
+				Actual:         actual,
+				Variance:       variance,
+			}
+
+			if period.Budget != 0 {
+				percent := math.Round(float64(line.Variance)/math.Abs(float64(period.Budget))*10000) / 100
+				line.VariancePercent = &percent
+			}
+
+			report.Periods[index].Lines = append(report.Periods[index].Lines, line)
+		}
+	}
+
+	// lines split by month may add months between those of lines already seen.
+	sort.SliceStable(report.Periods, func(i, j int) bool {
+		return report.Periods[i].PeriodStart.Before(report.Periods[j].PeriodStart)
+	})
+
+	return &report, nil
+}
+
+func (s *reportService) getDimensionTypeByCode(
+	ctx context.Context,
+	ledgerID string,
+	code string,
+) (*models.DimensionType, error) {
+	dimensionType, err := s.dimensionType.GetByCodeAndLedgerID(ctx, code, ledgerID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("unknown dimension '%s'", code)
+		}
+
+		return nil, err
+	}
+
+	return dimensionType, nil
+}
+
+// addAmounts adds two amounts of the report's currency, refusing a total too large to be kept.
+func addAmounts(currency string, a int64, b int64) (int64, error) {
+	sum, err := lib.Money{Amount: a, Currency: currency}.Add(lib.Money{Amount: b, Currency: currency})
+	return sum.Amount, err
+}
+
+// subAmounts subtracts b from a in the report's currency, refusing a result too large to be kept.
+func subAmounts(currency string, a int64, b int64) (int64, error) {
+	difference, err := lib.Money{Amount: a, Currency: currency}.Sub(lib.Money{Amount: b, Currency: currency})
+	return difference.Amount, err
+}
@@ -0,0 +1,116 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/Bendomey/fincore-engine/internal/lib"
+	"github.com/Bendomey/fincore-engine/internal/models"
+	"github.com/Bendomey/fincore-engine/internal/repository"
+	"github.com/getsentry/raven-go"
+)
+
+// RequestSignatureMaxAge is how far the timestamp of a signed request may be from the server's clock, either way.
+const RequestSignatureMaxAge = 5 * time.Minute
+
+const maxRequestNonceLength = 255
+
+var (
+	ErrInvalidRequestSignature = errors.New("invalid request signature")
+	ErrStaleRequestTimestamp   = errors.New("request timestamp is too far from the server's clock")
+	ErrRequestNonceReplayed    = errors.New("request nonce was already used")
+)
+
+type RequestSignatureService interface {
+	AuthenticateSignedRequest(ctx context.Context, input SignedRequest) (*models.ApiKey, error)
+}
+
+type requestSignatureService struct {
+	apiKey        repository.ApiKeyRepository
+	requestNonce  repository.RequestNonceRepository
+	encryptionKey []byte
+}
+
+func NewRequestSignatureService(
+	apiKey repository.ApiKeyRepository,
+	requestNonce repository.RequestNonceRepository,
+	encryptionKey []byte,
+) RequestSignatureService {
+	return &requestSignatureService{apiKey, requestNonce, encryptionKey}
+}
+
+type SignedRequest struct {
+	ClientID string
+	KeyID    string // the prefix of the api key the request was signed with
+
+	Method     string
+	RequestURI string
+	Body       []byte
+
+	Timestamp string // unix seconds
+	Nonce     string
+	Signature string // hex encoded
+}
+
+// AuthenticateSignedRequest checks the request was signed with the signing key of an active api key of the client,
+// recently and only once, returning the key with its client loaded.
+func (s *requestSignatureService) AuthenticateSignedRequest(
+	ctx context.Context,
+	input SignedRequest,
+) (*models.ApiKey, error) {
+	timestamp, err := strconv.ParseInt(input.Timestamp, 10, 64)
+	if err != nil {
+		return nil, ErrInvalidRequestSignature
+	}
+
+	now := time.Now()
+	signedAt := time.Unix(timestamp, 0)
+	if signedAt.Before(now.Add(-RequestSignatureMaxAge)) || signedAt.After(now.Add(RequestSignatureMaxAge)) {
+		return nil, ErrStaleRequestTimestamp
+	}
+
+	if input.Nonce == "" || len(input.Nonce) > maxRequestNonceLength {
+		return nil, ErrInvalidRequestSignature
+	}
+
+	apiKey, err := s.apiKey.GetByPrefix(ctx, input.KeyID)
+	if err != nil || apiKey.Client.ClientId != input.ClientID || apiKey.SigningKey == nil || !apiKey.IsActive(now) {
+		return nil, ErrInvalidRequestSignature
+	}
+
+	signingKey, err := lib.Decrypt(*apiKey.SigningKey, s.encryptionKey)
+	if err != nil {
+		raven.CaptureError(err, map[string]string{
+			"function": "AuthenticateSignedRequest",
+			"action":   "decrypting signing key",
+		})
+		return nil, err
+	}
+
+	signature := lib.SignRequest(signingKey, input.Method, input.RequestURI, input.Timestamp, input.Nonce, input.Body)
+	if !hmac.Equal([]byte(signature), []byte(input.Signature)) {
+		return nil, ErrInvalidRequestSignature
+	}
+
+	// only checked once the signature holds, so nonces can't be used up by requests nobody signed. The nonce is
+	// remembered for as long as the timestamp would be accepted.
+	reserved, err := s.requestNonce.Reserve(ctx, &models.RequestNonce{
+		ApiKeyID:  apiKey.ID.String(),
+		Nonce:     input.Nonce,
+		ExpiresAt: signedAt.Add(RequestSignatureMaxAge),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if !reserved {
+		return nil, ErrRequestNonceReplayed
+	}
+
+	touchApiKeyLastUsed(ctx, s.apiKey, apiKey, now)
+
+	return apiKey, nil
+}
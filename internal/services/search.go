@@ -0,0 +1,44 @@
+package services
+
+import (
+	"context"
+
+	"github.com/Bendomey/fincore-engine/internal/models"
+	"github.com/Bendomey/fincore-engine/internal/repository"
+)
+
+// SearchService runs full-text searches across a client's accounts, journal entries and lines.
+type SearchService interface {
+	Search(ctx context.Context, input SearchInput) ([]models.SearchHit, error)
+}
+
+type searchService struct {
+	repo repository.SearchRepository
+}
+
+func NewSearchService(repo repository.SearchRepository) SearchService {
+	return &searchService{repo}
+}
+
+type SearchInput struct {
+	ClientID string
+	LedgerID string
+	Query    string
+	Types    []string
+	Limit    int
+}
+
+func (s *searchService) Search(ctx context.Context, input SearchInput) ([]models.SearchHit, error) {
+	hits, err := s.repo.Search(ctx, repository.SearchFilter{
+		ClientId: input.ClientID,
+		LedgerId: input.LedgerID,
+		Query:    input.Query,
+		Types:    input.Types,
+		Limit:    input.Limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return *hits, nil
+}
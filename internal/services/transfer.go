@@ -0,0 +1,343 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"time"
+
+	"github.com/Bendomey/fincore-engine/internal/lib"
+	"github.com/Bendomey/fincore-engine/internal/models"
+	"github.com/Bendomey/fincore-engine/internal/repository"
+	"github.com/Bendomey/fincore-engine/internal/transformations"
+	"github.com/gofrs/uuid"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// ErrTransferIDReused is returned when a transfer id is sent again with a different transfer.
+var ErrTransferIDReused = errors.New("transfer id was already used for a different transfer")
+
+// TransferService moves money between two accounts without the caller building journal entry lines.
+type TransferService interface {
+	CreateTransfer(ctx context.Context, input CreateTransferInput) (*models.Transfer, bool, error)
+	GetTransfer(ctx context.Context, input GetTransferInput) (*models.Transfer, error)
+	ListTransfers(
+		ctx context.Context,
+		filterQuery lib.FilterQuery,
+		filters repository.ListTransfersFilter,
+	) ([]models.Transfer, error)
+	CountTransfers(
+		ctx context.Context,
+		filterQuery lib.FilterQuery,
+		filters repository.ListTransfersFilter,
+	) (int64, error)
+}
+
+type transferService struct {
+	repo         repository.TransferRepository
+	account      repository.AccountRepository
+	approvalRule repository.ApprovalRuleRepository
+	auditEvent   repository.AuditEventRepository
+	transactor   repository.Transactor
+}
+
+func NewTransferService(
+	repo repository.TransferRepository,
+	account repository.AccountRepository,
+	approvalRule repository.ApprovalRuleRepository,
+	auditEvent repository.AuditEventRepository,
+	transactor repository.Transactor,
+) TransferService {
+	return &transferService{repo, account, approvalRule, auditEvent, transactor}
+}
+
+type CreateTransferInput struct {
+	ClientID string
+	LedgerID string
+	ActorID  string
+
+	// ID is chosen by the caller so a retried transfer is not made twice.
+	ID *string
+
+	FromAccountID string
+	ToAccountID   string
+
+	// Amount is in the minor unit of Currency, which must be the ledger's.
+	Amount          int64
+	Currency        string
+	Reference       string
+	TransactionDate *string
+	Metadata        *map[string]interface{}
+}
+
+// CreateTransfer lowers the source account's balance and raises the destination's by the amount, in one posted
+// journal entry of the selected ledger. Like any other entry it waits for approval instead when it matches an
+// approval rule.
+// The returned bool is false when the transfer already existed under the given id, it is returned as is.
+func (s *transferService) CreateTransfer(
+	ctx context.Context,
+	input CreateTransferInput,
+) (*models.Transfer, bool, error) {
+	if input.ID != nil {
+		existing, err := s.getExistingTransfer(ctx, input)
+		if err != nil || existing != nil {
+			return existing, false, err
+		}
+	}
+
+	from, to, err := getTransferAccounts(ctx, s.account, input.LedgerID, input.FromAccountID, input.ToAccountID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	amount, err := lib.NewMoney(input.Amount, input.Currency)
+	if err != nil {
+		return nil, false, err
+	}
+
+	lines := transferLines(from, to, amount)
+
+	journalEntry := models.JournalEntry{
+		ClientID:          input.ClientID,
+		LedgerID:          input.LedgerID,
+		Currency:          amount.Currency,
+		Status:            models.JournalEntryStatusPosted,
+		Reference:         input.Reference,
+		CreatedBy:         &input.ActorID,
+		JournalEntryLines: lines,
+	}
+
+	needsApproval, err := requiresApproval(ctx, s.approvalRule, input.LedgerID, lines)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if needsApproval {
+		journalEntry.Status = models.JournalEntryStatusPendingApproval
+	} else {
+		now := time.Now()
+		journalEntry.PostedAt = &now
+	}
+
+	if input.TransactionDate != nil {
+		t, err := time.Parse(time.RFC3339, *input.TransactionDate)
+		if err != nil {
+			return nil, false, errors.New("invalid transaction date format")
+		}
+
+		journalEntry.TransactionDate = t
+	}
+
+	transfer := models.Transfer{
+		ClientID:      input.ClientID,
+		LedgerID:      input.LedgerID,
+		FromAccountID: input.FromAccountID,
+		ToAccountID:   input.ToAccountID,
+		Amount:        amount.Amount,
+		Currency:      amount.Currency,
+		Reference:     input.Reference,
+	}
+
+	if input.ID != nil {
+		id, err := uuid.FromString(*input.ID)
+		if err != nil {
+			return nil, false, err
+		}
+
+		transfer.ID = id
+	}
+
+	if input.Metadata != nil {
+		metadata, err := lib.InterfaceToJSON(*input.Metadata)
+		if err != nil {
+			return nil, false, errors.New("invalid metadata format")
+		}
+
+		transfer.Metadata = metadata
+		journalEntry.Metadata = metadata
+	}
+
+	journalEntry.JournalEntryTransitions = []models.JournalEntryTransition{
+		{ToStatus: journalEntry.Status, ActorID: &input.ActorID},
+	}
+
+	transfer.JournalEntry = journalEntry
+
+	err = s.transactor.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.Create(ctx, &transfer); err != nil {
+			return err
+		}
+
+		err := recordAuditEvent(ctx, s.auditEvent, auditEventInput{
+			ClientID:     transfer.ClientID,
+			LedgerID:     transfer.LedgerID,
+			Action:       "create",
+			ResourceType: "transfer",
+			ResourceID:   transfer.ID.String(),
+			After:        auditSnapshot(transformations.DBTransferToRestTransfer(&transfer, nil)),
+		})
+		if err != nil {
+			return err
+		}
+
+		return recordAuditEvent(ctx, s.auditEvent, auditEventInput{
+			ClientID:     transfer.ClientID,
+			LedgerID:     transfer.LedgerID,
+			Action:       "create",
+			ResourceType: "journal_entry",
+			ResourceID:   transfer.JournalEntry.ID.String(),
+			After:        auditSnapshot(transformations.DBJournalEntryToRestJournalEntry(&transfer.JournalEntry, nil)),
+		})
+	})
+	if err != nil {
+		// lost a race against the same transfer, answer with the one that won.
+		if input.ID != nil {
+			existing, existingErr := s.getExistingTransfer(ctx, input)
+			if existingErr == nil && existing != nil {
+				return existing, false, nil
+			}
+		}
+
+		return nil, false, err
+	}
+
+	return &transfer, true, nil
+}
+
+// getExistingTransfer looks up the transfer already made under input.ID, nil when there is none. A transfer that
+// differs from the input, metadata included, is ErrTransferIDReused, one made in another ledger of the client
+// included since ids are unique across them.
+func (s *transferService) getExistingTransfer(
+	ctx context.Context,
+	input CreateTransferInput,
+) (*models.Transfer, error) {
+	existing, err := s.repo.GetByIDAndClientID(ctx, *input.ID, input.ClientID, nil)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	if existing.LedgerID != input.LedgerID ||
+		existing.FromAccountID != input.FromAccountID ||
+		existing.ToAccountID != input.ToAccountID ||
+		existing.Amount != input.Amount ||
+		existing.Reference != input.Reference ||
+		!sameMetadata(existing.Metadata, input.Metadata) {
+		return nil, ErrTransferIDReused
+	}
+
+	return existing, nil
+}
+
+// sameMetadata tells whether the stored metadata holds what was sent. It compares the decoded values, postgres
+// not keeping the key order or spacing of what it was given.
+func sameMetadata(stored *datatypes.JSON, sent *map[string]interface{}) bool {
+	var storedValue interface{}
+	if stored != nil && len(*stored) > 0 {
+		if err := json.Unmarshal(*stored, &storedValue); err != nil {
+			return false
+		}
+	}
+
+	var sentValue interface{}
+	if sent != nil {
+		raw, err := json.Marshal(*sent)
+		if err != nil {
+			return false
+		}
+
+		if err := json.Unmarshal(raw, &sentValue); err != nil {
+			return false
+		}
+	}
+
+	return reflect.DeepEqual(storedValue, sentValue)
+}
+
+// getTransferAccounts loads the two accounts of a move of money between accounts of the ledger, making sure one
+// can be made.
+func getTransferAccounts(
+	ctx context.Context,
+	accountRepo repository.AccountRepository,
+	ledgerID string,
+	fromAccountID string,
+	toAccountID string,
+) (*models.Account, *models.Account, error) {
+	if fromAccountID == toAccountID {
+		return nil, nil, errors.New("cannot transfer to the same account")
+	}
+
+	accounts := make([]*models.Account, 0, 2)
+	for _, accountID := range []string{fromAccountID, toAccountID} {
+		account, err := accountRepo.GetByIDAndLedgerID(ctx, accountID, ledgerID, nil)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if account.IsGroup {
+			return nil, nil, errors.New("cannot transfer from or to a group account")
+		}
+
+		accounts = append(accounts, account)
+	}
+
+	// the amount leaves the source's balance and is added to the destination's, which only balances when both
+	// grow on the same side.
+	if accounts[0].IsDebitNormal() != accounts[1].IsDebitNormal() {
+		return nil, nil, errors.New("cannot transfer between a debit normal and a credit normal account")
+	}
+
+	return accounts[0], accounts[1], nil
+}
+
+// transferLines are the journal entry lines lowering from's balance and raising to's by amount.
+func transferLines(from *models.Account, to *models.Account, amount lib.Money) []models.JournalEntryLine {
+	if from.IsDebitNormal() {
+		return []models.JournalEntryLine{
+			{AccountID: from.ID.String(), Credit: amount.Amount, Currency: amount.Currency},
+			{AccountID: to.ID.String(), Debit: amount.Amount, Currency: amount.Currency},
+		}
+	}
+
+	return []models.JournalEntryLine{
+		{AccountID: from.ID.String(), Debit: amount.Amount, Currency: amount.Currency},
+		{AccountID: to.ID.String(), Credit: amount.Amount, Currency: amount.Currency},
+	}
+}
+
+type GetTransferInput struct {
+	ClientID string
+	LedgerID string
+	ID       string
+	Populate *[]string
+}
+
+func (s *transferService) GetTransfer(ctx context.Context, input GetTransferInput) (*models.Transfer, error) {
+	return s.repo.GetByIDAndLedgerID(ctx, input.ID, input.LedgerID, input.Populate)
+}
+
+func (s *transferService) ListTransfers(
+	ctx context.Context,
+	filterQuery lib.FilterQuery,
+	filters repository.ListTransfersFilter,
+) ([]models.Transfer, error) {
+	transfers, err := s.repo.List(ctx, filterQuery, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	return *transfers, nil
+}
+
+func (s *transferService) CountTransfers(
+	ctx context.Context,
+	filterQuery lib.FilterQuery,
+	filters repository.ListTransfersFilter,
+) (int64, error) {
+	return s.repo.Count(ctx, filterQuery, filters)
+}
@@ -0,0 +1,279 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"time"
+
+	"github.com/Bendomey/fincore-engine/internal/lib"
+	"github.com/Bendomey/fincore-engine/internal/models"
+	"github.com/Bendomey/fincore-engine/internal/repository"
+	"github.com/Bendomey/fincore-engine/internal/webhooks"
+	"github.com/getsentry/raven-go"
+	"gorm.io/datatypes"
+)
+
+// WebhookService manages the client's webhook endpoints and their delivery log. Sending is done by the
+// dispatcher in the webhooks package.
+type WebhookService interface {
+	CreateWebhookEndpoint(ctx context.Context, input CreateWebhookEndpointInput) (*CreateWebhookEndpointResponse, error)
+	UpdateWebhookEndpoint(ctx context.Context, input UpdateWebhookEndpointInput) (*models.WebhookEndpoint, error)
+	DeleteWebhookEndpoint(ctx context.Context, input GetWebhookEndpointInput) error
+	GetWebhookEndpoint(ctx context.Context, input GetWebhookEndpointInput) (*models.WebhookEndpoint, error)
+	ListWebhookEndpoints(
+		ctx context.Context,
+		filterQuery lib.FilterQuery,
+		filters repository.ListWebhookEndpointsFilter,
+	) ([]models.WebhookEndpoint, error)
+	CountWebhookEndpoints(
+		ctx context.Context,
+		filterQuery lib.FilterQuery,
+		filters repository.ListWebhookEndpointsFilter,
+	) (int64, error)
+
+	GetWebhookDelivery(ctx context.Context, input GetWebhookDeliveryInput) (*models.WebhookDelivery, error)
+	RedeliverWebhookDelivery(ctx context.Context, input GetWebhookDeliveryInput) (*models.WebhookDelivery, error)
+	ListWebhookDeliveries(
+		ctx context.Context,
+		filterQuery lib.FilterQuery,
+		filters repository.ListWebhookDeliveriesFilter,
+	) ([]models.WebhookDelivery, error)
+	CountWebhookDeliveries(
+		ctx context.Context,
+		filterQuery lib.FilterQuery,
+		filters repository.ListWebhookDeliveriesFilter,
+	) (int64, error)
+}
+
+type webhookService struct {
+	repo     repository.WebhookEndpointRepository
+	delivery repository.WebhookDeliveryRepository
+}
+
+func NewWebhookService(
+	repo repository.WebhookEndpointRepository,
+	delivery repository.WebhookDeliveryRepository,
+) WebhookService {
+	return &webhookService{repo, delivery}
+}
+
+type CreateWebhookEndpointInput struct {
+	ClientID    string
+	LedgerID    string
+	URL         string
+	Description *string
+	Events      []string
+}
+
+type CreateWebhookEndpointResponse struct {
+	WebhookEndpoint models.WebhookEndpoint
+	Secret          string
+}
+
+func (s *webhookService) CreateWebhookEndpoint(
+	ctx context.Context,
+	input CreateWebhookEndpointInput,
+) (*CreateWebhookEndpointResponse, error) {
+	if err := webhooks.ValidateEndpointURL(ctx, input.URL); err != nil {
+		return nil, err
+	}
+
+	secretBytes := make([]byte, 32)
+	if _, err := rand.Read(secretBytes); err != nil {
+		raven.CaptureError(err, map[string]string{
+			"function": "CreateWebhookEndpoint",
+			"action":   "generating random bytes",
+		})
+		return nil, err
+	}
+
+	// kept as is, deliveries are signed with it.
+	secret := "whsec_" + base64.RawURLEncoding.EncodeToString(secretBytes)
+
+	webhookEndpoint := models.WebhookEndpoint{
+		ClientID:    input.ClientID,
+		LedgerID:    input.LedgerID,
+		URL:         input.URL,
+		Description: input.Description,
+		Events:      datatypes.NewJSONSlice(input.Events),
+		Secret:      secret,
+		IsActive:    true,
+	}
+
+	if err := s.repo.Create(ctx, &webhookEndpoint); err != nil {
+		return nil, err
+	}
+
+	return &CreateWebhookEndpointResponse{
+		WebhookEndpoint: webhookEndpoint,
+		Secret:          secret,
+	}, nil
+}
+
+type UpdateWebhookEndpointInput struct {
+	ClientID    string
+	LedgerID    string
+	ID          string
+	URL         *string
+	Description *string
+	Events      *[]string
+	IsActive    *bool
+}
+
+func (s *webhookService) UpdateWebhookEndpoint(
+	ctx context.Context,
+	input UpdateWebhookEndpointInput,
+) (*models.WebhookEndpoint, error) {
+	webhookEndpoint, err := s.repo.GetByIDAndLedgerID(ctx, input.ID, input.LedgerID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.URL != nil {
+		if err := webhooks.ValidateEndpointURL(ctx, *input.URL); err != nil {
+			return nil, err
+		}
+
+		webhookEndpoint.URL = *input.URL
+	}
+
+	if input.Description != nil {
+		webhookEndpoint.Description = input.Description
+	}
+
+	if input.Events != nil {
+		webhookEndpoint.Events = datatypes.NewJSONSlice(*input.Events)
+	}
+
+	if input.IsActive != nil {
+		webhookEndpoint.IsActive = *input.IsActive
+	}
+
+	err = s.repo.Update(ctx, webhookEndpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	return webhookEndpoint, nil
+}
+
+type GetWebhookEndpointInput struct {
+	ClientID string
+	LedgerID string
+	ID       string
+}
+
+func (s *webhookService) DeleteWebhookEndpoint(ctx context.Context, input GetWebhookEndpointInput) error {
+	webhookEndpoint, err := s.repo.GetByIDAndLedgerID(ctx, input.ID, input.LedgerID, nil)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.Delete(ctx, webhookEndpoint)
+}
+
+func (s *webhookService) GetWebhookEndpoint(
+	ctx context.Context,
+	input GetWebhookEndpointInput,
+) (*models.WebhookEndpoint, error) {
+	return s.repo.GetByIDAndLedgerID(ctx, input.ID, input.LedgerID, nil)
+}
+
+func (s *webhookService) ListWebhookEndpoints(
+	ctx context.Context,
+	filterQuery lib.FilterQuery,
+	filters repository.ListWebhookEndpointsFilter,
+) ([]models.WebhookEndpoint, error) {
+	webhookEndpoints, err := s.repo.List(ctx, filterQuery, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	return *webhookEndpoints, nil
+}
+
+func (s *webhookService) CountWebhookEndpoints(
+	ctx context.Context,
+	filterQuery lib.FilterQuery,
+	filters repository.ListWebhookEndpointsFilter,
+) (int64, error) {
+	return s.repo.Count(ctx, filterQuery, filters)
+}
+
+type GetWebhookDeliveryInput struct {
+	ClientID          string
+	LedgerID          string
+	WebhookEndpointID string
+	ID                string
+	Populate          *[]string
+}
+
+func (s *webhookService) GetWebhookDelivery(
+	ctx context.Context,
+	input GetWebhookDeliveryInput,
+) (*models.WebhookDelivery, error) {
+	// the endpoint lookup scopes the delivery to the ledger.
+	_, err := s.repo.GetByIDAndLedgerID(ctx, input.WebhookEndpointID, input.LedgerID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.delivery.GetByIDAndEndpointID(ctx, input.ID, input.WebhookEndpointID, input.Populate)
+}
+
+// RedeliverWebhookDelivery queues the delivery's event to be sent to the endpoint again, as a new delivery so
+// the log of the earlier one is kept.
+func (s *webhookService) RedeliverWebhookDelivery(
+	ctx context.Context,
+	input GetWebhookDeliveryInput,
+) (*models.WebhookDelivery, error) {
+	webhookDelivery, err := s.GetWebhookDelivery(ctx, GetWebhookDeliveryInput{
+		ClientID:          input.ClientID,
+		LedgerID:          input.LedgerID,
+		WebhookEndpointID: input.WebhookEndpointID,
+		ID:                input.ID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	redeliveryOfID := webhookDelivery.ID.String()
+
+	redelivery := models.WebhookDelivery{
+		ClientID:          webhookDelivery.ClientID,
+		WebhookEndpointID: webhookDelivery.WebhookEndpointID,
+		WebhookEventID:    webhookDelivery.WebhookEventID,
+		RedeliveryOfID:    &redeliveryOfID,
+		Status:            models.WebhookDeliveryStatusPending,
+		NextAttemptAt:     &now,
+	}
+
+	if err := s.delivery.Create(ctx, &redelivery); err != nil {
+		return nil, err
+	}
+
+	return &redelivery, nil
+}
+
+func (s *webhookService) ListWebhookDeliveries(
+	ctx context.Context,
+	filterQuery lib.FilterQuery,
+	filters repository.ListWebhookDeliveriesFilter,
+) ([]models.WebhookDelivery, error) {
+	webhookDeliveries, err := s.delivery.List(ctx, filterQuery, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	return *webhookDeliveries, nil
+}
+
+func (s *webhookService) CountWebhookDeliveries(
+	ctx context.Context,
+	filterQuery lib.FilterQuery,
+	filters repository.ListWebhookDeliveriesFilter,
+) (int64, error) {
+	return s.delivery.Count(ctx, filterQuery, filters)
+}
@@ -0,0 +1,116 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLocalStorage(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+
+	storage, err := NewLocalStorage(filepath.Join(root, "attachments"))
+	if err != nil {
+		t.Fatalf("NewLocalStorage() error = %v", err)
+	}
+
+	key := "client-id/journal-entry-id/receipt.pdf"
+	if err := storage.Put(ctx, key, []byte("%PDF-1.7"), "application/pdf"); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(root, "attachments", key)); err != nil {
+		t.Errorf("Put() left no file under the root: %v", err)
+	}
+
+	file, err := storage.Get(ctx, key)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+
+	content, err := io.ReadAll(file)
+	file.Close()
+	if err != nil || string(content) != "%PDF-1.7" {
+		t.Errorf("Get() = %q, %v, want %q", content, err, "%PDF-1.7")
+	}
+
+	if err := storage.Put(ctx, key, []byte("%PDF-2.0"), "application/pdf"); err != nil {
+		t.Fatalf("Put() again error = %v", err)
+	}
+
+	file, err = storage.Get(ctx, key)
+	if err != nil {
+		t.Fatalf("Get() after overwriting error = %v", err)
+	}
+
+	content, _ = io.ReadAll(file)
+	file.Close()
+	if string(content) != "%PDF-2.0" {
+		t.Errorf("Get() after overwriting = %q, want %q", content, "%PDF-2.0")
+	}
+
+	if err := storage.Delete(ctx, key); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+
+	if _, err := storage.Get(ctx, key); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get() after Delete() error = %v, want %v", err, ErrNotFound)
+	}
+
+	if err := storage.Delete(ctx, key); err != nil {
+		t.Errorf("Delete() of a missing file error = %v, want none", err)
+	}
+}
+
+func TestLocalStorageKeys(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+
+	storage, err := NewLocalStorage(filepath.Join(root, "attachments"))
+	if err != nil {
+		t.Fatalf("NewLocalStorage() error = %v", err)
+	}
+
+	tests := []struct {
+		key     string
+		path    string
+		wantErr bool
+	}{
+		{"receipt.pdf", "attachments/receipt.pdf", false},
+		{"/client-id/receipt.pdf", "attachments/client-id/receipt.pdf", false},
+		{"client-id//receipt.pdf", "attachments/client-id/receipt.pdf", false},
+		{"", "", true},
+		{"/", "", true},
+		{"../escaped.pdf", "", true},
+		{"client-id/../../escaped.pdf", "", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.key, func(t *testing.T) {
+			err := storage.Put(ctx, test.key, []byte("content"), "text/plain")
+			if (err != nil) != test.wantErr {
+				t.Fatalf("Put(%q) error = %v, want error %v", test.key, err, test.wantErr)
+			}
+
+			if test.wantErr {
+				if _, err := storage.Get(ctx, test.key); err == nil {
+					t.Errorf("Get(%q) error = nil, want an error", test.key)
+				}
+
+				return
+			}
+
+			if _, err := os.Stat(filepath.Join(root, test.path)); err != nil {
+				t.Errorf("Put(%q) did not write %s: %v", test.key, test.path, err)
+			}
+		})
+	}
+
+	if _, err := os.Stat(filepath.Join(root, "escaped.pdf")); err == nil {
+		t.Error("a key wrote outside the storage root")
+	}
+}
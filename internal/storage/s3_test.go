@@ -0,0 +1,258 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Bendomey/fincore-engine/internal/config"
+)
+
+// fakeS3 is an in-memory S3 stand-in for path style requests. Like S3 it refuses requests whose signature doesn't
+// match the request it received.
+type fakeS3 struct {
+	region          string
+	accessKeyID     string
+	secretAccessKey string
+
+	mutex        sync.Mutex
+	objects      map[string][]byte
+	contentTypes map[string]string
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+
+	if err := f.verify(r, body); err != nil {
+		w.WriteHeader(http.StatusForbidden)
+		fmt.Fprintf(w, "<Error><Code>SignatureDoesNotMatch</Code><Message>%s</Message></Error>", err)
+		return
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	switch r.Method {
+	case http.MethodPut:
+		f.objects[r.URL.Path] = body
+		f.contentTypes[r.URL.Path] = r.Header.Get("Content-Type")
+	case http.MethodGet:
+		object, ok := f.objects[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, "<Error><Code>NoSuchKey</Code></Error>")
+			return
+		}
+
+		w.Write(object)
+	case http.MethodDelete:
+		// deleting a missing object succeeds on S3 too.
+		delete(f.objects, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// verify checks the request's AWS Signature Version 4 from the headers it names as signed.
+func (f *fakeS3) verify(r *http.Request, body []byte) error {
+	credential, signedHeaders, signature, err := parseAuthorization(r.Header.Get("Authorization"))
+	if err != nil {
+		return err
+	}
+
+	amzDate := r.Header.Get("X-Amz-Date")
+	requestTime, err := time.Parse("20060102T150405Z", amzDate)
+	if err != nil {
+		return fmt.Errorf("bad X-Amz-Date %q", amzDate)
+	}
+
+	scope := requestTime.Format("20060102") + "/" + f.region + "/s3/aws4_request"
+	if credential != f.accessKeyID+"/"+scope {
+		return fmt.Errorf("credential = %s, want %s/%s", credential, f.accessKeyID, scope)
+	}
+
+	if r.Header.Get("X-Amz-Content-Sha256") != sha256Hex(body) {
+		return errors.New("X-Amz-Content-Sha256 is not the hash of the body")
+	}
+
+	names := strings.Split(signedHeaders, ";")
+	if !sort.StringsAreSorted(names) {
+		return fmt.Errorf("signed headers %s are not sorted", signedHeaders)
+	}
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		value := r.Header.Get(name)
+		if name == "host" {
+			value = r.Host
+		}
+
+		canonicalHeaders.WriteString(name + ":" + strings.TrimSpace(value) + "\n")
+	}
+
+	canonicalRequest := r.Method + "\n" +
+		r.URL.EscapedPath() + "\n" +
+		r.URL.RawQuery + "\n" +
+		canonicalHeaders.String() + "\n" +
+		signedHeaders + "\n" +
+		sha256Hex(body)
+
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonicalRequest))
+
+	key := []byte("AWS4" + f.secretAccessKey)
+	for _, part := range []string{requestTime.Format("20060102"), f.region, "s3", "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+
+	if want := fmt.Sprintf("%x", hmacSHA256(key, stringToSign)); signature != want {
+		return fmt.Errorf("signature = %s, want %s", signature, want)
+	}
+
+	return nil
+}
+
+func parseAuthorization(authorization string) (string, string, string, error) {
+	fields, ok := strings.CutPrefix(authorization, "AWS4-HMAC-SHA256 ")
+	if !ok {
+		return "", "", "", fmt.Errorf("authorization %q is not AWS4-HMAC-SHA256", authorization)
+	}
+
+	values := map[string]string{}
+	for _, field := range strings.Split(fields, ", ") {
+		name, value, _ := strings.Cut(field, "=")
+		values[name] = value
+	}
+
+	return values["Credential"], values["SignedHeaders"], values["Signature"], nil
+}
+
+func newTestS3Storage(t *testing.T, endpoint string, secretAccessKey string) Storage {
+	t.Helper()
+
+	storage, err := NewS3Storage(config.IStorage{
+		Driver:            "s3",
+		S3Endpoint:        endpoint,
+		S3Region:          "eu-west-1",
+		S3Bucket:          "fincore-attachments",
+		S3AccessKeyID:     "AKIDEXAMPLE",
+		S3SecretAccessKey: secretAccessKey,
+	})
+	if err != nil {
+		t.Fatalf("NewS3Storage() error = %v", err)
+	}
+
+	return storage
+}
+
+func newFakeS3() *fakeS3 {
+	return &fakeS3{
+		region:          "eu-west-1",
+		accessKeyID:     "AKIDEXAMPLE",
+		secretAccessKey: "secret",
+		objects:         map[string][]byte{},
+		contentTypes:    map[string]string{},
+	}
+}
+
+func TestS3Storage(t *testing.T) {
+	ctx := context.Background()
+	s3 := newFakeS3()
+
+	server := httptest.NewServer(s3)
+	defer server.Close()
+
+	for _, endpoint := range []string{server.URL, server.URL + "/minio/"} {
+		t.Run(endpoint, func(t *testing.T) {
+			storage := newTestS3Storage(t, endpoint, "secret")
+
+			prefix := strings.TrimSuffix(strings.TrimPrefix(endpoint, server.URL), "/")
+			path := prefix + "/fincore-attachments/client-id/journal-entry-id/receipt 1.pdf"
+
+			key := "client-id/journal-entry-id/receipt 1.pdf"
+			if err := storage.Put(ctx, key, []byte("%PDF-1.7"), "application/pdf"); err != nil {
+				t.Fatalf("Put() error = %v", err)
+			}
+
+			if got := string(s3.objects[path]); got != "%PDF-1.7" {
+				t.Errorf("stored %s = %q, want %q", path, got, "%PDF-1.7")
+			}
+
+			if got := s3.contentTypes[path]; got != "application/pdf" {
+				t.Errorf("stored content type = %q, want application/pdf", got)
+			}
+
+			file, err := storage.Get(ctx, key)
+			if err != nil {
+				t.Fatalf("Get() error = %v", err)
+			}
+
+			content, err := io.ReadAll(file)
+			file.Close()
+			if err != nil || string(content) != "%PDF-1.7" {
+				t.Errorf("Get() = %q, %v, want %q", content, err, "%PDF-1.7")
+			}
+
+			if err := storage.Delete(ctx, key); err != nil {
+				t.Fatalf("Delete() error = %v", err)
+			}
+
+			if _, ok := s3.objects[path]; ok {
+				t.Errorf("Delete() left %s", path)
+			}
+
+			if _, err := storage.Get(ctx, key); !errors.Is(err, ErrNotFound) {
+				t.Errorf("Get() after Delete() error = %v, want %v", err, ErrNotFound)
+			}
+
+			if err := storage.Delete(ctx, key); err != nil {
+				t.Errorf("Delete() of a missing object error = %v, want none", err)
+			}
+		})
+	}
+}
+
+func TestS3StorageRefused(t *testing.T) {
+	s3 := newFakeS3()
+
+	server := httptest.NewServer(s3)
+	defer server.Close()
+
+	storage := newTestS3Storage(t, server.URL, "wrong")
+
+	err := storage.Put(context.Background(), "receipt.pdf", []byte("%PDF-1.7"), "application/pdf")
+	if err == nil || !strings.Contains(err.Error(), "403") || !strings.Contains(err.Error(), "SignatureDoesNotMatch") {
+		t.Errorf("Put() error = %v, want the 403 and S3's answer", err)
+	}
+
+	if _, err := storage.Get(context.Background(), "receipt.pdf"); err == nil || errors.Is(err, ErrNotFound) {
+		t.Errorf("Get() error = %v, want a failure other than %v", err, ErrNotFound)
+	}
+
+	if len(s3.objects) != 0 {
+		t.Errorf("a refused Put() stored %d objects", len(s3.objects))
+	}
+}
+
+func TestNewS3Storage(t *testing.T) {
+	if _, err := NewS3Storage(config.IStorage{Driver: "s3", S3Region: "eu-west-1"}); err == nil {
+		t.Error("NewS3Storage() without a bucket error = nil, want an error")
+	}
+
+	storage, err := NewS3Storage(config.IStorage{Driver: "s3", S3Region: "eu-west-1", S3Bucket: "b"})
+	if err != nil {
+		t.Fatalf("NewS3Storage() error = %v", err)
+	}
+
+	if got := storage.(*s3Storage).endpoint.String(); got != "https://s3.eu-west-1.amazonaws.com" {
+		t.Errorf("default endpoint = %s, want https://s3.eu-west-1.amazonaws.com", got)
+	}
+}
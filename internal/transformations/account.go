@@ -11,16 +11,20 @@ func DBAccountToRestAccount(i *models.Account, populate *[]string) interface{} {
 	}
 
 	data := map[string]interface{}{
-		"id":                i.ID.String(),
-		"code":              i.Code,
-		"name":              i.Name,
-		"description":       i.Description,
-		"type":              i.Type,
-		"is_contra":         i.IsContra,
-		"is_group":          i.IsGroup,
-		"parent_account_id": i.ParentAccountID,
-		"created_at":        i.CreatedAt,
-		"updated_at":        i.UpdatedAt,
+		"id":                        i.ID.String(),
+		"ledger_id":                 i.LedgerID,
+		"code":                      i.Code,
+		"name":                      i.Name,
+		"description":               i.Description,
+		"type":                      i.Type,
+		"is_contra":                 i.IsContra,
+		"is_group":                  i.IsGroup,
+		"parent_account_id":         i.ParentAccountID,
+		"disallow_negative_balance": i.DisallowNegativeBalance,
+		"overdraft_limit":           i.OverdraftLimit,
+		"version":                   i.Version,
+		"created_at":                i.CreatedAt,
+		"updated_at":                i.UpdatedAt,
 	}
 
 	if populate != nil {
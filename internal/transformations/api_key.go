@@ -0,0 +1,37 @@
+package transformations
+
+import (
+	"time"
+
+	"github.com/Bendomey/fincore-engine/internal/models"
+)
+
+// DBApiKeyToRestApiKey transforms api_key db input to rest type
+func DBApiKeyToRestApiKey(i *models.ApiKey, secret *string) interface{} {
+	if i == nil {
+		return nil
+	}
+
+	data := map[string]interface{}{
+		"id":              i.ID.String(),
+		"label":           i.Label,
+		"prefix":          i.Prefix,
+		"scopes":          i.Scopes,
+		"status":          i.Status(time.Now()),
+		"can_sign":        i.SigningKey != nil,
+		"last_used_at":    i.LastUsedAt,
+		"expires_at":      i.ExpiresAt,
+		"revoked_at":      i.RevokedAt,
+		"rotated_from_id": i.RotatedFromID,
+		"credential_id":   i.CredentialID,
+		"created_at":      i.CreatedAt,
+		"updated_at":      i.UpdatedAt,
+	}
+
+	// only sent once, when the key is created or rotated.
+	if secret != nil {
+		data["secret"] = secret
+	}
+
+	return data
+}
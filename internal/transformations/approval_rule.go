@@ -0,0 +1,34 @@
+package transformations
+
+import (
+	"github.com/Bendomey/fincore-engine/internal/models"
+)
+
+// DBApprovalRuleToRestApprovalRule transforms approval_rule db input to rest type
+func DBApprovalRuleToRestApprovalRule(i *models.ApprovalRule, populate *[]string) interface{} {
+	if i == nil {
+		return nil
+	}
+
+	data := map[string]interface{}{
+		"id":         i.ID.String(),
+		"ledger_id":  i.LedgerID,
+		"name":       i.Name,
+		"min_amount": i.MinAmount,
+		"account_id": i.AccountID,
+		"is_active":  i.IsActive,
+		"created_at": i.CreatedAt,
+		"updated_at": i.UpdatedAt,
+	}
+
+	if populate != nil {
+		for _, field := range *populate {
+			switch field {
+			case "Account":
+				data["account"] = DBAccountToRestAccount(i.Account, nil)
+			}
+		}
+	}
+
+	return data
+}
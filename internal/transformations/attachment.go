@@ -0,0 +1,23 @@
+package transformations
+
+import (
+	"github.com/Bendomey/fincore-engine/internal/models"
+)
+
+// DBAttachmentToRestAttachment transforms attachment db input to rest type
+func DBAttachmentToRestAttachment(i *models.Attachment) interface{} {
+	if i == nil {
+		return nil
+	}
+
+	return map[string]interface{}{
+		"id":               i.ID.String(),
+		"journal_entry_id": i.JournalEntryID,
+		"file_name":        i.FileName,
+		"content_type":     i.ContentType,
+		"size":             i.Size,
+		"content_hash":     i.ContentHash,
+		"created_at":       i.CreatedAt,
+		"updated_at":       i.UpdatedAt,
+	}
+}
@@ -0,0 +1,25 @@
+package transformations
+
+import (
+	"github.com/Bendomey/fincore-engine/internal/models"
+)
+
+// DBAuditEventToRestAuditEvent transforms audit_event db input to rest type
+func DBAuditEventToRestAuditEvent(i *models.AuditEvent) interface{} {
+	if i == nil {
+		return nil
+	}
+
+	return map[string]interface{}{
+		"id":            i.ID.String(),
+		"ledger_id":     i.LedgerID,
+		"actor_id":      i.ActorID,
+		"request_id":    i.RequestID,
+		"action":        i.Action,
+		"resource_type": i.ResourceType,
+		"resource_id":   i.ResourceID,
+		"before":        i.Before,
+		"after":         i.After,
+		"created_at":    i.CreatedAt,
+	}
+}
@@ -0,0 +1,30 @@
+package transformations
+
+import (
+	"github.com/Bendomey/fincore-engine/internal/models"
+)
+
+// DBBudgetToRestBudget transforms budget db input to rest type
+func DBBudgetToRestBudget(i *models.Budget, populate *[]string) interface{} {
+	if i == nil {
+		return nil
+	}
+
+	data := map[string]interface{}{
+		"id":          i.ID.String(),
+		"name":        i.Name,
+		"description": i.Description,
+		"created_at":  i.CreatedAt,
+		"updated_at":  i.UpdatedAt,
+	}
+
+	if len(i.BudgetLines) > 0 {
+		lines := make([]interface{}, 0)
+		for _, line := range i.BudgetLines {
+			lines = append(lines, DBBudgetLineToRestBudgetLine(&line, populate))
+		}
+		data["lines"] = lines
+	}
+
+	return data
+}
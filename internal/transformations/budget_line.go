@@ -0,0 +1,39 @@
+package transformations
+
+import (
+	"time"
+
+	"github.com/Bendomey/fincore-engine/internal/models"
+)
+
+// DBBudgetLineToRestBudgetLine transforms budget_line db input to rest type
+func DBBudgetLineToRestBudgetLine(i *models.BudgetLine, populate *[]string) interface{} {
+	if i == nil {
+		return nil
+	}
+
+	data := map[string]interface{}{
+		"id":                 i.ID.String(),
+		"budget_id":          i.BudgetID,
+		"account_id":         i.AccountID,
+		"dimension_value_id": i.DimensionValueID,
+		"period_start":       i.PeriodStart.Format(time.DateOnly),
+		"period_end":         i.PeriodEnd.Format(time.DateOnly),
+		"amount":             i.Amount,
+		"created_at":         i.CreatedAt,
+		"updated_at":         i.UpdatedAt,
+	}
+
+	if populate != nil {
+		for _, field := range *populate {
+			switch field {
+			case "Account":
+				data["account"] = DBAccountToRestAccount(&i.Account, nil)
+			case "DimensionValue":
+				data["dimension_value"] = DBDimensionValueToRestDimensionValue(i.DimensionValue, nil)
+			}
+		}
+	}
+
+	return data
+}
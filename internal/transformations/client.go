@@ -15,6 +15,7 @@ func DBClientToRestClient(i *models.Client, secret *string) interface{} {
 		"name":       i.Name,
 		"email":      i.Email,
 		"client_id":  i.ClientId,
+		"currency":   i.Currency,
 		"created_at": i.CreatedAt,
 		"updated_at": i.UpdatedAt,
 	}
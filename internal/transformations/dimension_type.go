@@ -0,0 +1,37 @@
+package transformations
+
+import (
+	"github.com/Bendomey/fincore-engine/internal/models"
+)
+
+// DBDimensionTypeToRestDimensionType transforms dimension_type db input to rest type
+func DBDimensionTypeToRestDimensionType(i *models.DimensionType, populate *[]string) interface{} {
+	if i == nil {
+		return nil
+	}
+
+	data := map[string]interface{}{
+		"id":          i.ID.String(),
+		"ledger_id":   i.LedgerID,
+		"code":        i.Code,
+		"name":        i.Name,
+		"description": i.Description,
+		"created_at":  i.CreatedAt,
+		"updated_at":  i.UpdatedAt,
+	}
+
+	if populate != nil {
+		for _, field := range *populate {
+			switch field {
+			case "DimensionValues":
+				values := make([]interface{}, 0)
+				for _, value := range i.DimensionValues {
+					values = append(values, DBDimensionValueToRestDimensionValue(&value, nil))
+				}
+				data["values"] = values
+			}
+		}
+	}
+
+	return data
+}
@@ -0,0 +1,35 @@
+package transformations
+
+import (
+	"github.com/Bendomey/fincore-engine/internal/models"
+)
+
+// DBDimensionValueToRestDimensionValue transforms dimension_value db input to rest type
+func DBDimensionValueToRestDimensionValue(i *models.DimensionValue, populate *[]string) interface{} {
+	if i == nil {
+		return nil
+	}
+
+	data := map[string]interface{}{
+		"id":                i.ID.String(),
+		"ledger_id":         i.LedgerID,
+		"dimension_type_id": i.DimensionTypeID,
+		"code":              i.Code,
+		"name":              i.Name,
+		"description":       i.Description,
+		"is_active":         i.IsActive,
+		"created_at":        i.CreatedAt,
+		"updated_at":        i.UpdatedAt,
+	}
+
+	if populate != nil {
+		for _, field := range *populate {
+			switch field {
+			case "DimensionType":
+				data["dimension_type"] = DBDimensionTypeToRestDimensionType(&i.DimensionType, nil)
+			}
+		}
+	}
+
+	return data
+}
@@ -0,0 +1,49 @@
+package transformations
+
+import (
+	"github.com/Bendomey/fincore-engine/internal/lib"
+	"github.com/Bendomey/fincore-engine/internal/models"
+)
+
+// DBHoldToRestHold transforms hold db input to rest type
+func DBHoldToRestHold(i *models.Hold, populate *[]string) interface{} {
+	if i == nil {
+		return nil
+	}
+
+	data := map[string]interface{}{
+		"id":                      i.ID.String(),
+		"ledger_id":               i.LedgerID,
+		"account_id":              i.AccountID,
+		"destination_account_id":  i.DestinationAccountID,
+		"amount":                  i.Amount,
+		"currency":                i.Currency,
+		"amount_decimal":          lib.Money{Amount: i.Amount, Currency: i.Currency}.Decimal(),
+		"reference":               i.Reference,
+		"metadata":                i.Metadata,
+		"status":                  i.Status,
+		"expires_at":              i.ExpiresAt,
+		"captured_amount":         i.CapturedAmount,
+		"captured_amount_decimal": lib.Money{Amount: i.CapturedAmount, Currency: i.Currency}.Decimal(),
+		"captured_at":             i.CapturedAt,
+		"voided_at":               i.VoidedAt,
+		"journal_entry_id":        i.JournalEntryID,
+		"created_at":              i.CreatedAt,
+		"updated_at":              i.UpdatedAt,
+	}
+
+	if populate != nil {
+		for _, field := range *populate {
+			switch field {
+			case "Account":
+				data["account"] = DBAccountToRestAccount(&i.Account, nil)
+			case "DestinationAccount":
+				data["destination_account"] = DBAccountToRestAccount(&i.DestinationAccount, nil)
+			case "JournalEntry":
+				data["journal_entry"] = DBJournalEntryToRestJournalEntry(i.JournalEntry, populate)
+			}
+		}
+	}
+
+	return data
+}
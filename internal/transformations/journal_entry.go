@@ -12,11 +12,25 @@ func DBJournalEntryToRestJournalEntry(i *models.JournalEntry, populate *[]string
 
 	data := map[string]interface{}{
 		"id":               i.ID.String(),
+		"ledger_id":        i.LedgerID,
 		"reference":        i.Reference,
 		"status":           i.Status,
 		"posted_at":        i.PostedAt,
 		"transaction_date": i.TransactionDate,
+		"currency":         i.Currency,
 		"metadata":         i.Metadata,
+		"created_by":       i.CreatedBy,
+		"approved_by":      i.ApprovedBy,
+		"approved_at":      i.ApprovedAt,
+		"rejected_by":      i.RejectedBy,
+		"rejected_at":      i.RejectedAt,
+		"rejection_reason": i.RejectionReason,
+		"reversal_of_id":   i.ReversalOfID,
+		"ledger_sequence":  i.LedgerSequence,
+		"previous_hash":    i.PreviousHash,
+		"hash":             i.Hash,
+		"hash_version":     i.HashVersion,
+		"version":          i.Version,
 		"created_at":       i.CreatedAt,
 		"updated_at":       i.UpdatedAt,
 	}
@@ -29,5 +43,13 @@ func DBJournalEntryToRestJournalEntry(i *models.JournalEntry, populate *[]string
 		data["lines"] = lines
 	}
 
+	if len(i.JournalEntryTransitions) > 0 {
+		transitions := make([]interface{}, 0)
+		for _, transition := range i.JournalEntryTransitions {
+			transitions = append(transitions, DBJournalEntryTransitionToRestJournalEntryTransition(&transition))
+		}
+		data["transitions"] = transitions
+	}
+
 	return data
 }
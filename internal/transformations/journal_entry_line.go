@@ -1,6 +1,7 @@
 package transformations
 
 import (
+	"github.com/Bendomey/fincore-engine/internal/lib"
 	"github.com/Bendomey/fincore-engine/internal/models"
 )
 
@@ -16,11 +17,22 @@ func DBJournalEntryLineToRestJournalEntryLine(i *models.JournalEntryLine, popula
 		"account_id":       i.AccountID,
 		"debit":            i.Debit,
 		"credit":           i.Credit,
+		"currency":         i.Currency,
+		"debit_decimal":    lib.Money{Amount: i.Debit, Currency: i.Currency}.Decimal(),
+		"credit_decimal":   lib.Money{Amount: i.Credit, Currency: i.Currency}.Decimal(),
 		"notes":            i.Notes,
 		"created_at":       i.CreatedAt,
 		"updated_at":       i.UpdatedAt,
 	}
 
+	if len(i.DimensionValues) > 0 {
+		dimensions := make([]interface{}, 0)
+		for _, value := range i.DimensionValues {
+			dimensions = append(dimensions, DBDimensionValueToRestDimensionValue(&value, nil))
+		}
+		data["dimensions"] = dimensions
+	}
+
 	if populate != nil {
 		for _, field := range *populate {
 			switch field {
@@ -34,3 +46,18 @@ func DBJournalEntryLineToRestJournalEntryLine(i *models.JournalEntryLine, popula
 
 	return data
 }
+
+// DBJournalEntryLineToRestJournalEntryLineRow transforms a line listed on its own, outside of its entry. It carries
+// the reference, transaction date and status of its entry, which must be loaded.
+func DBJournalEntryLineToRestJournalEntryLineRow(i *models.JournalEntryLine, populate *[]string) interface{} {
+	if i == nil {
+		return nil
+	}
+
+	data := DBJournalEntryLineToRestJournalEntryLine(i, populate).(map[string]interface{})
+	data["reference"] = i.JournalEntry.Reference
+	data["transaction_date"] = i.JournalEntry.TransactionDate
+	data["status"] = i.JournalEntry.Status
+
+	return data
+}
@@ -0,0 +1,21 @@
+package transformations
+
+import (
+	"github.com/Bendomey/fincore-engine/internal/models"
+)
+
+// DBJournalEntryTransitionToRestJournalEntryTransition transforms journal_entry_transition db input to rest type
+func DBJournalEntryTransitionToRestJournalEntryTransition(i *models.JournalEntryTransition) interface{} {
+	if i == nil {
+		return nil
+	}
+
+	return map[string]interface{}{
+		"id":          i.ID.String(),
+		"from_status": i.FromStatus,
+		"to_status":   i.ToStatus,
+		"actor_id":    i.ActorID,
+		"reason":      i.Reason,
+		"created_at":  i.CreatedAt,
+	}
+}
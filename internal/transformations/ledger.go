@@ -0,0 +1,45 @@
+package transformations
+
+import (
+	"github.com/Bendomey/fincore-engine/internal/models"
+)
+
+// DBLedgerToRestLedger transforms ledger db input to rest type
+func DBLedgerToRestLedger(i *models.Ledger) interface{} {
+	if i == nil {
+		return nil
+	}
+
+	return map[string]interface{}{
+		"id":                      i.ID.String(),
+		"name":                    i.Name,
+		"description":             i.Description,
+		"currency":                i.Currency,
+		"fiscal_year_start_month": i.FiscalYearStartMonth,
+		"is_default":              i.IsDefault,
+		"created_at":              i.CreatedAt,
+		"updated_at":              i.UpdatedAt,
+	}
+}
+
+// LedgerVerificationToRestLedgerVerification transforms a ledger verification to rest type
+func LedgerVerificationToRestLedgerVerification(i *models.LedgerVerification) interface{} {
+	if i == nil {
+		return nil
+	}
+
+	var firstBreak interface{}
+	if i.FirstBreak != nil {
+		firstBreak = map[string]interface{}{
+			"journal_entry_id": i.FirstBreak.JournalEntryID,
+			"ledger_sequence":  i.FirstBreak.LedgerSequence,
+			"reason":           i.FirstBreak.Reason,
+		}
+	}
+
+	return map[string]interface{}{
+		"entries_checked": i.EntriesChecked,
+		"valid":           i.Valid,
+		"first_break":     firstBreak,
+	}
+}
@@ -0,0 +1,135 @@
+package transformations
+
+import (
+	"time"
+
+	"github.com/Bendomey/fincore-engine/internal/models"
+)
+
+// ReportAccountBalancesToRestAccountBalances transforms the account balances report to rest type
+func ReportAccountBalancesToRestAccountBalances(i *models.AccountBalancesReport) interface{} {
+	if i == nil {
+		return nil
+	}
+
+	accounts := make([]interface{}, 0)
+	for _, balance := range i.Accounts {
+		accounts = append(accounts, reportAccountBalanceToRest(balance, i.GroupBy != nil, true))
+	}
+
+	return map[string]interface{}{
+		"currency": i.Currency,
+		"group_by": DBDimensionTypeToRestDimensionType(i.GroupBy, nil),
+		"accounts": accounts,
+	}
+}
+
+// ReportIncomeStatementToRestIncomeStatement transforms the income statement report to rest type
+func ReportIncomeStatementToRestIncomeStatement(i *models.IncomeStatementReport) interface{} {
+	if i == nil {
+		return nil
+	}
+
+	grouped := i.GroupBy != nil
+	income := make([]interface{}, 0)
+	for _, balance := range i.Income.Accounts {
+		income = append(income, reportAccountBalanceToRest(balance, grouped, false))
+	}
+
+	expenses := make([]interface{}, 0)
+	for _, balance := range i.Expenses.Accounts {
+		expenses = append(expenses, reportAccountBalanceToRest(balance, grouped, false))
+	}
+
+	data := map[string]interface{}{
+		"currency": i.Currency,
+		"group_by": DBDimensionTypeToRestDimensionType(i.GroupBy, nil),
+		"income": map[string]interface{}{
+			"accounts": income,
+			"total":    i.Income.Total,
+		},
+		"expenses": map[string]interface{}{
+			"accounts": expenses,
+			"total":    i.Expenses.Total,
+		},
+		"net_income": i.NetIncome,
+	}
+
+	if grouped {
+		subtotals := make([]interface{}, 0)
+		for _, subtotal := range i.Subtotals {
+			subtotals = append(subtotals, map[string]interface{}{
+				"dimension_value": DBDimensionValueToRestDimensionValue(subtotal.DimensionValue, nil),
+				"income":          subtotal.Income,
+				"expenses":        subtotal.Expenses,
+				"net_income":      subtotal.NetIncome,
+			})
+		}
+		data["subtotals"] = subtotals
+	}
+
+	return data
+}
+
+// ReportBudgetVsActualToRestBudgetVsActual transforms the budget vs actual report to rest type
+func ReportBudgetVsActualToRestBudgetVsActual(i *models.BudgetVsActualReport) interface{} {
+	if i == nil {
+		return nil
+	}
+
+	periods := make([]interface{}, 0)
+	for _, period := range i.Periods {
+		lines := make([]interface{}, 0)
+		for _, line := range period.Lines {
+			lines = append(lines, map[string]interface{}{
+				"account":          DBAccountToRestAccount(&line.Account, nil),
+				"dimension_value":  DBDimensionValueToRestDimensionValue(line.DimensionValue, nil),
+				"budget":           line.Budget,
+				"actual":           line.Actual,
+				"variance":         line.Variance,
+				"variance_percent": line.VariancePercent,
+			})
+		}
+
+		periods = append(periods, map[string]interface{}{
+			"period_start": period.PeriodStart.Format(time.DateOnly),
+			"period_end":   period.PeriodEnd.Format(time.DateOnly),
+			"lines":        lines,
+		})
+	}
+
+	return map[string]interface{}{
+		"currency": i.Currency,
+		"budget":   DBBudgetToRestBudget(&i.Budget, nil),
+		"periods":  periods,
+	}
+}
+
+func reportAccountBalanceToRest(balance models.AccountBalance, grouped bool, includeAvailable bool) interface{} {
+	data := map[string]interface{}{
+		"account": DBAccountToRestAccount(&balance.Account, nil),
+		"debit":   balance.Debit,
+		"credit":  balance.Credit,
+		"balance": balance.Balance,
+	}
+
+	if includeAvailable {
+		data["held"] = balance.Held
+		data["available_balance"] = balance.Available
+	}
+
+	if grouped {
+		subtotals := make([]interface{}, 0)
+		for _, subtotal := range balance.Subtotals {
+			subtotals = append(subtotals, map[string]interface{}{
+				"dimension_value": DBDimensionValueToRestDimensionValue(subtotal.DimensionValue, nil),
+				"debit":           subtotal.Debit,
+				"credit":          subtotal.Credit,
+				"balance":         subtotal.Balance,
+			})
+		}
+		data["subtotals"] = subtotals
+	}
+
+	return data
+}
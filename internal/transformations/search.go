@@ -0,0 +1,33 @@
+package transformations
+
+import (
+	"github.com/Bendomey/fincore-engine/internal/models"
+)
+
+// SearchHitToRestSearchHit transforms a search hit to rest type, with only the fields of its type.
+func SearchHitToRestSearchHit(i *models.SearchHit) interface{} {
+	if i == nil {
+		return nil
+	}
+
+	data := map[string]interface{}{
+		"type": i.Type,
+		"id":   i.ID,
+		"rank": i.Rank,
+		"text": i.Text,
+	}
+
+	switch i.Type {
+	case models.SearchHitTypeAccount:
+		data["code"] = i.Code
+	case models.SearchHitTypeJournalEntry:
+		data["reference"] = i.Reference
+		data["transaction_date"] = i.TransactionDate
+	case models.SearchHitTypeJournalEntryLine:
+		data["journal_entry_id"] = i.JournalEntryID
+		data["reference"] = i.Reference
+		data["transaction_date"] = i.TransactionDate
+	}
+
+	return data
+}
@@ -0,0 +1,44 @@
+package transformations
+
+import (
+	"github.com/Bendomey/fincore-engine/internal/lib"
+	"github.com/Bendomey/fincore-engine/internal/models"
+)
+
+// DBTransferToRestTransfer transforms transfer db input to rest type
+func DBTransferToRestTransfer(i *models.Transfer, populate *[]string) interface{} {
+	if i == nil {
+		return nil
+	}
+
+	data := map[string]interface{}{
+		"id":               i.ID.String(),
+		"ledger_id":        i.LedgerID,
+		"from_account_id":  i.FromAccountID,
+		"to_account_id":    i.ToAccountID,
+		"amount":           i.Amount,
+		"currency":         i.Currency,
+		"amount_decimal":   lib.Money{Amount: i.Amount, Currency: i.Currency}.Decimal(),
+		"reference":        i.Reference,
+		"metadata":         i.Metadata,
+		"status":           i.JournalEntry.Status,
+		"journal_entry_id": i.JournalEntryID,
+		"created_at":       i.CreatedAt,
+		"updated_at":       i.UpdatedAt,
+	}
+
+	if populate != nil {
+		for _, field := range *populate {
+			switch field {
+			case "FromAccount":
+				data["from_account"] = DBAccountToRestAccount(&i.FromAccount, nil)
+			case "ToAccount":
+				data["to_account"] = DBAccountToRestAccount(&i.ToAccount, nil)
+			case "JournalEntry":
+				data["journal_entry"] = DBJournalEntryToRestJournalEntry(&i.JournalEntry, populate)
+			}
+		}
+	}
+
+	return data
+}
@@ -0,0 +1,54 @@
+package transformations
+
+import (
+	"github.com/Bendomey/fincore-engine/internal/models"
+)
+
+// DBWebhookDeliveryToRestWebhookDelivery transforms webhook_delivery db input to rest type
+func DBWebhookDeliveryToRestWebhookDelivery(i *models.WebhookDelivery, populate *[]string) interface{} {
+	if i == nil {
+		return nil
+	}
+
+	data := map[string]interface{}{
+		"id":                  i.ID.String(),
+		"webhook_endpoint_id": i.WebhookEndpointID,
+		"webhook_event_id":    i.WebhookEventID,
+		"redelivery_of_id":    i.RedeliveryOfID,
+		"status":              i.Status,
+		"attempts":            i.Attempts,
+		"next_attempt_at":     i.NextAttemptAt,
+		"last_attempt_at":     i.LastAttemptAt,
+		"response_status":     i.ResponseStatus,
+		"response_body":       i.ResponseBody,
+		"error":               i.Error,
+		"created_at":          i.CreatedAt,
+		"updated_at":          i.UpdatedAt,
+	}
+
+	if populate != nil {
+		for _, field := range *populate {
+			switch field {
+			case "WebhookEvent":
+				data["event"] = DBWebhookEventToRestWebhookEvent(&i.WebhookEvent)
+			}
+		}
+	}
+
+	return data
+}
+
+// DBWebhookEventToRestWebhookEvent transforms webhook_event db input to rest type, the body that is delivered.
+func DBWebhookEventToRestWebhookEvent(i *models.WebhookEvent) interface{} {
+	if i == nil {
+		return nil
+	}
+
+	return map[string]interface{}{
+		"id":         i.ID.String(),
+		"ledger_id":  i.LedgerID,
+		"type":       i.Type,
+		"created_at": i.CreatedAt,
+		"data":       i.Payload,
+	}
+}
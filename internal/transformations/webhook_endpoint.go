@@ -0,0 +1,30 @@
+package transformations
+
+import (
+	"github.com/Bendomey/fincore-engine/internal/models"
+)
+
+// DBWebhookEndpointToRestWebhookEndpoint transforms webhook_endpoint db input to rest type
+func DBWebhookEndpointToRestWebhookEndpoint(i *models.WebhookEndpoint, secret *string) interface{} {
+	if i == nil {
+		return nil
+	}
+
+	data := map[string]interface{}{
+		"id":          i.ID.String(),
+		"ledger_id":   i.LedgerID,
+		"url":         i.URL,
+		"description": i.Description,
+		"events":      i.Events,
+		"is_active":   i.IsActive,
+		"created_at":  i.CreatedAt,
+		"updated_at":  i.UpdatedAt,
+	}
+
+	// only sent once, when the endpoint is created.
+	if secret != nil {
+		data["secret"] = secret
+	}
+
+	return data
+}
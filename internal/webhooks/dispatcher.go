@@ -0,0 +1,208 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/Bendomey/fincore-engine/internal/models"
+	"github.com/Bendomey/fincore-engine/internal/repository"
+	"github.com/Bendomey/fincore-engine/internal/transformations"
+	"github.com/getsentry/raven-go"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	defaultPollInterval   = 5 * time.Second
+	defaultMaxAttempts    = 8
+	defaultBackoffBase    = 30 * time.Second
+	defaultBackoffMax     = 6 * time.Hour
+	defaultRequestTimeout = 10 * time.Second
+	defaultBatchSize      = 100
+
+	// long enough for a slow endpoint to answer before another worker tries the delivery again.
+	claimLease = 2 * time.Minute
+
+	// how much of the endpoint's answer is kept in the delivery log.
+	maxResponseBodySize = 4 * 1024
+)
+
+// Dispatcher moves events from the outbox to the subscribed endpoints. It fans new events out into deliveries
+// and sends the deliveries that are due, retrying failures with exponential backoff. Any number of dispatchers
+// can run against the same database.
+type Dispatcher struct {
+	events     repository.WebhookEventRepository
+	deliveries repository.WebhookDeliveryRepository
+
+	// exported so they can be tuned, eg. pointed at a local server with short backoffs. HTTPClient only reaches
+	// public addresses unless replaced.
+	HTTPClient   *http.Client
+	PollInterval time.Duration
+	MaxAttempts  int
+	BackoffBase  time.Duration
+	BackoffMax   time.Duration
+	Now          func() time.Time
+}
+
+func NewDispatcher(
+	events repository.WebhookEventRepository,
+	deliveries repository.WebhookDeliveryRepository,
+) *Dispatcher {
+	return &Dispatcher{
+		events:       events,
+		deliveries:   deliveries,
+		HTTPClient:   NewEndpointClient(defaultRequestTimeout),
+		PollInterval: defaultPollInterval,
+		MaxAttempts:  defaultMaxAttempts,
+		BackoffBase:  defaultBackoffBase,
+		BackoffMax:   defaultBackoffMax,
+		Now:          time.Now,
+	}
+}
+
+// Run polls until ctx is done.
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := d.Tick(ctx); err != nil {
+			log.Errorf("webhook dispatcher: %v", err)
+			raven.CaptureError(err, nil)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Tick dispatches new events and sends the deliveries that are due, once.
+func (d *Dispatcher) Tick(ctx context.Context) error {
+	for {
+		dispatched, err := d.events.Dispatch(ctx, defaultBatchSize)
+		if err != nil {
+			return err
+		}
+
+		if dispatched < defaultBatchSize {
+			break
+		}
+	}
+
+	for {
+		deliveries, err := d.deliveries.ClaimDue(ctx, claimLease, defaultBatchSize)
+		if err != nil {
+			return err
+		}
+
+		for index := range *deliveries {
+			if err := d.Deliver(ctx, &(*deliveries)[index]); err != nil {
+				return err
+			}
+		}
+
+		if len(*deliveries) < defaultBatchSize {
+			return nil
+		}
+	}
+}
+
+// Deliver makes one attempt at the delivery, its endpoint and event must be loaded, and records the outcome.
+// The returned error is about recording it, a failing endpoint only schedules a retry.
+func (d *Dispatcher) Deliver(ctx context.Context, delivery *models.WebhookDelivery) error {
+	now := d.Now()
+
+	delivery.Attempts++
+	delivery.LastAttemptAt = &now
+	delivery.ResponseStatus = nil
+	delivery.ResponseBody = nil
+	delivery.Error = nil
+
+	if delivery.WebhookEndpoint.DeletedAt.Valid || !delivery.WebhookEndpoint.IsActive {
+		message := "webhook endpoint was removed or disabled"
+		delivery.Error = &message
+		delivery.Status = models.WebhookDeliveryStatusFailed
+		delivery.NextAttemptAt = nil
+
+		return d.deliveries.Update(ctx, delivery)
+	}
+
+	status, body, err := d.send(ctx, delivery, now)
+	delivery.ResponseStatus = status
+	delivery.ResponseBody = body
+
+	if err == nil {
+		delivery.Status = models.WebhookDeliveryStatusSucceeded
+		delivery.NextAttemptAt = nil
+
+		return d.deliveries.Update(ctx, delivery)
+	}
+
+	message := err.Error()
+	delivery.Error = &message
+
+	if delivery.Attempts >= d.MaxAttempts {
+		delivery.Status = models.WebhookDeliveryStatusFailed
+		delivery.NextAttemptAt = nil
+	} else {
+		next := now.Add(d.backoff(delivery.Attempts))
+		delivery.Status = models.WebhookDeliveryStatusPending
+		delivery.NextAttemptAt = &next
+	}
+
+	return d.deliveries.Update(ctx, delivery)
+}
+
+func (d *Dispatcher) send(ctx context.Context, delivery *models.WebhookDelivery, now time.Time) (*int, *string, error) {
+	body, err := json.Marshal(transformations.DBWebhookEventToRestWebhookEvent(&delivery.WebhookEvent))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.WebhookEndpoint.URL, bytes.NewReader(body))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("User-Agent", "FinCore-Webhooks/1.0")
+	request.Header.Set("X-FinCore-Event", delivery.WebhookEvent.Type)
+	request.Header.Set("X-FinCore-Event-Id", delivery.WebhookEvent.ID.String())
+	request.Header.Set("X-FinCore-Delivery-Id", delivery.ID.String())
+	request.Header.Set(SignatureHeader, SignatureHeaderValue(delivery.WebhookEndpoint.Secret, now, body))
+
+	response, err := d.HTTPClient.Do(request)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer response.Body.Close()
+
+	responseBody, _ := io.ReadAll(io.LimitReader(response.Body, maxResponseBodySize))
+	responseText := string(responseBody)
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return &response.StatusCode, &responseText, fmt.Errorf("endpoint responded with %d", response.StatusCode)
+	}
+
+	return &response.StatusCode, &responseText, nil
+}
+
+// backoff doubles the wait after every failed attempt, up to BackoffMax.
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	wait := d.BackoffBase
+	for i := 1; i < attempts; i++ {
+		wait *= 2
+		if wait >= d.BackoffMax {
+			return d.BackoffMax
+		}
+	}
+
+	return wait
+}
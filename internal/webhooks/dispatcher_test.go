@@ -0,0 +1,298 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Bendomey/fincore-engine/internal/models"
+	"github.com/Bendomey/fincore-engine/internal/repository"
+	"github.com/gofrs/uuid"
+)
+
+// fakeEvents has no new events to dispatch.
+type fakeEvents struct {
+	repository.WebhookEventRepository
+}
+
+func (f *fakeEvents) Dispatch(ctx context.Context, limit int) (int, error) {
+	return 0, nil
+}
+
+// fakeDeliveries hands out its due deliveries once and keeps every update, the delivery log as it was written.
+type fakeDeliveries struct {
+	repository.WebhookDeliveryRepository
+	due     []models.WebhookDelivery
+	updates []models.WebhookDelivery
+}
+
+func (f *fakeDeliveries) ClaimDue(
+	ctx context.Context,
+	lease time.Duration,
+	limit int,
+) (*[]models.WebhookDelivery, error) {
+	due := f.due
+	f.due = nil
+	return &due, nil
+}
+
+func (f *fakeDeliveries) Update(ctx context.Context, webhookDelivery *models.WebhookDelivery) error {
+	f.updates = append(f.updates, *webhookDelivery)
+	return nil
+}
+
+func newTestDelivery(url string) models.WebhookDelivery {
+	delivery := models.WebhookDelivery{
+		Status: models.WebhookDeliveryStatusPending,
+		WebhookEndpoint: models.WebhookEndpoint{
+			URL:      url,
+			Secret:   "whsec_test",
+			IsActive: true,
+		},
+		WebhookEvent: models.WebhookEvent{
+			Sequence: 7,
+			Type:     models.WebhookEventAccountCreated,
+			Payload:  []byte(`{"id":"account-id"}`),
+		},
+	}
+	delivery.ID = uuid.Must(uuid.NewV4())
+	delivery.WebhookEvent.ID = uuid.Must(uuid.NewV4())
+
+	return delivery
+}
+
+func newTestDispatcher(deliveries *fakeDeliveries, now time.Time) *Dispatcher {
+	dispatcher := NewDispatcher(&fakeEvents{}, deliveries)
+	// the test server listens on loopback, which the default client refuses.
+	dispatcher.HTTPClient = &http.Client{Timeout: time.Second}
+	dispatcher.Now = func() time.Time { return now }
+
+	return dispatcher
+}
+
+func TestDispatcherRetriesUntilDelivered(t *testing.T) {
+	now := time.Unix(1760000000, 0)
+	statuses := []int{http.StatusInternalServerError, http.StatusBadGateway, http.StatusOK}
+
+	var requests []*http.Request
+	var bodies [][]byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, r)
+		bodies = append(bodies, body)
+
+		w.WriteHeader(statuses[len(requests)-1])
+		fmt.Fprintf(w, "attempt %d", len(requests))
+	}))
+	defer server.Close()
+
+	deliveries := &fakeDeliveries{}
+	dispatcher := newTestDispatcher(deliveries, now)
+	delivery := newTestDelivery(server.URL)
+
+	for range statuses {
+		if err := dispatcher.Deliver(context.Background(), &delivery); err != nil {
+			t.Fatalf("Deliver() error = %v", err)
+		}
+	}
+
+	if len(requests) != 3 {
+		t.Fatalf("endpoint got %d requests, want 3", len(requests))
+	}
+
+	for index, request := range requests {
+		if request.Method != http.MethodPost {
+			t.Errorf("request %d method = %s, want POST", index, request.Method)
+		}
+
+		want := "t=1760000000,v1=" + Sign("whsec_test", now, bodies[index])
+		if got := request.Header.Get(SignatureHeader); got != want {
+			t.Errorf("request %d %s = %s, want %s", index, SignatureHeader, got, want)
+		}
+
+		if got := request.Header.Get("X-FinCore-Event"); got != models.WebhookEventAccountCreated {
+			t.Errorf("request %d X-FinCore-Event = %s, want %s", index, got, models.WebhookEventAccountCreated)
+		}
+
+		if got := request.Header.Get("X-FinCore-Delivery-Id"); got != delivery.ID.String() {
+			t.Errorf("request %d X-FinCore-Delivery-Id = %s, want %s", index, got, delivery.ID)
+		}
+
+		if !strings.Contains(string(bodies[index]), `"account-id"`) {
+			t.Errorf("request %d body = %s, want the event payload", index, bodies[index])
+		}
+	}
+
+	tests := []struct {
+		status         string
+		responseStatus int
+		responseBody   string
+		nextAttemptIn  time.Duration
+		hasError       bool
+	}{
+		{models.WebhookDeliveryStatusPending, http.StatusInternalServerError, "attempt 1", 30 * time.Second, true},
+		{models.WebhookDeliveryStatusPending, http.StatusBadGateway, "attempt 2", time.Minute, true},
+		{models.WebhookDeliveryStatusSucceeded, http.StatusOK, "attempt 3", 0, false},
+	}
+
+	if len(deliveries.updates) != len(tests) {
+		t.Fatalf("delivery log has %d rows, want %d", len(deliveries.updates), len(tests))
+	}
+
+	for index, test := range tests {
+		logged := deliveries.updates[index]
+
+		if logged.Attempts != index+1 {
+			t.Errorf("attempt %d attempts = %d, want %d", index+1, logged.Attempts, index+1)
+		}
+
+		if logged.Status != test.status {
+			t.Errorf("attempt %d status = %s, want %s", index+1, logged.Status, test.status)
+		}
+
+		if logged.ResponseStatus == nil || *logged.ResponseStatus != test.responseStatus {
+			t.Errorf("attempt %d response status = %v, want %d", index+1, logged.ResponseStatus, test.responseStatus)
+		}
+
+		if logged.ResponseBody == nil || *logged.ResponseBody != test.responseBody {
+			t.Errorf("attempt %d response body = %v, want %s", index+1, logged.ResponseBody, test.responseBody)
+		}
+
+		if (logged.Error != nil) != test.hasError {
+			t.Errorf("attempt %d error = %v, want error %v", index+1, logged.Error, test.hasError)
+		}
+
+		if test.nextAttemptIn == 0 {
+			if logged.NextAttemptAt != nil {
+				t.Errorf("attempt %d next attempt = %v, want none", index+1, logged.NextAttemptAt)
+			}
+
+			continue
+		}
+
+		want := now.Add(test.nextAttemptIn)
+		if logged.NextAttemptAt == nil || !logged.NextAttemptAt.Equal(want) {
+			t.Errorf("attempt %d next attempt = %v, want %v", index+1, logged.NextAttemptAt, want)
+		}
+	}
+}
+
+func TestDispatcherGivesUpAfterMaxAttempts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	deliveries := &fakeDeliveries{due: []models.WebhookDelivery{newTestDelivery(server.URL)}}
+	dispatcher := newTestDispatcher(deliveries, time.Unix(1760000000, 0))
+	dispatcher.MaxAttempts = 1
+
+	if err := dispatcher.Tick(context.Background()); err != nil {
+		t.Fatalf("Tick() error = %v", err)
+	}
+
+	if len(deliveries.updates) != 1 {
+		t.Fatalf("delivery log has %d rows, want 1", len(deliveries.updates))
+	}
+
+	logged := deliveries.updates[0]
+	if logged.Status != models.WebhookDeliveryStatusFailed || logged.NextAttemptAt != nil {
+		t.Errorf("delivery = %s next at %v, want FAILED with no next attempt", logged.Status, logged.NextAttemptAt)
+	}
+}
+
+func TestDispatcherSkipsDisabledEndpoints(t *testing.T) {
+	requested := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = true
+	}))
+	defer server.Close()
+
+	delivery := newTestDelivery(server.URL)
+	delivery.WebhookEndpoint.IsActive = false
+
+	deliveries := &fakeDeliveries{}
+	dispatcher := newTestDispatcher(deliveries, time.Unix(1760000000, 0))
+
+	if err := dispatcher.Deliver(context.Background(), &delivery); err != nil {
+		t.Fatalf("Deliver() error = %v", err)
+	}
+
+	if requested {
+		t.Error("disabled endpoint was sent the event")
+	}
+
+	if delivery.Status != models.WebhookDeliveryStatusFailed {
+		t.Errorf("delivery status = %s, want %s", delivery.Status, models.WebhookDeliveryStatusFailed)
+	}
+}
+
+func TestDispatcherRefusesLoopback(t *testing.T) {
+	requested := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = true
+	}))
+	defer server.Close()
+
+	deliveries := &fakeDeliveries{}
+	dispatcher := NewDispatcher(&fakeEvents{}, deliveries)
+	delivery := newTestDelivery(server.URL)
+
+	if err := dispatcher.Deliver(context.Background(), &delivery); err != nil {
+		t.Fatalf("Deliver() error = %v", err)
+	}
+
+	if requested {
+		t.Error("the default client connected to a loopback address")
+	}
+
+	if delivery.Error == nil || !strings.Contains(*delivery.Error, ErrDisallowedEndpoint.Error()) {
+		t.Errorf("delivery error = %v, want %v", delivery.Error, ErrDisallowedEndpoint)
+	}
+}
+
+func TestValidateEndpointURL(t *testing.T) {
+	tests := []struct {
+		url     string
+		allowed bool
+	}{
+		{"https://93.184.216.34/hooks", true},
+		{"http://93.184.216.34:8080/hooks", true},
+		{"https://[2606:2800:220:1:248:1893:25c8:1946]/hooks", true},
+		{"ftp://93.184.216.34/hooks", false},
+		{"/hooks", false},
+		{"http://127.0.0.1/hooks", false},
+		{"http://127.1.2.3:9000/hooks", false},
+		{"http://[::1]/hooks", false},
+		{"http://localhost/hooks", false},
+		{"http://api.localhost/hooks", false},
+		{"http://10.0.0.5/hooks", false},
+		{"http://172.16.0.1/hooks", false},
+		{"http://192.168.1.10/hooks", false},
+		{"http://100.64.0.1/hooks", false},
+		{"http://169.254.169.254/latest/meta-data", false},
+		{"http://[fe80::1]/hooks", false},
+		{"http://[fd00::1]/hooks", false},
+		{"http://[::ffff:127.0.0.1]/hooks", false},
+		{"http://0.0.0.0/hooks", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.url, func(t *testing.T) {
+			err := ValidateEndpointURL(context.Background(), test.url)
+			if (err == nil) != test.allowed {
+				t.Errorf("ValidateEndpointURL() error = %v, want allowed %v", err, test.allowed)
+			}
+
+			if err != nil && !errors.Is(err, ErrDisallowedEndpoint) {
+				t.Errorf("ValidateEndpointURL() error = %v, want %v", err, ErrDisallowedEndpoint)
+			}
+		})
+	}
+}
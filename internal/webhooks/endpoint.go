@@ -0,0 +1,95 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// ErrDisallowedEndpoint is a webhook url that leads to the service's own network: loopback, private, link-local
+// (which includes cloud metadata services) and other addresses that aren't reachable from the internet.
+var ErrDisallowedEndpoint = errors.New("webhook endpoint must be a public http or https url")
+
+// sharedAddressSpace is the carrier-grade NAT range, private to the network it is used in but not in net.IP's
+// IsPrivate.
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// ValidateEndpointURL refuses urls that are not http(s) or whose host is, or resolves to, an address that isn't
+// public. Names can be pointed elsewhere afterwards, deliveries are therefore checked again when they connect,
+// see NewEndpointClient.
+func ValidateEndpointURL(ctx context.Context, rawURL string) error {
+	endpointURL, err := url.Parse(rawURL)
+	if err != nil || (endpointURL.Scheme != "http" && endpointURL.Scheme != "https") || endpointURL.Host == "" {
+		return ErrDisallowedEndpoint
+	}
+
+	host := endpointURL.Hostname()
+	if ip := net.ParseIP(host); ip != nil {
+		if !isPublicIP(ip) {
+			return ErrDisallowedEndpoint
+		}
+
+		return nil
+	}
+
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return ErrDisallowedEndpoint
+	}
+
+	addresses, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return fmt.Errorf("%w: %s does not resolve", ErrDisallowedEndpoint, host)
+	}
+
+	for _, address := range addresses {
+		if !isPublicIP(address.IP) {
+			return ErrDisallowedEndpoint
+		}
+	}
+
+	return nil
+}
+
+// NewEndpointClient returns the client deliveries are sent with. It only connects to public addresses, whatever
+// the endpoint's name resolves to by then and wherever it redirects, and never through a proxy, which would
+// connect for it.
+func NewEndpointClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network string, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+
+			if ip := net.ParseIP(host); ip == nil || !isPublicIP(ip) {
+				return fmt.Errorf("%w: %s", ErrDisallowedEndpoint, host)
+			}
+
+			return nil
+		},
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{Timeout: timeout, Transport: transport}
+}
+
+func isPublicIP(ip net.IP) bool {
+	return !ip.IsLoopback() &&
+		!ip.IsPrivate() &&
+		!ip.IsLinkLocalUnicast() &&
+		!ip.IsLinkLocalMulticast() &&
+		!ip.IsInterfaceLocalMulticast() &&
+		!ip.IsMulticast() &&
+		!ip.IsUnspecified() &&
+		!sharedAddressSpace.Contains(ip)
+}
@@ -0,0 +1,28 @@
+package webhooks
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"time"
+)
+
+// SignatureHeader carries `t=<unix timestamp>,v1=<hex hmac>` on every delivery.
+const SignatureHeader = "X-FinCore-Signature"
+
+// Sign returns the hex encoded HMAC-SHA256 of `<timestamp>.<body>` under the endpoint's secret. Receivers compute
+// the same over the raw body and compare in constant time, rejecting old timestamps to stop replays.
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp.Unix(), 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// SignatureHeaderValue formats the signature header for a delivery made at timestamp.
+func SignatureHeaderValue(secret string, timestamp time.Time, body []byte) string {
+	return "t=" + strconv.FormatInt(timestamp.Unix(), 10) + ",v1=" + Sign(secret, timestamp, body)
+}